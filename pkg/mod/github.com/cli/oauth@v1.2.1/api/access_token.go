@@ -1,5 +1,7 @@
 package api
 
+import "strconv"
+
 // AccessToken is an OAuth access token.
 type AccessToken struct {
 	// The token value, typically a 40-character random string.
@@ -10,16 +12,24 @@ type AccessToken struct {
 	Type string
 	// Space-separated list of OAuth scopes that this token grants.
 	Scope string
+	// The number of seconds until the token expires, or zero if it does not expire.
+	ExpiresIn int
+	// The number of seconds until the refresh token expires, or zero if the server did not say.
+	RefreshTokenExpiresIn int
 }
 
 // AccessToken extracts the access token information from a server response.
 func (f FormResponse) AccessToken() (*AccessToken, error) {
 	if accessToken := f.Get("access_token"); accessToken != "" {
+		expiresIn, _ := strconv.Atoi(f.Get("expires_in"))
+		refreshExpiresIn, _ := strconv.Atoi(f.Get("refresh_token_expires_in"))
 		return &AccessToken{
-			Token:        accessToken,
-			RefreshToken: f.Get("refresh_token"),
-			Type:         f.Get("token_type"),
-			Scope:        f.Get("scope"),
+			Token:                 accessToken,
+			RefreshToken:          f.Get("refresh_token"),
+			Type:                  f.Get("token_type"),
+			Scope:                 f.Get("scope"),
+			ExpiresIn:             expiresIn,
+			RefreshTokenExpiresIn: refreshExpiresIn,
 		}, nil
 	}
 
@@ -48,6 +48,26 @@ func TestFormResponse_AccessToken(t *testing.T) {
 			},
 			wantErr: nil,
 		},
+		{
+			name: "with expiry",
+			response: FormResponse{
+				values: url.Values{
+					"access_token":             []string{"ATOKEN"},
+					"refresh_token":            []string{"AREFRESHTOKEN"},
+					"token_type":               []string{"bearer"},
+					"expires_in":               []string{"28800"},
+					"refresh_token_expires_in": []string{"15811200"},
+				},
+			},
+			want: &AccessToken{
+				Token:                 "ATOKEN",
+				RefreshToken:          "AREFRESHTOKEN",
+				Type:                  "bearer",
+				ExpiresIn:             28800,
+				RefreshTokenExpiresIn: 15811200,
+			},
+			wantErr: nil,
+		},
 		{
 			name: "no token",
 			response: FormResponse{
@@ -0,0 +1,128 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+)
+
+// clientAssertionType is the client_assertion_type of a JWT client assertion.
+const clientAssertionType = "urn:ietf:params:oauth:client-assertion-type:jwt-bearer"
+
+// timeNow is time.Now, replaceable in tests.
+var timeNow = time.Now
+
+// ClientAssertion authenticates a client at the token endpoint with a JWT signed by the private key
+// of the client (RFC 7523), known as "private_key_jwt" authentication, instead of a client secret.
+type ClientAssertion struct {
+	// The private key to sign the JWT with: an RSA key, which signs with RS256, or an ECDSA key on
+	// the P-256 curve, which signs with ES256. Keys held by a hardware module or a key management
+	// service can be used through their crypto.Signer implementation.
+	Key crypto.Signer
+	// The ID of the key, which the server uses to find the public key. Optional.
+	KeyID string
+	// How long the JWT is valid. Defaults to five minutes.
+	Lifetime time.Duration
+}
+
+// Apply sets the parameters for a request to tokenURL by clientID in params, replacing any client
+// secret. A new JWT is signed for each request, as servers may reject a JWT that they have seen.
+func (a *ClientAssertion) Apply(params url.Values, clientID, tokenURL string) error {
+	jwt, err := a.sign(clientID, tokenURL)
+	if err != nil {
+		return err
+	}
+	params.Del("client_secret")
+	params.Set("client_assertion_type", clientAssertionType)
+	params.Set("client_assertion", jwt)
+	return nil
+}
+
+func (a *ClientAssertion) sign(clientID, audience string) (string, error) {
+	var alg string
+	switch pub := a.Key.Public().(type) {
+	case *rsa.PublicKey:
+		alg = "RS256"
+	case *ecdsa.PublicKey:
+		if pub.Curve != elliptic.P256() {
+			return "", fmt.Errorf("unsupported ECDSA curve %s", pub.Curve.Params().Name)
+		}
+		alg = "ES256"
+	default:
+		return "", fmt.Errorf("unsupported key type %T", pub)
+	}
+
+	jti := make([]byte, 16)
+	if _, err := rand.Read(jti); err != nil {
+		return "", err
+	}
+	lifetime := a.Lifetime
+	if lifetime <= 0 {
+		lifetime = 5 * time.Minute
+	}
+	now := timeNow()
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if a.KeyID != "" {
+		header["kid"] = a.KeyID
+	}
+	claims := map[string]interface{}{
+		"iss": clientID,
+		"sub": clientID,
+		"aud": audience,
+		"jti": hex.EncodeToString(jti),
+		"iat": now.Unix(),
+		"exp": now.Add(lifetime).Unix(),
+	}
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c)
+
+	digest := sha256.Sum256([]byte(signingInput))
+	sig, err := a.Key.Sign(rand.Reader, digest[:], crypto.SHA256)
+	if err != nil {
+		return "", fmt.Errorf("signing client assertion: %w", err)
+	}
+	if alg == "ES256" {
+		// JWS uses the fixed-size concatenation of r and s rather than ASN.1 (RFC 7518, section 3.4).
+		if sig, err = ecdsaJWSSignature(sig); err != nil {
+			return "", err
+		}
+	}
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(sig), nil
+}
+
+func ecdsaJWSSignature(der []byte) ([]byte, error) {
+	var sig struct {
+		R, S *big.Int
+	}
+	if rest, err := asn1.Unmarshal(der, &sig); err != nil || len(rest) > 0 {
+		return nil, errors.New("signing client assertion: invalid ECDSA signature")
+	}
+	out := make([]byte, 64)
+	rb, sb := sig.R.Bytes(), sig.S.Bytes()
+	if len(rb) > 32 || len(sb) > 32 {
+		return nil, errors.New("signing client assertion: invalid ECDSA signature")
+	}
+	copy(out[32-len(rb):32], rb)
+	copy(out[64-len(sb):], sb)
+	return out, nil
+}
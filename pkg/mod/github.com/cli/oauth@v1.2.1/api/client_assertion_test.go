@@ -0,0 +1,139 @@
+package api
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClientAssertion(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		key    crypto.Signer
+		alg    string
+		verify func(digest, sig []byte) bool
+	}{
+		{
+			name: "RSA",
+			key:  rsaKey,
+			alg:  "RS256",
+			verify: func(digest, sig []byte) bool {
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest, sig) == nil
+			},
+		},
+		{
+			name: "ECDSA",
+			key:  ecKey,
+			alg:  "ES256",
+			verify: func(digest, sig []byte) bool {
+				if len(sig) != 64 {
+					return false
+				}
+				r := new(big.Int).SetBytes(sig[:32])
+				s := new(big.Int).SetBytes(sig[32:])
+				return ecdsa.Verify(&ecKey.PublicKey, digest, r, s)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &ClientAssertion{Key: tt.key, KeyID: "KEY-1"}
+			params := url.Values{"client_secret": {"SECRET"}, "code": {"ABC"}}
+			if err := a.Apply(params, "CLIENT-ID", "https://example.com/token"); err != nil {
+				t.Fatal(err)
+			}
+			if params.Get("client_secret") != "" || params.Get("code") != "ABC" {
+				t.Errorf("params = %v", params)
+			}
+			if got := params.Get("client_assertion_type"); got != clientAssertionType {
+				t.Errorf("client_assertion_type = %q", got)
+			}
+
+			parts := strings.Split(params.Get("client_assertion"), ".")
+			if len(parts) != 3 {
+				t.Fatalf("JWT has %d parts", len(parts))
+			}
+			var header map[string]string
+			var claims map[string]interface{}
+			decode(t, parts[0], &header)
+			decode(t, parts[1], &claims)
+			if header["alg"] != tt.alg || header["typ"] != "JWT" || header["kid"] != "KEY-1" {
+				t.Errorf("header = %v", header)
+			}
+			want := map[string]interface{}{
+				"iss": "CLIENT-ID",
+				"sub": "CLIENT-ID",
+				"aud": "https://example.com/token",
+				"iat": float64(now.Unix()),
+				"exp": float64(now.Add(5 * time.Minute).Unix()),
+			}
+			for k, v := range want {
+				if claims[k] != v {
+					t.Errorf("claim %s = %v, want %v", k, claims[k], v)
+				}
+			}
+			if jti, _ := claims["jti"].(string); len(jti) != 32 {
+				t.Errorf("jti = %v", claims["jti"])
+			}
+
+			sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+			if err != nil {
+				t.Fatal(err)
+			}
+			digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+			if !tt.verify(digest[:], sig) {
+				t.Error("invalid signature")
+			}
+
+			other := url.Values{}
+			if err := a.Apply(other, "CLIENT-ID", "https://example.com/token"); err != nil {
+				t.Fatal(err)
+			}
+			if other.Get("client_assertion") == params.Get("client_assertion") {
+				t.Error("Apply() reused the JWT")
+			}
+		})
+	}
+
+	p384, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (&ClientAssertion{Key: p384}).Apply(url.Values{}, "CLIENT-ID", "https://example.com/token"); err == nil {
+		t.Error("Apply() with a P-384 key: got no error")
+	}
+}
+
+func decode(t *testing.T, part string, v interface{}) {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(b, v); err != nil {
+		t.Fatal(err)
+	}
+}
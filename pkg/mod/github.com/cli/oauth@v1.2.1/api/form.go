@@ -2,6 +2,8 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -10,6 +12,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 )
 
 type httpClient interface {
@@ -35,22 +38,24 @@ func (f FormResponse) Err() error {
 		RequestURI:   f.requestURI,
 		ResponseCode: f.StatusCode,
 		Code:         f.Get("error"),
-		message:      f.Get("error_description"),
+		Description:  f.Get("error_description"),
 	}
 }
 
-// Error is the result of an unexpected HTTP response from the server.
+// Error is the result of an unexpected HTTP response from the server, or of a web redirect that
+// denies authorization.
 type Error struct {
+	// The OAuth error code, such as "access_denied".
 	Code         string
 	ResponseCode int
 	RequestURI   string
-
-	message string
+	// The human-readable error_description, if the server sent one.
+	Description string
 }
 
 func (e Error) Error() string {
-	if e.message != "" {
-		return fmt.Sprintf("%s (%s)", e.message, e.Code)
+	if e.Description != "" {
+		return fmt.Sprintf("%s (%s)", e.Description, e.Code)
 	}
 	if e.Code != "" {
 		return e.Code
@@ -58,10 +63,57 @@ func (e Error) Error() string {
 	return fmt.Sprintf("HTTP %d", e.ResponseCode)
 }
 
+// Is reports whether target is an *Error with the same Code, so that errors.Is(err, ErrAccessDenied)
+// matches any error with the "access_denied" code.
+func (e Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code != "" && t.Code == e.Code
+}
+
+// Errors to compare with errors.Is, for the error codes of RFC 6749, sections 4.1.2.1 and 5.2, and
+// RFC 8628, section 3.5.
+var (
+	ErrInvalidRequest       = &Error{Code: "invalid_request"}
+	ErrInvalidClient        = &Error{Code: "invalid_client"}
+	ErrInvalidGrant         = &Error{Code: "invalid_grant"}
+	ErrUnauthorizedClient   = &Error{Code: "unauthorized_client"}
+	ErrUnsupportedGrantType = &Error{Code: "unsupported_grant_type"}
+	ErrInvalidScope         = &Error{Code: "invalid_scope"}
+	ErrAccessDenied         = &Error{Code: "access_denied"}
+	ErrServerError          = &Error{Code: "server_error"}
+	ErrAuthorizationPending = &Error{Code: "authorization_pending"}
+	ErrSlowDown             = &Error{Code: "slow_down"}
+	ErrExpiredToken         = &Error{Code: "expired_token"}
+)
+
 // PostForm makes an POST request by serializing input parameters as a form and parsing the response
 // of the same type.
 func PostForm(c httpClient, u string, params url.Values) (*FormResponse, error) {
-	resp, err := c.PostForm(u, params)
+	return PostFormContext(context.Background(), c, u, params)
+}
+
+// PostFormContext is like PostForm, but the request is canceled when ctx is done. If c is not an
+// *http.Client or a *RetryClient, ctx is only checked before the request is made.
+func PostFormContext(ctx context.Context, c httpClient, u string, params url.Values) (*FormResponse, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var resp *http.Response
+	var err error
+	if hc, ok := c.(*http.Client); ok {
+		var req *http.Request
+		req, err = http.NewRequestWithContext(ctx, "POST", u, strings.NewReader(params.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		resp, err = hc.Do(req)
+	} else if cp, ok := c.(contextPoster); ok {
+		resp, err = cp.postFormContext(ctx, u, params)
+	} else {
+		resp, err = c.PostForm(u, params)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -75,8 +127,8 @@ func PostForm(c httpClient, u string, params url.Values) (*FormResponse, error)
 	}
 
 	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
-	switch mediaType {
-	case "application/x-www-form-urlencoded":
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
 		var bb []byte
 		bb, err = ioutil.ReadAll(resp.Body)
 		if err != nil {
@@ -87,29 +139,57 @@ func PostForm(c httpClient, u string, params url.Values) (*FormResponse, error)
 		if err != nil {
 			return r, err
 		}
-	case "application/json":
-		var values map[string]interface{}
-		if err := json.NewDecoder(resp.Body).Decode(&values); err != nil {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		r.values, err = parseJSON(resp.Body)
+		if err != nil {
 			return r, err
 		}
-
-		r.values = make(url.Values)
-		for key, value := range values {
-			switch v := value.(type) {
-			case string:
-				r.values.Set(key, v)
-			case int64:
-				r.values.Set(key, strconv.FormatInt(v, 10))
-			case float64:
-				r.values.Set(key, strconv.FormatFloat(v, 'f', -1, 64))
-			}
-		}
 	default:
-		_, err = io.Copy(ioutil.Discard, resp.Body)
+		// Some servers send JSON with a generic content type such as "text/plain".
+		var bb []byte
+		bb, err = ioutil.ReadAll(resp.Body)
 		if err != nil {
 			return r, err
 		}
+		if trimmed := bytes.TrimSpace(bb); len(trimmed) > 0 && trimmed[0] == '{' {
+			if values, err := parseJSON(bytes.NewReader(trimmed)); err == nil {
+				r.values = values
+			}
+		}
 	}
 
 	return r, nil
 }
+
+// parseJSON parses a JSON object into values. Strings, numbers and booleans are converted to their
+// text form. Arrays of strings, which some servers send for the scope, are joined with spaces.
+// Other values are ignored.
+func parseJSON(r io.Reader) (url.Values, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var object map[string]interface{}
+	if err := dec.Decode(&object); err != nil {
+		return nil, err
+	}
+
+	values := make(url.Values)
+	for key, value := range object {
+		switch v := value.(type) {
+		case string:
+			values.Set(key, v)
+		case json.Number:
+			values.Set(key, v.String())
+		case bool:
+			values.Set(key, strconv.FormatBool(v))
+		case []interface{}:
+			var items []string
+			for _, item := range v {
+				if s, ok := item.(string); ok {
+					items = append(items, s)
+				}
+			}
+			values.Set(key, strings.Join(items, " "))
+		}
+	}
+	return values, nil
+}
@@ -2,8 +2,12 @@ package api
 
 import (
 	"bytes"
+	"context"
+	"errors"
+	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"reflect"
 	"testing"
@@ -69,6 +73,7 @@ func TestFormResponse_Err(t *testing.T) {
 				Code:         "try_again",
 				ResponseCode: 422,
 				RequestURI:   "http://example.com/path",
+				Description:  "maybe it works later",
 			},
 			errorMsg: "maybe it works later (try_again)",
 		},
@@ -102,6 +107,9 @@ func TestFormResponse_Err(t *testing.T) {
 			if apiError.RequestURI != tt.wantErr.RequestURI {
 				t.Errorf("Error.RequestURI = %v, want %v", apiError.RequestURI, tt.wantErr.RequestURI)
 			}
+			if apiError.Description != tt.wantErr.Description {
+				t.Errorf("Error.Description = %v, want %v", apiError.Description, tt.wantErr.Description)
+			}
 			if apiError.Error() != tt.errorMsg {
 				t.Errorf("Error.Error() = %q, want %q", apiError.Error(), tt.errorMsg)
 			}
@@ -109,6 +117,22 @@ func TestFormResponse_Err(t *testing.T) {
 	}
 }
 
+func TestError_Is(t *testing.T) {
+	err := fmt.Errorf("wrapped: %w", FormResponse{
+		StatusCode: 400,
+		values:     url.Values{"error": {"access_denied"}},
+	}.Err())
+	if !errors.Is(err, ErrAccessDenied) {
+		t.Errorf("errors.Is(%v, ErrAccessDenied) = false", err)
+	}
+	if errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("errors.Is(%v, ErrInvalidGrant) = true", err)
+	}
+	if errors.Is(FormResponse{StatusCode: 500}.Err(), &Error{}) {
+		t.Error("an error without a code matches an empty code")
+	}
+}
+
 type apiClient struct {
 	status      int
 	body        string
@@ -180,6 +204,85 @@ func TestPostForm(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "JSON with numbers, booleans and arrays",
+			args: args{
+				url: "https://example.com/token",
+			},
+			http: apiClient{
+				body:        `{"access_token":"123abc", "expires_in":28800, "interval":5.5, "id":12345678901234567890, "refreshable":true, "scope":["repo","gist"], "extra":{"a":1}}`,
+				status:      200,
+				contentType: "application/json",
+			},
+			want: &FormResponse{
+				StatusCode: 200,
+				requestURI: "https://example.com/token",
+				values: url.Values{
+					"access_token": {"123abc"},
+					"expires_in":   {"28800"},
+					"interval":     {"5.5"},
+					"id":           {"12345678901234567890"},
+					"refreshable":  {"true"},
+					"scope":        {"repo gist"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "JSON error with a structured suffix",
+			args: args{
+				url: "https://example.com/token",
+			},
+			http: apiClient{
+				body:        `{"error":"authorization_pending","error_description":"Not yet"}`,
+				status:      400,
+				contentType: "application/problem+json",
+			},
+			want: &FormResponse{
+				StatusCode: 400,
+				requestURI: "https://example.com/token",
+				values: url.Values{
+					"error":             {"authorization_pending"},
+					"error_description": {"Not yet"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "JSON with a generic content type",
+			args: args{
+				url: "https://example.com/token",
+			},
+			http: apiClient{
+				body:        ` {"access_token":"123abc"}`,
+				status:      200,
+				contentType: "text/plain",
+			},
+			want: &FormResponse{
+				StatusCode: 200,
+				requestURI: "https://example.com/token",
+				values: url.Values{
+					"access_token": {"123abc"},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid JSON",
+			args: args{
+				url: "https://example.com/token",
+			},
+			http: apiClient{
+				body:        `{"access_token":`,
+				status:      200,
+				contentType: "application/json",
+			},
+			want: &FormResponse{
+				StatusCode: 200,
+				requestURI: "https://example.com/token",
+			},
+			wantErr: true,
+		},
 		{
 			name: "HTML response",
 			args: args{
@@ -214,3 +317,31 @@ func TestPostForm(t *testing.T) {
 		})
 	}
 }
+
+func TestPostFormContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		fmt.Fprintf(w, "access_token=%s", r.PostForm.Get("code"))
+	}))
+	defer srv.Close()
+
+	resp, err := PostFormContext(context.Background(), srv.Client(), srv.URL, url.Values{"code": {"ATOKEN"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Get("access_token"); got != "ATOKEN" {
+		t.Errorf("access_token = %q, want ATOKEN", got)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, c := range []httpClient{srv.Client(), &apiClient{}} {
+		if _, err := PostFormContext(ctx, c, srv.URL, nil); !errors.Is(err, context.Canceled) {
+			t.Errorf("%T: got error %v, want context.Canceled", c, err)
+		}
+	}
+}
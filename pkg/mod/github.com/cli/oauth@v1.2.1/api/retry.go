@@ -0,0 +1,119 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// RetryClient is an HTTP client for PostForm that limits the time of each request and retries
+// requests that fail with a network error or a 5xx response, so that a transient failure does not
+// abort an authorization flow.
+type RetryClient struct {
+	// The client to make requests with. Defaults to http.DefaultClient.
+	Client *http.Client
+	// The time limit for each request, including reading the response. Zero means no limit.
+	Timeout time.Duration
+	// The maximum number of times to retry a request.
+	MaxRetries int
+	// The time to wait before the first retry, which doubles for each further retry. Defaults to
+	// one second.
+	Backoff time.Duration
+	// If UnsentOnly is set, only requests that were never sent, because the connection to the
+	// server could not be established, are retried. Set it for requests that must not reach the
+	// server twice, such as the exchange of an authorization code, which can be used only once.
+	UnsentOnly bool
+}
+
+// contextPoster is implemented by clients that PostFormContext can pass the context to.
+type contextPoster interface {
+	postFormContext(ctx context.Context, u string, params url.Values) (*http.Response, error)
+}
+
+// PostForm makes a POST request with params encoded as a form, retrying it if it fails.
+func (c *RetryClient) PostForm(u string, params url.Values) (*http.Response, error) {
+	return c.postFormContext(context.Background(), u, params)
+}
+
+func (c *RetryClient) postFormContext(ctx context.Context, u string, params url.Values) (*http.Response, error) {
+	backoff := c.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	for retries := 0; ; retries++ {
+		resp, err := c.post(ctx, u, params)
+		retry := retryable(resp, err)
+		if c.UnsentOnly {
+			retry = err != nil && unsent(err)
+		}
+		if retries >= c.MaxRetries || !retry || ctx.Err() != nil {
+			return resp, err
+		}
+
+		t := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return nil, ctx.Err()
+		case <-t.C:
+		}
+		backoff *= 2
+	}
+}
+
+// post makes a single request. The response body is read before post returns, so that the
+// timeout also applies to reading it.
+func (c *RetryClient) post(ctx context.Context, u string, params url.Values) (*http.Response, error) {
+	hc := c.Client
+	if hc == nil {
+		hc = http.DefaultClient
+	}
+	if c.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", u, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// retryable reports whether a request that returned resp and err may succeed if made again.
+func retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// The client returns a *url.Error for failed requests. A URL that cannot be parsed fails
+		// again.
+		urlErr, ok := err.(*url.Error)
+		return ok && urlErr.Op != "parse"
+	}
+	return resp.StatusCode >= 500
+}
+
+// unsent reports whether a request that failed with err was never sent, because dialing the
+// server failed.
+func unsent(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
@@ -0,0 +1,131 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryClient(t *testing.T) {
+	var calls int32
+	failures := int32(2)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&calls, 1)
+		if n <= atomic.LoadInt32(&failures) {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.URL.Path == "/slow" && n == atomic.LoadInt32(&failures)+1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		_ = r.ParseForm()
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		fmt.Fprintf(w, "access_token=%s", r.PostForm.Get("code"))
+	}))
+	defer srv.Close()
+
+	post := func(c *RetryClient, path string) (*FormResponse, error) {
+		atomic.StoreInt32(&calls, 0)
+		return PostFormContext(context.Background(), c, srv.URL+path, url.Values{"code": {"ATOKEN"}})
+	}
+
+	c := &RetryClient{Client: srv.Client(), MaxRetries: 2, Backoff: time.Millisecond}
+	resp, err := post(c, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Get("access_token"); got != "ATOKEN" || calls != 3 {
+		t.Errorf("got access_token %q after %d calls, want ATOKEN after 3", got, calls)
+	}
+
+	c.MaxRetries = 1
+	resp, err = post(c, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusBadGateway || calls != 2 {
+		t.Errorf("got HTTP %d after %d calls, want 502 after 2", resp.StatusCode, calls)
+	}
+
+	// The slow response times out, and the retry succeeds.
+	atomic.StoreInt32(&failures, 0)
+	c.MaxRetries = 1
+	c.Timeout = 50 * time.Millisecond
+	resp, err = post(c, "/slow")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Get("access_token"); got != "ATOKEN" || calls != 2 {
+		t.Errorf("got access_token %q after %d calls, want ATOKEN after 2", got, calls)
+	}
+
+	c.MaxRetries = 0
+	if _, err := post(c, "/slow"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+
+	c.MaxRetries = 3
+	if _, err := c.PostForm("://bad", nil); err == nil || retryable(nil, err) {
+		t.Errorf("got error %v, want a parse error that is not retried", err)
+	}
+}
+
+func TestRetryClient_cancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	c := &RetryClient{Client: srv.Client(), MaxRetries: 5, Backoff: time.Hour}
+	if _, err := PostFormContext(ctx, c, srv.URL, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("got error %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestRetryClient_unsentOnly(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/drop" {
+			// The server received the request, but the client gets a network error.
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+	c := &RetryClient{Client: srv.Client(), MaxRetries: 2, Backoff: time.Millisecond, UnsentOnly: true}
+
+	resp, err := c.PostForm(srv.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(&calls); resp.StatusCode != http.StatusBadGateway || n != 1 {
+		t.Errorf("got HTTP %d after %d calls, want 502 after 1", resp.StatusCode, n)
+	}
+
+	atomic.StoreInt32(&calls, 0)
+	_, err = c.PostForm(srv.URL+"/drop", nil)
+	if n := atomic.LoadInt32(&calls); err == nil || n != 1 {
+		t.Errorf("got error %v after %d calls, want an error after 1", err, n)
+	}
+
+	// A server that refuses connections never receives the request, which is retried.
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	_, err = c.PostForm(closed.URL, nil)
+	if err == nil || !unsent(err) {
+		t.Errorf("got error %v, want a dial error", err)
+	}
+}
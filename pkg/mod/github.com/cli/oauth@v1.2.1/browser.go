@@ -0,0 +1,60 @@
+package oauth
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+
+	"github.com/cli/browser"
+)
+
+// openBrowser is browser.OpenURL, replaceable in tests.
+var openBrowser = browser.OpenURL
+
+// PrintURL returns a function for Flow.BrowseURL that prints the URL to w for the user to open,
+// instead of opening a web browser.
+func PrintURL(w io.Writer) func(string) error {
+	return func(u string) error {
+		_, err := fmt.Fprintf(w, "Open this URL in your web browser: %s\n", u)
+		return err
+	}
+}
+
+// browseURL returns oa.BrowseURL, or the default, which opens the default system browser. Where
+// that cannot work, as in an SSH session without a display, or if it fails, the default prints the
+// URL to oa.Stdout instead.
+func (oa *Flow) browseURL() func(string) error {
+	if oa.BrowseURL != nil {
+		return oa.BrowseURL
+	}
+	stdout := oa.Stdout
+	if stdout == nil {
+		stdout = os.Stdout
+	}
+	printURL := PrintURL(stdout)
+	if remoteSession() {
+		return printURL
+	}
+	return func(u string) error {
+		if err := openBrowser(u); err != nil {
+			fmt.Fprintf(stdout, "Could not open the web browser: %v\n", err)
+			return printURL(u)
+		}
+		return nil
+	}
+}
+
+// remoteSession reports whether the program runs in an SSH session in which a browser would open,
+// if at all, on the remote machine rather than in front of the user.
+func remoteSession() bool {
+	if os.Getenv("SSH_CONNECTION") == "" && os.Getenv("SSH_TTY") == "" {
+		return false
+	}
+	switch runtime.GOOS {
+	case "darwin", "windows":
+		return true
+	}
+	// With X11 forwarding or a graphical session, the browser is shown to the user.
+	return os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == ""
+}
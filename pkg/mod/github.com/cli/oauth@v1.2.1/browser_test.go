@@ -0,0 +1,75 @@
+package oauth
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"testing"
+)
+
+// setenv sets or, if value is empty, unsets an environment variable, and returns a function that
+// restores it.
+func setenv(key, value string) func() {
+	old, ok := os.LookupEnv(key)
+	if value == "" {
+		os.Unsetenv(key)
+	} else {
+		os.Setenv(key, value)
+	}
+	return func() {
+		if ok {
+			os.Setenv(key, old)
+		} else {
+			os.Unsetenv(key)
+		}
+	}
+}
+
+func TestFlow_browseURL(t *testing.T) {
+	for _, key := range []string{"SSH_CONNECTION", "SSH_TTY", "DISPLAY", "WAYLAND_DISPLAY"} {
+		defer setenv(key, "")()
+	}
+	defer func(f func(string) error) { openBrowser = f }(openBrowser)
+	var opened []string
+	var openErr error
+	openBrowser = func(u string) error {
+		opened = append(opened, u)
+		return openErr
+	}
+
+	var stdout bytes.Buffer
+	flow := &Flow{Stdout: &stdout}
+	if err := flow.browseURL()("https://example.com/1"); err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 1 || stdout.Len() != 0 {
+		t.Errorf("opened %v and printed %q, want only opened", opened, stdout.String())
+	}
+
+	openErr = errors.New("no browser")
+	if err := flow.browseURL()("https://example.com/2"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "Could not open the web browser: no browser\nOpen this URL in your web browser: https://example.com/2\n"; stdout.String() != want {
+		t.Errorf("printed %q, want %q", stdout.String(), want)
+	}
+
+	opened = nil
+	stdout.Reset()
+	defer setenv("SSH_TTY", "/dev/pts/0")()
+	if err := flow.browseURL()("https://example.com/3"); err != nil {
+		t.Fatal(err)
+	}
+	if len(opened) != 0 || stdout.String() != "Open this URL in your web browser: https://example.com/3\n" {
+		t.Errorf("in an SSH session, opened %v and printed %q", opened, stdout.String())
+	}
+
+	var custom []string
+	flow.BrowseURL = func(u string) error {
+		custom = append(custom, u)
+		return nil
+	}
+	if err := flow.browseURL()("https://example.com/4"); err != nil || len(custom) != 1 {
+		t.Errorf("BrowseURL was not used: %v, %v", custom, err)
+	}
+}
@@ -32,6 +32,28 @@ var (
 	ErrTimeout = errors.New("authentication timed out")
 )
 
+// flowError is an error that matches a sentinel error such as ErrUnsupported with errors.Is, and
+// wraps its cause, such as an *api.Error with the code and description from the server.
+type flowError struct {
+	sentinel error
+	cause    error
+}
+
+func (e *flowError) Error() string {
+	if e.sentinel == ErrUnsupported {
+		return e.sentinel.Error()
+	}
+	return fmt.Sprintf("%s: %s", e.sentinel, e.cause)
+}
+
+func (e *flowError) Is(target error) bool {
+	return target == e.sentinel
+}
+
+func (e *flowError) Unwrap() error {
+	return e.cause
+}
+
 type httpClient interface {
 	PostForm(string, url.Values) (*http.Response, error)
 }
@@ -66,8 +88,24 @@ func WithAudience(audience string) AuthRequestEditorFn {
 	}
 }
 
+// WithResources sets the resource indicators (RFC 8707) in the request: the absolute URIs of the
+// APIs that the access token is for.
+func WithResources(resources ...string) AuthRequestEditorFn {
+	return func(values *url.Values) {
+		for _, r := range resources {
+			values.Add("resource", r)
+		}
+	}
+}
+
 // RequestCode initiates the authorization flow by requesting a code from uri.
 func RequestCode(c httpClient, uri string, clientID string, scopes []string,
+	optionalRequestParams ...AuthRequestEditorFn) (*CodeResponse, error) {
+	return RequestCodeContext(context.Background(), c, uri, clientID, scopes, optionalRequestParams...)
+}
+
+// RequestCodeContext is like RequestCode, but the request is canceled when ctx is done.
+func RequestCodeContext(ctx context.Context, c httpClient, uri string, clientID string, scopes []string,
 	optionalRequestParams ...AuthRequestEditorFn) (*CodeResponse, error) {
 	values := url.Values{
 		"client_id": {clientID},
@@ -78,7 +116,7 @@ func RequestCode(c httpClient, uri string, clientID string, scopes []string,
 		fn(&values)
 	}
 
-	resp, err := api.PostForm(c, uri, values)
+	resp, err := api.PostFormContext(ctx, c, uri, values)
 	if err != nil {
 		return nil, err
 	}
@@ -93,16 +131,20 @@ func RequestCode(c httpClient, uri string, clientID string, scopes []string,
 		(resp.StatusCode == 200 && verificationURI == "") ||
 		(resp.StatusCode == 400 && resp.Get("error") == "device_flow_disabled") ||
 		(resp.StatusCode == 400 && resp.Get("error") == "unauthorized_client") {
-		return nil, ErrUnsupported
+		return nil, &flowError{sentinel: ErrUnsupported, cause: resp.Err()}
 	}
 
 	if resp.StatusCode != 200 {
 		return nil, resp.Err()
 	}
 
-	intervalSeconds, err := strconv.Atoi(resp.Get("interval"))
-	if err != nil {
-		return nil, fmt.Errorf("could not parse interval=%q as integer: %w", resp.Get("interval"), err)
+	// The interval is optional and defaults to 5 seconds (RFC 8628, section 3.2).
+	intervalSeconds := defaultInterval
+	if s := resp.Get("interval"); s != "" {
+		intervalSeconds, err = strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("could not parse interval=%q as integer: %w", s, err)
+		}
 	}
 
 	expiresIn, err := strconv.Atoi(resp.Get("expires_in"))
@@ -122,6 +164,9 @@ func RequestCode(c httpClient, uri string, clientID string, scopes []string,
 
 const defaultGrantType = "urn:ietf:params:oauth:grant-type:device_code"
 
+// defaultInterval is the polling interval in seconds when the server does not specify one.
+const defaultInterval = 5
+
 // PollToken polls the server at pollURL until an access token is granted or denied.
 //
 // Deprecated: use Wait.
@@ -138,18 +183,33 @@ type WaitOptions struct {
 	ClientID string
 	// ClientSecret is the app client secret value. Optional: only pass if the server requires it.
 	ClientSecret string
+	// ClientAssertion authenticates the app with a signed JWT instead of ClientSecret. Optional.
+	ClientAssertion *api.ClientAssertion
 	// DeviceCode is the value obtained from RequestCode.
 	DeviceCode *CodeResponse
 	// GrantType overrides the default value specified by OAuth 2.0 Device Code. Optional.
 	GrantType string
+	// MaxDuration limits how long Wait polls, independently of the expiry of the device code, after
+	// which Wait returns ErrTimeout. Optional.
+	MaxDuration time.Duration
+	// MaxInterval caps the polling interval when the server asks to slow down. Optional; note that
+	// polling faster than the server asks may get more slow_down responses.
+	MaxInterval time.Duration
+	// Jitter randomly lengthens each polling interval by up to this fraction of it, for example 0.1
+	// for up to 10%, so that many clients do not poll in step. Optional.
+	Jitter float64
 
 	newPoller pollerFactory
 }
 
-// Wait polls the server at uri until authorization completes.
+// Wait polls the server at uri until authorization completes. Canceling ctx stops the polling and
+// any request in progress.
 func Wait(ctx context.Context, c httpClient, uri string, opts WaitOptions) (*api.AccessToken, error) {
 	baseCheckInterval := time.Duration(opts.DeviceCode.Interval) * time.Second
 	expiresIn := time.Duration(opts.DeviceCode.ExpiresIn) * time.Second
+	if opts.MaxDuration > 0 && (expiresIn <= 0 || opts.MaxDuration < expiresIn) {
+		expiresIn = opts.MaxDuration
+	}
 	grantType := opts.GrantType
 	if opts.GrantType == "" {
 		grantType = defaultGrantType
@@ -157,12 +217,17 @@ func Wait(ctx context.Context, c httpClient, uri string, opts WaitOptions) (*api
 
 	makePoller := opts.newPoller
 	if makePoller == nil {
-		makePoller = newPoller
+		makePoller = newPoller(opts.Jitter)
 	}
-	_, poll := makePoller(ctx, baseCheckInterval, expiresIn)
+	tctx, poll := makePoller(ctx, baseCheckInterval, expiresIn)
+	defer poll.Cancel()
 
 	for {
 		if err := poll.Wait(); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+				// The device code expired, or MaxDuration passed.
+				return nil, &flowError{sentinel: ErrTimeout, cause: err}
+			}
 			return nil, err
 		}
 
@@ -176,29 +241,28 @@ func Wait(ctx context.Context, c httpClient, uri string, opts WaitOptions) (*api
 		if opts.ClientSecret != "" {
 			values.Add("client_secret", opts.ClientSecret)
 		}
+		if opts.ClientAssertion != nil {
+			if err := opts.ClientAssertion.Apply(values, opts.ClientID, uri); err != nil {
+				return nil, err
+			}
+		}
 
-		// TODO: pass tctx down to the HTTP layer
-		resp, err := api.PostForm(c, uri, values)
+		resp, err := api.PostFormContext(tctx, c, uri, values)
 		if err != nil {
 			return nil, err
 		}
 
-		var apiError *api.Error
 		token, err := resp.AccessToken()
 		if err == nil {
 			return token, nil
 		}
 
-		if !errors.As(err, &apiError) {
-			return nil, err
-		}
-
-		if apiError.Code == "authorization_pending" {
+		if errors.Is(err, api.ErrAuthorizationPending) {
 			// Keep polling
 			continue
 		}
 
-		if apiError.Code == "slow_down" {
+		if errors.Is(err, api.ErrSlowDown) {
 			// Based on the RFC spec, we must add 5 seconds to our current polling interval.
 			// (See https://www.rfc-editor.org/rfc/rfc8628#section-3.5)
 			newInterval := poll.GetInterval() + 5*time.Second
@@ -212,10 +276,16 @@ func Wait(ctx context.Context, c httpClient, uri string, opts WaitOptions) (*api
 				}
 			}
 
+			if opts.MaxInterval > 0 && newInterval > opts.MaxInterval {
+				newInterval = opts.MaxInterval
+			}
 			poll.SetInterval(newInterval)
 			continue
 		}
 
+		if errors.Is(err, api.ErrExpiredToken) {
+			return nil, &flowError{sentinel: ErrTimeout, cause: err}
+		}
 		return nil, err
 	}
 }
@@ -47,11 +47,12 @@ func (c *apiClient) PostForm(u string, params url.Values) (*http.Response, error
 
 func TestRequestCode(t *testing.T) {
 	type args struct {
-		http     apiClient
-		url      string
-		clientID string
-		scopes   []string
-		audience string
+		http      apiClient
+		url       string
+		clientID  string
+		scopes    []string
+		audience  string
+		resources []string
 	}
 	tests := []struct {
 		name    string
@@ -93,6 +94,39 @@ func TestRequestCode(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "JSON without interval",
+			args: args{
+				http: apiClient{
+					stubs: []apiStub{
+						{
+							body:        `{"verification_uri":"http://verify.me","expires_in":99,"device_code":"DEVIC","user_code":"123-abc"}`,
+							status:      200,
+							contentType: "application/json",
+						},
+					},
+				},
+				url:      "https://example.com/device",
+				clientID: "CLIENT-ID",
+				scopes:   []string{"openid"},
+			},
+			want: &CodeResponse{
+				DeviceCode:      "DEVIC",
+				UserCode:        "123-abc",
+				VerificationURI: "http://verify.me",
+				ExpiresIn:       99,
+				Interval:        5,
+			},
+			posts: []postArgs{
+				{
+					url: "https://example.com/device",
+					params: url.Values{
+						"client_id": {"CLIENT-ID"},
+						"scope":     {"openid"},
+					},
+				},
+			},
+		},
 		{
 			name: "with verification_uri_complete",
 			args: args{
@@ -163,6 +197,41 @@ func TestRequestCode(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "with resources",
+			args: args{
+				http: apiClient{
+					stubs: []apiStub{
+						{
+							body:        "verification_uri=http://verify.me&interval=5&expires_in=99&device_code=DEVIC&user_code=123-abc",
+							status:      200,
+							contentType: "application/x-www-form-urlencoded; charset=utf-8",
+						},
+					},
+				},
+				url:       "https://example.com/device",
+				clientID:  "CLIENT-ID",
+				scopes:    []string{"read"},
+				resources: []string{"https://api.example.com/", "https://files.example.com/"},
+			},
+			want: &CodeResponse{
+				DeviceCode:      "DEVIC",
+				UserCode:        "123-abc",
+				VerificationURI: "http://verify.me",
+				ExpiresIn:       99,
+				Interval:        5,
+			},
+			posts: []postArgs{
+				{
+					url: "https://example.com/device",
+					params: url.Values{
+						"client_id": {"CLIENT-ID"},
+						"scope":     {"read"},
+						"resource":  {"https://api.example.com/", "https://files.example.com/"},
+					},
+				},
+			},
+		},
 		{
 			name: "unsupported",
 			args: args{
@@ -275,7 +344,7 @@ func TestRequestCode(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := RequestCode(&tt.args.http, tt.args.url,
-				tt.args.clientID, tt.args.scopes, WithAudience(tt.args.audience))
+				tt.args.clientID, tt.args.scopes, WithAudience(tt.args.audience), WithResources(tt.args.resources...))
 			if (err != nil) != (tt.wantErr != "") {
 				t.Errorf("RequestCode() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -296,6 +365,64 @@ func TestRequestCode(t *testing.T) {
 	}
 }
 
+func TestRequestCodeUnsupported(t *testing.T) {
+	client := &apiClient{stubs: []apiStub{{
+		body:        "error=unauthorized_client&error_description=Device+flow+is+not+allowed",
+		status:      400,
+		contentType: "application/x-www-form-urlencoded",
+	}}}
+	_, err := RequestCode(client, "https://github.com/oauth", "CLIENT-ID", nil)
+	if !errors.Is(err, ErrUnsupported) {
+		t.Fatalf("RequestCode() error = %v, want ErrUnsupported", err)
+	}
+	var apiError *api.Error
+	if !errors.As(err, &apiError) || !errors.Is(err, api.ErrUnauthorizedClient) || apiError.Description != "Device flow is not allowed" {
+		t.Errorf("RequestCode() error = %#v, want the error from the server", err)
+	}
+}
+
+func TestWaitExpiredToken(t *testing.T) {
+	client := &apiClient{stubs: []apiStub{{
+		body:        "error=expired_token",
+		status:      400,
+		contentType: "application/x-www-form-urlencoded",
+	}}}
+	_, err := Wait(context.Background(), client, "https://github.com/oauth", WaitOptions{
+		ClientID:   "CLIENT-ID",
+		DeviceCode: &CodeResponse{DeviceCode: "DEVIC", ExpiresIn: 99, Interval: 5},
+		newPoller: func(ctx context.Context, interval, expiresIn time.Duration) (context.Context, poller) {
+			return ctx, &fakePoller{maxWaits: 1}
+		},
+	})
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, api.ErrExpiredToken) {
+		t.Errorf("Wait() error = %v, want ErrTimeout and api.ErrExpiredToken", err)
+	}
+}
+
+func TestWaitDeadline(t *testing.T) {
+	_, err := Wait(context.Background(), &apiClient{}, "https://github.com/oauth", WaitOptions{
+		ClientID:    "CLIENT-ID",
+		DeviceCode:  &CodeResponse{DeviceCode: "DEVIC", ExpiresIn: 99, Interval: 1},
+		MaxDuration: time.Millisecond,
+	})
+	if !errors.Is(err, ErrTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Wait() error = %v, want ErrTimeout and context.DeadlineExceeded", err)
+	}
+}
+
+func TestRequestCodeContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	client := &apiClient{}
+	_, err := RequestCodeContext(ctx, client, "https://github.com/oauth", "CLIENT-ID", []string{"repo"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RequestCodeContext() error = %v, want context.Canceled", err)
+	}
+	if client.postCount != 0 {
+		t.Errorf("expected no PostForm; happened %d times", client.postCount)
+	}
+}
+
 func TestPollToken(t *testing.T) {
 	singletonFakePoller := func(maxWaits int) pollerFactory {
 		var instance *fakePoller
@@ -459,6 +586,67 @@ func TestPollToken(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "slow down, interval capped by MaxInterval",
+			args: args{
+				http: apiClient{
+					stubs: []apiStub{
+						{
+							body:        "error=slow_down&interval=22",
+							status:      200,
+							contentType: "application/x-www-form-urlencoded; charset=utf-8",
+						},
+						{
+							body:        "access_token=123abc",
+							status:      200,
+							contentType: "application/x-www-form-urlencoded; charset=utf-8",
+						},
+					},
+				},
+				url: "https://github.com/oauth",
+				opts: WaitOptions{
+					ClientID: "CLIENT-ID",
+					DeviceCode: &CodeResponse{
+						DeviceCode:      "DEVIC",
+						UserCode:        "123-abc",
+						VerificationURI: "http://verify.me",
+						ExpiresIn:       99,
+						Interval:        5,
+					},
+					MaxInterval: 10 * time.Second,
+					newPoller:   singletonFakePoller(2),
+				},
+			},
+			want: &api.AccessToken{
+				Token: "123abc",
+			},
+			posts: []postArgs{
+				{
+					url: "https://github.com/oauth",
+					params: url.Values{
+						"client_id":   {"CLIENT-ID"},
+						"device_code": {"DEVIC"},
+						"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+					},
+				},
+				{
+					url: "https://github.com/oauth",
+					params: url.Values{
+						"client_id":   {"CLIENT-ID"},
+						"device_code": {"DEVIC"},
+						"grant_type":  {"urn:ietf:params:oauth:grant-type:device_code"},
+					},
+				},
+			},
+			assertFunc: func(t *testing.T, a args) {
+				_, poller := a.opts.newPoller(context.Background(), 0, 0)
+				got := poller.(*fakePoller).updatedIntervals
+				want := []time.Duration{10 * time.Second}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("unexpected updated intervals = %v, want %v", got, want)
+				}
+			},
+		},
 		{
 			name: "success with multiple slow down, new interval in returned response",
 			args: args{
@@ -861,6 +1049,57 @@ func TestPollToken(t *testing.T) {
 	}
 }
 
+func TestWaitMaxDuration(t *testing.T) {
+	tests := []struct {
+		name        string
+		expiresIn   int
+		maxDuration time.Duration
+		want        time.Duration
+	}{
+		{name: "expiry", expiresIn: 900, want: 900 * time.Second},
+		{name: "max duration", expiresIn: 900, maxDuration: time.Minute, want: time.Minute},
+		{name: "expiry before max duration", expiresIn: 30, maxDuration: time.Minute, want: 30 * time.Second},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got time.Duration
+			c := &apiClient{stubs: []apiStub{{
+				body:        "access_token=123abc",
+				status:      200,
+				contentType: "application/x-www-form-urlencoded",
+			}}}
+			_, err := Wait(context.Background(), c, "https://github.com/oauth", WaitOptions{
+				ClientID:    "CLIENT-ID",
+				DeviceCode:  &CodeResponse{DeviceCode: "DEVIC", ExpiresIn: tt.expiresIn, Interval: 5},
+				MaxDuration: tt.maxDuration,
+				newPoller: func(ctx context.Context, interval, expiresIn time.Duration) (context.Context, poller) {
+					got = expiresIn
+					return ctx, &fakePoller{maxWaits: 1, interval: interval}
+				},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("poller expiry = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIntervalPollerJitter(t *testing.T) {
+	p := &intervalPoller{interval: 5 * time.Second}
+	if got := p.nextInterval(); got != 5*time.Second {
+		t.Errorf("nextInterval() without jitter = %v", got)
+	}
+	p.jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := p.nextInterval(); got < 5*time.Second || got > 6*time.Second {
+			t.Fatalf("nextInterval() = %v, want between 5s and 6s", got)
+		}
+	}
+}
+
 type fakePoller struct {
 	interval         time.Duration
 	maxWaits         int
@@ -2,6 +2,7 @@ package device
 
 import (
 	"context"
+	"math/rand"
 	"time"
 )
 
@@ -14,18 +15,24 @@ type poller interface {
 
 type pollerFactory func(context.Context, time.Duration, time.Duration) (context.Context, poller)
 
-func newPoller(ctx context.Context, checkInterval, expiresIn time.Duration) (context.Context, poller) {
-	c, cancel := context.WithTimeout(ctx, expiresIn)
-	return c, &intervalPoller{
-		ctx:        c,
-		interval:   checkInterval,
-		cancelFunc: cancel,
+// newPoller returns a pollerFactory for pollers that randomly lengthen each interval by up to the
+// jitter fraction of it.
+func newPoller(jitter float64) pollerFactory {
+	return func(ctx context.Context, checkInterval, expiresIn time.Duration) (context.Context, poller) {
+		c, cancel := context.WithTimeout(ctx, expiresIn)
+		return c, &intervalPoller{
+			ctx:        c,
+			interval:   checkInterval,
+			jitter:     jitter,
+			cancelFunc: cancel,
+		}
 	}
 }
 
 type intervalPoller struct {
 	ctx        context.Context
 	interval   time.Duration
+	jitter     float64
 	cancelFunc func()
 }
 
@@ -38,7 +45,7 @@ func (p *intervalPoller) SetInterval(d time.Duration) {
 }
 
 func (p *intervalPoller) Wait() error {
-	t := time.NewTimer(p.interval)
+	t := time.NewTimer(p.nextInterval())
 	select {
 	case <-p.ctx.Done():
 		t.Stop()
@@ -48,6 +55,14 @@ func (p *intervalPoller) Wait() error {
 	}
 }
 
+// nextInterval returns the interval with jitter added.
+func (p *intervalPoller) nextInterval() time.Duration {
+	if p.jitter <= 0 {
+		return p.interval
+	}
+	return p.interval + time.Duration(rand.Float64()*p.jitter*float64(p.interval))
+}
+
 func (p *intervalPoller) Cancel() {
 	p.cancelFunc()
 }
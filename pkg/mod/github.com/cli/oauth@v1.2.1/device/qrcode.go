@@ -0,0 +1,18 @@
+package device
+
+import "github.com/cli/oauth/internal/qrcode"
+
+// QRCode renders the verification URL as a QR code for display in a terminal, so that the user can
+// scan it with a phone instead of typing it. VerificationURIComplete, which includes the UserCode, is
+// used if the server returned it.
+func (c *CodeResponse) QRCode() (string, error) {
+	uri := c.VerificationURIComplete
+	if uri == "" {
+		uri = c.VerificationURI
+	}
+	qr, err := qrcode.Encode(uri)
+	if err != nil {
+		return "", err
+	}
+	return qr.Terminal(), nil
+}
@@ -0,0 +1,33 @@
+package device
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestCodeResponse_QRCode(t *testing.T) {
+	short := &CodeResponse{VerificationURI: "https://example.com/device"}
+	complete := &CodeResponse{
+		VerificationURI:         "https://example.com/device",
+		VerificationURIComplete: "https://example.com/device?user_code=ABCD-1234-EFGH-5678",
+	}
+	shortQR, err := short.QRCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	completeQR, err := complete.QRCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(shortQR, "█") {
+		t.Errorf("QRCode() = %q", shortQR)
+	}
+	if len(completeQR) <= len(shortQR) {
+		t.Error("QRCode() does not use VerificationURIComplete")
+	}
+
+	long := &CodeResponse{VerificationURI: "https://example.com/" + strings.Repeat("x", 3000)}
+	if _, err := long.QRCode(); err == nil {
+		t.Error("QRCode() of a long URI: got no error")
+	}
+}
@@ -0,0 +1,116 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// serverMetadata is the part of an OpenID Connect provider configuration or of OAuth authorization
+// server metadata (RFC 8414) that describes the endpoints of a Host.
+type serverMetadata struct {
+	Issuer                      string `json:"issuer"`
+	AuthorizationEndpoint       string `json:"authorization_endpoint"`
+	TokenEndpoint               string `json:"token_endpoint"`
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+	RevocationEndpoint          string `json:"revocation_endpoint"`
+}
+
+// DiscoverHost constructs a Host from the metadata that an OAuth server publishes about itself.
+// The issuer is the URL that identifies the server, e.g. "https://accounts.example.com".
+//
+// DiscoverHost fetches the OpenID Connect configuration at issuer +
+// "/.well-known/openid-configuration" and, if the server does not have one, the OAuth
+// authorization server metadata defined by RFC 8414. Endpoints that the server does not support
+// are left empty in the Host.
+func DiscoverHost(issuer string) (*Host, error) {
+	return DiscoverHostContext(context.Background(), http.DefaultClient, issuer)
+}
+
+// DiscoverHostContext is like DiscoverHost, but uses the given HTTP client, or http.DefaultClient if
+// it is nil, and cancels the requests when ctx is done.
+func DiscoverHostContext(ctx context.Context, c *http.Client, issuer string) (*Host, error) {
+	if c == nil {
+		c = http.DefaultClient
+	}
+	u, err := url.Parse(strings.TrimSpace(issuer))
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+		return nil, fmt.Errorf("issuer %q is not an absolute HTTP URL", issuer)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("issuer %q has a query or fragment", issuer)
+	}
+	issuer = strings.TrimSuffix(u.String(), "/")
+	path := strings.TrimSuffix(u.Path, "/")
+
+	// OpenID Connect appends the well-known path to the issuer, and RFC 8414 inserts it between
+	// the host and the path of the issuer.
+	oidcURL := *u
+	oidcURL.Path = path + "/.well-known/openid-configuration"
+	oauthURL := *u
+	oauthURL.Path = "/.well-known/oauth-authorization-server" + path
+
+	md, err := fetchServerMetadata(ctx, c, oidcURL.String())
+	if err == errNoMetadata {
+		md, err = fetchServerMetadata(ctx, c, oauthURL.String())
+	}
+	if err == errNoMetadata {
+		return nil, fmt.Errorf("no OAuth server metadata found for %s", issuer)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// The issuer in the metadata must match, so that one server cannot pose as another.
+	if strings.TrimSuffix(md.Issuer, "/") != issuer {
+		return nil, fmt.Errorf("server metadata is for issuer %q, not %q", md.Issuer, issuer)
+	}
+	if md.TokenEndpoint == "" {
+		return nil, fmt.Errorf("server metadata for %s has no token endpoint", issuer)
+	}
+
+	return &Host{
+		DeviceCodeURL: md.DeviceAuthorizationEndpoint,
+		AuthorizeURL:  md.AuthorizationEndpoint,
+		TokenURL:      md.TokenEndpoint,
+		RevocationURL: md.RevocationEndpoint,
+	}, nil
+}
+
+// errNoMetadata is returned by fetchServerMetadata when there is no document at the URL.
+var errNoMetadata = errors.New("no server metadata")
+
+func fetchServerMetadata(ctx context.Context, c *http.Client, u string) (*serverMetadata, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		_, _ = io.Copy(ioutil.Discard, resp.Body)
+		return nil, errNoMetadata
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching %s: HTTP %d", u, resp.StatusCode)
+	}
+	var md serverMetadata
+	if err := json.NewDecoder(resp.Body).Decode(&md); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", u, err)
+	}
+	return &md, nil
+}
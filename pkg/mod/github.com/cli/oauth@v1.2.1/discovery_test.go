@@ -0,0 +1,90 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDiscoverHost(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{
+				"issuer": %q,
+				"authorization_endpoint": "%[1]s/authorize",
+				"token_endpoint": "%[1]s/token",
+				"device_authorization_endpoint": "%[1]s/device",
+				"revocation_endpoint": "%[1]s/revoke"
+			}`, srv.URL)
+		case "/.well-known/oauth-authorization-server/tenant":
+			fmt.Fprintf(w, `{
+				"issuer": "%s/tenant",
+				"authorization_endpoint": "%[1]s/tenant/authorize",
+				"token_endpoint": "%[1]s/tenant/token"
+			}`, srv.URL)
+		case "/other/.well-known/openid-configuration":
+			fmt.Fprint(w, `{"issuer": "https://evil.example.com", "token_endpoint": "https://evil.example.com/token"}`)
+		case "/notoken/.well-known/openid-configuration":
+			fmt.Fprintf(w, `{"issuer": "%s/notoken"}`, srv.URL)
+		case "/broken/.well-known/openid-configuration":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		issuer  string
+		want    *Host
+		wantErr string
+	}{
+		{
+			name:   "OpenID Connect",
+			issuer: srv.URL + "/",
+			want: &Host{
+				DeviceCodeURL: srv.URL + "/device",
+				AuthorizeURL:  srv.URL + "/authorize",
+				TokenURL:      srv.URL + "/token",
+				RevocationURL: srv.URL + "/revoke",
+			},
+		},
+		{
+			name:   "RFC 8414",
+			issuer: srv.URL + "/tenant",
+			want: &Host{
+				AuthorizeURL: srv.URL + "/tenant/authorize",
+				TokenURL:     srv.URL + "/tenant/token",
+			},
+		},
+		{name: "issuer mismatch", issuer: srv.URL + "/other", wantErr: "is for issuer"},
+		{name: "no token endpoint", issuer: srv.URL + "/notoken", wantErr: "no token endpoint"},
+		{name: "server error", issuer: srv.URL + "/broken", wantErr: "HTTP 500"},
+		{name: "not found", issuer: srv.URL + "/missing", wantErr: "no OAuth server metadata"},
+		{name: "not a URL", issuer: "example.com", wantErr: "not an absolute HTTP URL"},
+		{name: "query", issuer: srv.URL + "?a=b", wantErr: "query or fragment"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DiscoverHostContext(context.Background(), nil, tt.issuer)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if *got != *tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package oauth_test
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"github.com/cli/oauth"
 )
@@ -31,3 +35,64 @@ func ExampleFlow_DetectFlow() {
 
 	fmt.Printf("Access token: %s\n", accessToken.Token)
 }
+
+// A TokenSource refreshes an expiring access token, such as one issued to a GitHub app, when it is
+// needed, so that the user does not have to authorize a long-lived application again.
+func ExampleTokenSource() {
+	host, err := oauth.NewGitHubHost("https://github.com")
+	if err != nil {
+		panic(err)
+	}
+	flow := &oauth.Flow{
+		Host:     host,
+		ClientID: os.Getenv("OAUTH_CLIENT_ID"),
+	}
+
+	issued := time.Now()
+	accessToken, err := flow.DeviceFlow()
+	if err != nil {
+		panic(err)
+	}
+
+	src := oauth.NewTokenSource(oauth.NewTokenSet(accessToken, issued), host, flow.ClientID, "")
+	src.OnRefresh = func(tokens *oauth.TokenSet) error {
+		// Save the tokens for the next run of the application.
+		return nil
+	}
+
+	tokens, err := src.Token(context.Background())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Access token: %s\n", tokens.Token)
+}
+
+// With Storage set, a flow saves the token it obtains, and later runs of the application can load
+// it instead of asking the user to authorize the application again.
+func ExampleFlow_LoadToken() {
+	host, err := oauth.NewGitHubHost("https://github.com")
+	if err != nil {
+		panic(err)
+	}
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		panic(err)
+	}
+	flow := &oauth.Flow{
+		Host:     host,
+		ClientID: os.Getenv("OAUTH_CLIENT_ID"),
+		Storage:  oauth.NewTokenStorage("myapp", filepath.Join(configDir, "myapp", "tokens.json")),
+	}
+
+	tokens, err := flow.LoadToken()
+	if errors.Is(err, oauth.ErrTokenNotFound) {
+		// DetectFlow saves the token to flow.Storage.
+		if _, err = flow.DetectFlow(); err == nil {
+			tokens, err = flow.LoadToken()
+		}
+	}
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("Access token: %s\n", tokens.Token)
+}
@@ -0,0 +1,305 @@
+package qrcode
+
+// newCode returns a QR code of the given version with its function patterns drawn and the format
+// and version information reserved.
+func newCode(version int) *Code {
+	size := version*4 + 17
+	c := &Code{
+		Version:    version,
+		Size:       size,
+		modules:    make([][]bool, size),
+		isFunction: make([][]bool, size),
+	}
+	for i := 0; i < size; i++ {
+		c.modules[i] = make([]bool, size)
+		c.isFunction[i] = make([]bool, size)
+	}
+
+	for i := 0; i < size; i++ {
+		c.setFunction(6, i, i%2 == 0)
+		c.setFunction(i, 6, i%2 == 0)
+	}
+
+	c.drawFinderPattern(3, 3)
+	c.drawFinderPattern(size-4, 3)
+	c.drawFinderPattern(3, size-4)
+
+	positions := alignmentPatternPositions(version)
+	n := len(positions)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			// The corners with finder patterns have no alignment patterns.
+			if i == 0 && j == 0 || i == 0 && j == n-1 || i == n-1 && j == 0 {
+				continue
+			}
+			c.drawAlignmentPattern(positions[i], positions[j])
+		}
+	}
+
+	c.drawFormatBits(0)
+	c.drawVersion()
+	return c
+}
+
+func (c *Code) setFunction(x, y int, dark bool) {
+	c.modules[y][x] = dark
+	c.isFunction[y][x] = true
+}
+
+// drawFinderPattern draws a finder pattern and its separator centered at (x, y).
+func (c *Code) drawFinderPattern(x, y int) {
+	for dy := -4; dy <= 4; dy++ {
+		for dx := -4; dx <= 4; dx++ {
+			dist := max(abs(dx), abs(dy))
+			xx, yy := x+dx, y+dy
+			if 0 <= xx && xx < c.Size && 0 <= yy && yy < c.Size {
+				c.setFunction(xx, yy, dist != 2 && dist != 4)
+			}
+		}
+	}
+}
+
+// drawAlignmentPattern draws an alignment pattern centered at (x, y).
+func (c *Code) drawAlignmentPattern(x, y int) {
+	for dy := -2; dy <= 2; dy++ {
+		for dx := -2; dx <= 2; dx++ {
+			c.setFunction(x+dx, y+dy, max(abs(dx), abs(dy)) != 1)
+		}
+	}
+}
+
+// alignmentPatternPositions returns the coordinates of the centers of the alignment patterns, which
+// are the same horizontally and vertically.
+func alignmentPatternPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := (version*8 + numAlign*3 + 5) / (numAlign*4 - 4) * 2
+	result := make([]int, numAlign)
+	result[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		result[i] = pos
+	}
+	return result
+}
+
+// formatBits returns the 15 bits of format information for the mask, with their BCH error
+// correction, as they are drawn.
+func formatBits(mask int) int {
+	data := eclFormatBits<<3 | mask
+	rem := data
+	for i := 0; i < 10; i++ {
+		rem = (rem << 1) ^ ((rem >> 9) * 0x537)
+	}
+	return (data<<10 | rem) ^ 0x5412
+}
+
+// drawFormatBits draws both copies of the format information.
+func (c *Code) drawFormatBits(mask int) {
+	bits := formatBits(mask)
+
+	for i := 0; i <= 5; i++ {
+		c.setFunction(8, i, bit(bits, i))
+	}
+	c.setFunction(8, 7, bit(bits, 6))
+	c.setFunction(8, 8, bit(bits, 7))
+	c.setFunction(7, 8, bit(bits, 8))
+	for i := 9; i < 15; i++ {
+		c.setFunction(14-i, 8, bit(bits, i))
+	}
+
+	for i := 0; i < 8; i++ {
+		c.setFunction(c.Size-1-i, 8, bit(bits, i))
+	}
+	for i := 8; i < 15; i++ {
+		c.setFunction(8, c.Size-15+i, bit(bits, i))
+	}
+	c.setFunction(8, c.Size-8, true) // the dark module
+}
+
+// versionBits returns the 18 bits of version information, with their BCH error correction.
+func versionBits(version int) int {
+	rem := version
+	for i := 0; i < 12; i++ {
+		rem = (rem << 1) ^ ((rem >> 11) * 0x1F25)
+	}
+	return version<<12 | rem
+}
+
+// drawVersion draws both copies of the version information, which versions below 7 do not have.
+func (c *Code) drawVersion() {
+	if c.Version < 7 {
+		return
+	}
+	bits := versionBits(c.Version)
+	for i := 0; i < 18; i++ {
+		a := c.Size - 11 + i%3
+		b := i / 3
+		c.setFunction(a, b, bit(bits, i))
+		c.setFunction(b, a, bit(bits, i))
+	}
+}
+
+// drawCodewords draws the codewords in the zigzag order, in two-module-wide columns from the right,
+// alternately upwards and downwards, skipping the function patterns.
+func (c *Code) drawCodewords(data []byte) {
+	i := 0
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			// Skip the vertical timing pattern.
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				upward := (right+1)&2 == 0
+				y := vert
+				if upward {
+					y = c.Size - 1 - vert
+				}
+				if !c.isFunction[y][x] && i < len(data)*8 {
+					c.modules[y][x] = bit(int(data[i>>3]), 7-(i&7))
+					i++
+				}
+			}
+		}
+	}
+}
+
+// applyMask inverts the modules that are not part of function patterns where the mask pattern is
+// true. Applying a mask twice undoes it.
+func (c *Code) applyMask(mask int) {
+	for y := 0; y < c.Size; y++ {
+		for x := 0; x < c.Size; x++ {
+			if !c.isFunction[y][x] && maskPattern(mask, x, y) {
+				c.modules[y][x] = !c.modules[y][x]
+			}
+		}
+	}
+}
+
+func maskPattern(mask, x, y int) bool {
+	switch mask {
+	case 0:
+		return (x+y)%2 == 0
+	case 1:
+		return y%2 == 0
+	case 2:
+		return x%3 == 0
+	case 3:
+		return (x+y)%3 == 0
+	case 4:
+		return (x/3+y/2)%2 == 0
+	case 5:
+		return x*y%2+x*y%3 == 0
+	case 6:
+		return (x*y%2+x*y%3)%2 == 0
+	default:
+		return ((x+y)%2+x*y%3)%2 == 0
+	}
+}
+
+// Weights of the penalty rules for choosing a mask.
+const (
+	penaltyN1 = 3
+	penaltyN2 = 3
+	penaltyN3 = 40
+	penaltyN4 = 10
+)
+
+// penalty scores how hard c is to scan; the mask with the lowest score is used.
+func (c *Code) penalty() int {
+	result := 0
+	at := func(x, y int, vertical bool) bool {
+		if vertical {
+			return c.Dark(y, x)
+		}
+		return c.Dark(x, y)
+	}
+	for _, vertical := range []bool{false, true} {
+		for y := 0; y < c.Size; y++ {
+			// Runs of five or more modules of the same color.
+			run := 1
+			for x := 1; x < c.Size; x++ {
+				if at(x, y, vertical) == at(x-1, y, vertical) {
+					run++
+					continue
+				}
+				if run >= 5 {
+					result += penaltyN1 + run - 5
+				}
+				run = 1
+			}
+			if run >= 5 {
+				result += penaltyN1 + run - 5
+			}
+
+			// Patterns like a finder pattern, with four light modules on either side. Dark
+			// returns false outside the code, where the quiet zone is light.
+			finder := []bool{true, false, true, true, true, false, true}
+			for x := -4; x < c.Size+4; x++ {
+				match := true
+				for i, dark := range finder {
+					if at(x+i, y, vertical) != dark {
+						match = false
+						break
+					}
+				}
+				if !match {
+					continue
+				}
+				lightBefore, lightAfter := true, true
+				for i := 1; i <= 4; i++ {
+					lightBefore = lightBefore && !at(x-i, y, vertical)
+					lightAfter = lightAfter && !at(x+6+i, y, vertical)
+				}
+				if lightBefore || lightAfter {
+					result += penaltyN3
+				}
+			}
+		}
+	}
+
+	// 2x2 blocks of the same color.
+	for y := 0; y < c.Size-1; y++ {
+		for x := 0; x < c.Size-1; x++ {
+			dark := c.modules[y][x]
+			if dark == c.modules[y][x+1] && dark == c.modules[y+1][x] && dark == c.modules[y+1][x+1] {
+				result += penaltyN2
+			}
+		}
+	}
+
+	// The balance of dark and light modules, in steps of 5% away from 50%.
+	dark := 0
+	for _, row := range c.modules {
+		for _, m := range row {
+			if m {
+				dark++
+			}
+		}
+	}
+	total := c.Size * c.Size
+	k := (abs(dark*20-total*10)+total-1)/total - 1
+	result += k * penaltyN4
+	return result
+}
+
+func bit(x, i int) bool {
+	return (x>>uint(i))&1 != 0
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func max(x, y int) int {
+	if x > y {
+		return x
+	}
+	return y
+}
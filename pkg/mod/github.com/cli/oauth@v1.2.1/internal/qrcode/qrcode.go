@@ -0,0 +1,242 @@
+// Package qrcode encodes text as a QR code (ISO/IEC 18004) and renders it for a terminal.
+//
+// Only what is needed to display a URL is implemented: the text is encoded in byte mode with error
+// correction level L, in the smallest version that fits.
+package qrcode
+
+import (
+	"errors"
+	"strings"
+)
+
+// ErrTooLong is returned by Encode when the text does not fit in the largest QR code.
+var ErrTooLong = errors.New("qrcode: text too long")
+
+// Code is a QR code.
+type Code struct {
+	// The version, from 1 to 40, which determines the size.
+	Version int
+	// The number of modules on each side.
+	Size int
+
+	modules    [][]bool // dark modules, indexed by y and then x
+	isFunction [][]bool // modules of function patterns, which are not masked
+}
+
+// Error correction level L, in the tables below and in the format information.
+const eclFormatBits = 1
+
+// The number of error correction codewords per block, and the number of blocks, for error
+// correction level L, indexed by version.
+var (
+	eccCodewordsPerBlock = [41]int{-1,
+		7, 10, 15, 20, 26, 18, 20, 24, 30, 18,
+		20, 24, 26, 30, 22, 24, 28, 30, 28, 28,
+		28, 28, 30, 30, 26, 28, 30, 30, 30, 30,
+		30, 30, 30, 30, 30, 30, 30, 30, 30, 30}
+	numErrorCorrectionBlocks = [41]int{-1,
+		1, 1, 1, 1, 1, 2, 2, 2, 2, 4,
+		4, 4, 4, 4, 6, 6, 6, 6, 7, 8,
+		8, 9, 9, 10, 12, 12, 12, 13, 14, 15,
+		16, 17, 18, 19, 19, 20, 21, 22, 24, 25}
+)
+
+// Encode returns the QR code for text.
+func Encode(text string) (*Code, error) {
+	data := []byte(text)
+	version := 1
+	for ; ; version++ {
+		if version > 40 {
+			return nil, ErrTooLong
+		}
+		if 4+charCountBits(version)+8*len(data) <= numDataCodewords(version)*8 {
+			break
+		}
+	}
+
+	var bb bitBuffer
+	bb.append(0x4, 4) // byte mode
+	bb.append(len(data), charCountBits(version))
+	for _, b := range data {
+		bb.append(int(b), 8)
+	}
+	capacity := numDataCodewords(version) * 8
+	terminator := capacity - len(bb)
+	if terminator > 4 {
+		terminator = 4
+	}
+	bb.append(0, terminator)
+	bb.append(0, (8-len(bb)%8)%8)
+	for pad := 0xEC; len(bb) < capacity; pad ^= 0xEC ^ 0x11 {
+		bb.append(pad, 8)
+	}
+	codewords := make([]byte, len(bb)/8)
+	for i, bit := range bb {
+		if bit {
+			codewords[i>>3] |= 1 << (7 - uint(i&7))
+		}
+	}
+
+	c := newCode(version)
+	c.drawCodewords(addECCAndInterleave(codewords, version))
+
+	mask, minPenalty := 0, -1
+	for m := 0; m < 8; m++ {
+		c.applyMask(m)
+		c.drawFormatBits(m)
+		if p := c.penalty(); minPenalty < 0 || p < minPenalty {
+			mask, minPenalty = m, p
+		}
+		c.applyMask(m) // masking twice undoes it
+	}
+	c.applyMask(mask)
+	c.drawFormatBits(mask)
+	return c, nil
+}
+
+// Dark reports whether the module at (x, y) is dark. The top left module is at (0, 0).
+func (c *Code) Dark(x, y int) bool {
+	return x >= 0 && x < c.Size && y >= 0 && y < c.Size && c.modules[y][x]
+}
+
+// quietZone is the width in modules of the light border around a QR code.
+const quietZone = 4
+
+// Terminal renders c with Unicode block characters, two modules per character. The light modules
+// are drawn, so that the code can be scanned on a terminal with a dark background.
+func (c *Code) Terminal() string {
+	var sb strings.Builder
+	for y := -quietZone; y < c.Size+quietZone; y += 2 {
+		for x := -quietZone; x < c.Size+quietZone; x++ {
+			top := !c.Dark(x, y)
+			bottom := !c.Dark(x, y+1) && y+1 < c.Size+quietZone
+			switch {
+			case top && bottom:
+				sb.WriteString("█")
+			case top:
+				sb.WriteString("▀")
+			case bottom:
+				sb.WriteString("▄")
+			default:
+				sb.WriteString(" ")
+			}
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func charCountBits(version int) int {
+	if version < 10 {
+		return 8
+	}
+	return 16
+}
+
+// numRawDataModules returns the number of modules that hold codewords in a QR code of the given
+// version, that is, those that are not part of a function pattern or of the format or version
+// information.
+func numRawDataModules(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result
+}
+
+func numDataCodewords(version int) int {
+	return numRawDataModules(version)/8 - eccCodewordsPerBlock[version]*numErrorCorrectionBlocks[version]
+}
+
+// addECCAndInterleave splits data into blocks, appends the error correction codewords to each block,
+// and interleaves the blocks.
+func addECCAndInterleave(data []byte, version int) []byte {
+	numBlocks := numErrorCorrectionBlocks[version]
+	blockECCLen := eccCodewordsPerBlock[version]
+	rawCodewords := numRawDataModules(version) / 8
+	numShortBlocks := numBlocks - rawCodewords%numBlocks
+	shortBlockLen := rawCodewords / numBlocks
+
+	divisor := reedSolomonDivisor(blockECCLen)
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := range blocks {
+		n := shortBlockLen - blockECCLen
+		if i >= numShortBlocks {
+			n++
+		}
+		dat := data[k : k+n]
+		k += n
+		block := append([]byte(nil), dat...)
+		if i < numShortBlocks {
+			// A placeholder, so that all blocks have the same length; it is skipped below.
+			block = append(block, 0)
+		}
+		blocks[i] = append(block, reedSolomonRemainder(dat, divisor)...)
+	}
+
+	result := make([]byte, 0, rawCodewords)
+	for i := 0; i < len(blocks[0]); i++ {
+		for j, block := range blocks {
+			if i != shortBlockLen-blockECCLen || j >= numShortBlocks {
+				result = append(result, block[i])
+			}
+		}
+	}
+	return result
+}
+
+// reedSolomonDivisor returns the coefficients of the generator polynomial of the given degree,
+// from the highest power to the lowest, without the leading coefficient, which is 1.
+func reedSolomonDivisor(degree int) []byte {
+	result := make([]byte, degree)
+	result[degree-1] = 1
+	root := byte(1)
+	for i := 0; i < degree; i++ {
+		// Multiply the polynomial by (x - root).
+		for j := range result {
+			result[j] = gfMultiply(result[j], root)
+			if j+1 < len(result) {
+				result[j] ^= result[j+1]
+			}
+		}
+		root = gfMultiply(root, 0x02)
+	}
+	return result
+}
+
+// reedSolomonRemainder returns the error correction codewords for data.
+func reedSolomonRemainder(data, divisor []byte) []byte {
+	result := make([]byte, len(divisor))
+	for _, b := range data {
+		factor := b ^ result[0]
+		copy(result, result[1:])
+		result[len(result)-1] = 0
+		for i, coef := range divisor {
+			result[i] ^= gfMultiply(coef, factor)
+		}
+	}
+	return result
+}
+
+// gfMultiply returns the product of x and y in GF(2^8) modulo x^8 + x^4 + x^3 + x^2 + 1.
+func gfMultiply(x, y byte) byte {
+	z := 0
+	for i := 7; i >= 0; i-- {
+		z = (z << 1) ^ ((z >> 7) * 0x11D)
+		z ^= int((y>>uint(i))&1) * int(x)
+	}
+	return byte(z)
+}
+
+type bitBuffer []bool
+
+func (bb *bitBuffer) append(val, n int) {
+	for i := n - 1; i >= 0; i-- {
+		*bb = append(*bb, (val>>uint(i))&1 != 0)
+	}
+}
@@ -0,0 +1,233 @@
+package qrcode
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatBits(t *testing.T) {
+	// ISO/IEC 18004, table C.1, for error correction level L.
+	want := []int{
+		0x77C4, 0x72F3, 0x7DAA, 0x789D, 0x662F, 0x6318, 0x6C41, 0x6976,
+	}
+	for mask, w := range want {
+		if got := formatBits(mask); got != w {
+			t.Errorf("formatBits(%d) = %015b, want %015b", mask, got, w)
+		}
+	}
+}
+
+func TestVersionBits(t *testing.T) {
+	// ISO/IEC 18004, table D.1.
+	want := map[int]int{7: 0x07C94, 8: 0x085BC, 21: 0x15683, 40: 0x28C69}
+	for version, w := range want {
+		if got := versionBits(version); got != w {
+			t.Errorf("versionBits(%d) = %018b, want %018b", version, got, w)
+		}
+	}
+}
+
+func TestAlignmentPatternPositions(t *testing.T) {
+	// ISO/IEC 18004, table E.1.
+	want := map[int]string{
+		1:  "[]",
+		2:  "[6 18]",
+		7:  "[6 22 38]",
+		32: "[6 34 60 86 112 138]",
+		40: "[6 30 58 86 114 142 170]",
+	}
+	for version, w := range want {
+		if got := fmt.Sprint(alignmentPatternPositions(version)); got != w {
+			t.Errorf("alignmentPatternPositions(%d) = %s, want %s", version, got, w)
+		}
+	}
+}
+
+func TestDataCapacity(t *testing.T) {
+	// ISO/IEC 18004, table 7: the number of data codewords for error correction level L.
+	want := map[int]int{1: 19, 2: 34, 5: 108, 7: 156, 10: 274, 20: 861, 40: 2956}
+	for version, w := range want {
+		if got := numDataCodewords(version); got != w {
+			t.Errorf("numDataCodewords(%d) = %d, want %d", version, got, w)
+		}
+	}
+}
+
+func TestEncode(t *testing.T) {
+	tests := []struct {
+		text    string
+		version int
+	}{
+		{"", 1},
+		{"https://example.com", 2},
+		{"https://github.com/login/device?user_code=ABCD-1234", 3},
+		{"https://microsoft.com/devicelogin?otc=ABCD-EFGH&client=0123456789", 4},
+		{strings.Repeat("x", 154), 7},
+		{strings.Repeat("y", 2953), 40},
+	}
+	for _, tt := range tests {
+		c, err := Encode(tt.text)
+		if err != nil {
+			t.Fatalf("Encode(%d bytes): %v", len(tt.text), err)
+		}
+		if c.Version != tt.version {
+			t.Errorf("Encode(%d bytes) has version %d, want %d", len(tt.text), c.Version, tt.version)
+		}
+		if got := decode(t, c); got != tt.text {
+			t.Errorf("decoded %q, want %q", got, tt.text)
+		}
+	}
+
+	if _, err := Encode(strings.Repeat("z", 2954)); err != ErrTooLong {
+		t.Errorf("got error %v, want ErrTooLong", err)
+	}
+}
+
+func TestTerminal(t *testing.T) {
+	c, err := Encode("https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSuffix(c.Terminal(), "\n"), "\n")
+	width := c.Size + 2*quietZone
+	if len(lines) != (width+1)/2 {
+		t.Errorf("got %d lines, want %d", len(lines), (width+1)/2)
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n != width {
+			t.Errorf("line %d has %d characters, want %d", i, n, width)
+		}
+	}
+	// The top of the quiet zone is light, and the top left finder pattern starts on the third line.
+	if lines[0] != strings.Repeat("█", width) {
+		t.Errorf("first line is %q", lines[0])
+	}
+	if got := []rune(lines[2])[quietZone]; got != ' ' {
+		t.Errorf("finder pattern corner is %q, want a dark space", got)
+	}
+}
+
+// decode reads the text back from c without using the encoder's record of the function modules,
+// checking the format information and the error correction codewords along the way.
+func decode(t *testing.T, c *Code) string {
+	t.Helper()
+
+	// Read the first copy of the format information.
+	var bits int
+	for i := 0; i <= 5; i++ {
+		bits |= b2i(c.Dark(8, i)) << uint(i)
+	}
+	bits |= b2i(c.Dark(8, 7)) << 6
+	bits |= b2i(c.Dark(8, 8)) << 7
+	bits |= b2i(c.Dark(7, 8)) << 8
+	for i := 9; i < 15; i++ {
+		bits |= b2i(c.Dark(14-i, 8)) << uint(i)
+	}
+	mask := -1
+	for m := 0; m < 8; m++ {
+		if formatBits(m) == bits {
+			mask = m
+		}
+	}
+	if mask < 0 {
+		t.Fatalf("invalid format information %015b", bits)
+	}
+	if (bits^0x5412)>>13 != eclFormatBits {
+		t.Fatalf("format information is not for error correction level L")
+	}
+
+	// Read the codewords in zigzag order, unmasking them.
+	function := newCode(c.Version).isFunction
+	var codewords []byte
+	var cur, n int
+	for right := c.Size - 1; right >= 1; right -= 2 {
+		if right == 6 {
+			right = 5
+		}
+		for vert := 0; vert < c.Size; vert++ {
+			for j := 0; j < 2; j++ {
+				x := right - j
+				y := vert
+				if (right+1)&2 == 0 {
+					y = c.Size - 1 - vert
+				}
+				if function[y][x] {
+					continue
+				}
+				cur = cur<<1 | b2i(c.Dark(x, y) != maskPattern(mask, x, y))
+				if n++; n == 8 {
+					codewords = append(codewords, byte(cur))
+					cur, n = 0, 0
+				}
+			}
+		}
+	}
+	if want := numRawDataModules(c.Version) / 8; len(codewords) != want {
+		t.Fatalf("read %d codewords, want %d", len(codewords), want)
+	}
+
+	// Deinterleave the blocks and check that each is a multiple of the generator polynomial, whose
+	// roots are the first powers of 2.
+	numBlocks := numErrorCorrectionBlocks[c.Version]
+	eccLen := eccCodewordsPerBlock[c.Version]
+	numShort := numBlocks - len(codewords)%numBlocks
+	shortDataLen := len(codewords)/numBlocks - eccLen
+	blocks := make([][]byte, numBlocks)
+	k := 0
+	for i := 0; i < shortDataLen+1; i++ {
+		for j := range blocks {
+			if i < shortDataLen || j >= numShort {
+				blocks[j] = append(blocks[j], codewords[k])
+				k++
+			}
+		}
+	}
+	for i := 0; i < eccLen; i++ {
+		for j := range blocks {
+			blocks[j] = append(blocks[j], codewords[k])
+			k++
+		}
+	}
+	var data []byte
+	for j, block := range blocks {
+		root := byte(1)
+		for i := 0; i < eccLen; i++ {
+			var syndrome byte
+			for _, b := range block {
+				syndrome = gfMultiply(syndrome, root) ^ b
+			}
+			if syndrome != 0 {
+				t.Fatalf("block %d: syndrome %d is %d", j, i, syndrome)
+			}
+			root = gfMultiply(root, 2)
+		}
+		data = append(data, block[:len(block)-eccLen]...)
+	}
+
+	// Parse the byte mode segment.
+	bit := 0
+	read := func(n int) int {
+		v := 0
+		for i := 0; i < n; i++ {
+			v = v<<1 | int(data[bit>>3]>>uint(7-bit&7)&1)
+			bit++
+		}
+		return v
+	}
+	if mode := read(4); mode != 4 {
+		t.Fatalf("mode is %d, want byte mode", mode)
+	}
+	text := make([]byte, read(charCountBits(c.Version)))
+	for i := range text {
+		text[i] = byte(read(8))
+	}
+	return string(text)
+}
+
+func b2i(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// The exit status of the security command when the item is not in the keychain.
+const errSecItemNotFound = 44
+
+func keychainAvailable() bool {
+	_, err := exec.LookPath("security")
+	return err == nil
+}
+
+func keychainGet(service, key string) (string, error) {
+	out, err := exec.Command("security", "find-generic-password", "-s", service, "-a", key, "-w").Output()
+	if err != nil {
+		return "", securityError(err)
+	}
+	return strings.TrimSuffix(string(out), "\n"), nil
+}
+
+func keychainSet(service, key, secret string) error {
+	// The secret is passed on stdin rather than as an argument, which other users could read. The
+	// security command reads one command per line in interactive mode, and -X takes the secret in
+	// hexadecimal so that it does not need quoting.
+	cmd := exec.Command("security", "-i")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf("add-generic-password -U -s %s -a %s -X %s\n",
+		securityQuote(service), securityQuote(key), hex.EncodeToString([]byte(secret))))
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("saving to the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	// In interactive mode, the exit status does not report whether the command failed.
+	if stderr.Len() > 0 {
+		return fmt.Errorf("saving to the keychain: %s", strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func keychainDelete(service, key string) error {
+	err := exec.Command("security", "delete-generic-password", "-s", service, "-a", key).Run()
+	if err != nil {
+		return securityError(err)
+	}
+	return nil
+}
+
+func securityError(err error) error {
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == errSecItemNotFound {
+		return ErrTokenNotFound
+	}
+	return fmt.Errorf("reading the keychain: %w", err)
+}
+
+// securityQuote quotes s for the interactive mode of the security command.
+func securityQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
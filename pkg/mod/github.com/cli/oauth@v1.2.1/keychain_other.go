@@ -0,0 +1,20 @@
+//go:build !darwin && !windows && !linux && !freebsd && !openbsd && !netbsd && !dragonfly
+// +build !darwin,!windows,!linux,!freebsd,!openbsd,!netbsd,!dragonfly
+
+package oauth
+
+func keychainAvailable() bool {
+	return false
+}
+
+func keychainGet(service, key string) (string, error) {
+	return "", ErrKeychainUnsupported
+}
+
+func keychainSet(service, key, secret string) error {
+	return ErrKeychainUnsupported
+}
+
+func keychainDelete(service, key string) error {
+	return ErrKeychainUnsupported
+}
@@ -0,0 +1,60 @@
+//go:build linux || freebsd || openbsd || netbsd || dragonfly
+// +build linux freebsd openbsd netbsd dragonfly
+
+package oauth
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// The Secret Service is reached through the secret-tool command of libsecret, which needs a
+// D-Bus session.
+func keychainAvailable() bool {
+	if os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+		return false
+	}
+	_, err := exec.LookPath("secret-tool")
+	return err == nil
+}
+
+func keychainGet(service, key string) (string, error) {
+	var stdout, stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "lookup", "service", service, "account", key)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	// secret-tool exits with status 1 and prints nothing when the secret is not found.
+	if stdout.Len() == 0 && stderr.Len() == 0 {
+		return "", ErrTokenNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("reading the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func keychainSet(service, key, secret string) error {
+	cmd := exec.Command("secret-tool", "store", "--label="+service+" "+key, "service", service, "account", key)
+	// The secret is passed on stdin rather than as an argument, which other users could read.
+	cmd.Stdin = strings.NewReader(secret)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("saving to the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+func keychainDelete(service, key string) error {
+	var stderr bytes.Buffer
+	cmd := exec.Command("secret-tool", "clear", "service", service, "account", key)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil && stderr.Len() > 0 {
+		return fmt.Errorf("deleting from the keychain: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package oauth
+
+import (
+	"fmt"
+	"syscall"
+	"unsafe"
+)
+
+var (
+	advapi32        = syscall.NewLazyDLL("advapi32.dll")
+	procCredReadW   = advapi32.NewProc("CredReadW")
+	procCredWriteW  = advapi32.NewProc("CredWriteW")
+	procCredDeleteW = advapi32.NewProc("CredDeleteW")
+	procCredFree    = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	credMaxBlobSize         = 5 * 512
+	errorNotFound           = syscall.Errno(1168)
+)
+
+// credential is the CREDENTIALW structure of the Credential Manager.
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        syscall.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func keychainAvailable() bool {
+	return advapi32.Load() == nil
+}
+
+func credTarget(service, key string) (*uint16, error) {
+	return syscall.UTF16PtrFromString(service + ":" + key)
+}
+
+func keychainGet(service, key string) (string, error) {
+	target, err := credTarget(service, key)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	r, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if r == 0 {
+		if err == errorNotFound {
+			return "", ErrTokenNotFound
+		}
+		return "", fmt.Errorf("reading the credential manager: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+	n := int(cred.CredentialBlobSize)
+	if n == 0 {
+		return "", nil
+	}
+	return string((*[1 << 20]byte)(unsafe.Pointer(cred.CredentialBlob))[:n:n]), nil
+}
+
+func keychainSet(service, key, secret string) error {
+	target, err := credTarget(service, key)
+	if err != nil {
+		return err
+	}
+	user, err := syscall.UTF16PtrFromString(key)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	if len(blob) > credMaxBlobSize {
+		return fmt.Errorf("%w: %d bytes is more than the credential manager's limit of %d", ErrTokenTooLarge, len(blob), credMaxBlobSize)
+	}
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+	r, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(&cred)), 0)
+	if r == 0 {
+		return fmt.Errorf("saving to the credential manager: %w", err)
+	}
+	return nil
+}
+
+func keychainDelete(service, key string) error {
+	target, err := credTarget(service, key)
+	if err != nil {
+		return err
+	}
+	r, _, err := procCredDeleteW.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0)
+	if r == 0 {
+		if err == errorNotFound {
+			return ErrTokenNotFound
+		}
+		return fmt.Errorf("deleting from the credential manager: %w", err)
+	}
+	return nil
+}
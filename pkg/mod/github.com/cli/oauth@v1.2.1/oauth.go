@@ -3,12 +3,14 @@
 package oauth
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/cli/oauth/api"
 	"github.com/cli/oauth/device"
@@ -23,6 +25,8 @@ type Host struct {
 	DeviceCodeURL string
 	AuthorizeURL  string
 	TokenURL      string
+	// The token revocation endpoint (RFC 7009). Optional; GitHub does not have one.
+	RevocationURL string
 }
 
 // NewGitHubHost constructs a Host from the given URL to a GitHub instance.
@@ -70,35 +74,109 @@ type Flow struct {
 	Scopes []string
 	// OAuth audience to request from the user.
 	Audience string
+	// Resource indicators (RFC 8707): the absolute URIs of the APIs that the access token is for.
+	Resources []string
 	// OAuth application ID.
 	ClientID string
 	// OAuth application secret. Only applicable in web application flow.
 	ClientSecret string
-	// The localhost URI for web application flow callback, e.g. "http://127.0.0.1/callback".
+	// Authenticates the application with a JWT signed by its private key instead of ClientSecret,
+	// for servers that require it.
+	ClientAssertion *api.ClientAssertion
+	// The localhost URI for web application flow callback, e.g. "http://127.0.0.1/callback", or a
+	// URI with a custom scheme that the application is registered to open, e.g. "myapp://callback".
 	CallbackURI string
+	// The ports to try, in order, for the local server of web application flow, for OAuth apps that
+	// only allow redirect URIs with specific ports. Defaults to a random free port. The server
+	// listens on the IP address of CallbackURI, or on 127.0.0.1 if its host is not an IP address.
+	CallbackPorts []int
+	// Receive the web redirect to a CallbackURI with a custom scheme. Blocks until the operating
+	// system opens the application with the redirect, and returns its URI. Required for such a
+	// CallbackURI, and not used otherwise.
+	ReceiveRedirect func(context.Context) (string, error)
 
 	// Display a one-time code to the user. Receives the code and the browser URL as arguments. Defaults to printing the
 	// code to the user on Stdout with instructions to copy the code and to press Enter to continue in their browser.
 	DisplayCode func(string, string) error
-	// Open a web browser at a URL. Defaults to opening the default system browser.
+	// Also print a QR code of the verification URL, for the user to scan with a phone, when
+	// printing the one-time code. Only used when DisplayCode is nil.
+	ShowQRCode bool
+	// Open a web browser at a URL, for example with an application's own launcher, or with PrintURL
+	// to only print the URL. Defaults to opening the default system browser, and to printing the URL
+	// to Stdout in an SSH session without a display or if the browser cannot be opened.
 	BrowseURL func(string) error
 	// Render an HTML page to the user upon completion of web application flow. The default is to
 	// render a simple message that informs the user they can close the browser tab and return to the app.
 	WriteSuccessHTML func(io.Writer)
+	// Render an HTML page to the user if the server denied authorization in web application flow.
+	// The default is to render a simple message with the error.
+	WriteFailureHTML func(io.Writer, error)
+	// Redirect the browser to these URLs, such as pages on the application's website, instead of
+	// rendering the pages above when web application flow succeeds or fails.
+	SuccessURL string
+	FailureURL string
 
-	// The HTTP client to use for API POST requests. Defaults to http.DefaultClient.
+	// The HTTP client to use for API POST requests. Defaults to an api.RetryClient that limits each
+	// request to 30 seconds and retries transient failures up to 3 times. Since an authorization
+	// code can be used only once, the default client retries its exchange in the web application
+	// flow only if the request was never sent.
 	HTTPClient httpClient
 	// The stream to listen to keyboard input on. Defaults to os.Stdin.
 	Stdin io.Reader
 	// The stream to print UI messages to. Defaults to os.Stdout.
 	Stdout io.Writer
+
+	// Where to save the token sets that the flows obtain, so that LoadToken can return them in
+	// later runs of the application. If nil, the token sets are not saved.
+	Storage TokenStorage
+}
+
+// defaultHTTPClient is the client for flows without an HTTPClient.
+var defaultHTTPClient = &api.RetryClient{Timeout: 30 * time.Second, MaxRetries: 3}
+
+// defaultCodeHTTPClient is the client that exchanges authorization codes for flows without an
+// HTTPClient.
+var defaultCodeHTTPClient = &api.RetryClient{Timeout: 30 * time.Second, MaxRetries: 3, UnsentOnly: true}
+
+// client returns oa.HTTPClient, or defaultHTTPClient if it is not set.
+func (oa *Flow) client() httpClient {
+	if oa.HTTPClient != nil {
+		return oa.HTTPClient
+	}
+	return defaultHTTPClient
+}
+
+// codeClient returns oa.HTTPClient, or defaultCodeHTTPClient if it is not set.
+func (oa *Flow) codeClient() httpClient {
+	if oa.HTTPClient != nil {
+		return oa.HTTPClient
+	}
+	return defaultCodeHTTPClient
+}
+
+// host returns oa.Host, or the GitHub host for the deprecated oa.Hostname.
+func (oa *Flow) host() (*Host, error) {
+	if oa.Host != nil {
+		return oa.Host, nil
+	}
+	host, err := NewGitHubHost("https://" + oa.Hostname)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing the hostname '%s': %w", oa.Hostname, err)
+	}
+	return host, nil
 }
 
 // DetectFlow tries to perform Device flow first and falls back to Web application flow.
 func (oa *Flow) DetectFlow() (*api.AccessToken, error) {
-	accessToken, err := oa.DeviceFlow()
+	return oa.DetectFlowContext(context.Background())
+}
+
+// DetectFlowContext is like DetectFlow, but stops waiting for the user and cancels requests in
+// progress when ctx is done, for example when the user interrupts the program.
+func (oa *Flow) DetectFlowContext(ctx context.Context) (*api.AccessToken, error) {
+	accessToken, err := oa.DeviceFlowContext(ctx)
 	if errors.Is(err, device.ErrUnsupported) {
-		return oa.WebAppFlow()
+		return oa.WebAppFlowContext(ctx)
 	}
 	return accessToken, err
 }
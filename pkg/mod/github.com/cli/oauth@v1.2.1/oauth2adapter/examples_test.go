@@ -0,0 +1,40 @@
+package oauth2adapter_test
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/cli/oauth"
+	"github.com/cli/oauth/oauth2adapter"
+	"golang.org/x/oauth2"
+)
+
+// TokenSource adapts a TokenSource for HTTP clients and SDKs that use golang.org/x/oauth2.
+func ExampleTokenSource() {
+	host, err := oauth.NewGitHubHost("https://github.com")
+	if err != nil {
+		panic(err)
+	}
+	flow := &oauth.Flow{
+		Host:     host,
+		ClientID: os.Getenv("OAUTH_CLIENT_ID"),
+	}
+
+	issued := time.Now()
+	accessToken, err := flow.DeviceFlow()
+	if err != nil {
+		panic(err)
+	}
+
+	src := oauth.NewTokenSource(oauth.NewTokenSet(accessToken, issued), host, flow.ClientID, "")
+	httpClient := oauth2.NewClient(context.Background(), oauth2adapter.TokenSource(context.Background(), src))
+
+	resp, err := httpClient.Get("https://api.github.com/user")
+	if err != nil {
+		panic(err)
+	}
+	defer resp.Body.Close()
+	fmt.Println(resp.Status)
+}
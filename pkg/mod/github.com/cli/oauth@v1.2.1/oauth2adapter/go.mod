@@ -0,0 +1,15 @@
+module github.com/cli/oauth/oauth2adapter
+
+go 1.24.0
+
+require (
+	github.com/cli/oauth v1.2.1
+	golang.org/x/oauth2 v0.32.0
+)
+
+require (
+	github.com/cli/browser v1.0.0 // indirect
+	github.com/cli/safeexec v1.0.0 // indirect
+)
+
+replace github.com/cli/oauth => ../
@@ -0,0 +1,45 @@
+// Package oauth2adapter adapts the tokens of github.com/cli/oauth to golang.org/x/oauth2, so that
+// they can be used with oauth2.NewClient and with SDKs that accept an oauth2.TokenSource.
+//
+// It is a separate module so that github.com/cli/oauth does not depend on golang.org/x/oauth2.
+package oauth2adapter
+
+import (
+	"context"
+
+	"github.com/cli/oauth"
+	"golang.org/x/oauth2"
+)
+
+// Token returns ts as an oauth2.Token.
+func Token(ts *oauth.TokenSet) *oauth2.Token {
+	t := &oauth2.Token{
+		AccessToken:  ts.Token,
+		TokenType:    ts.Type,
+		RefreshToken: ts.RefreshToken,
+		Expiry:       ts.Expiry,
+	}
+	if ts.Scope != "" {
+		t = t.WithExtra(map[string]interface{}{"scope": ts.Scope})
+	}
+	return t
+}
+
+// TokenSource returns s as an oauth2.TokenSource. The tokens are refreshed with requests that are
+// canceled when ctx is done.
+func TokenSource(ctx context.Context, s *oauth.TokenSource) oauth2.TokenSource {
+	return &tokenSource{ctx: ctx, src: s}
+}
+
+type tokenSource struct {
+	ctx context.Context
+	src *oauth.TokenSource
+}
+
+func (s *tokenSource) Token() (*oauth2.Token, error) {
+	ts, err := s.src.Token(s.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return Token(ts), nil
+}
@@ -0,0 +1,63 @@
+package oauth2adapter
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cli/oauth"
+	"github.com/cli/oauth/api"
+	"golang.org/x/oauth2"
+)
+
+// refreshClient answers refresh requests with the access token "T2".
+type refreshClient struct{}
+
+func (c *refreshClient) PostForm(u string, params url.Values) (*http.Response, error) {
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	rec.WriteString(`{"access_token": "T2", "token_type": "bearer", "expires_in": 3600}`)
+	return rec.Result(), nil
+}
+
+func TestTokenSource(t *testing.T) {
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	tokens := &oauth.TokenSet{
+		AccessToken: &api.AccessToken{Token: "T1", RefreshToken: "R", Type: "bearer", Scope: "repo"},
+		Expiry:      time.Now().Add(-time.Minute),
+	}
+	src := oauth.NewTokenSource(tokens, &oauth.Host{TokenURL: "https://example.com/token"}, "CLIENT-ID", "")
+	src.HTTPClient = &refreshClient{}
+
+	ctx := context.WithValue(context.Background(), oauth2.HTTPClient, srv.Client())
+	resp, err := oauth2.NewClient(ctx, TokenSource(context.Background(), src)).Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if auth != "Bearer T2" {
+		t.Errorf("Authorization = %q, want the refreshed token", auth)
+	}
+}
+
+func TestToken(t *testing.T) {
+	tokens := &oauth.TokenSet{
+		AccessToken: &api.AccessToken{Token: "T1", RefreshToken: "R", Type: "bearer", Scope: "repo"},
+		Expiry:      time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	tok := Token(tokens)
+	if tok.AccessToken != "T1" || tok.RefreshToken != "R" || tok.Type() != "Bearer" || !tok.Expiry.Equal(tokens.Expiry) {
+		t.Errorf("Token() = %+v", tok)
+	}
+	if tok.Extra("scope") != "repo" {
+		t.Errorf("scope = %v, want repo", tok.Extra("scope"))
+	}
+}
@@ -5,10 +5,8 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net/http"
 	"os"
 
-	"github.com/cli/browser"
 	"github.com/cli/oauth/api"
 	"github.com/cli/oauth/device"
 )
@@ -16,10 +14,13 @@ import (
 // DeviceFlow captures the full OAuth Device flow, including prompting the user to copy a one-time
 // code and opening their web browser, and returns an access token upon completion.
 func (oa *Flow) DeviceFlow() (*api.AccessToken, error) {
-	httpClient := oa.HTTPClient
-	if httpClient == nil {
-		httpClient = http.DefaultClient
-	}
+	return oa.DeviceFlowContext(context.Background())
+}
+
+// DeviceFlowContext is like DeviceFlow, but stops waiting for the user and cancels requests in
+// progress when ctx is done.
+func (oa *Flow) DeviceFlowContext(ctx context.Context) (*api.AccessToken, error) {
+	httpClient := oa.client()
 
 	stdin := oa.Stdin
 	if stdin == nil {
@@ -30,25 +31,29 @@ func (oa *Flow) DeviceFlow() (*api.AccessToken, error) {
 		stdout = os.Stdout
 	}
 
-	host := oa.Host
-	if host == nil {
-		parsedHost, err := NewGitHubHost("https://" + oa.Hostname)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing the hostname '%s': %w", oa.Hostname, err)
-		}
-		host = parsedHost
+	host, err := oa.host()
+	if err != nil {
+		return nil, err
 	}
 
-	code, err := device.RequestCode(httpClient, host.DeviceCodeURL,
-		oa.ClientID, oa.Scopes, device.WithAudience(oa.Audience))
+	code, err := device.RequestCodeContext(ctx, httpClient, host.DeviceCodeURL,
+		oa.ClientID, oa.Scopes, device.WithAudience(oa.Audience), device.WithResources(oa.Resources...))
 	if err != nil {
 		return nil, err
 	}
 
 	if oa.DisplayCode == nil {
 		fmt.Fprintf(stdout, "First, copy your one-time code: %s\n", code.UserCode)
+		if oa.ShowQRCode {
+			// The QR code is a convenience; the user can still type the URL if it cannot be shown.
+			if qr, err := code.QRCode(); err == nil {
+				fmt.Fprintf(stdout, "To continue on another device, scan this QR code:\n%s", qr)
+			}
+		}
 		fmt.Fprint(stdout, "Then press [Enter] to continue in the web browser... ")
-		_ = waitForEnter(stdin)
+		if err := waitForEnter(ctx, stdin); err != nil && ctx.Err() != nil {
+			return nil, err
+		}
 	} else {
 		err := oa.DisplayCode(code.UserCode, code.VerificationURI)
 		if err != nil {
@@ -56,23 +61,34 @@ func (oa *Flow) DeviceFlow() (*api.AccessToken, error) {
 		}
 	}
 
-	browseURL := oa.BrowseURL
-	if browseURL == nil {
-		browseURL = browser.OpenURL
-	}
-
-	if err = browseURL(code.VerificationURI); err != nil {
+	if err = oa.browseURL()(code.VerificationURI); err != nil {
 		return nil, fmt.Errorf("error opening the web browser: %w", err)
 	}
 
-	return device.Wait(context.TODO(), httpClient, host.TokenURL, device.WaitOptions{
-		ClientID:   oa.ClientID,
-		DeviceCode: code,
+	issued := timeNow()
+	token, err := device.Wait(ctx, httpClient, host.TokenURL, device.WaitOptions{
+		ClientID:        oa.ClientID,
+		ClientAssertion: oa.ClientAssertion,
+		DeviceCode:      code,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return token, oa.saveToken(host, token, issued)
 }
 
-func waitForEnter(r io.Reader) error {
-	scanner := bufio.NewScanner(r)
-	scanner.Scan()
-	return scanner.Err()
+// waitForEnter reads a line from r, or returns the error of ctx if ctx is done first.
+func waitForEnter(ctx context.Context, r io.Reader) error {
+	done := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(r)
+		scanner.Scan()
+		done <- scanner.Err()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,23 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestWaitForEnter(t *testing.T) {
+	if err := waitForEnter(context.Background(), strings.NewReader("\n")); err != nil {
+		t.Errorf("waitForEnter() error = %v", err)
+	}
+
+	r, w := io.Pipe()
+	defer w.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := waitForEnter(ctx, r); !errors.Is(err, context.Canceled) {
+		t.Errorf("waitForEnter() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,149 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/cli/oauth/api"
+)
+
+// expiryDelta is how long before its expiry a token is considered expired, so that it is not
+// used for a request that reaches the server after the token has expired.
+const expiryDelta = time.Minute
+
+// timeNow is time.Now, replaceable in tests.
+var timeNow = time.Now
+
+// ErrNoRefreshToken is returned when a token needs to be refreshed but has no refresh token.
+var ErrNoRefreshToken = errors.New("oauth: token has no refresh token")
+
+// TokenSet is an access token together with the times at which it and its refresh token expire.
+type TokenSet struct {
+	*api.AccessToken
+	// When the access token expires. The zero time means that it does not expire.
+	Expiry time.Time
+	// When the refresh token expires. The zero time means that the server did not say.
+	RefreshExpiry time.Time
+}
+
+// NewTokenSet returns a TokenSet for an access token that the server issued at the given time.
+func NewTokenSet(token *api.AccessToken, issued time.Time) *TokenSet {
+	ts := &TokenSet{AccessToken: token}
+	if token.ExpiresIn > 0 {
+		ts.Expiry = issued.Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+	if token.RefreshTokenExpiresIn > 0 {
+		ts.RefreshExpiry = issued.Add(time.Duration(token.RefreshTokenExpiresIn) * time.Second)
+	}
+	return ts
+}
+
+// Valid reports whether the access token is present and is not about to expire.
+func (ts *TokenSet) Valid() bool {
+	if ts == nil || ts.AccessToken == nil || ts.Token == "" {
+		return false
+	}
+	return ts.Expiry.IsZero() || timeNow().Add(expiryDelta).Before(ts.Expiry)
+}
+
+// Refresh exchanges the refresh token of ts for a new token set at the token endpoint of host.
+// The client secret is required by GitHub OAuth apps, but not by GitHub apps.
+func (ts *TokenSet) Refresh(ctx context.Context, host *Host, clientID, clientSecret string) (*TokenSet, error) {
+	return refresh(ctx, http.DefaultClient, host, clientID, clientSecret, nil, ts)
+}
+
+func refresh(ctx context.Context, c httpClient, host *Host, clientID, clientSecret string, assertion *api.ClientAssertion, ts *TokenSet) (*TokenSet, error) {
+	if ts == nil || ts.AccessToken == nil || ts.RefreshToken == "" {
+		return nil, ErrNoRefreshToken
+	}
+
+	params := url.Values{
+		"client_id":     {clientID},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {ts.RefreshToken},
+	}
+	if clientSecret != "" {
+		params.Set("client_secret", clientSecret)
+	}
+	if assertion != nil {
+		if err := assertion.Apply(params, clientID, host.TokenURL); err != nil {
+			return nil, err
+		}
+	}
+	issued := timeNow()
+	resp, err := api.PostFormContext(ctx, c, host.TokenURL, params)
+	if err != nil {
+		return nil, err
+	}
+	token, err := resp.AccessToken()
+	if err != nil {
+		return nil, err
+	}
+	newTS := NewTokenSet(token, issued)
+	if token.RefreshToken == "" {
+		// The server may keep the refresh token without returning it again.
+		newTS.RefreshToken = ts.RefreshToken
+		newTS.RefreshExpiry = ts.RefreshExpiry
+	}
+	return newTS, nil
+}
+
+// TokenSource provides access tokens, refreshing them when they expire, so that a long-lived
+// application does not have to ask the user to authorize it again. It is safe for concurrent use.
+type TokenSource struct {
+	// Host configuration to refresh tokens with.
+	Host *Host
+	// OAuth application ID.
+	ClientID string
+	// OAuth application secret. Required by GitHub OAuth apps, but not by GitHub apps.
+	ClientSecret string
+	// Authenticates the application with a JWT signed by its private key instead of ClientSecret.
+	ClientAssertion *api.ClientAssertion
+	// The HTTP client to use for API POST requests. Defaults to http.DefaultClient.
+	HTTPClient httpClient
+	// Called with each refreshed token set, for example to save it for later runs of the
+	// application. An error from OnRefresh is returned by Token, along with the new token set.
+	OnRefresh func(*TokenSet) error
+
+	mu     sync.Mutex
+	tokens *TokenSet
+}
+
+// NewTokenSource returns a TokenSource that starts with the given token set.
+func NewTokenSource(tokens *TokenSet, host *Host, clientID, clientSecret string) *TokenSource {
+	return &TokenSource{
+		Host:         host,
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		tokens:       tokens,
+	}
+}
+
+// Token returns a valid token set, refreshing the current one first if it has expired.
+func (s *TokenSource) Token(ctx context.Context) (*TokenSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.tokens.Valid() {
+		return s.tokens, nil
+	}
+
+	httpClient := s.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	tokens, err := refresh(ctx, httpClient, s.Host, s.ClientID, s.ClientSecret, s.ClientAssertion, s.tokens)
+	if err != nil {
+		return nil, err
+	}
+	s.tokens = tokens
+	if s.OnRefresh != nil {
+		if err := s.OnRefresh(tokens); err != nil {
+			return tokens, err
+		}
+	}
+	return tokens, nil
+}
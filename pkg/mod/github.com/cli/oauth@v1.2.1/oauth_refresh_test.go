@@ -0,0 +1,160 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/cli/oauth/api"
+)
+
+func TestTokenSet_Valid(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	tests := []struct {
+		name string
+		ts   *TokenSet
+		want bool
+	}{
+		{"nil", nil, false},
+		{"no token", &TokenSet{AccessToken: &api.AccessToken{}}, false},
+		{"no expiry", &TokenSet{AccessToken: &api.AccessToken{Token: "T"}}, true},
+		{"not expired", &TokenSet{AccessToken: &api.AccessToken{Token: "T"}, Expiry: now.Add(time.Hour)}, true},
+		{"about to expire", &TokenSet{AccessToken: &api.AccessToken{Token: "T"}, Expiry: now.Add(30 * time.Second)}, false},
+		{"expired", &TokenSet{AccessToken: &api.AccessToken{Token: "T"}, Expiry: now.Add(-time.Hour)}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.ts.Valid(); got != tt.want {
+				t.Errorf("Valid() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenSet_Refresh(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		got = r.PostForm
+		w.Header().Set("Content-Type", "application/x-www-form-urlencoded")
+		if r.PostForm.Get("refresh_token") != "R1" {
+			fmt.Fprint(w, "error=bad_refresh_token&error_description=The+refresh+token+is+invalid")
+			return
+		}
+		fmt.Fprint(w, "access_token=T2&refresh_token=R2&token_type=bearer&expires_in=28800&refresh_token_expires_in=15811200")
+	}))
+	defer srv.Close()
+	host := &Host{TokenURL: srv.URL}
+
+	ts := &TokenSet{AccessToken: &api.AccessToken{Token: "T1", RefreshToken: "R1"}}
+	before := time.Now()
+	newTS, err := ts.Refresh(context.Background(), host, "CLIENT-ID", "SECRET")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"client_id":     {"CLIENT-ID"},
+		"client_secret": {"SECRET"},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {"R1"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("posted %v, want %v", got, want)
+	}
+	if newTS.Token != "T2" || newTS.RefreshToken != "R2" {
+		t.Errorf("got tokens %q and %q, want T2 and R2", newTS.Token, newTS.RefreshToken)
+	}
+	if e := newTS.Expiry; e.Before(before.Add(8*time.Hour)) || e.After(time.Now().Add(8*time.Hour)) {
+		t.Errorf("Expiry = %v, want 8 hours from now", e)
+	}
+	if newTS.RefreshExpiry.IsZero() {
+		t.Error("RefreshExpiry is zero")
+	}
+
+	_, err = newTS.Refresh(context.Background(), host, "CLIENT-ID", "SECRET")
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "bad_refresh_token" {
+		t.Errorf("got error %v, want bad_refresh_token", err)
+	}
+
+	noRefresh := &TokenSet{AccessToken: &api.AccessToken{Token: "T1"}}
+	if _, err := noRefresh.Refresh(context.Background(), host, "CLIENT-ID", ""); err != ErrNoRefreshToken {
+		t.Errorf("got error %v, want ErrNoRefreshToken", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := ts.Refresh(ctx, host, "CLIENT-ID", "SECRET"); !errors.Is(err, context.Canceled) {
+		t.Errorf("got error %v, want context.Canceled", err)
+	}
+}
+
+type refreshClient struct {
+	calls int
+}
+
+func (c *refreshClient) PostForm(u string, params url.Values) (*http.Response, error) {
+	c.calls++
+	rec := httptest.NewRecorder()
+	rec.Header().Set("Content-Type", "application/json")
+	// This server does not return the refresh token again.
+	fmt.Fprintf(rec, `{"access_token": "T%d", "token_type": "bearer", "expires_in": 3600}`, c.calls+1)
+	return rec.Result(), nil
+}
+
+func TestTokenSource(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	client := &refreshClient{}
+	tokens := &TokenSet{
+		AccessToken: &api.AccessToken{Token: "T1", RefreshToken: "R"},
+		Expiry:      now.Add(time.Hour),
+	}
+	src := NewTokenSource(tokens, &Host{TokenURL: "https://example.com/token"}, "CLIENT-ID", "")
+	src.HTTPClient = client
+	var saved []string
+	src.OnRefresh = func(ts *TokenSet) error {
+		saved = append(saved, ts.Token)
+		return nil
+	}
+
+	token := func() string {
+		t.Helper()
+		ts, err := src.Token(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return ts.Token
+	}
+	if got := token(); got != "T1" {
+		t.Errorf("got %s, want T1", got)
+	}
+	now = now.Add(2 * time.Hour)
+	if got := token(); got != "T2" {
+		t.Errorf("got %s after expiry, want T2", got)
+	}
+	if got := token(); got != "T2" {
+		t.Errorf("got %s, want T2 again", got)
+	}
+	if client.calls != 1 {
+		t.Errorf("refreshed %d times, want 1", client.calls)
+	}
+	if fmt.Sprint(saved) != "[T2]" {
+		t.Errorf("OnRefresh got %v, want [T2]", saved)
+	}
+	if src.tokens.RefreshToken != "R" {
+		t.Errorf("refresh token is %q, want the original R", src.tokens.RefreshToken)
+	}
+}
@@ -0,0 +1,45 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/cli/oauth/api"
+)
+
+// ErrRevocationUnsupported is returned by RevokeToken when the host has no revocation endpoint.
+var ErrRevocationUnsupported = errors.New("oauth: host does not support token revocation")
+
+// RevokeToken invalidates an access token or a refresh token at the revocation endpoint of host
+// (RFC 7009), so that logging out of an application also invalidates its token on the server.
+// Revoking a refresh token also revokes the access tokens issued with it, if the server supports
+// that. The client secret is optional: only pass it if the server requires it.
+//
+// Revoking a token that is already invalid succeeds.
+func RevokeToken(ctx context.Context, host *Host, token, clientID, clientSecret string) error {
+	return revoke(ctx, http.DefaultClient, host, token, clientID, clientSecret)
+}
+
+func revoke(ctx context.Context, c httpClient, host *Host, token, clientID, clientSecret string) error {
+	if host.RevocationURL == "" {
+		return ErrRevocationUnsupported
+	}
+
+	params := url.Values{
+		"client_id": {clientID},
+		"token":     {token},
+	}
+	if clientSecret != "" {
+		params.Set("client_secret", clientSecret)
+	}
+	resp, err := api.PostFormContext(ctx, c, host.RevocationURL, params)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return resp.Err()
+	}
+	return nil
+}
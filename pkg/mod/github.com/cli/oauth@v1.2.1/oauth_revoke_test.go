@@ -0,0 +1,52 @@
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/cli/oauth/api"
+)
+
+func TestRevokeToken(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+		got = r.PostForm
+		if r.PostForm.Get("token") == "UNSUPPORTED" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error": "unsupported_token_type"}`)
+		}
+	}))
+	defer srv.Close()
+	host := &Host{TokenURL: srv.URL + "/token", RevocationURL: srv.URL + "/revoke"}
+
+	if err := RevokeToken(context.Background(), host, "R1", "CLIENT-ID", "SECRET"); err != nil {
+		t.Fatal(err)
+	}
+	want := url.Values{
+		"client_id":     {"CLIENT-ID"},
+		"client_secret": {"SECRET"},
+		"token":         {"R1"},
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("posted %v, want %v", got, want)
+	}
+
+	err := RevokeToken(context.Background(), host, "UNSUPPORTED", "CLIENT-ID", "")
+	var apiErr *api.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "unsupported_token_type" {
+		t.Errorf("got error %v, want unsupported_token_type", err)
+	}
+
+	if err := RevokeToken(context.Background(), &Host{}, "R1", "CLIENT-ID", ""); err != ErrRevocationUnsupported {
+		t.Errorf("got error %v, want ErrRevocationUnsupported", err)
+	}
+}
@@ -0,0 +1,54 @@
+package oauth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/cli/oauth/api"
+)
+
+// GrantedScopes splits the scope of a token response into scopes. Most servers separate the scopes
+// with spaces, and GitHub with commas.
+func GrantedScopes(scope string) []string {
+	return strings.FieldsFunc(scope, func(r rune) bool {
+		return r == ' ' || r == ','
+	})
+}
+
+// MissingScopes returns the scopes in required that token does not grant, in the order of required.
+// A nil token grants no scopes. Scopes are compared exactly, so a scope that implies another, such
+// as GitHub's "repo" and "repo:status", does not count as granting it.
+func MissingScopes(token *api.AccessToken, required []string) []string {
+	granted := map[string]bool{}
+	if token != nil {
+		for _, s := range GrantedScopes(token.Scope) {
+			granted[s] = true
+		}
+	}
+	var missing []string
+	for _, s := range required {
+		if !granted[s] {
+			missing = append(missing, s)
+			granted[s] = true
+		}
+	}
+	return missing
+}
+
+// UpgradeScopes returns token if it grants all the required scopes, for example before a command
+// that needs more permissions than the application asked for when the user logged in. Otherwise
+// it runs DetectFlowContext again, requesting only the missing scopes instead of oa.Scopes, and
+// returns the new token.
+//
+// Servers that authorize incrementally keep the scopes that the user has already granted to the
+// application, so that the new token grants those as well. Use MissingScopes on the new token to
+// check that it grants all of the required scopes.
+func (oa *Flow) UpgradeScopes(ctx context.Context, token *api.AccessToken, required []string) (*api.AccessToken, error) {
+	missing := MissingScopes(token, required)
+	if len(missing) == 0 {
+		return token, nil
+	}
+	upgrade := *oa
+	upgrade.Scopes = missing
+	return upgrade.DetectFlowContext(ctx)
+}
@@ -0,0 +1,80 @@
+package oauth
+
+import (
+	"context"
+	"net/url"
+	"reflect"
+	"testing"
+
+	"github.com/cli/oauth/api"
+)
+
+func TestMissingScopes(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    *api.AccessToken
+		required []string
+		want     []string
+	}{
+		{name: "no token", required: []string{"repo"}, want: []string{"repo"}},
+		{name: "spaces", token: &api.AccessToken{Scope: "repo gist"}, required: []string{"gist", "repo"}},
+		{name: "commas", token: &api.AccessToken{Scope: "repo,read:org"}, required: []string{"read:org", "workflow"}, want: []string{"workflow"}},
+		{name: "duplicates", token: &api.AccessToken{}, required: []string{"gist", "gist"}, want: []string{"gist"}},
+		{name: "exact match", token: &api.AccessToken{Scope: "repo"}, required: []string{"repo:status"}, want: []string{"repo:status"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MissingScopes(tt.token, tt.required); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("MissingScopes() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFlow_UpgradeScopes(t *testing.T) {
+	var browsed *url.URL
+	flow := &Flow{
+		Host: &Host{
+			DeviceCodeURL: "https://github.com/login/device/code",
+			AuthorizeURL:  "https://github.com/login/oauth/authorize",
+			TokenURL:      "https://github.com/login/oauth/access_token",
+		},
+		ClientID:    "CLIENT-ID",
+		Scopes:      []string{"repo"},
+		CallbackURI: "myapp://callback",
+		// The device code response has no verification URI, so the flow falls back to web
+		// application flow.
+		HTTPClient: &tokenClient{},
+		BrowseURL: func(u string) error {
+			var err error
+			browsed, err = url.Parse(u)
+			return err
+		},
+		ReceiveRedirect: func(ctx context.Context) (string, error) {
+			return "myapp://callback?code=ABC-123&state=" + url.QueryEscape(browsed.Query().Get("state")), nil
+		},
+	}
+
+	token := &api.AccessToken{Token: "OLD", Scope: "repo"}
+	got, err := flow.UpgradeScopes(context.Background(), token, []string{"repo"})
+	if err != nil || got != token {
+		t.Fatalf("UpgradeScopes() = %v, %v, want the same token", got, err)
+	}
+	if browsed != nil {
+		t.Errorf("UpgradeScopes() opened %v, want no flow", browsed)
+	}
+
+	got, err = flow.UpgradeScopes(context.Background(), token, []string{"repo", "gist"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Token != "ATOKEN" {
+		t.Errorf("Token = %q, want ATOKEN", got.Token)
+	}
+	if scope := browsed.Query().Get("scope"); scope != "gist" {
+		t.Errorf("requested scope %q, want gist", scope)
+	}
+	if len(flow.Scopes) != 1 || flow.Scopes[0] != "repo" {
+		t.Errorf("flow.Scopes = %q, want it unchanged", flow.Scopes)
+	}
+}
@@ -3,9 +3,9 @@ package oauth
 import (
 	"context"
 	"fmt"
-	"net/http"
+	"net"
+	"net/url"
 
-	"github.com/cli/browser"
 	"github.com/cli/oauth/api"
 	"github.com/cli/oauth/webapp"
 )
@@ -13,17 +13,40 @@ import (
 // WebAppFlow starts a local HTTP server, opens the web browser to initiate the OAuth Web application
 // flow, blocks until the user completes authorization and is redirected back, and returns the access token.
 func (oa *Flow) WebAppFlow() (*api.AccessToken, error) {
-	host := oa.Host
+	return oa.WebAppFlowContext(context.Background())
+}
 
-	if host == nil {
-		parsedHost, err := NewGitHubHost("https://" + oa.Hostname)
-		if err != nil {
-			return nil, fmt.Errorf("error parsing the hostname '%s': %w", oa.Hostname, err)
+// WebAppFlowContext is like WebAppFlow, but stops the local server and cancels requests in
+// progress when ctx is done.
+func (oa *Flow) WebAppFlowContext(ctx context.Context) (*api.AccessToken, error) {
+	host, err := oa.host()
+	if err != nil {
+		return nil, err
+	}
+
+	customScheme := false
+	var serverOpts webapp.ServerOptions
+	if u, err := url.Parse(oa.CallbackURI); err == nil {
+		customScheme = u.Scheme != "" && u.Scheme != "http" && u.Scheme != "https"
+		// Listen on the IP address of the callback URI, such as "[::1]".
+		if ip := net.ParseIP(u.Hostname()); ip != nil {
+			serverOpts.BindAddress = ip.String()
 		}
-		host = parsedHost
+	}
+	serverOpts.Ports = oa.CallbackPorts
+	serverOpts.WriteFailureHTML = oa.WriteFailureHTML
+	serverOpts.SuccessURL = oa.SuccessURL
+	serverOpts.FailureURL = oa.FailureURL
+	if customScheme && oa.ReceiveRedirect == nil {
+		return nil, fmt.Errorf("callback URI %q has a custom scheme, but ReceiveRedirect is not set", oa.CallbackURI)
 	}
 
-	flow, err := webapp.InitFlow()
+	var flow *webapp.Flow
+	if customScheme {
+		flow, err = webapp.InitCustomSchemeFlow(oa.CallbackURI)
+	} else {
+		flow, err = webapp.InitFlowWithOptions(serverOpts)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -33,6 +56,7 @@ func (oa *Flow) WebAppFlow() (*api.AccessToken, error) {
 		RedirectURI: oa.CallbackURI,
 		Scopes:      oa.Scopes,
 		Audience:    oa.Audience,
+		Resources:   oa.Resources,
 		AllowSignup: true,
 	}
 	browserURL, err := flow.BrowserURL(host.AuthorizeURL, params)
@@ -40,26 +64,36 @@ func (oa *Flow) WebAppFlow() (*api.AccessToken, error) {
 		return nil, err
 	}
 
-	go func() {
-		_ = flow.StartServer(oa.WriteSuccessHTML)
-	}()
-
-	browseURL := oa.BrowseURL
-	if browseURL == nil {
-		browseURL = browser.OpenURL
+	if !customScheme {
+		go func() {
+			_ = flow.StartServerContext(ctx, oa.WriteSuccessHTML)
+		}()
 	}
 
-	err = browseURL(browserURL)
+	err = oa.browseURL()(browserURL)
 	if err != nil {
 		return nil, fmt.Errorf("error opening the web browser: %w", err)
 	}
 
-	httpClient := oa.HTTPClient
-	if httpClient == nil {
-		httpClient = http.DefaultClient
+	if customScheme {
+		redirectURL, err := oa.ReceiveRedirect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := flow.HandleRedirect(redirectURL); err != nil {
+			return nil, err
+		}
 	}
 
-	return flow.Wait(context.TODO(), httpClient, host.TokenURL, webapp.WaitOptions{
-		ClientSecret: oa.ClientSecret,
+	httpClient := oa.codeClient()
+
+	issued := timeNow()
+	token, err := flow.Wait(ctx, httpClient, host.TokenURL, webapp.WaitOptions{
+		ClientSecret:    oa.ClientSecret,
+		ClientAssertion: oa.ClientAssertion,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return token, oa.saveToken(host, token, issued)
 }
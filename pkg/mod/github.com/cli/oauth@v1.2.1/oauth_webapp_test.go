@@ -0,0 +1,111 @@
+package oauth
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type tokenClient struct {
+	params url.Values
+}
+
+func (c *tokenClient) PostForm(u string, params url.Values) (*http.Response, error) {
+	c.params = params
+	return &http.Response{
+		Body:       ioutil.NopCloser(bytes.NewBufferString("access_token=ATOKEN&token_type=bearer")),
+		Header:     http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+		StatusCode: 200,
+	}, nil
+}
+
+func TestWebAppFlowCustomScheme(t *testing.T) {
+	client := &tokenClient{}
+	var browsed *url.URL
+	flow := &Flow{
+		Host:        &Host{AuthorizeURL: "https://github.com/login/oauth/authorize", TokenURL: "https://github.com/login/oauth/access_token"},
+		ClientID:    "CLIENT-ID",
+		CallbackURI: "myapp://callback",
+		HTTPClient:  client,
+		BrowseURL: func(u string) error {
+			var err error
+			browsed, err = url.Parse(u)
+			return err
+		},
+		ReceiveRedirect: func(ctx context.Context) (string, error) {
+			// The operating system opens the application with the redirect.
+			return "myapp://callback?code=ABC-123&state=" + url.QueryEscape(browsed.Query().Get("state")), nil
+		},
+	}
+
+	token, err := flow.WebAppFlowContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "ATOKEN" {
+		t.Errorf("Token = %q", token.Token)
+	}
+	if got := client.params.Get("code"); got != "ABC-123" {
+		t.Errorf("posted code = %q, want ABC-123", got)
+	}
+
+	flow.ReceiveRedirect = nil
+	if _, err := flow.WebAppFlowContext(context.Background()); err == nil {
+		t.Error("WebAppFlowContext without ReceiveRedirect succeeded, want error")
+	}
+}
+
+func TestWebAppFlowCallbackPorts(t *testing.T) {
+	l, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	port := l.Addr().(*net.TCPAddr).Port
+	l.Close()
+
+	client := &tokenClient{}
+	var redirectURI string
+	flow := &Flow{
+		Host:          &Host{AuthorizeURL: "https://github.com/login/oauth/authorize", TokenURL: "https://github.com/login/oauth/access_token"},
+		ClientID:      "CLIENT-ID",
+		CallbackURI:   "http://127.0.0.1/callback",
+		CallbackPorts: []int{port},
+		HTTPClient:    client,
+		BrowseURL: func(u string) error {
+			browsed, err := url.Parse(u)
+			if err != nil {
+				return err
+			}
+			redirectURI = browsed.Query().Get("redirect_uri")
+			// The browser follows the redirect once the user authorizes the app.
+			go func() {
+				for i := 0; i < 50; i++ {
+					resp, err := http.Get(redirectURI + "?code=ABC-123&state=" + url.QueryEscape(browsed.Query().Get("state")))
+					if err == nil {
+						resp.Body.Close()
+						return
+					}
+					time.Sleep(10 * time.Millisecond)
+				}
+			}()
+			return nil
+		},
+	}
+
+	token, err := flow.WebAppFlowContext(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if token.Token != "ATOKEN" {
+		t.Errorf("Token = %q", token.Token)
+	}
+	if want := fmt.Sprintf("http://127.0.0.1:%d/callback", port); redirectURI != want {
+		t.Errorf("redirect_uri = %q, want %q", redirectURI, want)
+	}
+}
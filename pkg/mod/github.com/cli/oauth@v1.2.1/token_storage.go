@@ -0,0 +1,255 @@
+package oauth
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/cli/oauth/api"
+)
+
+// ErrTokenNotFound is returned by TokenStorage.Load when no token set is stored under the key.
+var ErrTokenNotFound = errors.New("oauth: token not found")
+
+// ErrKeychainUnsupported is returned by KeychainStorage when the operating system keychain is not
+// available.
+var ErrKeychainUnsupported = errors.New("oauth: keychain not available")
+
+// ErrTokenTooLarge is returned by KeychainStorage.Save when the token set is larger than the
+// keychain can store. The Credential Manager on Windows stores at most 2560 bytes per credential.
+var ErrTokenTooLarge = errors.New("oauth: token set too large for the keychain")
+
+// TokenStorage persists token sets between runs of an application. Token sets are stored under
+// keys chosen by the caller; Flow uses the client ID and the token URL of the host.
+type TokenStorage interface {
+	// Load returns the token set stored under key, or ErrTokenNotFound.
+	Load(key string) (*TokenSet, error)
+	// Save stores ts under key, replacing any token set stored under key.
+	Save(key string, ts *TokenSet) error
+	// Delete removes the token set stored under key. It is not an error if there is none.
+	Delete(key string) error
+}
+
+// NewTokenStorage returns a TokenStorage that stores token sets in the operating system keychain
+// for service, if it is available, and otherwise in a FileStorage at fallbackPath. Token sets that
+// are too large for the keychain are stored in the FileStorage too.
+func NewTokenStorage(service, fallbackPath string) TokenStorage {
+	file := &FileStorage{Path: fallbackPath}
+	if keychainAvailable() {
+		return &fallbackStorage{keychain: &KeychainStorage{Service: service}, file: file}
+	}
+	return file
+}
+
+// fallbackStorage stores token sets in keychain, and those that are too large for it in file.
+type fallbackStorage struct {
+	keychain TokenStorage
+	file     TokenStorage
+}
+
+func (s *fallbackStorage) Load(key string) (*TokenSet, error) {
+	ts, err := s.keychain.Load(key)
+	if err == ErrTokenNotFound {
+		return s.file.Load(key)
+	}
+	return ts, err
+}
+
+func (s *fallbackStorage) Save(key string, ts *TokenSet) error {
+	err := s.keychain.Save(key, ts)
+	if errors.Is(err, ErrTokenTooLarge) {
+		// Load looks in the keychain first, so remove any older token set from it.
+		if err := s.keychain.Delete(key); err != nil {
+			return err
+		}
+		return s.file.Save(key, ts)
+	}
+	if err != nil {
+		return err
+	}
+	// Don't leave an older token set, which may hold a refresh token, in the file. It would not be
+	// loaded, so a failure to remove it is not an error.
+	_ = s.file.Delete(key)
+	return nil
+}
+
+func (s *fallbackStorage) Delete(key string) error {
+	if err := s.keychain.Delete(key); err != nil {
+		return err
+	}
+	return s.file.Delete(key)
+}
+
+// KeychainStorage stores token sets in the keychain of the operating system: the Keychain on macOS,
+// the Credential Manager on Windows, and the Secret Service (libsecret) on Linux and other Unix
+// systems, where the secret-tool command must be installed.
+type KeychainStorage struct {
+	// The name of the application, under which the token sets are stored.
+	Service string
+}
+
+// Load returns the token set stored under key.
+func (s *KeychainStorage) Load(key string) (*TokenSet, error) {
+	secret, err := keychainGet(s.Service, key)
+	if err != nil {
+		return nil, err
+	}
+	return unmarshalTokenSet([]byte(secret))
+}
+
+// Save stores ts under key.
+func (s *KeychainStorage) Save(key string, ts *TokenSet) error {
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+	return keychainSet(s.Service, key, string(data))
+}
+
+// Delete removes the token set stored under key.
+func (s *KeychainStorage) Delete(key string) error {
+	err := keychainDelete(s.Service, key)
+	if errors.Is(err, ErrTokenNotFound) {
+		return nil
+	}
+	return err
+}
+
+// FileStorage stores token sets in a JSON file that only the user can read. Use it where no
+// keychain is available: the tokens are not encrypted.
+type FileStorage struct {
+	// The path of the file. Its directory is created if it does not exist.
+	Path string
+
+	mu sync.Mutex
+}
+
+// Load returns the token set stored under key.
+func (s *FileStorage) Load(key string) (*TokenSet, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	data, ok := tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return unmarshalTokenSet(data)
+}
+
+// Save stores ts under key.
+func (s *FileStorage) Save(key string, ts *TokenSet) error {
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = data
+	return s.write(tokens)
+}
+
+// Delete removes the token set stored under key.
+func (s *FileStorage) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return s.write(tokens)
+}
+
+// read returns the contents of the file, which is empty if the file does not exist.
+func (s *FileStorage) read() (map[string]json.RawMessage, error) {
+	tokens := map[string]json.RawMessage{}
+	data, err := ioutil.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &tokens); err != nil {
+		return nil, fmt.Errorf("reading tokens from %s: %w", s.Path, err)
+	}
+	return tokens, nil
+}
+
+// write replaces the contents of the file, so that readers never see a partial file.
+func (s *FileStorage) write(tokens map[string]json.RawMessage) error {
+	data, err := json.MarshalIndent(tokens, "", "  ")
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(s.Path)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+	f, err := ioutil.TempFile(dir, filepath.Base(s.Path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	// TempFile creates the file with mode 0600.
+	return os.Rename(f.Name(), s.Path)
+}
+
+func unmarshalTokenSet(data []byte) (*TokenSet, error) {
+	var ts TokenSet
+	if err := json.Unmarshal(data, &ts); err != nil {
+		return nil, fmt.Errorf("reading stored token: %w", err)
+	}
+	return &ts, nil
+}
+
+// tokenKey returns the key under which Flow stores the token sets for host.
+func (oa *Flow) tokenKey(host *Host) string {
+	return oa.ClientID + "@" + host.TokenURL
+}
+
+// saveToken stores token, which the server issued at the given time, in oa.Storage, if it is set.
+func (oa *Flow) saveToken(host *Host, token *api.AccessToken, issued time.Time) error {
+	if oa.Storage == nil {
+		return nil
+	}
+	if err := oa.Storage.Save(oa.tokenKey(host), NewTokenSet(token, issued)); err != nil {
+		return fmt.Errorf("saving token: %w", err)
+	}
+	return nil
+}
+
+// LoadToken returns the token set that a flow stored in oa.Storage, or ErrTokenNotFound. The token
+// set may have expired; use a TokenSource to refresh it.
+func (oa *Flow) LoadToken() (*TokenSet, error) {
+	if oa.Storage == nil {
+		return nil, ErrTokenNotFound
+	}
+	host, err := oa.host()
+	if err != nil {
+		return nil, err
+	}
+	return oa.Storage.Load(oa.tokenKey(host))
+}
@@ -0,0 +1,190 @@
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cli/oauth/api"
+)
+
+func TestFileStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config", "tokens.json")
+	s := &FileStorage{Path: path}
+
+	if _, err := s.Load("a"); err != ErrTokenNotFound {
+		t.Fatalf("Load() from a missing file: got error %v, want ErrTokenNotFound", err)
+	}
+	expiry := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	if err := s.Save("a", &TokenSet{AccessToken: &api.AccessToken{Token: "T1", RefreshToken: "R1"}, Expiry: expiry}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save("b", &TokenSet{AccessToken: &api.AccessToken{Token: "T2"}}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new FileStorage reads what the first one saved.
+	s = &FileStorage{Path: path}
+	ts, err := s.Load("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Token != "T1" || ts.RefreshToken != "R1" || !ts.Expiry.Equal(expiry) {
+		t.Errorf("Load(a) = %+v, %+v", ts.AccessToken, ts)
+	}
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if mode := info.Mode().Perm(); mode != 0600 {
+			t.Errorf("file mode is %v, want 0600", mode)
+		}
+	}
+
+	if err := s.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("a"); err != nil {
+		t.Errorf("Delete() of a missing key: %v", err)
+	}
+	if _, err := s.Load("a"); err != ErrTokenNotFound {
+		t.Errorf("Load(a) after Delete: got error %v, want ErrTokenNotFound", err)
+	}
+	if ts, err := s.Load("b"); err != nil || ts.Token != "T2" {
+		t.Errorf("Load(b) = %v, %v", ts, err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("b"); err == nil {
+		t.Error("Load() from a corrupt file: got no error")
+	}
+}
+
+// limitedStorage is a TokenStorage in memory that, like the Windows Credential Manager, rejects
+// token sets larger than limit bytes.
+type limitedStorage struct {
+	limit  int
+	tokens map[string]*TokenSet
+}
+
+func (s *limitedStorage) Load(key string) (*TokenSet, error) {
+	ts, ok := s.tokens[key]
+	if !ok {
+		return nil, ErrTokenNotFound
+	}
+	return ts, nil
+}
+
+func (s *limitedStorage) Save(key string, ts *TokenSet) error {
+	data, err := json.Marshal(ts)
+	if err != nil {
+		return err
+	}
+	if len(data) > s.limit {
+		return fmt.Errorf("%w: %d bytes", ErrTokenTooLarge, len(data))
+	}
+	s.tokens[key] = ts
+	return nil
+}
+
+func (s *limitedStorage) Delete(key string) error {
+	delete(s.tokens, key)
+	return nil
+}
+
+func TestFallbackStorage(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	keychain := &limitedStorage{limit: 2560, tokens: map[string]*TokenSet{}}
+	file := &FileStorage{Path: filepath.Join(dir, "tokens.json")}
+	s := &fallbackStorage{keychain: keychain, file: file}
+
+	short := &TokenSet{AccessToken: &api.AccessToken{Token: "short"}}
+	if err := s.Save("a", short); err != nil {
+		t.Fatal(err)
+	}
+	// A long token, such as a JWT with many claims, goes to the file.
+	long := &TokenSet{AccessToken: &api.AccessToken{Token: strings.Repeat("x", 4000), RefreshToken: "R"}}
+	if err := s.Save("a", long); err != nil {
+		t.Fatalf("Save() of a long token: %v", err)
+	}
+	if _, err := keychain.Load("a"); err != ErrTokenNotFound {
+		t.Errorf("keychain Load(a) after saving a long token: got error %v, want ErrTokenNotFound", err)
+	}
+	if ts, err := s.Load("a"); err != nil || ts.Token != long.Token {
+		t.Errorf("Load(a) = %v, %v; want the long token", ts, err)
+	}
+
+	// A short token goes back to the keychain, and the file no longer holds the long one.
+	if err := s.Save("a", short); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := file.Load("a"); err != ErrTokenNotFound {
+		t.Errorf("file Load(a) after saving a short token: got error %v, want ErrTokenNotFound", err)
+	}
+	if ts, err := s.Load("a"); err != nil || ts.Token != "short" {
+		t.Errorf("Load(a) = %v, %v; want the short token", ts, err)
+	}
+
+	if err := s.Save("b", long); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Delete("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Load("b"); err != ErrTokenNotFound {
+		t.Errorf("Load(b) after Delete: got error %v, want ErrTokenNotFound", err)
+	}
+}
+
+func TestFlow_LoadToken(t *testing.T) {
+	dir, err := ioutil.TempDir("", "oauth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	flow := &Flow{
+		Host:     &Host{TokenURL: "https://example.com/token"},
+		ClientID: "CLIENT-ID",
+	}
+	if _, err := flow.LoadToken(); err != ErrTokenNotFound {
+		t.Errorf("LoadToken() without Storage: got error %v, want ErrTokenNotFound", err)
+	}
+
+	flow.Storage = &FileStorage{Path: filepath.Join(dir, "tokens.json")}
+	issued := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	token := &api.AccessToken{Token: "T", ExpiresIn: 3600}
+	if err := flow.saveToken(flow.Host, token, issued); err != nil {
+		t.Fatal(err)
+	}
+	ts, err := flow.LoadToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ts.Token != "T" || !ts.Expiry.Equal(issued.Add(time.Hour)) {
+		t.Errorf("LoadToken() = %+v, %+v", ts.AccessToken, ts)
+	}
+
+	other := &Flow{Host: flow.Host, ClientID: "OTHER-ID", Storage: flow.Storage}
+	if _, err := other.LoadToken(); err != ErrTokenNotFound {
+		t.Errorf("LoadToken() for another client: got error %v, want ErrTokenNotFound", err)
+	}
+}
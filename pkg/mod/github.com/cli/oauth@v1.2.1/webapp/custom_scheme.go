@@ -0,0 +1,106 @@
+package webapp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/cli/oauth/api"
+)
+
+// redirectResult is the authorization response passed to HandleRedirect.
+type redirectResult struct {
+	code CodeResponse
+	err  error
+}
+
+// InitCustomSchemeFlow creates a new Flow that receives the web redirect at redirectURI, a URI with
+// a custom scheme such as "myapp://callback" that the application is registered with the operating
+// system to open. Instead of a local server receiving the redirect, the application passes the URI
+// that it was opened with to HandleRedirect. The flow uses PKCE (RFC 7636) with the S256 method,
+// which native applications need to protect the authorization code from other applications that
+// claim the same scheme.
+func InitCustomSchemeFlow(redirectURI string) (*Flow, error) {
+	ru, err := url.Parse(redirectURI)
+	if err != nil {
+		return nil, err
+	}
+	if ru.Scheme == "" || ru.Scheme == "http" || ru.Scheme == "https" {
+		return nil, fmt.Errorf("redirect URI %q does not have a custom scheme", redirectURI)
+	}
+
+	state, _ := randomString(20)
+
+	codeVerifier, err := generateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Flow{
+		state:        state,
+		codeVerifier: codeVerifier,
+		redirectURI:  redirectURI,
+		redirects:    make(chan redirectResult, 1),
+	}, nil
+}
+
+// HandleRedirect completes a flow created by InitCustomSchemeFlow with the URI of the web redirect,
+// which the application receives from the operating system, so that Wait can exchange the code in
+// it for an access token. It returns an error if the URI does not match the redirect URI of the
+// flow, or if the server denied authorization, in which case Wait returns the error as well.
+func (flow *Flow) HandleRedirect(redirectURL string) error {
+	if flow.redirects == nil {
+		return errors.New("the flow does not have a custom scheme redirect")
+	}
+	u, err := url.Parse(redirectURL)
+	if err != nil {
+		return err
+	}
+	ru, _ := url.Parse(flow.redirectURI) // checked by InitCustomSchemeFlow
+	if !strings.EqualFold(u.Scheme, ru.Scheme) || u.Host != ru.Host || u.Path != ru.Path {
+		return fmt.Errorf("redirect %q does not match the redirect URI %q", redirectURL, flow.redirectURI)
+	}
+
+	params := u.Query()
+	var result redirectResult
+	if err := authorizationError(params); err != nil {
+		result.err = err
+	} else {
+		result.code = CodeResponse{
+			Code:  params.Get("code"),
+			State: params.Get("state"),
+		}
+	}
+
+	select {
+	case flow.redirects <- result:
+		return result.err
+	default:
+		return errors.New("the flow has already received a redirect")
+	}
+}
+
+// authorizationError returns the error in the query parameters of a web redirect, which wraps an
+// *api.Error, or nil if the server did not deny authorization.
+func authorizationError(params url.Values) error {
+	code := params.Get("error")
+	if code == "" {
+		return nil
+	}
+	return fmt.Errorf("authorization failed: %w", &api.Error{
+		Code:        code,
+		Description: params.Get("error_description"),
+	})
+}
+
+// waitForRedirect blocks until HandleRedirect is called or ctx is done.
+func (flow *Flow) waitForRedirect(ctx context.Context) (CodeResponse, error) {
+	select {
+	case <-ctx.Done():
+		return CodeResponse{}, ctx.Err()
+	case r := <-flow.redirects:
+		return r.code, r.err
+	}
+}
@@ -0,0 +1,105 @@
+package webapp
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/cli/oauth/api"
+)
+
+func TestInitCustomSchemeFlow(t *testing.T) {
+	for _, uri := range []string{"http://127.0.0.1/callback", "https://example.com/callback", "callback", "%"} {
+		if _, err := InitCustomSchemeFlow(uri); err == nil {
+			t.Errorf("InitCustomSchemeFlow(%q) succeeded, want error", uri)
+		}
+	}
+}
+
+func TestFlow_CustomScheme(t *testing.T) {
+	flow, err := InitCustomSchemeFlow("myapp://callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	browserURL, err := flow.BrowserURL("https://github.com/authorize", BrowserParams{
+		ClientID:    "CLIENT-ID",
+		Scopes:      []string{"repo"},
+		AllowSignup: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bu, err := url.Parse(browserURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := bu.Query()
+	if got := q.Get("redirect_uri"); got != "myapp://callback" {
+		t.Errorf("redirect_uri = %q, want myapp://callback", got)
+	}
+	if q.Get("code_challenge") == "" || q.Get("code_challenge_method") != "S256" {
+		t.Errorf("browser URL %q has no PKCE challenge", browserURL)
+	}
+	if _, err := flow.BrowserURL("https://github.com/authorize", BrowserParams{RedirectURI: "other://callback"}); err == nil {
+		t.Error("BrowserURL with a different redirect URI succeeded, want error")
+	}
+	if err := flow.StartServer(nil); err == nil {
+		t.Error("StartServer succeeded, want error")
+	}
+
+	if err := flow.HandleRedirect("myapp://elsewhere?code=ABC-123"); err == nil {
+		t.Error("HandleRedirect with a different URI succeeded, want error")
+	}
+	if err := flow.HandleRedirect("myapp://callback?code=ABC-123&state=" + url.QueryEscape(flow.state)); err != nil {
+		t.Fatalf("HandleRedirect() error: %v", err)
+	}
+	if err := flow.HandleRedirect("myapp://callback?code=ABC-123"); err == nil {
+		t.Error("second HandleRedirect succeeded, want error")
+	}
+
+	client := &apiClient{
+		stubs: []apiStub{
+			{
+				body:        "access_token=ATOKEN&token_type=bearer&scope=repo",
+				status:      200,
+				contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			},
+		},
+	}
+	token, err := flow.Wait(context.Background(), client, "https://github.com/access_token", WaitOptions{})
+	if err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if token.Token != "ATOKEN" {
+		t.Errorf("Token = %q", token.Token)
+	}
+	params := client.calls[0].params
+	if got := params.Get("redirect_uri"); got != "myapp://callback" {
+		t.Errorf("posted redirect_uri = %q, want myapp://callback", got)
+	}
+	if got := params.Get("code_verifier"); got != flow.codeVerifier {
+		t.Errorf("posted code_verifier = %q, want %q", got, flow.codeVerifier)
+	}
+}
+
+func TestFlow_CustomSchemeDenied(t *testing.T) {
+	flow, err := InitCustomSchemeFlow("myapp://callback")
+	if err != nil {
+		t.Fatal(err)
+	}
+	const redirect = "myapp://callback?error=access_denied&error_description=The+user+has+denied+your+application+access."
+	if err := flow.HandleRedirect(redirect); err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("HandleRedirect() error = %v, want access_denied", err)
+	}
+	_, err = flow.Wait(context.Background(), &apiClient{}, "https://github.com/access_token", WaitOptions{})
+	if !errors.Is(err, api.ErrAccessDenied) {
+		t.Errorf("Wait() error = %v, want api.ErrAccessDenied", err)
+	}
+	var apiError *api.Error
+	if !errors.As(err, &apiError) || apiError.Description != "The user has denied your application access." {
+		t.Errorf("Wait() error = %#v, want the error description", err)
+	}
+}
@@ -3,9 +3,11 @@ package webapp
 import (
 	"context"
 	"fmt"
+	"html"
 	"io"
 	"net"
 	"net/http"
+	"strconv"
 )
 
 // CodeResponse represents the code received by the local server's callback handler.
@@ -14,24 +16,94 @@ type CodeResponse struct {
 	State string
 }
 
-// bindLocalServer initializes a LocalServer that will listen on a randomly available TCP port.
-func bindLocalServer() (*localServer, error) {
-	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+// ServerOptions configures the local server that receives the web redirect, for OAuth apps that only
+// allow redirect URIs with a specific address, port or path.
+type ServerOptions struct {
+	// The IP address to listen on. Defaults to "127.0.0.1", or to "::1" if IPv6 is set.
+	BindAddress string
+	// Listen on an IPv6 address.
+	IPv6 bool
+	// The ports to try, in order; the first that is free is used. Defaults to a random free port.
+	Ports []int
+	// The path at which to receive the redirect, such as "/callback". Defaults to the path of the
+	// redirect URI passed to BrowserURL.
+	CallbackPath string
+
+	// Render an HTML page to the user if the server denied authorization, for example by executing
+	// an html/template with the error. Defaults to a simple message with the error.
+	WriteFailureHTML func(w io.Writer, err error)
+	// The URL to redirect the browser to once the local server has received the authorization code,
+	// such as a page on the application's website, instead of rendering the success page.
+	SuccessURL string
+	// The URL to redirect the browser to if the server denied authorization, instead of rendering
+	// the failure page.
+	FailureURL string
+}
+
+// PortRange returns the ports from first to last, for ServerOptions.Ports.
+func PortRange(first, last int) []int {
+	var ports []int
+	for port := first; port <= last; port++ {
+		ports = append(ports, port)
+	}
+	return ports
+}
+
+// bindLocalServer initializes a LocalServer that will listen on the first available TCP port of
+// opts.Ports, or on a random one.
+func bindLocalServer(opts ServerOptions) (*localServer, error) {
+	network := "tcp4"
+	host := "127.0.0.1"
+	if opts.IPv6 {
+		network = "tcp6"
+		host = "::1"
+	}
+	if opts.BindAddress != "" {
+		host = opts.BindAddress
+		if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+			network = "tcp6"
+		}
+	}
+
+	ports := opts.Ports
+	if len(ports) == 0 {
+		ports = []int{0}
+	}
+	var listener net.Listener
+	var err error
+	for _, port := range ports {
+		listener, err = net.Listen(network, net.JoinHostPort(host, strconv.Itoa(port)))
+		if err == nil {
+			break
+		}
+	}
 	if err != nil {
+		if len(ports) > 1 {
+			return nil, fmt.Errorf("no free port among %d ports: %w", len(ports), err)
+		}
 		return nil, err
 	}
 
 	return &localServer{
-		listener:   listener,
-		resultChan: make(chan CodeResponse, 1),
+		CallbackPath:     opts.CallbackPath,
+		WriteFailureHTML: opts.WriteFailureHTML,
+		SuccessURL:       opts.SuccessURL,
+		FailureURL:       opts.FailureURL,
+		listener:         listener,
+		resultChan:       make(chan CodeResponse, 1),
+		errChan:          make(chan error, 1),
 	}, nil
 }
 
 type localServer struct {
 	CallbackPath     string
 	WriteSuccessHTML func(w io.Writer)
+	WriteFailureHTML func(w io.Writer, err error)
+	SuccessURL       string
+	FailureURL       string
 
 	resultChan chan (CodeResponse)
+	errChan    chan error
 	listener   net.Listener
 }
 
@@ -53,6 +125,8 @@ func (s *localServer) WaitForCode(ctx context.Context) (CodeResponse, error) {
 		return CodeResponse{}, ctx.Err()
 	case code := <-s.resultChan:
 		return code, nil
+	case err := <-s.errChan:
+		return CodeResponse{}, err
 	}
 }
 
@@ -67,11 +141,30 @@ func (s *localServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	}()
 
 	params := r.URL.Query()
+	if err := authorizationError(params); err != nil {
+		s.errChan <- err
+		if s.FailureURL != "" {
+			http.Redirect(w, r, s.FailureURL, http.StatusFound)
+			return
+		}
+		w.Header().Add("content-type", "text/html")
+		if s.WriteFailureHTML != nil {
+			s.WriteFailureHTML(w, err)
+		} else {
+			defaultFailureHTML(w, err)
+		}
+		return
+	}
+
 	s.resultChan <- CodeResponse{
 		Code:  params.Get("code"),
 		State: params.Get("state"),
 	}
 
+	if s.SuccessURL != "" {
+		http.Redirect(w, r, s.SuccessURL, http.StatusFound)
+		return
+	}
 	w.Header().Add("content-type", "text/html")
 	if s.WriteSuccessHTML != nil {
 		s.WriteSuccessHTML(w)
@@ -83,3 +176,7 @@ func (s *localServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 func defaultSuccessHTML(w io.Writer) {
 	fmt.Fprintf(w, "<p>You may now close this page and return to the client app.</p>")
 }
+
+func defaultFailureHTML(w io.Writer, err error) {
+	fmt.Fprintf(w, "<p>%s</p><p>You may now close this page and return to the client app.</p>", html.EscapeString(err.Error()))
+}
@@ -2,9 +2,13 @@ package webapp
 
 import (
 	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"net"
 	"net/http"
+	"strings"
 	"testing"
 )
 
@@ -92,3 +96,107 @@ func Test_localServer_ServeHTTP(t *testing.T) {
 		t.Error("expected listener to be closed")
 	}
 }
+
+func Test_localServer_ServeHTTP_failure(t *testing.T) {
+	s := &localServer{
+		resultChan: make(chan CodeResponse, 1),
+		errChan:    make(chan error, 1),
+		listener:   &fakeListener{},
+		WriteFailureHTML: func(w io.Writer, err error) {
+			fmt.Fprintf(w, "<h1>Oops</h1><p>%s</p>", err)
+		},
+	}
+
+	w := &responseWriter{}
+	req, _ := http.NewRequest("GET", "http://127.0.0.1:12345/?error=access_denied&state=xyz", nil)
+	s.ServeHTTP(w, req)
+
+	_, err := s.WaitForCode(context.Background())
+	if err == nil || !strings.Contains(err.Error(), "access_denied") {
+		t.Errorf("WaitForCode() error = %v, want access_denied", err)
+	}
+	if got := w.written.String(); got != "<h1>Oops</h1><p>authorization failed: access_denied</p>" {
+		t.Errorf("written: %q", got)
+	}
+}
+
+func Test_localServer_ServeHTTP_redirect(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{name: "success", query: "code=ABC-123&state=xyz", want: "https://example.com/welcome"},
+		{name: "failure", query: "error=access_denied", want: "https://example.com/denied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &localServer{
+				SuccessURL: "https://example.com/welcome",
+				FailureURL: "https://example.com/denied",
+				resultChan: make(chan CodeResponse, 1),
+				errChan:    make(chan error, 1),
+				listener:   &fakeListener{},
+			}
+			w := &responseWriter{}
+			req, _ := http.NewRequest("GET", "http://127.0.0.1:12345/?"+tt.query, nil)
+			s.ServeHTTP(w, req)
+
+			if w.status != http.StatusFound {
+				t.Errorf("status = %d", w.status)
+			}
+			if got := w.Header().Get("Location"); got != tt.want {
+				t.Errorf("Location = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBindLocalServer(t *testing.T) {
+	busy, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+	free, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	freePort := free.Addr().(*net.TCPAddr).Port
+	free.Close()
+	busyPort := busy.Addr().(*net.TCPAddr).Port
+
+	s, err := bindLocalServer(ServerOptions{Ports: []int{busyPort, freePort}, CallbackPath: "/cb"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if s.Port() != freePort || s.CallbackPath != "/cb" {
+		t.Errorf("got port %d and path %q, want %d and /cb", s.Port(), s.CallbackPath, freePort)
+	}
+
+	if _, err := bindLocalServer(ServerOptions{Ports: []int{busyPort, busyPort}}); err == nil {
+		t.Error("bindLocalServer() with busy ports: got no error")
+	}
+
+	if got := fmt.Sprint(PortRange(8080, 8083)); got != "[8080 8081 8082 8083]" {
+		t.Errorf("PortRange() = %s", got)
+	}
+}
+
+func TestBindLocalServerIPv6(t *testing.T) {
+	l, err := net.Listen("tcp6", "[::1]:0")
+	if err != nil {
+		t.Skipf("IPv6 is not available: %v", err)
+	}
+	l.Close()
+
+	s, err := bindLocalServer(ServerOptions{IPv6: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if ip := s.listener.Addr().(*net.TCPAddr).IP; !ip.Equal(net.IPv6loopback) {
+		t.Errorf("listening on %v, want ::1", ip)
+	}
+}
@@ -1,16 +1,21 @@
 // Package webapp implements the OAuth Web Application authorization flow for client applications by
-// starting a server at localhost to receive the web redirect after the user has authorized the application.
+// starting a server at localhost to receive the web redirect after the user has authorized the application,
+// or by having the application receive a redirect to a URI with a custom scheme.
 package webapp
 
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/hex"
 	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/cli/oauth/api"
@@ -22,23 +27,45 @@ type httpClient interface {
 
 // Flow holds the state for the steps of OAuth Web Application flow.
 type Flow struct {
-	server   *localServer
+	server   *localServer // nil for a custom scheme redirect
 	clientID string
 	state    string
+	// The PKCE code verifier (RFC 7636). If empty, PKCE is not used.
+	codeVerifier string
+	// The audience and resource indicators from BrowserURL, which are sent again with the token
+	// request.
+	audience  string
+	resources []string
+
+	// For a custom scheme redirect, the redirect URI and the results of HandleRedirect.
+	redirectURI string
+	redirects   chan redirectResult
 }
 
-// InitFlow creates a new Flow instance by detecting a locally available port number.
+// InitFlow creates a new Flow instance by detecting a locally available port number. The flow
+// uses PKCE (RFC 7636) with the S256 method to protect the authorization code.
 func InitFlow() (*Flow, error) {
-	server, err := bindLocalServer()
+	return InitFlowWithOptions(ServerOptions{})
+}
+
+// InitFlowWithOptions is like InitFlow, but binds the local server as configured by opts.
+func InitFlowWithOptions(opts ServerOptions) (*Flow, error) {
+	server, err := bindLocalServer(opts)
 	if err != nil {
 		return nil, err
 	}
 
 	state, _ := randomString(20)
 
+	codeVerifier, err := generateCodeVerifier()
+	if err != nil {
+		return nil, err
+	}
+
 	return &Flow{
-		server: server,
-		state:  state,
+		server:       server,
+		state:        state,
+		codeVerifier: codeVerifier,
 	}, nil
 }
 
@@ -48,6 +75,8 @@ type BrowserParams struct {
 	RedirectURI string
 	Scopes      []string
 	Audience    string
+	// Resource indicators (RFC 8707): the absolute URIs of the APIs that the access token is for.
+	Resources   []string
 	LoginHandle string
 	AllowSignup bool
 }
@@ -55,24 +84,56 @@ type BrowserParams struct {
 // BrowserURL appends GET query parameters to baseURL and returns the url that the user should
 // navigate to in their web browser.
 func (flow *Flow) BrowserURL(baseURL string, params BrowserParams) (string, error) {
-	ru, err := url.Parse(params.RedirectURI)
-	if err != nil {
-		return "", err
-	}
+	var ru *url.URL
+	if flow.server == nil {
+		if params.RedirectURI != "" && params.RedirectURI != flow.redirectURI {
+			return "", fmt.Errorf("redirect URI %q differs from the URI %q of the flow", params.RedirectURI, flow.redirectURI)
+		}
+		ru, _ = url.Parse(flow.redirectURI) // checked by InitCustomSchemeFlow
+	} else if params.RedirectURI == "" {
+		// Redirect to the address that the server listens on.
+		ru = &url.URL{
+			Scheme: "http",
+			Host:   flow.server.listener.Addr().String(),
+			Path:   flow.server.CallbackPath,
+		}
+	} else {
+		var err error
+		ru, err = url.Parse(params.RedirectURI)
+		if err != nil {
+			return "", err
+		}
 
-	ru.Host = fmt.Sprintf("%s:%d", ru.Hostname(), flow.server.Port())
-	flow.server.CallbackPath = ru.Path
+		ru.Host = net.JoinHostPort(ru.Hostname(), strconv.Itoa(flow.server.Port()))
+		switch cp := flow.server.CallbackPath; {
+		case cp == "":
+			flow.server.CallbackPath = ru.Path
+		case ru.Path == "":
+			ru.Path = cp
+		case ru.Path != cp:
+			return "", fmt.Errorf("redirect URI path %q differs from the callback path %q of the server", ru.Path, cp)
+		}
+	}
 	flow.clientID = params.ClientID
+	flow.audience = params.Audience
+	flow.resources = params.Resources
 
 	q := url.Values{}
 	q.Set("client_id", params.ClientID)
 	q.Set("redirect_uri", ru.String())
 	q.Set("scope", strings.Join(params.Scopes, " "))
 	q.Set("state", flow.state)
+	if flow.codeVerifier != "" {
+		q.Set("code_challenge", codeChallenge(flow.codeVerifier))
+		q.Set("code_challenge_method", "S256")
+	}
 
 	if params.Audience != "" {
 		q.Set("audience", params.Audience)
 	}
+	for _, r := range params.Resources {
+		q.Add("resource", r)
+	}
 	if params.LoginHandle != "" {
 		q.Set("login", params.LoginHandle)
 	}
@@ -86,8 +147,29 @@ func (flow *Flow) BrowserURL(baseURL string, params BrowserParams) (string, erro
 // StartServer starts the localhost server and blocks until it has received the web redirect. The
 // writeSuccess function can be used to render a HTML page to the user upon completion.
 func (flow *Flow) StartServer(writeSuccess func(io.Writer)) error {
+	return flow.StartServerContext(context.Background(), writeSuccess)
+}
+
+// StartServerContext is like StartServer, but also stops the server when ctx is done.
+func (flow *Flow) StartServerContext(ctx context.Context, writeSuccess func(io.Writer)) error {
+	if flow.server == nil {
+		return errors.New("a flow with a custom scheme redirect has no local server")
+	}
 	flow.server.WriteSuccessHTML = writeSuccess
-	return flow.server.Serve()
+	stop := make(chan struct{})
+	defer close(stop)
+	go func() {
+		select {
+		case <-ctx.Done():
+			_ = flow.server.Close()
+		case <-stop:
+		}
+	}()
+	err := flow.server.Serve()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	return err
 }
 
 // AccessToken blocks until the browser flow has completed and returns the access token.
@@ -101,11 +183,22 @@ func (flow *Flow) AccessToken(c httpClient, tokenURL, clientSecret string) (*api
 type WaitOptions struct {
 	// ClientSecret is the app client secret value.
 	ClientSecret string
+	// ClientAssertion authenticates the app with a signed JWT instead of ClientSecret. Optional.
+	ClientAssertion *api.ClientAssertion
+	// Resources, if set, requests an access token for only these of the resources in
+	// BrowserParams (RFC 8707, section 2.2). By default, the token is for all of them.
+	Resources []string
 }
 
 // Wait blocks until the browser flow has completed and returns the access token.
 func (flow *Flow) Wait(ctx context.Context, c httpClient, tokenURL string, opts WaitOptions) (*api.AccessToken, error) {
-	code, err := flow.server.WaitForCode(ctx)
+	var code CodeResponse
+	var err error
+	if flow.server != nil {
+		code, err = flow.server.WaitForCode(ctx)
+	} else {
+		code, err = flow.waitForRedirect(ctx)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -113,13 +206,36 @@ func (flow *Flow) Wait(ctx context.Context, c httpClient, tokenURL string, opts
 		return nil, errors.New("state mismatch")
 	}
 
-	resp, err := api.PostForm(c, tokenURL,
-		url.Values{
-			"client_id":     {flow.clientID},
-			"client_secret": {opts.ClientSecret},
-			"code":          {code.Code},
-			"state":         {flow.state},
-		})
+	params := url.Values{
+		"client_id":     {flow.clientID},
+		"client_secret": {opts.ClientSecret},
+		"code":          {code.Code},
+		"state":         {flow.state},
+	}
+	if flow.codeVerifier != "" {
+		params.Set("code_verifier", flow.codeVerifier)
+	}
+	if flow.audience != "" {
+		params.Set("audience", flow.audience)
+	}
+	resources := flow.resources
+	if len(opts.Resources) > 0 {
+		resources = opts.Resources
+	}
+	for _, r := range resources {
+		params.Add("resource", r)
+	}
+	if flow.server == nil {
+		// The redirect URI was sent with the authorization request, so it must be sent again
+		// (RFC 6749, section 4.1.3).
+		params.Set("redirect_uri", flow.redirectURI)
+	}
+	if opts.ClientAssertion != nil {
+		if err := opts.ClientAssertion.Apply(params, flow.clientID, tokenURL); err != nil {
+			return nil, err
+		}
+	}
+	resp, err := api.PostFormContext(ctx, c, tokenURL, params)
 	if err != nil {
 		return nil, err
 	}
@@ -127,6 +243,22 @@ func (flow *Flow) Wait(ctx context.Context, c httpClient, tokenURL string, opts
 	return resp.AccessToken()
 }
 
+// generateCodeVerifier returns a random PKCE code verifier of 43 characters, the minimum length
+// allowed by RFC 7636, encoding 256 bits of randomness.
+func generateCodeVerifier() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// codeChallenge returns the S256 PKCE code challenge for verifier.
+func codeChallenge(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
+
 func randomString(length int) (string, error) {
 	b := make([]byte, length/2)
 	_, err := rand.Read(b)
@@ -3,11 +3,17 @@ package webapp
 import (
 	"bytes"
 	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
 	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
 	"testing"
+
+	"github.com/cli/oauth/api"
 )
 
 func TestFlow_BrowserURL(t *testing.T) {
@@ -18,9 +24,10 @@ func TestFlow_BrowserURL(t *testing.T) {
 	}
 
 	type fields struct {
-		server   *localServer
-		clientID string
-		state    string
+		server       *localServer
+		clientID     string
+		state        string
+		codeVerifier string
 	}
 	type args struct {
 		baseURL string
@@ -69,13 +76,50 @@ func TestFlow_BrowserURL(t *testing.T) {
 			},
 			want: "https://github.com/authorize?audience=https%3A%2F%2Fapi.github.com&client_id=CLIENT-ID&redirect_uri=http%3A%2F%2F127.0.0.1%3A12345%2Fhello&scope=repo+read%3Aorg&state=xy%2Fz",
 		},
+		{
+			name: "happy path with resources",
+			fields: fields{
+				server: server,
+				state:  "xy/z",
+			},
+			args: args{
+				baseURL: "https://example.com/authorize",
+				params: BrowserParams{
+					ClientID:    "CLIENT-ID",
+					RedirectURI: "http://127.0.0.1/hello",
+					Scopes:      []string{"read"},
+					AllowSignup: true,
+					Resources:   []string{"https://api.example.com/", "https://files.example.com/"},
+				},
+			},
+			want: "https://example.com/authorize?client_id=CLIENT-ID&redirect_uri=http%3A%2F%2F127.0.0.1%3A12345%2Fhello&resource=https%3A%2F%2Fapi.example.com%2F&resource=https%3A%2F%2Ffiles.example.com%2F&scope=read&state=xy%2Fz",
+		},
+		{
+			name: "happy path with PKCE",
+			fields: fields{
+				server:       server,
+				state:        "xy/z",
+				codeVerifier: "dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk",
+			},
+			args: args{
+				baseURL: "https://github.com/authorize",
+				params: BrowserParams{
+					ClientID:    "CLIENT-ID",
+					RedirectURI: "http://127.0.0.1/hello",
+					Scopes:      []string{"repo"},
+					AllowSignup: true,
+				},
+			},
+			want: "https://github.com/authorize?client_id=CLIENT-ID&code_challenge=E9Melhoa2OwvFrEMTJguCHaoeK1t8URWbuGJSstw-cM&code_challenge_method=S256&redirect_uri=http%3A%2F%2F127.0.0.1%3A12345%2Fhello&scope=repo&state=xy%2Fz",
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			flow := &Flow{
-				server:   tt.fields.server,
-				clientID: tt.fields.clientID,
-				state:    tt.fields.state,
+				server:       tt.fields.server,
+				clientID:     tt.fields.clientID,
+				state:        tt.fields.state,
+				codeVerifier: tt.fields.codeVerifier,
 			}
 			got, err := flow.BrowserURL(tt.args.baseURL, tt.args.params)
 			if (err != nil) != tt.wantErr {
@@ -89,6 +133,76 @@ func TestFlow_BrowserURL(t *testing.T) {
 	}
 }
 
+func TestFlow_BrowserURLRedirectURI(t *testing.T) {
+	newFlow := func(ip net.IP, callbackPath string) *Flow {
+		return &Flow{server: &localServer{
+			CallbackPath: callbackPath,
+			listener: &fakeListener{
+				addr: &net.TCPAddr{IP: ip, Port: 12345},
+			},
+		}}
+	}
+	tests := []struct {
+		name         string
+		flow         *Flow
+		redirectURI  string
+		want         string
+		wantCallback string
+		wantErr      bool
+	}{
+		{
+			name:         "IPv6",
+			flow:         newFlow(net.IPv6loopback, ""),
+			redirectURI:  "http://[::1]/callback",
+			want:         "http://[::1]:12345/callback",
+			wantCallback: "/callback",
+		},
+		{
+			name:         "from the server address",
+			flow:         newFlow(net.IPv4(127, 0, 0, 1), "/cb"),
+			want:         "http://127.0.0.1:12345/cb",
+			wantCallback: "/cb",
+		},
+		{
+			name:         "path from the server",
+			flow:         newFlow(net.IPv4(127, 0, 0, 1), "/cb"),
+			redirectURI:  "http://localhost",
+			want:         "http://localhost:12345/cb",
+			wantCallback: "/cb",
+		},
+		{
+			name:        "path mismatch",
+			flow:        newFlow(net.IPv4(127, 0, 0, 1), "/cb"),
+			redirectURI: "http://127.0.0.1/other",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.flow.BrowserURL("https://example.com/authorize", BrowserParams{
+				ClientID:    "CLIENT-ID",
+				RedirectURI: tt.redirectURI,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("BrowserURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			u, err := url.Parse(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ru := u.Query().Get("redirect_uri"); ru != tt.want {
+				t.Errorf("redirect_uri = %q, want %q", ru, tt.want)
+			}
+			if cp := tt.flow.server.CallbackPath; cp != tt.wantCallback {
+				t.Errorf("CallbackPath = %q, want %q", cp, tt.wantCallback)
+			}
+		})
+	}
+}
+
 type apiStub struct {
 	status      int
 	body        string
@@ -171,3 +285,185 @@ func TestFlow_AccessToken(t *testing.T) {
 		t.Errorf("Token = %q", token.Token)
 	}
 }
+
+func TestFlow_AccessTokenWithPKCE(t *testing.T) {
+	server := &localServer{
+		listener: &fakeListener{
+			addr: &net.TCPAddr{Port: 12345},
+		},
+		resultChan: make(chan CodeResponse),
+	}
+
+	flow := Flow{
+		server:       server,
+		clientID:     "CLIENT-ID",
+		state:        "xy/z",
+		codeVerifier: "VERIFIER",
+	}
+
+	client := &apiClient{
+		stubs: []apiStub{
+			{
+				body:        "access_token=ATOKEN&token_type=bearer&scope=repo+gist",
+				status:      200,
+				contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			},
+		},
+	}
+
+	go func() {
+		server.resultChan <- CodeResponse{
+			Code:  "ABC-123",
+			State: "xy/z",
+		}
+	}()
+
+	if _, err := flow.Wait(context.Background(), client, "https://github.com/access_token", WaitOptions{}); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if params := client.calls[0].params.Encode(); params != "client_id=CLIENT-ID&client_secret=&code=ABC-123&code_verifier=VERIFIER&state=xy%2Fz" {
+		t.Errorf("HTTP POST params: %v", params)
+	}
+}
+
+func TestFlow_WaitWithAudienceAndResources(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       WaitOptions
+		wantParams string
+	}{
+		{
+			name:       "resources from BrowserURL",
+			wantParams: "audience=AUD&client_id=CLIENT-ID&client_secret=&code=ABC-123&resource=https%3A%2F%2Fapi.example.com%2F&resource=https%3A%2F%2Ffiles.example.com%2F&state=xy%2Fz",
+		},
+		{
+			name:       "narrowed resources",
+			opts:       WaitOptions{Resources: []string{"https://files.example.com/"}},
+			wantParams: "audience=AUD&client_id=CLIENT-ID&client_secret=&code=ABC-123&resource=https%3A%2F%2Ffiles.example.com%2F&state=xy%2Fz",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := &localServer{
+				listener: &fakeListener{
+					addr: &net.TCPAddr{Port: 12345},
+				},
+				resultChan: make(chan CodeResponse),
+			}
+			flow := Flow{
+				server: server,
+				state:  "xy/z",
+			}
+			_, err := flow.BrowserURL("https://example.com/authorize", BrowserParams{
+				ClientID:    "CLIENT-ID",
+				RedirectURI: "http://127.0.0.1/hello",
+				Audience:    "AUD",
+				Resources:   []string{"https://api.example.com/", "https://files.example.com/"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := &apiClient{
+				stubs: []apiStub{
+					{
+						body:        "access_token=ATOKEN&token_type=bearer",
+						status:      200,
+						contentType: "application/x-www-form-urlencoded; charset=utf-8",
+					},
+				},
+			}
+			go func() {
+				server.resultChan <- CodeResponse{
+					Code:  "ABC-123",
+					State: "xy/z",
+				}
+			}()
+
+			if _, err := flow.Wait(context.Background(), client, "https://example.com/token", tt.opts); err != nil {
+				t.Fatalf("Wait() error: %v", err)
+			}
+			if params := client.calls[0].params.Encode(); params != tt.wantParams {
+				t.Errorf("HTTP POST params: %v", params)
+			}
+		})
+	}
+}
+
+func TestFlow_WaitWithClientAssertion(t *testing.T) {
+	server := &localServer{
+		listener: &fakeListener{
+			addr: &net.TCPAddr{Port: 12345},
+		},
+		resultChan: make(chan CodeResponse),
+	}
+	flow := Flow{
+		server:   server,
+		clientID: "CLIENT-ID",
+		state:    "xy/z",
+	}
+	client := &apiClient{
+		stubs: []apiStub{
+			{
+				body:        "access_token=ATOKEN&token_type=bearer",
+				status:      200,
+				contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			},
+		},
+	}
+	go func() {
+		server.resultChan <- CodeResponse{
+			Code:  "ABC-123",
+			State: "xy/z",
+		}
+	}()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := WaitOptions{ClientSecret: "OAUTH-SEKRIT", ClientAssertion: &api.ClientAssertion{Key: key}}
+	if _, err := flow.Wait(context.Background(), client, "https://example.com/token", opts); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	params := client.calls[0].params
+	if _, ok := params["client_secret"]; ok {
+		t.Error("client_secret was sent with a client assertion")
+	}
+	if params.Get("client_assertion_type") != "urn:ietf:params:oauth:client-assertion-type:jwt-bearer" || params.Get("client_assertion") == "" {
+		t.Errorf("HTTP POST params: %v", params)
+	}
+}
+
+func TestGenerateCodeVerifier(t *testing.T) {
+	v1, err := generateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2, err := generateCodeVerifier()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v1) != 43 {
+		t.Errorf("verifier %q has length %d, want 43", v1, len(v1))
+	}
+	if v1 == v2 {
+		t.Errorf("two verifiers are both %q", v1)
+	}
+}
+
+func TestFlow_StartServerContext(t *testing.T) {
+	flow, err := InitFlow()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errc := make(chan error, 1)
+	go func() {
+		errc <- flow.StartServerContext(ctx, nil)
+	}()
+	cancel()
+	if err := <-errc; !errors.Is(err, context.Canceled) {
+		t.Errorf("StartServerContext() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,340 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the translation of schemas to Go source.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// A generator translates a schema and its subschemas to Go type declarations.
+type generator struct {
+	defs    map[string]string             // $ref value to type name
+	defSchs map[string]*jsonschema.Schema // $ref value to schema
+	used    map[string]bool               // type names in use
+	queue   []namedSchema                 // types to declare
+	buf     bytes.Buffer
+}
+
+// A namedSchema is a schema that becomes a named type.
+type namedSchema struct {
+	name   string
+	schema *jsonschema.Schema
+}
+
+// generate returns the formatted source of a file in package pkg declaring
+// Go types for s, the root of which is named typeName.
+func generate(s *jsonschema.Schema, pkg, typeName string) ([]byte, error) {
+	if typeName == "" {
+		typeName = goName(s.Title)
+		if s.Title == "" {
+			typeName = "Schema"
+		}
+	}
+	g := &generator{
+		defs:    map[string]string{"#": typeName},
+		defSchs: map[string]*jsonschema.Schema{"#": s},
+		used:    map[string]bool{typeName: true},
+		queue:   []namedSchema{{typeName, s}},
+	}
+	// Name the definitions first, so that references can use the names.
+	for _, d := range []struct {
+		prefix string
+		defs   map[string]*jsonschema.Schema
+	}{
+		{"#/$defs/", s.Defs},
+		{"#/definitions/", s.Definitions},
+	} {
+		for _, name := range slices.Sorted(maps.Keys(d.defs)) {
+			tn := g.newName(goName(name))
+			g.defs[d.prefix+name] = tn
+			g.defSchs[d.prefix+name] = d.defs[name]
+			g.queue = append(g.queue, namedSchema{tn, d.defs[name]})
+		}
+	}
+
+	fmt.Fprintf(&g.buf, "// Code generated by jsonschema-gen; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&g.buf, "package %s\n", pkg)
+	for len(g.queue) > 0 {
+		ns := g.queue[0]
+		g.queue = g.queue[1:]
+		g.declare(ns.name, ns.schema)
+	}
+	src, err := format.Source(g.buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return src, nil
+}
+
+// declare writes the declaration of the type name for s.
+func (g *generator) declare(name string, s *jsonschema.Schema) {
+	fmt.Fprintf(&g.buf, "\n")
+	if s.Description != "" {
+		for _, line := range strings.Split(strings.TrimSpace(s.Description), "\n") {
+			fmt.Fprintf(&g.buf, "// %s\n", line)
+		}
+	}
+	if !isStruct(s) {
+		typ, _ := g.goType(s, name+"Elem")
+		fmt.Fprintf(&g.buf, "type %s %s\n", name, typ)
+		return
+	}
+	fmt.Fprintf(&g.buf, "type %s struct {\n", name)
+	fields := map[string]bool{}
+	for _, prop := range slices.Sorted(maps.Keys(s.Properties)) {
+		ps := s.Properties[prop]
+		field := uniqueName(goName(prop), fields)
+		fields[field] = true
+		typ, nullable := g.goType(ps, name+field)
+		required := slices.Contains(s.Required, prop)
+		if (!required || nullable || g.isStructRef(ps)) && !isReference(typ) {
+			typ = "*" + typ
+		}
+		jsonTag := prop
+		if !required {
+			jsonTag += ",omitempty"
+		}
+		tag := "json:" + strconv.Quote(jsonTag)
+		if hints := tagHints(ps); hints != "" {
+			tag += " jsonschema:" + strconv.Quote(hints)
+		}
+		if strings.Contains(tag, "`") {
+			tag = strconv.Quote(tag)
+		} else {
+			tag = "`" + tag + "`"
+		}
+		fmt.Fprintf(&g.buf, "%s %s %s\n", field, typ, tag)
+	}
+	fmt.Fprintf(&g.buf, "}\n")
+}
+
+// goType returns the Go type for s, and whether s allows null.
+// A new struct type for s is named after nameHint.
+func (g *generator) goType(s *jsonschema.Schema, nameHint string) (typ string, nullable bool) {
+	if s == nil {
+		return "any", false
+	}
+	if s.Ref != "" {
+		if name, ok := g.defs[s.Ref]; ok {
+			return name, false
+		}
+		return "any", false
+	}
+	types := s.Types
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	nullable = slices.Contains(types, "null")
+	types = slices.DeleteFunc(slices.Clone(types), func(t string) bool { return t == "null" })
+	if len(types) == 0 && isStruct(s) {
+		types = []string{"object"}
+	}
+	if len(types) != 1 {
+		return "any", nullable
+	}
+	switch types[0] {
+	case "string":
+		return "string", nullable
+	case "integer":
+		return "int64", nullable
+	case "number":
+		return "float64", nullable
+	case "boolean":
+		return "bool", nullable
+	case "array":
+		elem, elemNull := g.goType(s.Items, nameHint+"Item")
+		if elemNull && !isReference(elem) {
+			elem = "*" + elem
+		}
+		return "[]" + elem, nullable
+	case "object":
+		if isStruct(s) {
+			name := g.newName(nameHint)
+			g.queue = append(g.queue, namedSchema{name, s})
+			return name, nullable
+		}
+		if s.AdditionalProperties != nil && !isFalse(s.AdditionalProperties) {
+			elem, elemNull := g.goType(s.AdditionalProperties, nameHint+"Value")
+			if elemNull && !isReference(elem) {
+				elem = "*" + elem
+			}
+			return "map[string]" + elem, nullable
+		}
+		return "map[string]any", nullable
+	default:
+		return "any", nullable
+	}
+}
+
+// isStructRef reports whether s refers to a schema that becomes a struct.
+func (g *generator) isStructRef(s *jsonschema.Schema) bool {
+	return s.Ref != "" && isStruct(g.defSchs[s.Ref])
+}
+
+// newName returns a type name based on name that is not in use, and marks
+// it as used.
+func (g *generator) newName(name string) string {
+	name = uniqueName(name, g.used)
+	g.used[name] = true
+	return name
+}
+
+// uniqueName returns name, or name followed by a number if name is in used.
+func uniqueName(name string, used map[string]bool) string {
+	n := name
+	for i := 2; used[n]; i++ {
+		n = name + strconv.Itoa(i)
+	}
+	return n
+}
+
+// isStruct reports whether s becomes a struct type.
+func isStruct(s *jsonschema.Schema) bool {
+	if s == nil || len(s.Properties) == 0 {
+		return false
+	}
+	return s.Type == "object" || s.Type == "" && (len(s.Types) == 0 ||
+		slices.Equal(slices.DeleteFunc(slices.Clone(s.Types), func(t string) bool { return t == "null" }), []string{"object"}))
+}
+
+// isReference reports whether values of the Go type typ can be nil.
+func isReference(typ string) bool {
+	return typ == "any" || strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[")
+}
+
+// isFalse reports whether s is the schema false.
+func isFalse(s *jsonschema.Schema) bool {
+	data, err := json.Marshal(s)
+	return err == nil && string(data) == "false"
+}
+
+// initialisms are words that are written in upper case in Go names.
+var initialisms = map[string]bool{
+	"API": true, "HTTP": true, "HTTPS": true, "ID": true, "JSON": true,
+	"SQL": true, "URI": true, "URL": true, "UUID": true, "XML": true,
+}
+
+// goName returns an exported Go identifier for the JSON name s.
+func goName(s string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if u := strings.ToUpper(word); initialisms[u] {
+			b.WriteString(u)
+			continue
+		}
+		rs := []rune(word)
+		rs[0] = unicode.ToUpper(rs[0])
+		b.WriteString(string(rs))
+	}
+	name := b.String()
+	if name == "" {
+		return "Field"
+	}
+	if r := []rune(name)[0]; !unicode.IsLetter(r) || !unicode.IsUpper(r) {
+		name = "X" + name
+	}
+	return name
+}
+
+// tagHints returns the value of a jsonschema struct tag for the
+// description and validation keywords of s, or "" if there are none.
+// Keywords that do not apply to the Go type of s are omitted, as are
+// values that cannot be written in a tag.
+func tagHints(s *jsonschema.Schema) string {
+	var settings []string
+	add := func(kw, val string) {
+		if strings.ContainsRune(val, ',') || strings.HasPrefix(val, "'") {
+			if strings.ContainsRune(val, '\'') {
+				return
+			}
+			val = "'" + val + "'"
+		}
+		settings = append(settings, kw+"="+val)
+	}
+	typ := s.Type
+	for _, t := range s.Types {
+		if t != "null" {
+			typ = t
+		}
+	}
+	num := func(f float64) string { return strconv.FormatFloat(f, 'f', -1, 64) }
+
+	if s.Description != "" && !strings.ContainsRune(s.Description, '\n') {
+		add("description", s.Description)
+	}
+	if typ == "integer" || typ == "number" {
+		if s.Minimum != nil {
+			add("minimum", num(*s.Minimum))
+		}
+		if s.Maximum != nil {
+			add("maximum", num(*s.Maximum))
+		}
+	}
+	if typ == "string" {
+		if s.MinLength != nil {
+			add("minLength", strconv.Itoa(*s.MinLength))
+		}
+		if s.MaxLength != nil {
+			add("maxLength", strconv.Itoa(*s.MaxLength))
+		}
+		if s.Pattern != "" {
+			add("pattern", s.Pattern)
+		}
+		if s.Format != "" {
+			add("format", s.Format)
+		}
+	}
+	if enum, ok := enumHint(s.Enum, typ); ok {
+		add("enum", enum)
+	}
+	return strings.Join(settings, ",")
+}
+
+// enumHint returns the value of the enum setting of a jsonschema struct tag
+// for the enum values vals of a schema of type typ. It reports false if there
+// are no values, or they cannot be written in a tag.
+func enumHint(vals []any, typ string) (string, bool) {
+	if len(vals) == 0 {
+		return "", false
+	}
+	var strs []string
+	for _, v := range vals {
+		switch v := v.(type) {
+		case string:
+			if typ != "string" || strings.ContainsRune(v, '|') {
+				return "", false
+			}
+			strs = append(strs, v)
+		case float64:
+			if typ != "integer" && typ != "number" {
+				return "", false
+			}
+			strs = append(strs, strconv.FormatFloat(v, 'f', -1, 64))
+		case bool:
+			if typ != "boolean" {
+				return "", false
+			}
+			strs = append(strs, strconv.FormatBool(v))
+		default:
+			// Including null, which is not a value of the field's type.
+			return "", false
+		}
+	}
+	return strings.Join(strs, "|"), true
+}
@@ -0,0 +1,137 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+const testSchema = `{
+	"title": "order",
+	"description": "An order.",
+	"type": "object",
+	"properties": {
+		"id": {"type": "string", "format": "uuid"},
+		"quantity": {"type": "integer", "minimum": 1, "maximum": 100, "description": "how many, at most 100"},
+		"note": {"type": ["null", "string"], "maxLength": 200},
+		"status": {"type": "string", "enum": ["open", "closed"]},
+		"tags": {"type": "array", "items": {"type": "string"}},
+		"attrs": {"type": "object", "additionalProperties": {"type": "number"}},
+		"customer": {"$ref": "#/$defs/customer"},
+		"shipping": {
+			"type": "object",
+			"properties": {"street-address": {"type": "string", "pattern": "^[^,]+$"}},
+			"required": ["street-address"]
+		},
+		"extra": {},
+		"parent": {"$ref": "#"}
+	},
+	"required": ["id", "quantity", "customer", "shipping"],
+	"$defs": {
+		"customer": {
+			"type": "object",
+			"properties": {"name": {"type": "string", "minLength": 1}, "vip": {"type": "boolean"}},
+			"required": ["name"]
+		},
+		"code": {"type": "string", "pattern": "^[A-Z]+$"}
+	}
+}`
+
+const wantSource = "// Code generated by jsonschema-gen; DO NOT EDIT.\n" + `
+package orders
+
+// An order.
+type Order struct {
+	Attrs    map[string]float64 ` + "`" + `json:"attrs,omitempty"` + "`" + `
+	Customer *Customer          ` + "`" + `json:"customer"` + "`" + `
+	Extra    any                ` + "`" + `json:"extra,omitempty"` + "`" + `
+	ID       string             ` + "`" + `json:"id" jsonschema:"format=uuid"` + "`" + `
+	Note     *string            ` + "`" + `json:"note,omitempty" jsonschema:"maxLength=200"` + "`" + `
+	Parent   *Order             ` + "`" + `json:"parent,omitempty"` + "`" + `
+	Quantity int64              ` + "`" + `json:"quantity" jsonschema:"description='how many, at most 100',minimum=1,maximum=100"` + "`" + `
+	Shipping OrderShipping      ` + "`" + `json:"shipping"` + "`" + `
+	Status   *string            ` + "`" + `json:"status,omitempty" jsonschema:"enum=open|closed"` + "`" + `
+	Tags     []string           ` + "`" + `json:"tags,omitempty"` + "`" + `
+}
+
+type Code string
+
+type Customer struct {
+	Name string ` + "`" + `json:"name" jsonschema:"minLength=1"` + "`" + `
+	Vip  *bool  ` + "`" + `json:"vip,omitempty"` + "`" + `
+}
+
+type OrderShipping struct {
+	StreetAddress string ` + "`" + `json:"street-address" jsonschema:"pattern='^[^,]+$'"` + "`" + `
+}
+`
+
+func TestGenerate(t *testing.T) {
+	var s jsonschema.Schema
+	if err := json.Unmarshal([]byte(testSchema), &s); err != nil {
+		t.Fatal(err)
+	}
+	got, err := generate(&s, "orders", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantSource, string(got)); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestTagHintsRoundTrip(t *testing.T) {
+	// The hints in a tag reproduce the schema of a property under For.
+	for _, tt := range []struct {
+		schema string
+		typ    reflect.Type
+	}{
+		{`{"type": "integer", "description": "a, b", "minimum": -1.5, "maximum": 3}`, reflect.TypeFor[int64]()},
+		{`{"type": "string", "minLength": 1, "maxLength": 3, "pattern": "^a{1,3}$", "format": "email"}`, reflect.TypeFor[string]()},
+		{`{"type": "string", "enum": ["a b", "c"]}`, reflect.TypeFor[string]()},
+		{`{"type": "number", "enum": [1.5, 2]}`, reflect.TypeFor[float64]()},
+	} {
+		var want jsonschema.Schema
+		if err := json.Unmarshal([]byte(tt.schema), &want); err != nil {
+			t.Fatal(err)
+		}
+		hints := tagHints(&want)
+		st := reflect.StructOf([]reflect.StructField{{
+			Name: "F",
+			Type: tt.typ,
+			Tag:  reflect.StructTag(`json:"f" jsonschema:` + strconv.Quote(hints)),
+		}})
+		s, err := jsonschema.ForType(st, nil)
+		if err != nil {
+			t.Fatalf("%s: %v", hints, err)
+		}
+		if diff := cmp.Diff(&want, s.Properties["f"], cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", hints, diff)
+		}
+	}
+}
+
+func TestGoName(t *testing.T) {
+	for _, tt := range []struct{ in, want string }{
+		{"name", "Name"},
+		{"userName", "UserName"},
+		{"user_id", "UserID"},
+		{"api-url", "APIURL"},
+		{"2fa", "X2fa"},
+		{"", "Field"},
+		{"$$", "Field"},
+	} {
+		if got := goName(tt.in); got != tt.want {
+			t.Errorf("goName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Jsonschema-gen generates Go types from a JSON schema. It is the inverse of
+// [jsonschema.For], for when the schema, rather than Go code, is the source
+// of truth.
+//
+// Usage:
+//
+//	jsonschema-gen [flags] [schema.json]
+//
+// The schema is read from the named file, or from standard input if there is
+// none. The flags are:
+//
+//	-o FILE
+//		the file to write (default standard output)
+//	-package NAME
+//		the package of the generated file (default $GOPACKAGE, set by
+//		go generate, or "main")
+//	-type NAME
+//		the name of the type for the schema (default the schema's title, or
+//		"Schema")
+//
+// An object schema with properties becomes a struct type, with a field for
+// each property, in order of property name. Other schemas become the
+// corresponding Go types: strings, numbers, integers and booleans become
+// string, float64, int64 and bool; arrays become slices; objects without
+// properties become maps; and anything else becomes any.
+//
+// A field whose property is not required, or whose schema allows null, is a
+// pointer, unless its type is a slice, map or interface; a field for a
+// property that is not required has the "omitempty" option. Fields whose
+// property refers to a struct type with $ref are always pointers, so that
+// types can be recursive.
+//
+// Schemas in "$defs" or "definitions" become named types, and references to
+// them with "$ref" refer to those types. Other object schemas with properties
+// become types named after their enclosing type and property.
+//
+// Descriptions and validation keywords supported by the jsonschema struct tag
+// are recorded in the tag, so that [jsonschema.For] reproduces them:
+//
+//	Count *int64 `json:"count,omitempty" jsonschema:"description=the count,minimum=1"`
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+var (
+	outputFlag  = flag.String("o", "", "output file (default standard output)")
+	packageFlag = flag.String("package", "", "package name (default $GOPACKAGE or main)")
+	typeFlag    = flag.String("type", "", "name of the type for the schema (default title or Schema)")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: jsonschema-gen [flags] [schema.json]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("jsonschema-gen: ")
+	flag.Usage = usage
+	flag.Parse()
+
+	var (
+		data []byte
+		err  error
+	)
+	switch flag.NArg() {
+	case 0:
+		data, err = io.ReadAll(os.Stdin)
+	case 1:
+		data, err = os.ReadFile(flag.Arg(0))
+	default:
+		usage()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	var s jsonschema.Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Fatal(err)
+	}
+
+	pkg := *packageFlag
+	if pkg == "" {
+		pkg = os.Getenv("GOPACKAGE")
+	}
+	if pkg == "" {
+		pkg = "main"
+	}
+	src, err := generate(&s, pkg, *typeFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *outputFlag == "" {
+		_, err = os.Stdout.Write(src)
+	} else {
+		err = os.WriteFile(*outputFlag, src, 0o644)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
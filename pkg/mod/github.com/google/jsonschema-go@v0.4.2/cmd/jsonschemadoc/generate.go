@@ -0,0 +1,148 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the extraction of field doc comments and the
+// generation of Go source from them.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// A structDoc holds the descriptions of the fields of a struct type.
+type structDoc struct {
+	name   string
+	fields [][2]string // field name and description, in declaration order
+}
+
+// generate returns the formatted source of a file declaring a variable named
+// varName that holds the field descriptions of the given struct types in the
+// package in dir, or of all its struct types if types is empty.
+// The file named output is ignored.
+func generate(dir, output, varName string, types []string) ([]byte, error) {
+	pkgName, docs, err := parseDocs(dir, output)
+	if err != nil {
+		return nil, err
+	}
+	if len(types) > 0 {
+		byName := map[string]*structDoc{}
+		for _, d := range docs {
+			byName[d.name] = d
+		}
+		docs = docs[:0]
+		for _, name := range types {
+			d := byName[name]
+			if d == nil {
+				return nil, fmt.Errorf("no non-generic struct type %q in %s", name, dir)
+			}
+			docs = append(docs, d)
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by jsonschemadoc; DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	fmt.Fprintf(&buf, "import \"reflect\"\n\n")
+	fmt.Fprintf(&buf, "// %s maps struct types to the doc comments of their fields,\n", varName)
+	fmt.Fprintf(&buf, "// for use as jsonschema.ForOptions.FieldDescriptions.\n")
+	fmt.Fprintf(&buf, "var %s = map[reflect.Type]map[string]string{\n", varName)
+	for _, d := range docs {
+		if len(d.fields) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "reflect.TypeFor[%s](): {\n", d.name)
+		for _, f := range d.fields {
+			fmt.Fprintf(&buf, "%q: %q,\n", f[0], f[1])
+		}
+		fmt.Fprintf(&buf, "},\n")
+	}
+	fmt.Fprintf(&buf, "}\n")
+	return format.Source(buf.Bytes())
+}
+
+// parseDocs parses the non-test Go files in dir other than output, and returns
+// the package name and the field descriptions of its struct types, sorted by
+// type name.
+func parseDocs(dir, output string) (string, []*structDoc, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return "", nil, err
+	}
+	fset := token.NewFileSet()
+	var (
+		pkgName string
+		docs    []*structDoc
+	)
+	for _, file := range files {
+		base := filepath.Base(file)
+		if base == output || strings.HasSuffix(base, "_test.go") {
+			continue
+		}
+		src, err := os.ReadFile(file)
+		if err != nil {
+			return "", nil, err
+		}
+		f, err := parser.ParseFile(fset, file, src, parser.ParseComments)
+		if err != nil {
+			return "", nil, err
+		}
+		if pkgName == "" {
+			pkgName = f.Name.Name
+		} else if f.Name.Name != pkgName {
+			return "", nil, fmt.Errorf("%s: found packages %s and %s", dir, pkgName, f.Name.Name)
+		}
+		for _, decl := range f.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok || ts.TypeParams != nil || ts.Assign.IsValid() {
+					continue
+				}
+				docs = append(docs, &structDoc{name: ts.Name.Name, fields: fieldDocs(st)})
+			}
+		}
+	}
+	if pkgName == "" {
+		return "", nil, errors.New("no Go files in " + dir)
+	}
+	slices.SortFunc(docs, func(a, b *structDoc) int { return strings.Compare(a.name, b.name) })
+	return pkgName, docs, nil
+}
+
+// fieldDocs returns the names and descriptions of the documented exported
+// fields of st.
+func fieldDocs(st *ast.StructType) [][2]string {
+	var fields [][2]string
+	for _, field := range st.Fields.List {
+		doc := field.Doc
+		if doc == nil {
+			doc = field.Comment
+		}
+		text := strings.TrimSpace(doc.Text()) // Text is nil-safe
+		if text == "" {
+			continue
+		}
+		for _, name := range field.Names {
+			if name.IsExported() {
+				fields = append(fields, [2]string{name.Name, text})
+			}
+		}
+	}
+	return fields
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testSrc = `package game
+
+// A Player is a player.
+type Player struct {
+	// Name is the player's name.
+	// It is unique.
+	Name string
+	Level int // the player's "level"
+	X, Y  int // coordinates
+	secret string // not exported
+	Team
+	Undocumented bool
+}
+
+type Team struct {
+	// The team's name.
+	TeamName string
+}
+
+type Empty struct{}
+
+type Generic[T any] struct {
+	// Not documented.
+	V T
+}
+
+type Alias = Team
+`
+
+const wantAll = `// Code generated by jsonschemadoc; DO NOT EDIT.
+
+package game
+
+import "reflect"
+
+// docs maps struct types to the doc comments of their fields,
+// for use as jsonschema.ForOptions.FieldDescriptions.
+var docs = map[reflect.Type]map[string]string{
+	reflect.TypeFor[Player](): {
+		"Name":  "Name is the player's name.\nIt is unique.",
+		"Level": "the player's \"level\"",
+		"X":     "coordinates",
+		"Y":     "coordinates",
+	},
+	reflect.TypeFor[Team](): {
+		"TeamName": "The team's name.",
+	},
+}
+`
+
+func TestGenerate(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, src string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(src), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("game.go", testSrc)
+	// Test files and the output file are ignored.
+	write("game_test.go", "package game_test\n")
+	write("out.go", "package other\n")
+
+	got, err := generate(dir, "out.go", "docs", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(wantAll, string(got)); diff != "" {
+		t.Errorf("all types: mismatch (-want, +got):\n%s", diff)
+	}
+
+	got, err = generate(dir, "out.go", "docs", []string{"Team"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(got); strings.Contains(s, "Player") || !strings.Contains(s, "TeamName") {
+		t.Errorf("-type Team: got\n%s", s)
+	}
+
+	for _, types := range [][]string{{"Generic"}, {"Alias"}, {"Missing"}} {
+		if _, err := generate(dir, "out.go", "docs", types); err == nil {
+			t.Errorf("%v: got nil error, want error", types)
+		}
+	}
+	if _, err := generate(t.TempDir(), "out.go", "docs", nil); err == nil {
+		t.Error("empty directory: got nil error, want error")
+	}
+}
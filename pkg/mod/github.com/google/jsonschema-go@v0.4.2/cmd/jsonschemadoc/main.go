@@ -0,0 +1,88 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Jsonschemadoc generates a map from struct types to the doc comments of their
+// fields, for use as [jsonschema.ForOptions.FieldDescriptions]. With it, the
+// properties of schemas inferred by [jsonschema.For] are described by the
+// fields' documentation, without repeating it in struct tags.
+//
+// It is intended to be run by go generate. For example:
+//
+//	//go:generate go run github.com/google/jsonschema-go/cmd/jsonschemadoc -type Player,Team
+//
+// writes jsonschema_docs.go, declaring
+//
+//	var jsonschemaDescriptions = map[reflect.Type]map[string]string{...}
+//
+// which can then be used like this:
+//
+//	s, err := jsonschema.For[Player](&jsonschema.ForOptions{
+//		FieldDescriptions: jsonschemaDescriptions,
+//	})
+//
+// Usage:
+//
+//	jsonschemadoc [flags] [directory]
+//
+// The directory defaults to the current one. The flags are:
+//
+//	-type T1,T2,...
+//		the struct types to document; by default, all non-generic struct types
+//	-output FILE
+//		the file to write, relative to the directory (default "jsonschema_docs.go")
+//	-var NAME
+//		the name of the generated variable (default "jsonschemaDescriptions")
+//
+// The description of a field is its doc comment or, failing that, its line
+// comment. Only exported fields with a comment are included. Test files and
+// the output file are ignored, as are build constraints.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	typeFlag   = flag.String("type", "", "comma-separated struct types to document (default all)")
+	outputFlag = flag.String("output", "jsonschema_docs.go", "output file")
+	varFlag    = flag.String("var", "jsonschemaDescriptions", "name of the generated variable")
+)
+
+func usage() {
+	fmt.Fprintf(os.Stderr, "usage: jsonschemadoc [flags] [directory]\n")
+	flag.PrintDefaults()
+	os.Exit(2)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("jsonschemadoc: ")
+	flag.Usage = usage
+	flag.Parse()
+	dir := "."
+	switch flag.NArg() {
+	case 0:
+	case 1:
+		dir = flag.Arg(0)
+	default:
+		usage()
+	}
+	var types []string
+	if *typeFlag != "" {
+		types = strings.Split(*typeFlag, ",")
+	}
+	output := filepath.Join(dir, *outputFlag)
+	src, err := generate(dir, filepath.Base(output), *varFlag, types)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(output, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
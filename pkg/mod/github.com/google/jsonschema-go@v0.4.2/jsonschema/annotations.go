@@ -18,8 +18,14 @@ type annotations struct {
 	evaluatedProperties map[string]bool // set of properties evaluated by various keywords
 }
 
+// The note methods do nothing if a is nil, so that annotations need not be
+// tracked when no schema uses them.
+
 // noteIndex marks i as evaluated.
 func (a *annotations) noteIndex(i int) {
+	if a == nil {
+		return
+	}
 	if a.evaluatedIndexes == nil {
 		a.evaluatedIndexes = map[int]bool{}
 	}
@@ -28,6 +34,9 @@ func (a *annotations) noteIndex(i int) {
 
 // noteEndIndex marks items with index less than end as evaluated.
 func (a *annotations) noteEndIndex(end int) {
+	if a == nil {
+		return
+	}
 	if end > a.endIndex {
 		a.endIndex = end
 	}
@@ -35,6 +44,9 @@ func (a *annotations) noteEndIndex(end int) {
 
 // noteProperty marks prop as evaluated.
 func (a *annotations) noteProperty(prop string) {
+	if a == nil {
+		return
+	}
 	if a.evaluatedProperties == nil {
 		a.evaluatedProperties = map[string]bool{}
 	}
@@ -43,9 +55,28 @@ func (a *annotations) noteProperty(prop string) {
 
 // noteProperties marks all the properties in props as evaluated.
 func (a *annotations) noteProperties(props map[string]bool) {
+	if a == nil {
+		return
+	}
 	a.evaluatedProperties = merge(a.evaluatedProperties, props)
 }
 
+// noteAllItems marks all items as evaluated.
+func (a *annotations) noteAllItems() {
+	if a == nil {
+		return
+	}
+	a.allItems = true
+}
+
+// noteAllProperties marks all properties as evaluated.
+func (a *annotations) noteAllProperties() {
+	if a == nil {
+		return
+	}
+	a.allProperties = true
+}
+
 // merge adds b's annotations to a.
 // a must not be nil.
 func (a *annotations) merge(b *annotations) {
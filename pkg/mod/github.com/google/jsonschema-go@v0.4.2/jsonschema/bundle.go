@@ -0,0 +1,98 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements bundling of schemas into a single document.
+// See https://json-schema.org/draft/2020-12/json-schema-core#section-9.3.
+
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+)
+
+// Bundle returns a single schema document that contains s and every remote
+// schema that s refers to, directly or indirectly. The result can be resolved
+// without a [Loader].
+//
+// Bundle resolves s with opts, so opts.Loader or opts.ContextLoader must be
+// able to load the remote schemas. Each remote schema document is copied into
+// the "$defs" of the result ("definitions" for draft-07 and earlier) under its
+// URI, and is given that URI as its $id. References are left as they are: they
+// find the copies by their $ids. If s has no $id and opts.BaseURI is set, the
+// result's $id is the base URI, so that relative references keep their
+// meaning.
+//
+// Neither s nor the loaded schemas are modified.
+func Bundle(s *Schema, opts *ResolveOptions) (*Schema, error) {
+	rs, err := s.Resolve(opts)
+	if err != nil {
+		return nil, err
+	}
+	local := map[*Schema]bool{}
+	for ss := range s.all() {
+		local[ss] = true
+	}
+	// Collect the roots of the loaded documents by URI.
+	// Schemas with an $id inside those documents are bundled along with them.
+	remote := map[string]*Schema{}
+	for ss, info := range rs.resolvedInfos {
+		if !local[ss] && info.path == "root" {
+			remote[info.uri.String()] = ss
+		}
+	}
+
+	b := s.CloneSchemas()
+	if uri := rs.resolvedInfos[s].uri; uri.IsAbs() {
+		b.setID(rs.draft, uri.String())
+	}
+	if len(remote) == 0 {
+		return b, nil
+	}
+	defs := &b.Defs
+	if b.Definitions != nil || (b.Defs == nil && rs.draft <= draft7) {
+		defs = &b.Definitions
+	}
+	if *defs == nil {
+		*defs = map[string]*Schema{}
+	}
+	for _, uri := range slices.Sorted(maps.Keys(remote)) {
+		if _, ok := (*defs)[uri]; ok {
+			return nil, fmt.Errorf("bundling %s: definition already exists", uri)
+		}
+		rc := remote[uri].CloneSchemas()
+		rc.setID(rs.draft, uri)
+		(*defs)[uri] = rc
+	}
+
+	// Check that the bundle is self-contained. This fails if, for example,
+	// a schema was referred to by a URI other than its $id.
+	bopts := &ResolveOptions{Loader: func(uri *url.URL) (*Schema, error) {
+		return nil, errors.New("not in bundle")
+	}}
+	if _, err := b.Resolve(bopts); err != nil {
+		return nil, fmt.Errorf("bundled schema does not resolve: %w", err)
+	}
+	return b, nil
+}
+
+// setID sets the identifier of s to id, using the "id" keyword for draft-04.
+func (s *Schema) setID(d draft, id string) {
+	if d != draft4 {
+		s.ID = id
+		return
+	}
+	if s.ID != "" {
+		s.ID = id
+		return
+	}
+	s.Extra = maps.Clone(s.Extra)
+	if s.Extra == nil {
+		s.Extra = map[string]any{}
+	}
+	s.Extra["id"] = id
+}
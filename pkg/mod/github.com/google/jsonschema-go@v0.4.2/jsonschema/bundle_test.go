@@ -0,0 +1,136 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBundle(t *testing.T) {
+	schemas := map[string]string{
+		"https://example.com/person.json": `{
+			"type": "object",
+			"properties": {
+				"name": {"type": "string"},
+				"address": {"$ref": "address.json"}
+			},
+			"required": ["name"]
+		}`,
+		"https://example.com/address.json": `{
+			"$id": "https://example.com/address.json",
+			"properties": {"zip": {"$ref": "#/$defs/zip"}},
+			"$defs": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}
+		}`,
+	}
+	loader := func(uri *url.URL) (*Schema, error) {
+		data, ok := schemas[uri.String()]
+		if !ok {
+			return nil, fmt.Errorf("no schema at %s", uri)
+		}
+		var s Schema
+		if err := json.Unmarshal([]byte(data), &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+
+	s := &Schema{
+		Type:  "array",
+		Items: &Schema{Ref: "person.json"},
+	}
+	orig := s.CloneSchemas()
+	b, err := Bundle(s, &ResolveOptions{BaseURI: "https://example.com/people.json", Loader: loader})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(orig, s); diff != "" {
+		t.Errorf("Bundle modified its argument (-want, +got):\n%s", diff)
+	}
+	if got, want := b.ID, "https://example.com/people.json"; got != want {
+		t.Errorf("ID: got %q, want %q", got, want)
+	}
+	for uri := range schemas {
+		d := b.Defs[uri]
+		if d == nil {
+			t.Fatalf("%s is not in $defs", uri)
+		}
+		if d.ID != uri {
+			t.Errorf("%s: got $id %q", uri, d.ID)
+		}
+	}
+
+	// The bundle validates like the original, without a loader,
+	// and also after a round trip through JSON.
+	data, err := json.Marshal(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b2 Schema
+	if err := json.Unmarshal(data, &b2); err != nil {
+		t.Fatal(err)
+	}
+	for _, bs := range []*Schema{b, &b2} {
+		rs, err := bs.Resolve(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, tt := range []struct {
+			instance string
+			valid    bool
+		}{
+			{`[{"name": "a", "address": {"zip": "12345"}}]`, true},
+			{`[{"name": "a", "address": {"zip": "x"}}]`, false},
+			{`[{"address": {}}]`, false},
+		} {
+			var instance any
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			if err := rs.Validate(instance); (err == nil) != tt.valid {
+				t.Errorf("%s: got %v, want valid = %t", tt.instance, err, tt.valid)
+			}
+		}
+	}
+}
+
+func TestBundleDefinitions(t *testing.T) {
+	// Draft-07 schemas are bundled into "definitions".
+	loader := func(uri *url.URL) (*Schema, error) {
+		return &Schema{Type: "string"}, nil
+	}
+	s := &Schema{
+		Schema:     draft7SchemaVersion,
+		Properties: map[string]*Schema{"a": {Ref: "https://example.com/s"}},
+	}
+	b, err := Bundle(s, &ResolveOptions{Loader: loader})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &Schema{
+		Schema:     draft7SchemaVersion,
+		Properties: map[string]*Schema{"a": {Ref: "https://example.com/s"}},
+		Definitions: map[string]*Schema{
+			"https://example.com/s": {ID: "https://example.com/s", Type: "string"},
+		},
+	}
+	if diff := cmp.Diff(want, b); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Without remote references, the bundle is a copy.
+	s = &Schema{Type: "string"}
+	b, err = Bundle(s, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b == s || !cmp.Equal(b, s) {
+		t.Errorf("got %v, want a copy of %v", b, s)
+	}
+}
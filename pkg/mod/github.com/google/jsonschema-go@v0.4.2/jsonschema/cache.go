@@ -0,0 +1,121 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements a cache of resolved schemas.
+
+package jsonschema
+
+import (
+	"cmp"
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"sync"
+)
+
+// defaultCacheSize is the default size of a [Cache].
+const defaultCacheSize = 1000
+
+// A Cache holds resolved schemas, so that a program that validates instances
+// against many schemas, such as a server receiving them in requests, resolves
+// each schema only once. Schemas are identified by [Schema.Hash], so
+// schemas that differ only in ways that do not affect their meaning share a
+// [Resolved]. When the cache is full, the least recently used schema is
+// dropped.
+//
+// A Cache is safe for use by multiple goroutines.
+type Cache struct {
+	size int
+	opts ResolveOptions
+
+	mu      sync.Mutex
+	entries map[[sha256.Size]byte]*list.Element // values are *cacheEntry
+	lru     list.List                           // most recently used first
+}
+
+// A cacheEntry is an element of a Cache.
+type cacheEntry struct {
+	hash     [sha256.Size]byte
+	resolved *Resolved
+}
+
+// NewCache returns a Cache that holds at most size resolved schemas, and
+// resolves schemas with opts. If size is zero, the cache holds 1000. If opts
+// is nil, the default values are used.
+func NewCache(size int, opts *ResolveOptions) *Cache {
+	c := &Cache{
+		size:    cmp.Or(size, defaultCacheSize),
+		entries: map[[sha256.Size]byte]*list.Element{},
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	return c
+}
+
+// Resolve returns the resolved schema for s from the cache, or resolves s
+// and adds it to the cache. The [Resolved.Schema] of the result may be
+// another schema with the same hash as s. As with [Schema.Resolve], s must
+// not be changed after it is passed to Resolve. Errors are not cached.
+func (c *Cache) Resolve(s *Schema) (*Resolved, error) {
+	return c.ResolveContext(context.Background(), s)
+}
+
+// ResolveContext is like [Cache.Resolve], but resolves s with
+// [Schema.ResolveContext].
+func (c *Cache) ResolveContext(ctx context.Context, s *Schema) (*Resolved, error) {
+	hash, err := s.Hash()
+	if err != nil {
+		return nil, err
+	}
+	if rs := c.get(hash); rs != nil {
+		return rs, nil
+	}
+	// Resolve without holding the lock, because resolving may load remote
+	// schemas. If another goroutine resolves the same schema meanwhile, use
+	// its result.
+	rs, err := s.ResolveContext(ctx, &c.opts)
+	if err != nil {
+		return nil, err
+	}
+	return c.add(hash, rs), nil
+}
+
+// Len returns the number of resolved schemas in the cache.
+func (c *Cache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.lru.Len()
+}
+
+// get returns the resolved schema with the given hash, or nil if it is not in
+// the cache.
+func (c *Cache) get(hash [sha256.Size]byte) *Resolved {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[hash]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(e)
+	return e.Value.(*cacheEntry).resolved
+}
+
+// add adds rs to the cache under hash, unless the cache has a resolved
+// schema with that hash already, and returns the resolved schema in the cache.
+func (c *Cache) add(hash [sha256.Size]byte, rs *Resolved) *Resolved {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[hash]; ok {
+		c.lru.MoveToFront(e)
+		return e.Value.(*cacheEntry).resolved
+	}
+	c.entries[hash] = c.lru.PushFront(&cacheEntry{hash, rs})
+	for c.lru.Len() > c.size {
+		e := c.lru.Back()
+		c.lru.Remove(e)
+		delete(c.entries, e.Value.(*cacheEntry).hash)
+	}
+	return rs
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestCache(t *testing.T) {
+	c := NewCache(2, nil)
+	resolve := func(s *Schema) *Resolved {
+		t.Helper()
+		rs, err := c.Resolve(s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rs
+	}
+
+	str := resolve(&Schema{Type: "string"})
+	// An equivalent schema shares the resolved schema.
+	if got := resolve(&Schema{Types: []string{"string"}, MinLength: Ptr(0)}); got != str {
+		t.Error("equivalent schema was resolved again")
+	}
+	num := resolve(&Schema{Type: "number"})
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+	// Use str, so that num is the least recently used.
+	resolve(&Schema{Type: "string"})
+	resolve(&Schema{Type: "boolean"})
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+	if got := resolve(&Schema{Type: "string"}); got != str {
+		t.Error("recently used schema was evicted")
+	}
+	if got := resolve(&Schema{Type: "number"}); got == num {
+		t.Error("least recently used schema was not evicted")
+	}
+
+	// Errors are not cached.
+	bad := &Schema{Pattern: "("}
+	for range 2 {
+		if _, err := c.Resolve(bad); err == nil {
+			t.Error("got nil error, want error")
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+
+	// Integers that are not exact as float64 are distinct.
+	big := resolve(&Schema{Const: Ptr[any](int64(1<<53 + 1))})
+	if got := resolve(&Schema{Const: Ptr[any](int64(1 << 53))}); got == big {
+		t.Error("schemas with consts 2^53 and 2^53+1 share a resolved schema")
+	}
+
+	// A true applicator is not a default when an unevaluated keyword can see
+	// it, so these schemas do not share a resolved schema.
+	resolve(&Schema{AllOf: []*Schema{{}}, UnevaluatedProperties: falseSchema()})
+	rs := resolve(&Schema{AllOf: []*Schema{{AdditionalProperties: &Schema{}}}, UnevaluatedProperties: falseSchema()})
+	if err := rs.Validate(map[string]any{"x": 1}); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
+
+func TestCacheConcurrent(t *testing.T) {
+	c := NewCache(0, nil)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range 50 {
+				s := &Schema{Type: "integer", Minimum: Ptr(float64(j % 10))}
+				rs, err := c.Resolve(s)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if err := rs.Validate(j % 10); err != nil {
+					t.Errorf("goroutine %d: %v", i, err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if got, want := c.Len(), 10; got != want {
+		t.Errorf("Len = %d, want %d", got, want)
+	}
+	if _, err := c.Resolve(&Schema{Type: "integer", Minimum: Ptr(3.0)}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := c.Len(), 10; got != want {
+		t.Errorf("Len = %d after hit, want %d", got, want)
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements canonical forms of schemas.
+
+package jsonschema
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"maps"
+	"slices"
+)
+
+// Canonical returns a canonical JSON encoding of s, for comparing schemas or
+// using them as cache keys. Schemas that differ only in ways that do not
+// affect their meaning have the same canonical encoding. In particular,
+// Canonical
+//   - sorts object keys, including the keys of properties, ignoring
+//     [Schema.PropertyOrder];
+//   - sorts the elements of "type", "required" and "dependentRequired",
+//     and removes duplicates from them;
+//   - writes a single type as a string;
+//   - omits keywords whose values are their defaults, such as a "minLength"
+//     of 0 or a "propertyNames" of true, and empty objects and arrays
+//     whose absence means the same thing.
+//
+// Applicators such as "items" and "additionalProperties" are kept even when
+// they are true, because they mark the members they apply to as evaluated,
+// which an "unevaluatedItems" or "unevaluatedProperties" elsewhere in the
+// dynamic scope can observe.
+//
+// The encoding has no insignificant white space.
+// Annotations like "title" and "description" are retained, as are the order
+// of "enum" values and keywords in [Schema.Extra].
+func (s *Schema) Canonical() ([]byte, error) {
+	c := s.CloneSchemas()
+	// Canonicalize subschemas before the schemas containing them, so that,
+	// for example, an "items" of {"minLength": 0} is written as true.
+	all := slices.Collect(c.all())
+	for _, cs := range slices.Backward(all) {
+		canonicalize(cs)
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	// Schema.MarshalJSON writes keywords in the order of its fields.
+	return sortKeys(data)
+}
+
+// MarshalSorted returns the JSON encoding of s with the keys of every object
+// in sorted order, including the keywords of each schema and the keys of
+// "properties", whose [Schema.PropertyOrder] is ignored. Unlike
+// [Schema.Canonical], it omits nothing and rewrites no values, so the result
+// unmarshals to a schema equal to s apart from PropertyOrder. Use it to write
+// schemas whose bytes change only when their content does, as in golden files
+// or files kept under version control.
+func (s *Schema) MarshalSorted() ([]byte, error) {
+	c := s.CloneSchemas()
+	for cs := range c.all() {
+		cs.PropertyOrder = nil
+	}
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return sortKeys(data)
+}
+
+// sortKeys re-encodes the JSON value in data with the keys of every object in
+// sorted order. It round-trips through a map, keeping numbers exact, so that,
+// for example, consts of 2^53 and 2^53+1 remain distinct.
+func sortKeys(data []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+// Hash returns the SHA-256 digest of the canonical encoding of s.
+// See [Schema.Canonical].
+func (s *Schema) Hash() ([sha256.Size]byte, error) {
+	data, err := s.Canonical()
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	}
+	return sha256.Sum256(data), nil
+}
+
+// canonicalize rewrites the keywords of s, but not its subschemas, in
+// canonical form.
+func canonicalize(s *Schema) {
+	if s.Type != "" {
+		s.Types = []string{s.Type}
+	}
+	s.Types = sortedSet(s.Types)
+	s.Type = ""
+	if len(s.Types) == 1 {
+		s.Type = s.Types[0]
+		s.Types = nil
+	}
+	s.Required = sortedSet(s.Required)
+	// These maps are shared with the original schema.
+	s.DependentRequired = maps.Clone(s.DependentRequired)
+	for k, v := range s.DependentRequired {
+		s.DependentRequired[k] = sortedSet(v)
+	}
+	s.DependencyStrings = maps.Clone(s.DependencyStrings)
+	for k, v := range s.DependencyStrings {
+		s.DependencyStrings[k] = sortedSet(v)
+	}
+	s.PropertyOrder = nil
+
+	// Omit default values.
+	for _, p := range []**int{&s.MinLength, &s.MinItems, &s.MinProperties} {
+		if *p != nil && **p == 0 {
+			*p = nil
+		}
+	}
+	if s.MinContains != nil && *s.MinContains == 1 {
+		s.MinContains = nil
+	}
+	// Unlike the applicators that evaluate members, propertyNames produces no
+	// annotations, so a true value is the same as its absence.
+	if s.PropertyNames != nil && isTrueSchema(s.PropertyNames) {
+		s.PropertyNames = nil
+	}
+	if len(s.Properties) == 0 {
+		s.Properties = nil
+	}
+}
+
+// sortedSet returns the sorted elements of a without duplicates,
+// or nil if a is empty.
+func sortedSet(a []string) []string {
+	if len(a) == 0 {
+		return nil
+	}
+	return slices.Compact(slices.Sorted(slices.Values(a)))
+}
+
+// isTrueSchema reports whether s is the schema true, which validates
+// every instance.
+func isTrueSchema(s *Schema) bool {
+	data, err := json.Marshal(s)
+	return err == nil && string(data) == "true"
+}
@@ -0,0 +1,143 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestCanonical(t *testing.T) {
+	for _, tt := range []struct {
+		schemas []string // equivalent schemas
+		want    string   // their canonical encoding
+	}{
+		{
+			[]string{
+				`{"type": "object", "required": ["b", "a"], "properties": {"b": {"type": "string"}, "a": true}}`,
+				`{"properties": {"a": {}, "b": {"type": ["string"], "minLength": 0}}, "required": ["a", "b", "a"], "type": "object"}`,
+			},
+			`{"properties":{"a":true,"b":{"type":"string"}},"required":["a","b"],"type":"object"}`,
+		},
+		{
+			[]string{
+				`{"type": ["string", "null"], "title": "T"}`,
+				`{"title": "T", "type": ["null", "string"], "propertyNames": true}`,
+			},
+			`{"title":"T","type":["null","string"]}`,
+		},
+		{
+			[]string{
+				`{"type": "array", "items": {"minLength": 0}, "minItems": 0}`,
+				`{"type": "array", "items": true}`,
+			},
+			`{"items":true,"type":"array"}`,
+		},
+		{
+			[]string{`{}`, `{"properties": {}}`, `{"minContains": 1}`},
+			`true`,
+		},
+		{
+			[]string{`{"enum": [3, 1.0, "x"], "x-ext": {"b": 1, "a": 2}}`},
+			`{"enum":[3,1,"x"],"x-ext":{"a":2,"b":1}}`,
+		},
+	} {
+		var hash [32]byte
+		for i, schema := range tt.schemas {
+			var s Schema
+			if err := json.Unmarshal([]byte(schema), &s); err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Canonical()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("%s:\ngot  %s\nwant %s", schema, got, tt.want)
+			}
+			h, err := s.Hash()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if i > 0 && h != hash {
+				t.Errorf("%s: hash differs from that of %s", schema, tt.schemas[0])
+			}
+			hash = h
+		}
+	}
+
+	// Different schemas have different hashes.
+	h1, _ := (&Schema{Type: "string"}).Hash()
+	h2, _ := (&Schema{Type: "integer"}).Hash()
+	if h1 == h2 {
+		t.Error("different schemas have the same hash")
+	}
+
+	// A true applicator marks members as evaluated, so it is not a default
+	// when an unevaluated keyword can see it.
+	var evaluated, unevaluated Schema
+	if err := json.Unmarshal([]byte(`{"allOf": [{"additionalProperties": true}], "unevaluatedProperties": false}`), &evaluated); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"allOf": [{}], "unevaluatedProperties": false}`), &unevaluated); err != nil {
+		t.Fatal(err)
+	}
+	h1, _ = evaluated.Hash()
+	h2, _ = unevaluated.Hash()
+	if h1 == h2 {
+		t.Error("schemas that accept different instances have the same hash")
+	}
+}
+
+func TestCanonicalDoesNotModify(t *testing.T) {
+	s := &Schema{
+		Types:             []string{"string", "null"},
+		Required:          []string{"b", "a"},
+		DependentRequired: map[string][]string{"a": {"c", "b"}},
+		MinLength:         Ptr(0),
+		PropertyOrder:     []string{"x"},
+	}
+	before, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Canonical(); err != nil {
+		t.Fatal(err)
+	}
+	after, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(before) != string(after) || s.PropertyOrder == nil {
+		t.Errorf("Canonical modified the schema:\nbefore %s\nafter  %s", before, after)
+	}
+}
+
+func TestMarshalSorted(t *testing.T) {
+	s := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"b": {Type: "integer", Enum: []any{json.Number("9007199254740993")}},
+			"a": {Type: "string", MinLength: Ptr(0)},
+		},
+		PropertyOrder: []string{"b", "a"},
+		Defs:          map[string]*Schema{"z": {Properties: map[string]*Schema{"y": {}, "x": {}}, PropertyOrder: []string{"y", "x"}}},
+		Comment:       "c",
+		Extra:         map[string]any{"x-b": 1, "x-a": 2},
+	}
+	got, err := s.MarshalSorted()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"$comment":"c","$defs":{"z":{"properties":{"x":true,"y":true}}},` +
+		`"properties":{"a":{"minLength":0,"type":"string"},"b":{"enum":[9007199254740993],"type":"integer"}},` +
+		`"type":"object","x-a":2,"x-b":1}`
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	if s.PropertyOrder == nil || s.Defs["z"].PropertyOrder == nil {
+		t.Error("MarshalSorted modified the schema")
+	}
+}
@@ -0,0 +1,283 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements a check for breaking changes between versions of a schema.
+
+package jsonschema
+
+import (
+	"cmp"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"regexp"
+	"slices"
+)
+
+// A BreakingChange is a difference between two versions of a schema that may
+// make a value that is valid under the old version invalid under the new one.
+type BreakingChange struct {
+	// Location is the JSON Pointer to the changed keyword in the new schema.
+	Location string
+	// Message describes the change.
+	Message string
+}
+
+func (c BreakingChange) String() string {
+	return cmp.Or(c.Location, "root") + ": " + c.Message
+}
+
+// BreakingChanges reports how the schema to is more restrictive than the
+// schema from: removed properties, narrowed types, tightened constraints and
+// the like. A schema that describes the input of a tool or the body of an API
+// request can change without breaking its clients only if BreakingChanges
+// reports nothing.
+//
+// The comparison is syntactic: it compares the keywords at the same location in
+// both schemas. It does not follow references, so it reports a changed $ref
+// without comparing the schemas that the references refer to. Keywords that
+// cannot be compared, such as "pattern", are reported whenever they differ.
+// A new property is compared with the schema that applied to it before: a
+// matching pattern property, or else additionalProperties. Additions that allow
+// more values, such as a new property that was disallowed, are not reported.
+//
+// The changes are sorted by location.
+func BreakingChanges(from, to *Schema) []BreakingChange {
+	var c compatChecker
+	c.compare("", from, to)
+	slices.SortStableFunc(c.changes, func(a, b BreakingChange) int {
+		return cmp.Compare(a.Location, b.Location)
+	})
+	return c.changes
+}
+
+// A compatChecker accumulates breaking changes.
+type compatChecker struct {
+	changes []BreakingChange
+}
+
+func (c *compatChecker) report(loc, format string, args ...any) {
+	c.changes = append(c.changes, BreakingChange{Location: loc, Message: fmt.Sprintf(format, args...)})
+}
+
+// compare reports the breaking changes from the schema from to the schema to,
+// both at location loc. A nil schema is absent, so it allows every value.
+func (c *compatChecker) compare(loc string, from, to *Schema) {
+	if to == nil {
+		return
+	}
+	if from == nil {
+		from = &Schema{}
+	}
+	kw := func(keyword string) string { return loc + "/" + keyword }
+
+	// References.
+	if to.Ref != "" && to.Ref != from.Ref {
+		c.report(kw("$ref"), "reference changed from %q to %q", from.Ref, to.Ref)
+	}
+	if to.DynamicRef != "" && to.DynamicRef != from.DynamicRef {
+		c.report(kw("$dynamicRef"), "reference changed from %q to %q", from.DynamicRef, to.DynamicRef)
+	}
+	c.compareMaps(kw("$defs"), from.Defs, to.Defs, "definition", true)
+	c.compareMaps(kw("definitions"), from.Definitions, to.Definitions, "definition", true)
+
+	// Validation keywords for any instance type.
+	if toTypes := schemaTypes(to); toTypes != nil {
+		fromTypes := schemaTypes(from)
+		if fromTypes == nil {
+			c.report(kw("type"), "type restricted to %q", toTypes)
+		}
+		for _, t := range fromTypes {
+			if !slices.Contains(toTypes, t) && !(t == "integer" && slices.Contains(toTypes, "number")) {
+				c.report(kw("type"), "type %q removed", t)
+			}
+		}
+	}
+	if to.Enum != nil {
+		if from.Enum == nil {
+			c.report(kw("enum"), "enum added")
+		}
+		for _, v := range from.Enum {
+			if !slices.ContainsFunc(to.Enum, func(w any) bool { return Equal(v, w) }) {
+				c.report(kw("enum"), "enum value %v removed", v)
+			}
+		}
+	}
+	if to.Const != nil && (from.Const == nil || !Equal(*from.Const, *to.Const)) {
+		c.report(kw("const"), "const changed to %v", *to.Const)
+	}
+	if to.Format != "" && to.Format != from.Format {
+		c.report(kw("format"), "format changed from %q to %q", from.Format, to.Format)
+	}
+
+	// Numbers.
+	if to.MultipleOf != nil {
+		if from.MultipleOf == nil {
+			c.report(kw("multipleOf"), "multipleOf %v added", *to.MultipleOf)
+		} else if q := *from.MultipleOf / *to.MultipleOf; q != math.Trunc(q) {
+			c.report(kw("multipleOf"), "multipleOf changed from %v to %v", *from.MultipleOf, *to.MultipleOf)
+		}
+	}
+	// A boolean exclusiveMinimum or exclusiveMaximum changes the meaning of
+	// minimum or maximum, so compare the numeric forms.
+	nf, nt := numericBounds(from), numericBounds(to)
+	compareBound(c, kw("minimum"), nf.Minimum, nt.Minimum, false)
+	compareBound(c, kw("exclusiveMinimum"), nf.ExclusiveMinimum, nt.ExclusiveMinimum, false)
+	compareBound(c, kw("maximum"), nf.Maximum, nt.Maximum, true)
+	compareBound(c, kw("exclusiveMaximum"), nf.ExclusiveMaximum, nt.ExclusiveMaximum, true)
+
+	// Strings.
+	compareBound(c, kw("minLength"), from.MinLength, to.MinLength, false)
+	compareBound(c, kw("maxLength"), from.MaxLength, to.MaxLength, true)
+	if to.Pattern != "" && to.Pattern != from.Pattern {
+		c.report(kw("pattern"), "pattern changed from %q to %q", from.Pattern, to.Pattern)
+	}
+
+	// Arrays.
+	c.compareSlices(kw("prefixItems"), from.PrefixItems, to.PrefixItems, false)
+	c.compareSlices(kw("items"), from.ItemsArray, to.ItemsArray, false)
+	c.compare(kw("items"), from.Items, to.Items)
+	c.compare(kw("additionalItems"), from.AdditionalItems, to.AdditionalItems)
+	c.compare(kw("unevaluatedItems"), from.UnevaluatedItems, to.UnevaluatedItems)
+	c.compare(kw("contains"), from.Contains, to.Contains)
+	compareBound(c, kw("minItems"), from.MinItems, to.MinItems, false)
+	compareBound(c, kw("maxItems"), from.MaxItems, to.MaxItems, true)
+	if to.Contains != nil {
+		// The default of minContains is 1.
+		compareBound(c, kw("minContains"), cmp.Or(from.MinContains, Ptr(1)), cmp.Or(to.MinContains, Ptr(1)), false)
+	}
+	compareBound(c, kw("maxContains"), from.MaxContains, to.MaxContains, true)
+	if to.UniqueItems && !from.UniqueItems {
+		c.report(kw("uniqueItems"), "uniqueItems added")
+	}
+
+	// Objects.
+	for _, name := range to.Required {
+		if !slices.Contains(from.Required, name) {
+			c.report(kw("required"), "property %q is now required", name)
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(to.DependentRequired)) {
+		for _, r := range to.DependentRequired[name] {
+			if !slices.Contains(from.DependentRequired[name], r) {
+				c.report(kw("dependentRequired/"+escapeJSONPointerSegment(name)), "property %q now requires %q", name, r)
+			}
+		}
+	}
+	compareBound(c, kw("minProperties"), from.MinProperties, to.MinProperties, false)
+	compareBound(c, kw("maxProperties"), from.MaxProperties, to.MaxProperties, true)
+	c.compareProperties(kw("properties"), from, to)
+	c.compareMaps(kw("patternProperties"), from.PatternProperties, to.PatternProperties, "pattern property", false)
+	c.compareMaps(kw("dependentSchemas"), from.DependentSchemas, to.DependentSchemas, "dependent schema", false)
+	c.compare(kw("additionalProperties"), from.AdditionalProperties, to.AdditionalProperties)
+	c.compare(kw("propertyNames"), from.PropertyNames, to.PropertyNames)
+	c.compare(kw("unevaluatedProperties"), from.UnevaluatedProperties, to.UnevaluatedProperties)
+
+	// Logic.
+	c.compareSlices(kw("allOf"), from.AllOf, to.AllOf, false)
+	c.compareSlices(kw("anyOf"), from.AnyOf, to.AnyOf, true)
+	c.compareSlices(kw("oneOf"), from.OneOf, to.OneOf, true)
+	if to.Not != nil && !reflect.DeepEqual(from.Not, to.Not) {
+		c.report(kw("not"), "not changed")
+	}
+	if to.If != nil && !reflect.DeepEqual(from.If, to.If) {
+		c.report(kw("if"), "if changed")
+	}
+	c.compare(kw("then"), from.Then, to.Then)
+	c.compare(kw("else"), from.Else, to.Else)
+	c.compare(kw("contentSchema"), from.ContentSchema, to.ContentSchema)
+}
+
+// compareBound reports a breaking change if the bound to is tighter than the
+// bound from.
+// A nil bound is absent. If upper is true, the bounds are maximums;
+// otherwise they are minimums.
+func compareBound[T int | float64](c *compatChecker, loc string, from, to *T, upper bool) {
+	switch {
+	case to == nil:
+	case from == nil:
+		c.report(loc, "set to %v", *to)
+	case upper && *to < *from:
+		c.report(loc, "decreased from %v to %v", *from, *to)
+	case !upper && *to > *from:
+		c.report(loc, "increased from %v to %v", *from, *to)
+	}
+}
+
+// compareSlices compares the schemas of from and to pairwise.
+// If alternatives is true, as for anyOf, removing a schema is a breaking
+// change; otherwise, as for allOf, adding one is.
+func (c *compatChecker) compareSlices(loc string, from, to []*Schema, alternatives bool) {
+	for i, s := range to {
+		if i < len(from) {
+			c.compare(fmt.Sprintf("%s/%d", loc, i), from[i], s)
+		} else if !alternatives {
+			c.compare(fmt.Sprintf("%s/%d", loc, i), nil, s)
+		}
+	}
+	if alternatives && len(to) < len(from) && len(to) > 0 {
+		c.report(loc, "%d schemas removed", len(from)-len(to))
+	}
+}
+
+// compareMaps compares the schemas of from and to with the same key.
+// If removedBreaks is true, as for properties, removing a key is a breaking
+// change. Otherwise, as for patternProperties, a key that is only in to
+// constrains values that were unconstrained, so its schema is compared with an
+// absent one.
+func (c *compatChecker) compareMaps(loc string, from, to map[string]*Schema, what string, removedBreaks bool) {
+	if removedBreaks {
+		for _, k := range slices.Sorted(maps.Keys(from)) {
+			if _, ok := to[k]; !ok {
+				c.report(loc, "%s %q removed", what, k)
+			}
+		}
+	}
+	for _, k := range slices.Sorted(maps.Keys(to)) {
+		if fs, ok := from[k]; ok || !removedBreaks {
+			c.compare(loc+"/"+escapeJSONPointerSegment(k), fs, to[k])
+		}
+	}
+}
+
+// compareProperties compares the properties of from and to. A property that
+// is only in to is compared with the schema that from applied to it.
+func (c *compatChecker) compareProperties(loc string, from, to *Schema) {
+	c.compareMaps(loc, from.Properties, to.Properties, "property", true)
+	for _, k := range slices.Sorted(maps.Keys(to.Properties)) {
+		if _, ok := from.Properties[k]; ok {
+			continue
+		}
+		fs := additionalSchema(from, k)
+		if fs != nil && fs.Not != nil && reflect.DeepEqual(*fs.Not, Schema{}) {
+			// The property was disallowed, so no value of it was valid.
+			continue
+		}
+		c.compare(loc+"/"+escapeJSONPointerSegment(k), fs, to.Properties[k])
+	}
+}
+
+// additionalSchema returns the schema that s applies to a property name that
+// is not in its properties: the first matching pattern property, or else
+// additionalProperties. It returns nil if s does not constrain the property.
+func additionalSchema(s *Schema, name string) *Schema {
+	for _, p := range slices.Sorted(maps.Keys(s.PatternProperties)) {
+		if ok, err := regexp.MatchString(p, name); err == nil && ok {
+			return s.PatternProperties[p]
+		}
+	}
+	return s.AdditionalProperties
+}
+
+// schemaTypes returns the types allowed by s, or nil if s allows all types.
+func schemaTypes(s *Schema) []string {
+	if s.Types != nil {
+		return s.Types
+	}
+	if s.Type != "" {
+		return []string{s.Type}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestBreakingChanges(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		from, to string
+		want     []string // locations
+	}{
+		{"identical", `{"type": "object", "properties": {"a": {"type": "string"}}}`, `{"type": "object", "properties": {"a": {"type": "string"}}}`, nil},
+		{"property removed", `{"properties": {"a": {}, "b": {}}}`, `{"properties": {"a": {}}}`, []string{"/properties"}},
+		{"property added", `{"type": "object"}`, `{"type": "object", "properties": {"x": {"type": "integer"}}}`, []string{"/properties/x/type"}},
+		{"unconstrained property added", `{"properties": {"a": {}}}`, `{"properties": {"a": {}, "b": {}}}`, nil},
+		{"disallowed property added", `{"additionalProperties": false}`, `{"properties": {"b": {"type": "string"}}}`, nil},
+		{"additional property added", `{"additionalProperties": {"type": "string"}}`, `{"properties": {"b": {"type": "string", "minLength": 1}}}`, []string{"/properties/b/minLength"}},
+		{"pattern property added", `{"patternProperties": {"^x": {"type": "string"}}, "additionalProperties": false}`, `{"properties": {"xa": {"type": "string"}}}`, nil},
+		{"type narrowed", `{"type": ["string", "null"]}`, `{"type": "string"}`, []string{"/type"}},
+		{"type widened", `{"type": "integer"}`, `{"type": "number"}`, nil},
+		{"type added", `{}`, `{"type": "string"}`, []string{"/type"}},
+		{"nested type narrowed", `{"properties": {"a": {"type": "number"}}}`, `{"properties": {"a": {"type": "integer"}}}`, []string{"/properties/a/type"}},
+		{"required added", `{"required": ["a"]}`, `{"required": ["a", "b"]}`, []string{"/required"}},
+		{"required removed", `{"required": ["a", "b"]}`, `{"required": ["a"]}`, nil},
+		{"enum value removed", `{"enum": [1, 2, 3]}`, `{"enum": [3, 1]}`, []string{"/enum"}},
+		{"enum value added", `{"enum": [1, 2]}`, `{"enum": [1, 2, 3]}`, nil},
+		{"minimum raised", `{"minimum": 1}`, `{"minimum": 2}`, []string{"/minimum"}},
+		{"minimum lowered", `{"minimum": 2}`, `{"minimum": 1}`, nil},
+		{"minimum made exclusive", `{"minimum": 5}`, `{"minimum": 5, "exclusiveMinimum": true}`, []string{"/exclusiveMinimum"}},
+		{"maximum made exclusive", `{"maximum": 5, "exclusiveMaximum": false}`, `{"maximum": 5, "exclusiveMaximum": true}`, []string{"/exclusiveMaximum"}},
+		{"boolean to numeric exclusive minimum", `{"minimum": 5, "exclusiveMinimum": true}`, `{"exclusiveMinimum": 5}`, nil},
+		{"maxLength added", `{}`, `{"maxLength": 10}`, []string{"/maxLength"}},
+		{"maxLength removed", `{"maxLength": 10}`, `{}`, nil},
+		{"multipleOf divides", `{"multipleOf": 4}`, `{"multipleOf": 2}`, nil},
+		{"multipleOf changed", `{"multipleOf": 2}`, `{"multipleOf": 4}`, []string{"/multipleOf"}},
+		{"pattern changed", `{"pattern": "a"}`, `{"pattern": "b"}`, []string{"/pattern"}},
+		{"additionalProperties closed", `{"properties": {"a": {}}}`, `{"properties": {"a": {}}, "additionalProperties": false}`, []string{"/additionalProperties/not"}},
+		{"items narrowed", `{"items": {"type": ["string", "number"]}}`, `{"items": {"type": "string"}}`, []string{"/items/type"}},
+		{"anyOf alternative removed", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, `{"anyOf": [{"type": "string"}]}`, []string{"/anyOf"}},
+		{"allOf schema added", `{"allOf": [{}]}`, `{"allOf": [{}, {"minLength": 1}]}`, []string{"/allOf/1/minLength"}},
+		{"$ref changed", `{"$ref": "#/$defs/a", "$defs": {"a": {}, "b": {}}}`, `{"$ref": "#/$defs/b", "$defs": {"a": {}, "b": {}}}`, []string{"/$ref"}},
+		{"definition removed", `{"$defs": {"a": {}}}`, `{}`, []string{"/$defs"}},
+		{"patternProperties added", `{}`, `{"patternProperties": {"^x": {"type": "string"}}}`, []string{"/patternProperties/^x/type"}},
+		{"minContains default", `{"contains": {}}`, `{"contains": {}, "minContains": 1}`, nil},
+		{
+			"several",
+			`{"properties": {"a": {"type": "string"}, "b": {}}, "required": ["a"]}`,
+			`{"properties": {"a": {"type": "string", "minLength": 1}}, "required": ["a", "c"], "maxProperties": 3}`,
+			[]string{"/maxProperties", "/properties", "/properties/a/minLength", "/required"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var from, to Schema
+			if err := json.Unmarshal([]byte(tt.from), &from); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.to), &to); err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, c := range BreakingChanges(&from, &to) {
+				got = append(got, c.Location)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestBreakingChangeString(t *testing.T) {
+	from := &Schema{Properties: map[string]*Schema{"a/b": {MinLength: Ptr(1)}}}
+	to := &Schema{Type: "object", Properties: map[string]*Schema{"a/b": {MinLength: Ptr(2)}}}
+	var got []string
+	for _, c := range BreakingChanges(from, to) {
+		got = append(got, c.String())
+	}
+	want := []string{
+		`/properties/a~1b/minLength: increased from 1 to 2`,
+		`/type: type restricted to ["object"]`,
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
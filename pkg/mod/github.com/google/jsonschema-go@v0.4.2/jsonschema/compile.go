@@ -0,0 +1,241 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file compiles the assertion keywords of schemas into closures.
+
+package jsonschema
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// An assertion checks an instance against one or more keywords of the schema
+// being validated, calling [state.fail] for each failure.
+type assertion func(st *state, instance reflect.Value) error
+
+// compileSchemas prepares the schemas of rs for validation with opts.
+// For each schema, it builds closures for the keywords type, enum, const,
+// the number and string keywords, and format (if formats are asserted),
+// which see only the instance, so that validation calls only those present.
+// It must be called after [resolveBounds].
+func compileSchemas(rs *Resolved, opts *ResolveOptions) {
+	for s := range rs.root.all() {
+		info := rs.resolvedInfos[s]
+		info.assertions = compileAssertions(s, info, opts)
+		if ap := s.AdditionalProperties; ap != nil {
+			// additionalProperties: false unmarshals as {"not": {}}.
+			info.additionalPropertiesFalse = ap.Not != nil && reflect.ValueOf(*ap.Not).IsZero()
+		}
+	}
+}
+
+// compileAssertions returns the assertions of s, in the order in which the
+// validator checks their keywords.
+func compileAssertions(s *Schema, info *resolvedInfo, opts *ResolveOptions) []assertion {
+	var as []assertion
+	// type: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.1
+	if s.Type != "" || s.Types != nil {
+		as = append(as, typeAssertion(s.Type, s.Types))
+	}
+	// enum: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.2
+	if s.Enum != nil {
+		as = append(as, enumAssertion(s.Enum))
+	}
+	// const: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.3
+	if s.Const != nil {
+		c := *s.Const
+		cv := reflect.ValueOf(c)
+		as = append(as, func(st *state, instance reflect.Value) error {
+			if !equalValue(cv, instance) {
+				return st.fail("const", "%v does not equal %v", instance, c)
+			}
+			return nil
+		})
+	}
+	// numbers: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.2
+	// The bounds come from info, where draft-04 boolean exclusive bounds have
+	// been translated.
+	if s.MultipleOf != nil || info.minimum != nil || info.maximum != nil || info.exclusiveMinimum != nil || info.exclusiveMaximum != nil {
+		as = append(as, numberAssertion(s.MultipleOf, info.minimum, info.maximum, info.exclusiveMinimum, info.exclusiveMaximum))
+	}
+	// strings: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.3
+	if s.MinLength != nil || s.MaxLength != nil || s.Pattern != "" {
+		as = append(as, stringAssertion(s.MinLength, s.MaxLength, s.Pattern, info.pattern))
+	}
+	// format: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-7
+	if check := formatCheckers[s.Format]; opts.AssertFormats && check != nil {
+		format := s.Format
+		as = append(as, func(st *state, instance reflect.Value) error {
+			if instance.Kind() == reflect.String && !isJSONNumber(instance) && !check(instance.String()) {
+				return st.fail("format", "%q is not a valid %s", instance.String(), format)
+			}
+			return nil
+		})
+	}
+	return as
+}
+
+// typeAssertion returns the assertion for the type keyword, which is typ for
+// a single type and types otherwise.
+func typeAssertion(typ string, types []string) assertion {
+	// "number" subsumes integers.
+	number := typ == "number" || slices.Contains(types, "number")
+	return func(st *state, instance reflect.Value) error {
+		gotType, ok := jsonType(instance)
+		if !ok {
+			if err := st.fail("type", "%v of type %[1]T is not a valid JSON value", instance); err != nil {
+				return err
+			}
+		}
+		if typ != "" {
+			if !(gotType == typ || gotType == "integer" && number) {
+				return st.fail("type", "%v has type %q, want %q", instance, gotType, typ)
+			}
+		} else if !(slices.Contains(types, gotType) || gotType == "integer" && number) {
+			return st.fail("type", "%v has type %q, want one of %q", instance, gotType, strings.Join(types, ", "))
+		}
+		return nil
+	}
+}
+
+// enumAssertion returns the assertion for the enum keyword with values enum.
+func enumAssertion(enum []any) assertion {
+	if set, ok := stringSet(enum); ok {
+		// A string equals only another string, so looking it up is the same
+		// as comparing it with each value, but faster.
+		return func(st *state, instance reflect.Value) error {
+			if instance.Kind() == reflect.String && set[instance.String()] {
+				return nil
+			}
+			return st.fail("enum", "%v does not equal any of: %v", instance, enum)
+		}
+	}
+	vals := make([]reflect.Value, len(enum))
+	for i, e := range enum {
+		vals[i] = reflect.ValueOf(e)
+	}
+	return func(st *state, instance reflect.Value) error {
+		for _, v := range vals {
+			if equalValue(v, instance) {
+				return nil
+			}
+		}
+		return st.fail("enum", "%v does not equal any of: %v", instance, enum)
+	}
+}
+
+// stringSet returns the set of vals, if they are all strings.
+func stringSet(vals []any) (map[string]bool, bool) {
+	set := make(map[string]bool, len(vals))
+	for _, v := range vals {
+		s, ok := v.(string)
+		if !ok {
+			return nil, false
+		}
+		set[s] = true
+	}
+	return set, true
+}
+
+// numberAssertion returns the assertion for the number keywords with the
+// given values, any of which may be nil.
+func numberAssertion(multipleOf, minimum, maximum, exclusiveMinimum, exclusiveMaximum *float64) assertion {
+	return func(st *state, instance reflect.Value) error {
+		var (
+			nf    float64           // the instance as a float, possibly inexact
+			exact *big.Rat          // the instance, if it is not a float
+			cmp   func(float64) int // compares the instance with a bound
+		)
+		if instance.CanFloat() {
+			// A float compares exactly with the bounds, without allocating.
+			nf = instance.Float()
+			cmp = func(f float64) int {
+				switch {
+				case nf < f:
+					return -1
+				case nf > f:
+					return 1
+				}
+				return 0
+			}
+		} else if n, ok := jsonNumber(instance); ok {
+			exact = n
+			nf, _ = n.Float64() // don't care if it's exact or not
+			m := new(big.Rat)   // reuse for all comparisons
+			cmp = func(f float64) int { return n.Cmp(m.SetFloat64(f)) }
+		} else {
+			// These keywords don't apply to non-numbers.
+			return nil
+		}
+		// num returns the instance as a number, for messages.
+		num := func() *big.Rat {
+			n, _ := jsonNumber(instance)
+			return n
+		}
+		if multipleOf != nil {
+			// TODO: validate MultipleOf as non-zero.
+			// The test suite assumes floats.
+			if !isMultiple(nf, exact, *multipleOf) {
+				if err := st.fail("multipleOf", "%s is not a multiple of %f", num(), *multipleOf); err != nil {
+					return err
+				}
+			}
+		}
+		if minimum != nil && cmp(*minimum) < 0 {
+			if err := st.fail("minimum", "%s is less than %f", num(), *minimum); err != nil {
+				return err
+			}
+		}
+		if maximum != nil && cmp(*maximum) > 0 {
+			if err := st.fail("maximum", "%s is greater than %f", num(), *maximum); err != nil {
+				return err
+			}
+		}
+		if exclusiveMinimum != nil && cmp(*exclusiveMinimum) <= 0 {
+			if err := st.fail("exclusiveMinimum", "%s is less than or equal to %f", num(), *exclusiveMinimum); err != nil {
+				return err
+			}
+		}
+		if exclusiveMaximum != nil && cmp(*exclusiveMaximum) >= 0 {
+			if err := st.fail("exclusiveMaximum", "%s is greater than or equal to %f", num(), *exclusiveMaximum); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// stringAssertion returns the assertion for the string keywords with the
+// given values. The lengths may be nil and the pattern empty; re is the
+// compiled pattern.
+func stringAssertion(minLength, maxLength *int, pattern string, re *regexp.Regexp) assertion {
+	return func(st *state, instance reflect.Value) error {
+		if instance.Kind() != reflect.String || isJSONNumber(instance) {
+			return nil
+		}
+		str := instance.String()
+		if minLength != nil || maxLength != nil {
+			n := utf8.RuneCountInString(str)
+			if minLength != nil && n < *minLength {
+				if err := st.fail("minLength", "%q contains %d Unicode code points, fewer than %d", str, n, *minLength); err != nil {
+					return err
+				}
+			}
+			if maxLength != nil && n > *maxLength {
+				if err := st.fail("maxLength", "%q contains %d Unicode code points, more than %d", str, n, *maxLength); err != nil {
+					return err
+				}
+			}
+		}
+		if re != nil && !re.MatchString(str) {
+			return st.fail("pattern", "%q does not match regular expression %q", str, pattern)
+		}
+		return nil
+	}
+}
@@ -6,14 +6,21 @@
 Package jsonschema is an implementation of the [JSON Schema specification],
 a JSON-based format for describing the structure of JSON data.
 The package can be used to read schemas for code generation, and to validate
-data using the draft 2020-12 and draft-07 specifications. Validation with
-other drafts or custom meta-schemas is not supported.
+data using the draft 2020-12, draft 2019-09 and draft-07 specifications.
+Legacy draft-06 and draft-04 schemas are also supported. The boolean form
+of the draft-04 exclusiveMinimum and exclusiveMaximum keywords unmarshals into
+[Schema.ExclusiveMinimumBool] and [Schema.ExclusiveMaximumBool], and is
+honored in schemas of draft-07 and earlier.
+Validation with other drafts or custom meta-schemas is not supported.
 
 Construct a [Schema] as you would any Go struct (for example, by writing
 a struct literal), or unmarshal a JSON schema into a [Schema] in the usual
 way (with [encoding/json], for instance). It can then be used for code
 generation or other purposes without further processing.
 You can also infer a schema from a Go struct.
+Schemas can also be unmarshaled from YAML with any of the common YAML
+packages; see [Schema.UnmarshalYAML]. Use [FromYAML] to prepare an instance
+decoded from YAML for validation.
 
 # Resolution
 
@@ -21,8 +28,16 @@ A Schema can refer to other schemas, both inside and outside itself. These
 references must be resolved before a schema can be used for validation.
 Call [Schema.Resolve] to obtain a resolved schema (called a [Resolved]).
 If the schema has external references, pass a [ResolveOptions] with a [Loader]
-to load them. To validate default values in a schema, set
+to load them; [HTTPLoader] loads schemas over HTTP. To bound the time spent
+loading, as in a server, call [Schema.ResolveContext] with a [ContextLoader]
+such as [HTTPContextLoader]. To validate default values in a schema, set
 [ResolveOptions.ValidateDefaults] to true.
+A program that validates against many schemas, such as a server, can keep
+their resolved forms in a [Cache] rather than resolving them for each use.
+
+To ship a schema to a place where its external references cannot be loaded,
+call [Bundle]. It returns a single self-contained schema document that
+embeds the external schemas.
 
 # Validation
 
@@ -46,6 +61,52 @@ or as a value of this type:
 		Scores []int  `json:"scores"`
 	}
 
+Numbers may also be [json.Number] values, as produced by a [json.Decoder] with
+UseNumber. Validate compares them exactly, so integers such as int64 IDs that
+are too large to be represented exactly as a float64 validate correctly.
+
+Validate stops at the first failure. To report every failure at once, for
+example to show all the problems with a form, call [Resolved.ValidateAll].
+Each failure is described by a [ValidationError], which holds JSON Pointers
+to the failing value in the instance and to the failed keyword in the schema.
+Retrieve the failures with [errors.As], or test for one with [errors.Is] and a
+[ValidationError] whose non-empty fields it must match.
+[GetJSONPointer] and [SetJSONPointer] navigate an instance by such a pointer,
+for example to show or correct the failing value.
+To report the result in one of the standard output formats of the
+specification, call [Resolved.ValidateOutput].
+
+To translate or customize the messages of validation errors, for example
+for forms shown to users, provide a [MessageCatalog] in
+[ResolveOptions.Messages].
+
+To validate inputs too large to hold in memory, call [Resolved.ValidateStream]
+for a sequence of values such as NDJSON, or [Resolved.ValidateArrayStream]
+for the elements of a JSON array.
+
+Keywords that are not part of the specification are held in [Schema.Extra]
+and ignored during validation. To validate custom keywords, such as those of
+an organization-specific vocabulary, describe them with [Keyword] and pass them
+in [ResolveOptions.Keywords].
+
+To check that a new version of a schema accepts every value that the old
+version accepted, for example in a CI check, call [BreakingChanges].
+To compare what two unrelated schemas allow rather than their keywords, call
+[Subsumes].
+To detect whether a schema has changed at all, or to use schemas as cache
+keys, compare their [Schema.Canonical] encodings or [Schema.Hash] digests,
+which ignore differences that do not affect meaning, such as key order.
+To write a schema whose bytes are stable, for golden tests or version control,
+call [Schema.MarshalSorted], which sorts the keys of every object.
+To simplify a schema for display, [Merge] folds the branches of its "allOf"
+keywords into the schemas that contain them.
+To analyze or rewrite a schema, visit it and its subschemas with
+[Schema.Walk].
+
+For property-based testing of code that consumes instances, call
+[Resolved.RandomInstance] to generate random valid instances, or instances at
+the bounds of the schema or that fail validation.
+
 # Inference
 
 The [For] function returns a [Schema] describing the given Go type.
@@ -53,6 +114,8 @@ Each field in the struct becomes a property of the schema.
 The values of "json" tags are respected: the field's property name is taken
 from the tag, and fields omitted from the JSON are omitted from the schema as
 well.
+Fields without a name in their tag are named by [ForOptions.NameFunc], such
+as [SnakeCase], if it is set.
 For example, `jsonschema.For[Player]()` returns this schema:
 
 	{
@@ -76,6 +139,30 @@ Use the "jsonschema" struct tag to provide a description for the property:
 		Scores []int  `json:"scores" jsonschema:"scores of player's games"`
 	}
 
+The tag can also constrain the property, with a list of keyword settings
+that begins with a keyword; see [For] for the full list:
+
+	type Player struct {
+		Name  string `json:"name" jsonschema:"description=player name,minLength=1"`
+		Level int    `json:"level" jsonschema:"minimum=1,maximum=10,examples=1|5"`
+	}
+
+Flags such as readOnly and deprecated can be written alone:
+
+	type Player struct {
+		ID string `json:"id" jsonschema:"readOnly,description=the player's ID"`
+	}
+
+Fields whose json tag has "omitempty" or "omitzero" are optional; the
+"required" setting overrides this, as in `jsonschema:"required=true"`.
+
+To describe properties with the doc comments of their fields instead, generate
+a map of descriptions with the jsonschemadoc command and pass it to For in
+[ForOptions.FieldDescriptions].
+
+To describe a struct type that appears in many places only once, under
+"$defs", set [ForOptions.UseDefs].
+
 # Deviations from the specification
 
 Regular expressions are processed with Go's regexp package, which differs
@@ -83,13 +170,15 @@ from ECMA 262, most significantly in not supporting back-references.
 See [this table of differences] for more.
 
 The "format" keyword described in [section 7 of the validation spec] is recorded
-in the Schema, but is ignored during validation.
+in the Schema, but is ignored during validation unless
+[ResolveOptions.AssertFormats] is set.
 It does not even produce [annotations].
 Use the "pattern" keyword instead: it will work more reliably across JSON Schema
 implementations. See [learnjsonschema.com] for more recommendations about "format".
 
 The content keywords described in [section 8 of the validation spec]
-are recorded in the schema, but ignored during validation.
+are recorded in the schema, but ignored during validation unless
+[ResolveOptions.AssertContent] is set.
 
 # Controlling behavior changes
 
@@ -0,0 +1,170 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// TestDraft04And06Schema tests draft-04 and draft-06 specific schema behaviors.
+func TestDraft04And06Schema(t *testing.T) {
+	const (
+		draft4 = `"$schema": "http://json-schema.org/draft-04/schema#"`
+		draft6 = `"$schema": "http://json-schema.org/draft-06/schema#"`
+		draft7 = `"$schema": "http://json-schema.org/draft-07/schema#"`
+	)
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		valid  bool
+	}{
+		{
+			name:   "draft-04 exclusiveMinimum true",
+			schema: `{` + draft4 + `, "minimum": 5, "exclusiveMinimum": true}`,
+			data:   `5`,
+			valid:  false,
+		},
+		{
+			name:   "draft-04 exclusiveMinimum false",
+			schema: `{` + draft4 + `, "minimum": 5, "exclusiveMinimum": false}`,
+			data:   `5`,
+			valid:  true,
+		},
+		{
+			// Without a bound, a boolean has no effect.
+			name:   "draft-04 exclusiveMinimum without minimum",
+			schema: `{` + draft4 + `, "exclusiveMinimum": true}`,
+			data:   `5`,
+			valid:  true,
+		},
+		{
+			name:   "draft-04 exclusiveMaximum true",
+			schema: `{` + draft4 + `, "maximum": 5, "exclusiveMaximum": true}`,
+			data:   `4.5`,
+			valid:  true,
+		},
+		{
+			name:   "draft-04 id",
+			schema: `{` + draft4 + `, "id": "http://example.com/root.json", "definitions": {"s": {"id": "s.json", "type": "string"}}, "properties": {"a": {"$ref": "s.json"}}}`,
+			data:   `{"a": 1}`,
+			valid:  false,
+		},
+		{
+			name:   "draft-04 $ref siblings are ignored",
+			schema: `{` + draft4 + `, "definitions": {"s": {"type": "string"}}, "properties": {"a": {"$ref": "#/definitions/s", "maxLength": 1}}}`,
+			data:   `{"a": "abc"}`,
+			valid:  true,
+		},
+		{
+			name:   "draft-06 exclusiveMinimum number",
+			schema: `{` + draft6 + `, "exclusiveMinimum": 5}`,
+			data:   `5`,
+			valid:  false,
+		},
+		{
+			// Many schemas labeled draft-07 still use the draft-04 form.
+			name:   "draft-07 boolean exclusiveMaximum",
+			schema: `{` + draft7 + `, "properties": {"n": {"maximum": 5, "exclusiveMaximum": true}}}`,
+			data:   `{"n": 5}`,
+			valid:  false,
+		},
+		{
+			name:   "draft-06 const",
+			schema: `{` + draft6 + `, "const": 1}`,
+			data:   `2`,
+			valid:  false,
+		},
+		{
+			name:   "draft-06 has no if",
+			schema: `{` + draft6 + `, "if": true, "then": false}`,
+			data:   `1`,
+			valid:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("failed to unmarshal schema: %v", err)
+			}
+			var data any
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatalf("failed to unmarshal data: %v", err)
+			}
+			rs, err := schema.Resolve(nil)
+			if err != nil {
+				t.Fatalf("failed to resolve schema: %v", err)
+			}
+			err = rs.Validate(data)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid, got error: %v", err)
+			} else if !tt.valid && err == nil {
+				t.Error("expected invalid, got nil error")
+			}
+		})
+	}
+}
+
+func TestDraft04ExclusiveUnmarshal(t *testing.T) {
+	// The boolean forms are kept as they are, so schemas round-trip.
+	for _, tt := range []struct {
+		in   string
+		want *Schema
+	}{
+		{`{"exclusiveMinimum":true,"minimum":1}`, &Schema{Minimum: Ptr(1.0), ExclusiveMinimumBool: Ptr(true)}},
+		{`{"exclusiveMinimum":false,"minimum":1}`, &Schema{Minimum: Ptr(1.0), ExclusiveMinimumBool: Ptr(false)}},
+		{`{"exclusiveMaximum":true,"maximum":1}`, &Schema{Maximum: Ptr(1.0), ExclusiveMaximumBool: Ptr(true)}},
+		{`{"exclusiveMaximum":2}`, &Schema{ExclusiveMaximum: Ptr(2.0)}},
+		{`{"exclusiveMinimum":true}`, &Schema{ExclusiveMinimumBool: Ptr(true)}},
+		{`{"items":{"exclusiveMinimum":true,"minimum":0}}`, &Schema{Items: &Schema{Minimum: Ptr(0.0), ExclusiveMinimumBool: Ptr(true)}}},
+	} {
+		var got Schema
+		if err := json.Unmarshal([]byte(tt.in), &got); err != nil {
+			t.Fatalf("%s: %v", tt.in, err)
+		}
+		if diff := cmp.Diff(tt.want, &got); diff != "" {
+			t.Errorf("%s: mismatch (-want, +got):\n%s", tt.in, diff)
+		}
+		data, err := json.Marshal(&got)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != tt.in {
+			t.Errorf("%s: marshaled as %s", tt.in, data)
+		}
+	}
+
+	if _, err := json.Marshal(&Schema{ExclusiveMinimum: Ptr(1.0), ExclusiveMinimumBool: Ptr(true)}); err == nil {
+		t.Error("marshaling both forms of exclusiveMinimum: got nil error, want error")
+	}
+}
+
+func TestExclusiveBoolDrafts(t *testing.T) {
+	for _, tt := range []struct {
+		schema  string
+		wantErr bool
+	}{
+		{`{"$schema": "http://json-schema.org/draft-04/schema#", "exclusiveMinimum": true}`, false},
+		{`{"$schema": "http://json-schema.org/draft-07/schema#", "maximum": 1, "exclusiveMaximum": true}`, false},
+		{`{"$schema": "https://json-schema.org/draft/2019-09/schema", "minimum": 1, "exclusiveMinimum": true}`, true},
+		{`{"$schema": "https://json-schema.org/draft/2020-12/schema", "items": {"maximum": 1, "exclusiveMaximum": false}}`, true},
+		// Without $schema, the draft is 2020-12.
+		{`{"minimum": 1, "exclusiveMinimum": true}`, true},
+	} {
+		var s Schema
+		if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		_, err := s.Resolve(nil)
+		if gotErr := err != nil; gotErr != tt.wantErr {
+			t.Errorf("%s: got error %v, want error: %t", tt.schema, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+)
+
+// TestDraft2019Schema tests draft 2019-09 specific schema behaviors.
+func TestDraft2019Schema(t *testing.T) {
+	const version = `"$schema": "https://json-schema.org/draft/2019-09/schema"`
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		valid  bool
+	}{
+		{
+			name:   "items array",
+			schema: `{` + version + `, "items": [{"type": "string"}, {"type": "integer"}]}`,
+			data:   `["a", 1, true]`,
+			valid:  true,
+		},
+		{
+			name:   "items array mismatch",
+			schema: `{` + version + `, "items": [{"type": "string"}, {"type": "integer"}]}`,
+			data:   `["a", "b"]`,
+			valid:  false,
+		},
+		{
+			name:   "additionalItems",
+			schema: `{` + version + `, "items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`,
+			data:   `["a", 1, 2]`,
+			valid:  true,
+		},
+		{
+			name:   "additionalItems mismatch",
+			schema: `{` + version + `, "items": [{"type": "string"}], "additionalItems": {"type": "integer"}}`,
+			data:   `["a", 1, "b"]`,
+			valid:  false,
+		},
+		{
+			name:   "unevaluatedItems after items array",
+			schema: `{` + version + `, "items": [{"type": "string"}], "unevaluatedItems": false}`,
+			data:   `["a", "b"]`,
+			valid:  false,
+		},
+		{
+			name:   "dependentRequired",
+			schema: `{` + version + `, "dependentRequired": {"a": ["b"]}}`,
+			data:   `{"a": 1}`,
+			valid:  false,
+		},
+		{
+			name:   "dependentSchemas",
+			schema: `{` + version + `, "dependentSchemas": {"a": {"required": ["b"]}}}`,
+			data:   `{"a": 1, "b": 2}`,
+			valid:  true,
+		},
+		{
+			name:   "$ref siblings apply",
+			schema: `{` + version + `, "$defs": {"s": {"type": "string"}}, "$ref": "#/$defs/s", "maxLength": 1}`,
+			data:   `"ab"`,
+			valid:  false,
+		},
+		{
+			name:   "$anchor",
+			schema: `{` + version + `, "$defs": {"s": {"$anchor": "str", "type": "string"}}, "$ref": "#str"}`,
+			data:   `1`,
+			valid:  false,
+		},
+		{
+			name:   "$recursiveRef without $recursiveAnchor is a plain ref",
+			schema: `{` + version + `, "properties": {"child": {"$recursiveRef": "#"}}, "required": ["x"]}`,
+			data:   `{"x": 1, "child": {}}`,
+			valid:  false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatalf("failed to unmarshal schema: %v", err)
+			}
+			var data any
+			if err := json.Unmarshal([]byte(tt.data), &data); err != nil {
+				t.Fatalf("failed to unmarshal data: %v", err)
+			}
+			rs, err := schema.Resolve(nil)
+			if err != nil {
+				t.Fatalf("failed to resolve schema: %v", err)
+			}
+			err = rs.Validate(data)
+			if tt.valid && err != nil {
+				t.Errorf("expected valid, got error: %v", err)
+			} else if !tt.valid && err == nil {
+				t.Error("expected invalid, got nil error")
+			}
+		})
+	}
+}
+
+// TestDraft2019RecursiveRef tests the extensible tree example from the
+// draft 2019-09 specification.
+// See https://json-schema.org/draft/2019-09/json-schema-core#recursive-example.
+func TestDraft2019RecursiveRef(t *testing.T) {
+	schemas := map[string]string{
+		"https://example.com/tree": `{
+			"$schema": "https://json-schema.org/draft/2019-09/schema",
+			"$id": "https://example.com/tree",
+			"$recursiveAnchor": true,
+			"type": "object",
+			"properties": {
+				"data": true,
+				"children": {
+					"type": "array",
+					"items": { "$recursiveRef": "#" }
+				}
+			}
+		}`,
+		"https://example.com/strict-tree": `{
+			"$schema": "https://json-schema.org/draft/2019-09/schema",
+			"$id": "https://example.com/strict-tree",
+			"$recursiveAnchor": true,
+			"$ref": "tree",
+			"unevaluatedProperties": false
+		}`,
+	}
+	loader := func(uri *url.URL) (*Schema, error) {
+		var s Schema
+		if err := json.Unmarshal([]byte(schemas[uri.String()]), &s); err != nil {
+			return nil, err
+		}
+		return &s, nil
+	}
+	resolve := func(uri string) *Resolved {
+		t.Helper()
+		s, err := loader(&url.URL{Scheme: "https", Host: "example.com", Path: uri})
+		if err != nil {
+			t.Fatal(err)
+		}
+		rs, err := s.Resolve(&ResolveOptions{Loader: loader})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return rs
+	}
+	tree := resolve("/tree")
+	strict := resolve("/strict-tree")
+
+	// A misspelled property deep in the tree.
+	var instance any
+	if err := json.Unmarshal([]byte(`{"children": [{"daat": 1}]}`), &instance); err != nil {
+		t.Fatal(err)
+	}
+	if err := tree.Validate(instance); err != nil {
+		t.Errorf("tree: %v", err)
+	}
+	// The $recursiveRef in tree refers to strict-tree, so the misspelling is caught.
+	if err := strict.Validate(instance); err == nil {
+		t.Error("strict-tree: got nil error, want error")
+	}
+}
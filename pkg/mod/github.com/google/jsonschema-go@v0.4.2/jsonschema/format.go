@@ -0,0 +1,116 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements assertions for the "format" keyword.
+
+package jsonschema
+
+import (
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// formatCheckers maps the formats that can be asserted to functions that
+// report whether a string has the format.
+// See https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-7.3.
+var formatCheckers = map[string]func(string) bool{
+	"date-time":     isDateTime,
+	"date":          isDate,
+	"time":          isTime,
+	"email":         isEmail,
+	"hostname":      isHostname,
+	"ipv4":          isIPv4,
+	"ipv6":          isIPv6,
+	"uri":           isURI,
+	"uri-reference": isURIReference,
+	"uuid":          isUUID,
+	"regex":         isRegex,
+}
+
+// isDateTime reports whether s is an RFC 3339 date-time.
+func isDateTime(s string) bool {
+	// time.Parse accepts only an upper-case "T" and "Z", but RFC 3339 allows
+	// lower case.
+	_, err := time.Parse(time.RFC3339, strings.ToUpper(s))
+	return err == nil
+}
+
+// isDate reports whether s is an RFC 3339 full-date.
+func isDate(s string) bool {
+	_, err := time.Parse(time.DateOnly, s)
+	return err == nil
+}
+
+// isTime reports whether s is an RFC 3339 full-time, which requires a time
+// zone offset.
+func isTime(s string) bool {
+	return isDateTime("1970-01-01T" + s)
+}
+
+// isEmail reports whether s is an RFC 5321 mailbox, without a display name.
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	return err == nil && addr.Address == s
+}
+
+// isHostname reports whether s is an RFC 1123 host name.
+func isHostname(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if len(s) == 0 || len(s) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(s, ".") {
+		if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return false
+		}
+		for _, c := range []byte(label) {
+			if !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// isIPv4 reports whether s is an IPv4 address in dotted-quad notation.
+func isIPv4(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is4()
+}
+
+// isIPv6 reports whether s is an RFC 4291 IPv6 address, without a zone.
+func isIPv6(s string) bool {
+	addr, err := netip.ParseAddr(s)
+	return err == nil && addr.Is6() && addr.Zone() == ""
+}
+
+// isURI reports whether s is an absolute RFC 3986 URI.
+func isURI(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && u.IsAbs()
+}
+
+// isURIReference reports whether s is an RFC 3986 URI or relative reference.
+func isURIReference(s string) bool {
+	_, err := url.Parse(s)
+	return err == nil
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// isUUID reports whether s is an RFC 4122 UUID.
+func isUUID(s string) bool {
+	return uuidRegexp.MatchString(s)
+}
+
+// isRegex reports whether s is a regular expression. Like the "pattern"
+// keyword, it uses Go regexp syntax rather than ECMA-262.
+func isRegex(s string) bool {
+	_, err := regexp.Compile(s)
+	return err == nil
+}
@@ -7,6 +7,7 @@
 package jsonschema
 
 import (
+	"encoding/json"
 	"fmt"
 	"log/slog"
 	"maps"
@@ -16,7 +17,10 @@ import (
 	"reflect"
 	"regexp"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 const debugEnv = "JSONSCHEMAGODEBUG"
@@ -38,6 +42,83 @@ type ForOptions struct {
 	// in [For]'s documentation.
 	// PropertyOrder defined in these schemas will not be used in [For] or [ForType].
 	TypeSchemas map[reflect.Type]*Schema
+
+	// FieldDescriptions maps struct types to descriptions of their fields,
+	// keyed by Go field name. A description is used for the field's property
+	// unless the field's jsonschema tag provides one.
+	// The jsonschemadoc command (github.com/google/jsonschema-go/cmd/jsonschemadoc)
+	// generates this map from the doc comments of struct fields.
+	FieldDescriptions map[reflect.Type]map[string]string
+
+	// MapKeyPatterns maps map types to regular expressions that their keys
+	// must match. For a map type in this map, For produces a schema whose
+	// "patternProperties" holds the schema of the map's values under the
+	// pattern, and whose "additionalProperties" is false, instead of one whose
+	// "additionalProperties" is the value schema.
+	// Remember to anchor a pattern that must match the entire key.
+	MapKeyPatterns map[reflect.Type]string
+
+	// EnumValues maps named types to their allowed values, which must be of
+	// the type, typically its declared constants. For produces an "enum"
+	// schema for a type in this map, with each value as it marshals to JSON.
+	// So a type whose values marshal as strings, by implementing
+	// [encoding.TextMarshaler] or [json.Marshaler] with the help of its String
+	// method, has an enum of strings.
+	EnumValues map[reflect.Type][]any
+
+	// Implementations maps interface types to their implementations.
+	// For produces a "oneOf" schema for an interface type in this map,
+	// instead of an unrestricted one.
+	Implementations map[reflect.Type]*Implementations
+
+	// Dialect is the dialect of the schema. The zero value is JSON Schema
+	// draft 2020-12. The only other supported value is [DialectOpenAPI31].
+	Dialect string
+
+	// NameFunc, if non-nil, computes the property name of a struct field whose
+	// json tag does not provide one from the field's Go name. [CamelCase] and
+	// [SnakeCase] are common choices. Since encoding/json uses the Go name for
+	// such a field, use NameFunc only if values are encoded by other means
+	// that follow the same convention.
+	NameFunc func(fieldName string) string
+
+	// If UseDefs is true, each named struct type that the type passed to For
+	// refers to is described once, under "$defs" in the result, and referred
+	// to with "$ref" wherever it appears, instead of being described in full
+	// at every appearance. This keeps the schemas of types that use the same
+	// struct type in many places compact. It also allows recursive struct
+	// types, which are otherwise an error.
+	UseDefs bool
+
+	defs *typeDefs // for UseDefs; set by forRoot
+}
+
+// DialectOpenAPI31 is the [ForOptions.Dialect] for schemas in OpenAPI 3.1
+// documents. OpenAPI 3.1 schemas are JSON Schema draft 2020-12 schemas, but
+// consumers of OpenAPI documents expect some things to be written as the
+// OpenAPI specification writes them. For this dialect, For
+//   - lists the "null" type last, as in ["string", "null"];
+//   - replaces the OpenAPI 3.0 keywords "nullable" and "example", which may
+//     appear in [ForOptions.TypeSchemas], with a "null" type and "examples";
+//   - moves a "$ref" that has sibling keywords other than annotations into an
+//     "allOf", since many consumers ignore the siblings of a "$ref".
+const DialectOpenAPI31 = "https://spec.openapis.org/oas/3.1/dialect/base"
+
+// Implementations describes the concrete types that an interface value can
+// hold, for [ForOptions.Implementations].
+type Implementations struct {
+	// Types are the concrete types. Each must implement the interface.
+	// A pointer type is described by the schema of its element type.
+	Types []reflect.Type
+
+	// Discriminator, if non-empty, is the name of a property that identifies
+	// the type of a value. The schema of each type, which must be a struct
+	// type, requires the property to have the type's discriminator value.
+	Discriminator string
+
+	// Values are the discriminator values of the types, in the same order.
+	// If Values is nil, the names of the types are used.
+	Values []string
 }
 
 // For constructs a JSON schema object for the given type argument.
@@ -54,11 +135,18 @@ type ForOptions struct {
 //   - Slices and arrays have schema type "array", and a corresponding schema
 //     for items.
 //   - Maps with string key have schema type "object", and corresponding
-//     schema for additionalProperties.
+//     schema for additionalProperties, or for patternProperties if the map type
+//     is in [ForOptions.MapKeyPatterns].
+//   - Interface types in [ForOptions.Implementations] have a "oneOf" schema
+//     of their implementations.
+//   - Named types in [ForOptions.EnumValues] have an "enum" schema of their
+//     allowed values.
 //   - Structs have schema type "object", and disallow additionalProperties.
 //     Their properties are derived from exported struct fields, using the
-//     struct field JSON name. Fields that are marked "omitempty" or "omitzero" are
-//     considered optional; all other fields become required properties.
+//     struct field JSON name, or [ForOptions.NameFunc] of the Go name if the
+//     field's json tag does not name it. Fields that are marked "omitempty" or "omitzero" are
+//     considered optional; all other fields become required properties. The
+//     "required" setting of a jsonschema tag overrides this; see below.
 //     For structs, the PropertyOrder will be set to the field order.
 //   - Some types in the standard library that implement json.Marshaler
 //     translate to schemas that match the values to which they marshal.
@@ -79,14 +167,36 @@ type ForOptions struct {
 // A jsonschema tag on a field is used as the description for the corresponding property.
 // For future compatibility, descriptions must not start with "WORD=", where WORD is a
 // sequence of non-whitespace characters.
+//
+// A tag that starts with "KEYWORD=", where KEYWORD is one of the keywords below,
+// or with one of the flags required, readOnly, writeOnly or deprecated, is
+// instead a comma-separated list of keyword settings for the property:
+//
+//	description=TEXT   the description of the property
+//	minimum=N          the minimum of a number or integer
+//	maximum=N          the maximum of a number or integer
+//	minLength=N        the minimum length of a string
+//	maxLength=N        the maximum length of a string
+//	pattern=RE         a regular expression that a string must match
+//	format=NAME        the format of a string, such as "uuid" or "date-time"
+//	enum=V1|V2|...     the allowed values, separated by '|'
+//	examples=V1|V2|... example values, separated by '|'
+//	required[=BOOL]    whether the property is required, overriding the json tag
+//	readOnly[=BOOL]    whether the property is read-only
+//	writeOnly[=BOOL]   whether the property is write-only
+//	deprecated[=BOOL]  whether the property is deprecated
+//
+// For example:
+//
+//	Count int    `jsonschema:"minimum=1,maximum=10"`
+//	ID    string `jsonschema:"format=uuid,description=the user's ID"`
+//	Rev   int    `jsonschema:"readOnly,description=the revision"`
+//
+// A value containing commas must be enclosed in single quotes, as in
+// pattern='^[a-z]{1,8}$'. Enum and example values are converted to the type of
+// the field.
 func For[T any](opts *ForOptions) (*Schema, error) {
-	if opts == nil {
-		opts = &ForOptions{}
-	}
-	schemas := maps.Clone(initialSchemaMap)
-	// Add types from the options. They override the default ones.
-	maps.Copy(schemas, opts.TypeSchemas)
-	s, err := forType(reflect.TypeFor[T](), map[reflect.Type]bool{}, opts.IgnoreInvalidTypes, schemas)
+	s, err := forRoot(reflect.TypeFor[T](), opts)
 	if err != nil {
 		var z T
 		return nil, fmt.Errorf("For[%T](): %w", z, err)
@@ -96,15 +206,37 @@ func For[T any](opts *ForOptions) (*Schema, error) {
 
 // ForType is like [For], but takes a [reflect.Type]
 func ForType(t reflect.Type, opts *ForOptions) (*Schema, error) {
+	s, err := forRoot(t, opts)
+	if err != nil {
+		return nil, fmt.Errorf("ForType(%s): %w", t, err)
+	}
+	return s, nil
+}
+
+// forRoot returns the schema for t, the type passed to For or ForType.
+func forRoot(t reflect.Type, opts *ForOptions) (*Schema, error) {
 	if opts == nil {
 		opts = &ForOptions{}
 	}
 	schemas := maps.Clone(initialSchemaMap)
 	// Add types from the options. They override the default ones.
 	maps.Copy(schemas, opts.TypeSchemas)
-	s, err := forType(t, map[reflect.Type]bool{}, opts.IgnoreInvalidTypes, schemas)
+	if opts.UseDefs {
+		// Copy the options, so that concurrent calls with the same options
+		// have their own definitions.
+		o := *opts
+		o.defs = newTypeDefs(t)
+		opts = &o
+	}
+	s, err := forType(t, map[reflect.Type]bool{}, opts, schemas)
 	if err != nil {
-		return nil, fmt.Errorf("ForType(%s): %w", t, err)
+		return nil, err
+	}
+	if d := opts.defs; d != nil && s != nil && len(d.schemas) > 0 {
+		s.Defs = d.schemas
+	}
+	if err := applyDialect(s, opts.Dialect); err != nil {
+		return nil, err
 	}
 	return s, nil
 }
@@ -114,7 +246,8 @@ func f64Ptr(f float64) *float64 {
 	return &f
 }
 
-func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas map[reflect.Type]*Schema) (*Schema, error) {
+func forType(t reflect.Type, seen map[reflect.Type]bool, opts *ForOptions, schemas map[reflect.Type]*Schema) (*Schema, error) {
+	ignore := opts.IgnoreInvalidTypes
 	// Follow pointers: the schema for *T is almost the same as for T, except that
 	// an explicit JSON "null" is allowed for the pointer.
 	allowNull := false
@@ -123,6 +256,21 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 		t = t.Elem()
 	}
 
+	if d := opts.defs; d != nil && t.Kind() == reflect.Struct && t.Name() != "" && schemas[t] == nil && opts.EnumValues[t] == nil {
+		if t == d.expand {
+			d.expand = nil
+		} else {
+			s, err := d.ref(t, seen, opts, schemas)
+			if err != nil {
+				return nil, err
+			}
+			if allowNull {
+				s = &Schema{AnyOf: []*Schema{{Type: "null"}, s}}
+			}
+			return s, nil
+		}
+	}
+
 	// Check for cycles
 	// User defined types have a name, so we can skip those that are natively defined
 	if t.Name() != "" {
@@ -133,6 +281,21 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 		defer delete(seen, t)
 	}
 
+	if vals, ok := opts.EnumValues[t]; ok {
+		s, err := enumSchema(t, vals)
+		if err != nil {
+			return nil, err
+		}
+		if allowNull {
+			s.Enum = append(s.Enum, nil)
+			if s.Type != "" {
+				s.Types = []string{"null", s.Type}
+				s.Type = ""
+			}
+		}
+		return s, nil
+	}
+
 	if s := schemas[t]; s != nil {
 		cloned := s.CloneSchemas()
 		if os.Getenv(debugEnv) != "typeschemasnull=1" && allowNull {
@@ -196,7 +359,13 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 		s.Type = "number"
 
 	case reflect.Interface:
-		// Unrestricted
+		if impls := opts.Implementations[t]; impls != nil {
+			s.OneOf, err = implSchemas(t, impls, seen, opts, schemas)
+			if err != nil {
+				return nil, err
+			}
+		}
+		// Otherwise unrestricted.
 
 	case reflect.Map:
 		if t.Key().Kind() != reflect.String {
@@ -208,14 +377,24 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 		if t.Key().Kind() != reflect.String {
 		}
 		s.Type = "object"
-		s.AdditionalProperties, err = forType(t.Elem(), seen, ignore, schemas)
+		var vs *Schema
+		vs, err = forType(t.Elem(), seen, opts, schemas)
 		if err != nil {
 			return nil, fmt.Errorf("computing map value schema: %v", err)
 		}
-		if ignore && s.AdditionalProperties == nil {
+		if ignore && vs == nil {
 			// Ignore if the element type is invalid.
 			return nil, nil
 		}
+		if pat, ok := opts.MapKeyPatterns[t]; ok {
+			if _, err := regexp.Compile(pat); err != nil {
+				return nil, fmt.Errorf("key pattern for %v: %w", t, err)
+			}
+			s.PatternProperties = map[string]*Schema{pat: vs}
+			s.AdditionalProperties = falseSchema()
+		} else {
+			s.AdditionalProperties = vs
+		}
 
 	case reflect.Slice, reflect.Array:
 		if os.Getenv(debugEnv) != "typeschemasnull=1" && t.Kind() == reflect.Slice {
@@ -223,7 +402,7 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 		} else {
 			s.Type = "array"
 		}
-		itemsSchema, err := forType(t.Elem(), seen, ignore, schemas)
+		itemsSchema, err := forType(t.Elem(), seen, opts, schemas)
 		if err != nil {
 			return nil, fmt.Errorf("computing element schema: %v", err)
 		}
@@ -319,7 +498,10 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 			if info.omit {
 				continue
 			}
-			fs, err := forType(field.Type, seen, ignore, schemas)
+			if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "" && opts.NameFunc != nil {
+				info.name = opts.NameFunc(field.Name)
+			}
+			fs, err := forType(field.Type, seen, opts, schemas)
 			if err != nil {
 				return nil, err
 			}
@@ -327,20 +509,37 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 				// Skip fields of invalid type.
 				continue
 			}
+			var required *bool // from the jsonschema tag
+			if d, ok := opts.FieldDescriptions[fieldOwner(t, field)][field.Name]; ok {
+				fs.Description = d
+			}
 			if tag, ok := field.Tag.Lookup("jsonschema"); ok {
 				if tag == "" {
 					return nil, fmt.Errorf("empty jsonschema tag on struct field %s.%s", t, field.Name)
 				}
-				if disallowedPrefixRegexp.MatchString(tag) {
-					return nil, fmt.Errorf("tag must not begin with 'WORD=': %q", tag)
+				first, _, _ := strings.Cut(tag, ",")
+				if disallowedPrefixRegexp.MatchString(tag) || tagFlags[first] {
+					if kw, _, _ := strings.Cut(first, "="); !tagKeywords[kw] && !tagFlags[kw] {
+						return nil, fmt.Errorf("tag must not begin with 'WORD=': %q", tag)
+					}
+					if required, err = applyTag(fs, tag); err != nil {
+						return nil, fmt.Errorf("jsonschema tag on struct field %s.%s: %w", t, field.Name, err)
+					}
+				} else {
+					fs.Description = tag
 				}
-				fs.Description = tag
 			}
 			s.Properties[info.name] = fs
 
 			s.PropertyOrder = append(s.PropertyOrder, info.name)
 
-			if !info.settings["omitempty"] && !info.settings["omitzero"] {
+			// Fields that encoding/json may omit are optional, unless the
+			// jsonschema tag says otherwise.
+			isRequired := !info.settings["omitempty"] && !info.settings["omitzero"]
+			if required != nil {
+				isRequired = *required
+			}
+			if isRequired {
 				s.Required = append(s.Required, info.name)
 			}
 		}
@@ -380,6 +579,272 @@ func forType(t reflect.Type, seen map[reflect.Type]bool, ignore bool, schemas ma
 	return s, nil
 }
 
+// typeDefs holds the definitions of struct types for [ForOptions.UseDefs].
+type typeDefs struct {
+	names   map[reflect.Type]string // names of the types in schemas; "" for the root
+	used    map[string]bool         // names in use
+	schemas map[string]*Schema      // the definitions, by name
+	expand  reflect.Type            // the next struct type to describe in place
+}
+
+// newTypeDefs returns an empty typeDefs for the schema of root.
+func newTypeDefs(root reflect.Type) *typeDefs {
+	d := &typeDefs{
+		names:   map[reflect.Type]string{},
+		used:    map[string]bool{},
+		schemas: map[string]*Schema{},
+	}
+	for root.Kind() == reflect.Pointer {
+		root = root.Elem()
+	}
+	// The root type is described in place, and a reference to it from one
+	// of its fields refers to the whole schema.
+	d.names[root] = ""
+	d.expand = root
+	return d
+}
+
+// ref returns a reference to the definition of the struct type t, adding the
+// definition if there is none.
+func (d *typeDefs) ref(t reflect.Type, seen map[reflect.Type]bool, opts *ForOptions, schemas map[reflect.Type]*Schema) (*Schema, error) {
+	name, ok := d.names[t]
+	if !ok {
+		// Types with the same name in different packages get distinct names.
+		name = t.Name()
+		for i := 2; d.used[name]; i++ {
+			name = t.Name() + strconv.Itoa(i)
+		}
+		// Record the name first, so that recursive references to t find it.
+		d.names[t] = name
+		d.used[name] = true
+		d.expand = t
+		s, err := forType(t, seen, opts, schemas)
+		if err != nil {
+			return nil, err
+		}
+		d.schemas[name] = s
+	}
+	if name == "" {
+		return &Schema{Ref: "#"}, nil
+	}
+	return &Schema{Ref: "#/$defs/" + escapeJSONPointerSegment(name)}, nil
+}
+
+// enumSchema returns the schema for the type t whose allowed values are vals.
+func enumSchema(t reflect.Type, vals []any) (*Schema, error) {
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("no enum values for type %v", t)
+	}
+	s := new(Schema)
+	var jsonType string
+	for i, v := range vals {
+		if reflect.TypeOf(v) != t {
+			return nil, fmt.Errorf("enum value %v for type %v has type %T", v, t, v)
+		}
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("enum value %v for type %v: %w", v, t, err)
+		}
+		var jv any
+		if err := json.Unmarshal(data, &jv); err != nil {
+			return nil, err
+		}
+		var vt string
+		switch jv.(type) {
+		case string:
+			vt = "string"
+		case float64:
+			vt = "number"
+		case bool:
+			vt = "boolean"
+		default:
+			return nil, fmt.Errorf("enum value %v for type %v marshals to %s, which is not a string, number or boolean", v, t, data)
+		}
+		if i > 0 && vt != jsonType {
+			return nil, fmt.Errorf("enum values for type %v marshal to both %s and %s", t, jsonType, vt)
+		}
+		jsonType = vt
+		s.Enum = append(s.Enum, jv)
+	}
+	s.Type = jsonType
+	if jsonType == "number" {
+		switch t.Kind() {
+		case reflect.Float32, reflect.Float64:
+		default:
+			s.Type = "integer"
+		}
+	}
+	return s, nil
+}
+
+// implSchemas returns the schemas of the implementations of the interface
+// type t.
+func implSchemas(t reflect.Type, impls *Implementations, seen map[reflect.Type]bool, opts *ForOptions, schemas map[reflect.Type]*Schema) ([]*Schema, error) {
+	if len(impls.Types) == 0 {
+		return nil, fmt.Errorf("no implementations for interface %v", t)
+	}
+	if impls.Values != nil && len(impls.Values) != len(impls.Types) {
+		return nil, fmt.Errorf("interface %v: %d discriminator values for %d types", t, len(impls.Values), len(impls.Types))
+	}
+	var ss []*Schema
+	for i, it := range impls.Types {
+		if !it.Implements(t) {
+			return nil, fmt.Errorf("%v does not implement %v", it, t)
+		}
+		if it.Kind() == reflect.Pointer {
+			it = it.Elem()
+		}
+		if opts.defs != nil {
+			// The discriminator is added to the schema of the implementation
+			// itself, so it must not be a reference.
+			opts.defs.expand = it
+		}
+		is, err := forType(it, seen, opts, schemas)
+		if err != nil {
+			return nil, fmt.Errorf("implementation %v of %v: %w", it, t, err)
+		}
+		if is == nil {
+			continue // ignored invalid type
+		}
+		if d := impls.Discriminator; d != "" {
+			if it.Kind() != reflect.Struct {
+				return nil, fmt.Errorf("implementation %v of %v with discriminator %q is not a struct", it, t, d)
+			}
+			val := it.Name()
+			if impls.Values != nil {
+				val = impls.Values[i]
+			}
+			if is.Properties == nil {
+				is.Properties = map[string]*Schema{}
+			}
+			if is.Properties[d] == nil {
+				is.PropertyOrder = append([]string{d}, is.PropertyOrder...)
+			}
+			is.Properties[d] = &Schema{Type: "string", Const: Ptr[any](val)}
+			if !slices.Contains(is.Required, d) {
+				is.Required = append([]string{d}, is.Required...)
+			}
+		}
+		ss = append(ss, is)
+	}
+	return ss, nil
+}
+
+// applyDialect rewrites the inferred schema s for dialect.
+func applyDialect(s *Schema, dialect string) error {
+	switch dialect {
+	case "":
+	case DialectOpenAPI31:
+		// s may be nil if its type is ignored.
+		if s != nil {
+			toOpenAPI31(s)
+		}
+	default:
+		return fmt.Errorf("unknown dialect %q", dialect)
+	}
+	return nil
+}
+
+// toOpenAPI31 rewrites s and its subschemas as described at [DialectOpenAPI31].
+func toOpenAPI31(root *Schema) {
+	// Collect the schemas first, since we may add some.
+	for _, s := range slices.Collect(root.all()) {
+		if v, ok := s.Extra["nullable"]; ok {
+			delete(s.Extra, "nullable")
+			if v == true && s.Type != "" {
+				s.Types = []string{s.Type, "null"}
+				s.Type = ""
+			} else if v == true && len(s.Types) > 0 && !slices.Contains(s.Types, "null") {
+				s.Types = append(s.Types, "null")
+			}
+		}
+		if v, ok := s.Extra["example"]; ok {
+			delete(s.Extra, "example")
+			if s.Examples == nil {
+				s.Examples = []any{v}
+			}
+		}
+		if len(s.Extra) == 0 {
+			s.Extra = nil
+		}
+		if i := slices.Index(s.Types, "null"); i >= 0 {
+			s.Types = append(slices.Delete(s.Types, i, i+1), "null")
+		}
+		if s.Ref != "" {
+			// Annotations may accompany a $ref in OpenAPI.
+			c := *s
+			c.Ref, c.Title, c.Description, c.Examples = "", "", "", nil
+			c.Default, c.Deprecated, c.ReadOnly, c.WriteOnly = nil, false, false, false
+			if !reflect.ValueOf(c).IsZero() {
+				s.AllOf = append([]*Schema{{Ref: s.Ref}}, s.AllOf...)
+				s.Ref = ""
+			}
+		}
+	}
+}
+
+// CamelCase returns the Go name name in camel case, for [ForOptions.NameFunc].
+// The first word is in lower case, and the rest are unchanged, so "UserID"
+// becomes "userID" and "HTTPServer" becomes "httpServer".
+func CamelCase(name string) string {
+	words := goNameWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	words[0] = strings.ToLower(words[0])
+	return strings.Join(words, "")
+}
+
+// SnakeCase returns the Go name name in snake case, for [ForOptions.NameFunc].
+// Words are in lower case and separated by underscores, so "UserID" becomes
+// "user_id" and "HTTPServer" becomes "http_server".
+func SnakeCase(name string) string {
+	words := goNameWords(name)
+	if len(words) == 0 {
+		return name
+	}
+	for i, w := range words {
+		words[i] = strings.ToLower(w)
+	}
+	return strings.Join(words, "_")
+}
+
+// goNameWords splits a Go name into words at underscores and changes of case.
+// A run of upper-case letters is a word, like "HTTP" in "HTTPServer", and
+// digits belong to the word before them.
+func goNameWords(name string) []string {
+	var words []string
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool { return r == '_' }) {
+		rs := []rune(part)
+		start := 0
+		for i := 1; i < len(rs); i++ {
+			if !unicode.IsUpper(rs[i]) {
+				continue
+			}
+			prev := rs[i-1]
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+				unicode.IsUpper(prev) && i+1 < len(rs) && unicode.IsLower(rs[i+1]) {
+				words = append(words, string(rs[start:i]))
+				start = i
+			}
+		}
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// fieldOwner returns the struct type that declares field, a visible field of
+// the struct type t. It differs from t for fields promoted from embedded structs.
+func fieldOwner(t reflect.Type, field reflect.StructField) reflect.Type {
+	for _, i := range field.Index[:len(field.Index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Pointer {
+			t = t.Elem()
+		}
+	}
+	return t
+}
+
 // initialSchemaMap holds types from the standard library that have MarshalJSON methods.
 var initialSchemaMap = make(map[reflect.Type]*Schema)
 
@@ -398,3 +863,196 @@ func init() {
 
 // Disallow jsonschema tag values beginning "WORD=", for future expansion.
 var disallowedPrefixRegexp = regexp.MustCompile("^[^ \t\n]*=")
+
+// tagKeywords are the keywords that can appear in a jsonschema struct tag.
+var tagKeywords = map[string]bool{
+	"description": true,
+	"minimum":     true,
+	"maximum":     true,
+	"minLength":   true,
+	"maxLength":   true,
+	"pattern":     true,
+	"format":      true,
+	"enum":        true,
+	"examples":    true,
+}
+
+// tagFlags are the boolean keywords that can appear in a jsonschema struct
+// tag. They can be written alone, meaning KEYWORD=true.
+var tagFlags = map[string]bool{
+	"required":   true,
+	"readOnly":   true,
+	"writeOnly":  true,
+	"deprecated": true,
+}
+
+// applyTag sets the keywords in tag, a list of KEYWORD=VALUE settings and
+// flags, on s.
+// The required setting applies to the struct containing the property, so
+// applyTag returns it, or nil if there is none.
+func applyTag(s *Schema, tag string) (required *bool, err error) {
+	settings, err := splitTag(tag)
+	if err != nil {
+		return nil, err
+	}
+	// The type of the property, ignoring "null" for pointers.
+	typ := s.Type
+	for _, t := range s.Types {
+		if t != "null" {
+			typ = t
+		}
+	}
+	// checkType reports an error if the keyword does not apply to the property.
+	checkType := func(kw string, types ...string) error {
+		if typ != "" && !slices.Contains(types, typ) {
+			return fmt.Errorf("%s does not apply to type %q", kw, typ)
+		}
+		return nil
+	}
+	seen := map[string]bool{}
+	for _, setting := range settings {
+		kw, val, ok := strings.Cut(setting, "=")
+		if !ok && tagFlags[kw] {
+			val, ok = "true", true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%q is not of the form KEYWORD=VALUE", setting)
+		}
+		if !tagKeywords[kw] && !tagFlags[kw] {
+			return nil, fmt.Errorf("unknown keyword %q", kw)
+		}
+		if seen[kw] {
+			return nil, fmt.Errorf("duplicate keyword %q", kw)
+		}
+		seen[kw] = true
+		switch kw {
+		case "description":
+			s.Description = val
+		case "minimum", "maximum":
+			if err := checkType(kw, "number", "integer"); err != nil {
+				return nil, err
+			}
+			f, err := strconv.ParseFloat(val, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", kw, err)
+			}
+			if kw == "minimum" {
+				s.Minimum = &f
+			} else {
+				s.Maximum = &f
+			}
+		case "minLength", "maxLength":
+			if err := checkType(kw, "string"); err != nil {
+				return nil, err
+			}
+			n, err := strconv.Atoi(val)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%s: %q is not a non-negative integer", kw, val)
+			}
+			if kw == "minLength" {
+				s.MinLength = &n
+			} else {
+				s.MaxLength = &n
+			}
+		case "pattern":
+			if err := checkType(kw, "string"); err != nil {
+				return nil, err
+			}
+			if _, err := regexp.Compile(val); err != nil {
+				return nil, fmt.Errorf("pattern: %w", err)
+			}
+			s.Pattern = val
+		case "format":
+			if err := checkType(kw, "string"); err != nil {
+				return nil, err
+			}
+			s.Format = val
+		case "enum", "examples":
+			var vals []any
+			for _, v := range strings.Split(val, "|") {
+				ev, err := enumValue(typ, v)
+				if err != nil {
+					return nil, fmt.Errorf("%s: %w", kw, err)
+				}
+				vals = append(vals, ev)
+			}
+			if kw == "enum" {
+				// As for EnumValues, a property that may be null, such as a
+				// pointer, allows null as well as the listed values.
+				if slices.Contains(s.Types, "null") {
+					vals = append(vals, nil)
+				}
+				s.Enum = vals
+			} else {
+				s.Examples = vals
+			}
+		case "required":
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("required: %q is not true or false", val)
+			}
+			required = &b
+		case "readOnly", "writeOnly", "deprecated":
+			b, err := strconv.ParseBool(val)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %q is not true or false", kw, val)
+			}
+			switch kw {
+			case "readOnly":
+				s.ReadOnly = b
+			case "writeOnly":
+				s.WriteOnly = b
+			default:
+				s.Deprecated = b
+			}
+		}
+	}
+	return required, nil
+}
+
+// enumValue converts the enum or examples value v in a struct tag to a value
+// of the JSON type typ.
+func enumValue(typ, v string) (any, error) {
+	switch typ {
+	case "integer":
+		n, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			return nil, err
+		}
+		return float64(n), nil
+	case "number":
+		return strconv.ParseFloat(v, 64)
+	case "boolean":
+		return strconv.ParseBool(v)
+	case "string", "":
+		return v, nil
+	default:
+		return nil, fmt.Errorf("values cannot be given for type %q", typ)
+	}
+}
+
+// splitTag splits a jsonschema tag at commas. A value enclosed in single
+// quotes may contain commas; the quotes are removed.
+func splitTag(tag string) ([]string, error) {
+	var (
+		settings []string
+		cur      strings.Builder
+		quoted   bool
+	)
+	for i := 0; i < len(tag); i++ {
+		c := tag[i]
+		switch {
+		case c == '\'' && (quoted || strings.HasSuffix(cur.String(), "=")):
+			quoted = !quoted
+		case c == ',' && !quoted:
+			settings = append(settings, cur.String())
+			cur.Reset()
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if quoted {
+		return nil, fmt.Errorf("unterminated quote in %q", tag)
+	}
+	return append(settings, cur.String()), nil
+}
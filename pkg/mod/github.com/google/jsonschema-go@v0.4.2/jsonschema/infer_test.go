@@ -10,6 +10,7 @@ import (
 	"math"
 	"math/big"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -594,6 +595,498 @@ func forErr[T any]() error {
 	return err
 }
 
+func TestForTagKeywords(t *testing.T) {
+	type S struct {
+		Count  int     `json:"count" jsonschema:"minimum=1,maximum=10"`
+		Ratio  float64 `json:"ratio" jsonschema:"description=a ratio,maximum=0.5"`
+		ID     string  `json:"id" jsonschema:"format=uuid"`
+		Name   *string `json:"name" jsonschema:"minLength=1,maxLength=8,pattern='^[a-z]{1,8}$'"`
+		Color  string  `json:"color" jsonschema:"enum=red|green|blue"`
+		Level  int     `json:"level" jsonschema:"enum=1|2|3"`
+		Tier   *int    `json:"tier" jsonschema:"enum=1|2"`
+		Plain  string  `json:"plain" jsonschema:"a description, not keywords"`
+		Others []int   `json:"others" jsonschema:"description='list, of things'"`
+	}
+	got, err := jsonschema.For[S](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*jsonschema.Schema{
+		"count":  {Type: "integer", Minimum: jsonschema.Ptr(1.0), Maximum: jsonschema.Ptr(10.0)},
+		"ratio":  {Type: "number", Description: "a ratio", Maximum: jsonschema.Ptr(0.5)},
+		"id":     {Type: "string", Format: "uuid"},
+		"name":   {Types: []string{"null", "string"}, MinLength: jsonschema.Ptr(1), MaxLength: jsonschema.Ptr(8), Pattern: "^[a-z]{1,8}$"},
+		"color":  {Type: "string", Enum: []any{"red", "green", "blue"}},
+		"level":  {Type: "integer", Enum: []any{1.0, 2.0, 3.0}},
+		"tier":   {Types: []string{"null", "integer"}, Enum: []any{1.0, 2.0, nil}},
+		"plain":  {Type: "string", Description: "a description, not keywords"},
+		"others": {Types: []string{"null", "array"}, Items: &jsonschema.Schema{Type: "integer"}, Description: "list, of things"},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// The constraints are enforced.
+	rs, err := got.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]any{"count": 5, "ratio": 0.5, "id": "x", "name": "abc", "color": "red", "level": 2, "tier": nil, "plain": "", "others": nil}
+	if err := rs.Validate(valid); err != nil {
+		t.Errorf("valid instance: %v", err)
+	}
+	for prop, v := range map[string]any{"count": 11, "name": "ABC", "color": "pink", "level": 4, "tier": 3} {
+		inst := map[string]any{}
+		for k, v := range valid {
+			inst[k] = v
+		}
+		inst[prop] = v
+		if err := rs.Validate(inst); err == nil {
+			t.Errorf("%s=%v: got nil error, want failure", prop, v)
+		}
+	}
+}
+
+func TestForTagExamples(t *testing.T) {
+	type S struct {
+		Zip   string   `json:"zip" jsonschema:"examples=12345|90210,pattern=^[0-9]{5}$"`
+		Age   int      `json:"age" jsonschema:"examples=30"`
+		Ratio *float64 `json:"ratio" jsonschema:"examples=0.5|1.5"`
+	}
+	got, err := jsonschema.For[S](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*jsonschema.Schema{
+		"zip":   {Type: "string", Pattern: "^[0-9]{5}$", Examples: []any{"12345", "90210"}},
+		"age":   {Type: "integer", Examples: []any{30.0}},
+		"ratio": {Types: []string{"null", "number"}, Examples: []any{0.5, 1.5}},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestForTagFlags(t *testing.T) {
+	type S struct {
+		ID    string `json:"id" jsonschema:"readOnly"`
+		Pass  string `json:"pass" jsonschema:"writeOnly,minLength=8"`
+		Old   int    `json:"old" jsonschema:"deprecated,description=use New"`
+		New   int    `json:"new" jsonschema:"description=the new field,deprecated=false"`
+		Label string `json:"label" jsonschema:"deprecated labels are kept"`
+	}
+	got, err := jsonschema.For[S](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*jsonschema.Schema{
+		"id":    {Type: "string", ReadOnly: true},
+		"pass":  {Type: "string", WriteOnly: true, MinLength: jsonschema.Ptr(8)},
+		"old":   {Type: "integer", Deprecated: true, Description: "use New"},
+		"new":   {Type: "integer", Description: "the new field"},
+		"label": {Type: "string", Description: "deprecated labels are kept"},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+}
+
+func TestForTagRequired(t *testing.T) {
+	type S struct {
+		A int  `json:"a"`
+		B int  `json:"b,omitempty"`
+		C int  `json:"c,omitzero"`
+		D *int `json:"d,omitempty" jsonschema:"required=true"`
+		E int  `json:"e" jsonschema:"description=optional,required=false"`
+		F int  `json:"f,omitempty" jsonschema:"required=false"`
+		G int  `json:"g,omitempty" jsonschema:"required"`
+		H int  `json:"h,omitempty" jsonschema:"required,description=the h"`
+	}
+	got, err := jsonschema.For[S](nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"a", "d", "g", "h"}, got.Required); diff != "" {
+		t.Errorf("required mismatch (-want, +got):\n%s", diff)
+	}
+	if g, w := got.Properties["e"].Description, "optional"; g != w {
+		t.Errorf("description of e: got %q, want %q", g, w)
+	}
+	// The bare flag is a setting, not a description.
+	if g, w := got.Properties["g"].Description, ""; g != w {
+		t.Errorf("description of g: got %q, want %q", g, w)
+	}
+	if g, w := got.Properties["h"].Description, "the h"; g != w {
+		t.Errorf("description of h: got %q, want %q", g, w)
+	}
+}
+
+func TestForFieldDescriptions(t *testing.T) {
+	type Inner struct {
+		I int `json:"i"`
+	}
+	type S struct {
+		A int `json:"a"`
+		B int `json:"b" jsonschema:"from tag"`
+		C int `json:"c" jsonschema:"minimum=0"`
+		D int `json:"d"`
+		*Inner
+	}
+	opts := &jsonschema.ForOptions{
+		FieldDescriptions: map[reflect.Type]map[string]string{
+			reflect.TypeFor[S]():     {"A": "a doc", "B": "b doc", "C": "c doc"},
+			reflect.TypeFor[Inner](): {"I": "i doc"},
+		},
+	}
+	got, err := jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"a": "a doc", "b": "from tag", "c": "c doc", "d": "", "i": "i doc"}
+	for name, w := range want {
+		if g := got.Properties[name].Description; g != w {
+			t.Errorf("%s: got description %q, want %q", name, g, w)
+		}
+	}
+}
+
+func TestForMapKeyPatterns(t *testing.T) {
+	type Headers map[string]string
+	type S struct {
+		H Headers        `json:"h"`
+		M map[string]int `json:"m,omitempty"`
+	}
+	opts := &jsonschema.ForOptions{
+		MapKeyPatterns: map[reflect.Type]string{
+			reflect.TypeFor[Headers](): "^[A-Z][a-z-]*$",
+		},
+	}
+	got, err := jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*jsonschema.Schema{
+		"h": {
+			Type:                 "object",
+			PatternProperties:    map[string]*jsonschema.Schema{"^[A-Z][a-z-]*$": {Type: "string"}},
+			AdditionalProperties: falseSchema(),
+		},
+		"m": {Type: "object", AdditionalProperties: &jsonschema.Schema{Type: "integer"}},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	rs, err := got.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(map[string]any{"h": map[string]any{"Accept": "x"}}); err != nil {
+		t.Errorf("valid key: %v", err)
+	}
+	if err := rs.Validate(map[string]any{"h": map[string]any{"accept": "x"}}); err == nil {
+		t.Error("invalid key: got nil error, want failure")
+	}
+
+	opts.MapKeyPatterns[reflect.TypeFor[Headers]()] = "("
+	if _, err := jsonschema.For[S](opts); err == nil || !strings.Contains(err.Error(), "key pattern") {
+		t.Errorf("bad pattern: got %v, want key pattern error", err)
+	}
+}
+
+func TestForNameFunc(t *testing.T) {
+	type Inner struct {
+		ZipCode string
+	}
+	type S struct {
+		UserID    string
+		HTTPProxy string `json:",omitempty"`
+		Tagged    int    `json:"tagged_name"`
+		Home      Inner
+	}
+	for _, tt := range []struct {
+		nameFunc func(string) string
+		want     []string // PropertyOrder
+		inner    string
+	}{
+		{nil, []string{"UserID", "HTTPProxy", "tagged_name", "Home"}, "ZipCode"},
+		{jsonschema.CamelCase, []string{"userID", "httpProxy", "tagged_name", "home"}, "zipCode"},
+		{jsonschema.SnakeCase, []string{"user_id", "http_proxy", "tagged_name", "home"}, "zip_code"},
+	} {
+		got, err := jsonschema.For[S](&jsonschema.ForOptions{NameFunc: tt.nameFunc})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(tt.want, got.PropertyOrder); diff != "" {
+			t.Errorf("property order mismatch (-want, +got):\n%s", diff)
+		}
+		// HTTPProxy is optional.
+		if diff := cmp.Diff([]string{tt.want[0], tt.want[2], tt.want[3]}, got.Required); diff != "" {
+			t.Errorf("required mismatch (-want, +got):\n%s", diff)
+		}
+		if home := got.Properties[tt.want[3]]; home == nil || home.Properties[tt.inner] == nil {
+			t.Errorf("nested struct does not have property %q", tt.inner)
+		}
+	}
+}
+
+func TestNameCases(t *testing.T) {
+	for _, tt := range []struct {
+		in, camel, snake string
+	}{
+		{"Name", "name", "name"},
+		{"UserID", "userID", "user_id"},
+		{"ID", "id", "id"},
+		{"HTTPServer", "httpServer", "http_server"},
+		{"APIKeyV2", "apiKeyV2", "api_key_v2"},
+		{"Field2Name", "field2Name", "field2_name"},
+		{"Already_Snake", "alreadySnake", "already_snake"},
+		{"Ünïcode", "ünïcode", "ünïcode"},
+	} {
+		if got := jsonschema.CamelCase(tt.in); got != tt.camel {
+			t.Errorf("CamelCase(%q) = %q, want %q", tt.in, got, tt.camel)
+		}
+		if got := jsonschema.SnakeCase(tt.in); got != tt.snake {
+			t.Errorf("SnakeCase(%q) = %q, want %q", tt.in, got, tt.snake)
+		}
+	}
+}
+
+type color int
+
+const (
+	red color = iota
+	green
+	blue
+)
+
+func (c color) String() string { return [...]string{"red", "green", "blue"}[c] }
+
+func (c color) MarshalText() ([]byte, error) { return []byte(c.String()), nil }
+
+type level int
+
+func TestForEnumValues(t *testing.T) {
+	type S struct {
+		C  color    `json:"c"`
+		P  *color   `json:"p"`
+		L  level    `json:"l"`
+		Cs []color  `json:"cs"`
+		F  float64  `json:"f"`
+		Ls []*level `json:"ls"`
+	}
+	opts := &jsonschema.ForOptions{
+		EnumValues: map[reflect.Type][]any{
+			reflect.TypeFor[color](): {red, green, blue},
+			reflect.TypeFor[level](): {level(1), level(2)},
+		},
+	}
+	got, err := jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	colors := &jsonschema.Schema{Type: "string", Enum: []any{"red", "green", "blue"}}
+	want := map[string]*jsonschema.Schema{
+		"c":  colors,
+		"p":  {Types: []string{"null", "string"}, Enum: []any{"red", "green", "blue", nil}},
+		"l":  {Type: "integer", Enum: []any{1.0, 2.0}},
+		"cs": {Types: []string{"null", "array"}, Items: colors},
+		"f":  {Type: "number"},
+		"ls": {Types: []string{"null", "array"}, Items: &jsonschema.Schema{Types: []string{"null", "integer"}, Enum: []any{1.0, 2.0, nil}}},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	rs, err := got.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid := map[string]any{"c": "red", "p": nil, "l": 2, "cs": []any{"blue"}, "f": 1.5, "ls": []any{nil, 1}}
+	if err := rs.Validate(valid); err != nil {
+		t.Errorf("valid instance: %v", err)
+	}
+	valid["c"] = "pink"
+	if err := rs.Validate(valid); err == nil {
+		t.Error("invalid color: got nil error, want failure")
+	}
+
+	for _, tt := range []struct {
+		vals []any
+		want string
+	}{
+		{nil, "no enum values"},
+		{[]any{1}, "has type int"},
+	} {
+		opts := &jsonschema.ForOptions{EnumValues: map[reflect.Type][]any{reflect.TypeFor[level](): tt.vals}}
+		if _, err := jsonschema.For[level](opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: got %v, want error containing %q", tt.vals, err, tt.want)
+		}
+	}
+}
+
+type shape interface{ area() float64 }
+
+type circle struct {
+	Radius float64 `json:"radius"`
+}
+
+func (c circle) area() float64 { return math.Pi * c.Radius * c.Radius }
+
+type square struct {
+	Kind string  `json:"kind"`
+	Side float64 `json:"side"`
+}
+
+func (s *square) area() float64 { return s.Side * s.Side }
+
+type point struct{}
+
+func (point) area() float64 { return 0 }
+
+func TestForImplementations(t *testing.T) {
+	type S struct {
+		Shape shape `json:"shape"`
+	}
+	impls := &jsonschema.Implementations{
+		Types: []reflect.Type{reflect.TypeFor[circle](), reflect.TypeFor[*square]()},
+	}
+	opts := &jsonschema.ForOptions{Implementations: map[reflect.Type]*jsonschema.Implementations{
+		reflect.TypeFor[shape](): impls,
+	}}
+	got, err := jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	circleSchema := &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{"radius": {Type: "number"}},
+		Required:             []string{"radius"},
+		AdditionalProperties: falseSchema(),
+		PropertyOrder:        []string{"radius"},
+	}
+	squareSchema := &jsonschema.Schema{
+		Type:                 "object",
+		Properties:           map[string]*jsonschema.Schema{"kind": {Type: "string"}, "side": {Type: "number"}},
+		Required:             []string{"kind", "side"},
+		AdditionalProperties: falseSchema(),
+		PropertyOrder:        []string{"kind", "side"},
+	}
+	want := &jsonschema.Schema{OneOf: []*jsonschema.Schema{circleSchema, squareSchema}}
+	if diff := cmp.Diff(want, got.Properties["shape"], cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// With a discriminator.
+	impls.Types = append(impls.Types, reflect.TypeFor[point]())
+	impls.Discriminator = "kind"
+	impls.Values = []string{"circle", "square", "point"}
+	got, err = jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oneOf := got.Properties["shape"].OneOf
+	for i, w := range impls.Values {
+		k := oneOf[i].Properties["kind"]
+		if k == nil || k.Const == nil || *k.Const != w || !slices.Contains(oneOf[i].Required, "kind") {
+			t.Errorf("%s: got kind property %v, required %v", w, k, oneOf[i].Required)
+		}
+	}
+	rs, err := got.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		shape string
+		valid bool
+	}{
+		{`{"kind": "circle", "radius": 1}`, true},
+		{`{"kind": "square", "side": 1}`, true},
+		{`{"kind": "point"}`, true},
+		{`{"kind": "square", "radius": 1}`, false},
+		{`{"radius": 1}`, false},
+	} {
+		var shape any
+		if err := json.Unmarshal([]byte(tt.shape), &shape); err != nil {
+			t.Fatal(err)
+		}
+		if err := rs.Validate(map[string]any{"shape": shape}); (err == nil) != tt.valid {
+			t.Errorf("%s: got %v, want valid = %t", tt.shape, err, tt.valid)
+		}
+	}
+
+	for _, tt := range []struct {
+		impls *jsonschema.Implementations
+		want  string
+	}{
+		{&jsonschema.Implementations{}, "no implementations"},
+		{&jsonschema.Implementations{Types: []reflect.Type{reflect.TypeFor[square]()}}, "does not implement"},
+		{&jsonschema.Implementations{Types: []reflect.Type{reflect.TypeFor[circle]()}, Values: []string{"a", "b"}}, "2 discriminator values for 1 types"},
+	} {
+		opts := &jsonschema.ForOptions{Implementations: map[reflect.Type]*jsonschema.Implementations{
+			reflect.TypeFor[shape](): tt.impls,
+		}}
+		if _, err := jsonschema.For[S](opts); err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("got %v, want error containing %q", err, tt.want)
+		}
+	}
+}
+
+func TestForOpenAPI31(t *testing.T) {
+	type (
+		Money  struct{}
+		Amount struct{}
+		S      struct {
+			Name  *string `json:"name"`
+			Tags  []int   `json:"tags"`
+			Price Money   `json:"price"`
+			Total Amount  `json:"total" jsonschema:"the total"`
+		}
+	)
+	opts := &jsonschema.ForOptions{
+		Dialect: jsonschema.DialectOpenAPI31,
+		TypeSchemas: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeFor[Money](): {
+				Type:  "string",
+				Extra: map[string]any{"nullable": true, "example": "1.00 USD"},
+			},
+			reflect.TypeFor[Amount](): {Ref: "#/components/schemas/Amount"},
+		},
+	}
+	got, err := jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*jsonschema.Schema{
+		"name":  {Types: []string{"string", "null"}},
+		"tags":  {Types: []string{"array", "null"}, Items: &jsonschema.Schema{Type: "integer"}},
+		"price": {Types: []string{"string", "null"}, Examples: []any{"1.00 USD"}},
+		"total": {Ref: "#/components/schemas/Amount", Description: "the total"},
+	}
+	if diff := cmp.Diff(want, got.Properties, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// A $ref with constraints next to it is moved into an allOf.
+	opts.TypeSchemas[reflect.TypeFor[Amount]()] = &jsonschema.Schema{Ref: "#/components/schemas/Amount", MinLength: jsonschema.Ptr(1)}
+	got, err = jsonschema.For[S](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantTotal := &jsonschema.Schema{
+		AllOf:       []*jsonschema.Schema{{Ref: "#/components/schemas/Amount"}},
+		MinLength:   jsonschema.Ptr(1),
+		Description: "the total",
+	}
+	if diff := cmp.Diff(wantTotal, got.Properties["total"], cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("total: mismatch (-want, +got):\n%s", diff)
+	}
+
+	if _, err := jsonschema.For[S](&jsonschema.ForOptions{Dialect: "openapi"}); err == nil || !strings.Contains(err.Error(), "unknown dialect") {
+		t.Errorf("got %v, want unknown dialect error", err)
+	}
+}
+
 func TestForErrors(t *testing.T) {
 	type (
 		s1 struct {
@@ -602,6 +1095,33 @@ func TestForErrors(t *testing.T) {
 		s2 struct {
 			Bad int `jsonschema:"$foo=1,bar"`
 		}
+		s3 struct {
+			Bad int `jsonschema:"minimum=1,bar"`
+		}
+		s4 struct {
+			Bad int `jsonschema:"minimum=1,foo=2"`
+		}
+		s5 struct {
+			Bad int `jsonschema:"minLength=1"`
+		}
+		s6 struct {
+			Bad string `jsonschema:"pattern=("`
+		}
+		s7 struct {
+			Bad int `jsonschema:"enum=1|x"`
+		}
+		s8 struct {
+			Bad string `jsonschema:"pattern='a,b"`
+		}
+		s9 struct {
+			Bad string `jsonschema:"required=maybe"`
+		}
+		s10 struct {
+			Bad string `jsonschema:"readOnly=yes please"`
+		}
+		s11 struct {
+			Bad bool `jsonschema:"examples=yes"`
+		}
 	)
 
 	for _, tt := range []struct {
@@ -611,6 +1131,15 @@ func TestForErrors(t *testing.T) {
 		{forErr[map[int]int](), "unsupported map key type"},
 		{forErr[s1](), "empty jsonschema tag"},
 		{forErr[s2](), "must not begin with"},
+		{forErr[s3](), "not of the form KEYWORD=VALUE"},
+		{forErr[s4](), `unknown keyword "foo"`},
+		{forErr[s5](), `minLength does not apply to type "integer"`},
+		{forErr[s6](), "pattern: "},
+		{forErr[s7](), "enum: "},
+		{forErr[s8](), "unterminated quote"},
+		{forErr[s9](), `required: "maybe" is not true or false`},
+		{forErr[s10](), `readOnly: "yes please" is not true or false`},
+		{forErr[s11](), "examples: "},
 		{forErr[func()](), "unsupported"},
 	} {
 		if tt.got == nil {
@@ -713,6 +1242,73 @@ func TestForWithCycle(t *testing.T) {
 	}
 }
 
+type defsAddress struct {
+	Street string `json:"street"`
+}
+
+type defsPerson struct {
+	Home     defsAddress   `json:"home"`
+	Work     *defsAddress  `json:"work" jsonschema:"where they work"`
+	Previous []defsAddress `json:"previous,omitempty"`
+	Friends  []*defsPerson `json:"friends,omitempty"`
+}
+
+func TestForUseDefs(t *testing.T) {
+	opts := &jsonschema.ForOptions{UseDefs: true}
+	got, err := jsonschema.For[defsPerson](opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ref := &jsonschema.Schema{Ref: "#/$defs/defsAddress"}
+	want := &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"home":     ref,
+			"work":     {AnyOf: []*jsonschema.Schema{{Type: "null"}, ref}, Description: "where they work"},
+			"previous": {Types: []string{"null", "array"}, Items: ref},
+			"friends":  {Types: []string{"null", "array"}, Items: &jsonschema.Schema{AnyOf: []*jsonschema.Schema{{Type: "null"}, {Ref: "#"}}}},
+		},
+		Required:             []string{"home", "work"},
+		AdditionalProperties: falseSchema(),
+		PropertyOrder:        []string{"home", "work", "previous", "friends"},
+		Defs: map[string]*jsonschema.Schema{
+			"defsAddress": {
+				Type:                 "object",
+				Properties:           map[string]*jsonschema.Schema{"street": {Type: "string"}},
+				Required:             []string{"street"},
+				AdditionalProperties: falseSchema(),
+				PropertyOrder:        []string{"street"},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(jsonschema.Schema{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	rs, err := got.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := map[string]any{"street": "Main"}
+	valid := map[string]any{
+		"home":    addr,
+		"work":    nil,
+		"friends": []any{map[string]any{"home": addr, "work": addr}},
+	}
+	if err := rs.Validate(valid); err != nil {
+		t.Errorf("valid instance: %v", err)
+	}
+	invalid := map[string]any{"home": addr, "work": map[string]any{"street": 1}}
+	if err := rs.Validate(invalid); err == nil {
+		t.Error("invalid instance: got nil error")
+	}
+
+	// Cycles through struct types are allowed.
+	if _, err := jsonschema.For[x](opts); err != nil {
+		t.Errorf("cycle: %v", err)
+	}
+}
+
 func falseSchema() *jsonschema.Schema {
 	return &jsonschema.Schema{Not: &jsonschema.Schema{}}
 }
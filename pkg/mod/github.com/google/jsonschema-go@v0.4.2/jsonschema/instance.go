@@ -0,0 +1,532 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the generation of random instances of schemas.
+
+package jsonschema
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand/v2"
+	"regexp/syntax"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// InstanceOptions are options for [Resolved.RandomInstance].
+type InstanceOptions struct {
+	// Rand is the source of randomness. If nil, a randomly seeded source is used.
+	// Use a source with a fixed seed for reproducible instances.
+	Rand *rand.Rand
+
+	// If Boundary is true, numbers, and the lengths of strings and arrays, are
+	// chosen from the bounds of the schema when it has them, to exercise edge
+	// cases.
+	Boundary bool
+
+	// If Invalid is true, the instance fails validation against the schema.
+	Invalid bool
+}
+
+const (
+	// maxInstanceAttempts is the number of instances that RandomInstance
+	// generates before giving up on finding one that validates as desired.
+	maxInstanceAttempts = 100
+	// maxInstanceDepth is the depth beyond which RandomInstance generates
+	// only what is required, so that recursive schemas produce finite
+	// instances.
+	maxInstanceDepth = 8
+	// maxInstanceNesting is the nesting of subschemas beyond which
+	// RandomInstance abandons an attempt, as for a recursive schema that
+	// requires an instance of itself.
+	maxInstanceNesting = 256
+)
+
+// RandomInstance returns a random instance of the schema, for property-based
+// testing of code that consumes instances, such as tool handlers.
+// The instance is made of the values that [encoding/json] unmarshals into an
+// [any]: maps, slices, strings, float64s, bools and nil.
+//
+// RandomInstance honors types, enums, consts, numeric bounds, string lengths,
+// patterns, formats, array lengths, and required and optional properties.
+// It checks each instance with [Resolved.Validate], and tries again if the
+// instance does not validate as desired, since it may violate keywords it
+// does not consider, such as "not" or "uniqueItems". It returns an error if
+// it cannot find a suitable instance, as for a schema that nothing satisfies
+// or one whose instances are all infinitely deep.
+func (rs *Resolved) RandomInstance(opts *InstanceOptions) (any, error) {
+	if opts == nil {
+		opts = &InstanceOptions{}
+	}
+	r := opts.Rand
+	if r == nil {
+		r = rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64()))
+	}
+	g := &instanceGen{rs: rs, r: r, boundary: opts.Boundary}
+	tooDeep := false
+	for range maxInstanceAttempts {
+		g.tooDeep = false
+		v := g.value(rs.root, 0)
+		if g.tooDeep {
+			tooDeep = true
+			continue
+		}
+		if opts.Invalid {
+			v = g.invalidate(v)
+		}
+		if err := rs.Validate(v); (err == nil) == opts.Invalid {
+			continue
+		}
+		return v, nil
+	}
+	what := "valid"
+	if opts.Invalid {
+		what = "invalid"
+	}
+	if tooDeep {
+		return nil, fmt.Errorf("no %s instance of %s found in %d attempts: instances nest more than %d levels deep", what, rs.schemaString(rs.root), maxInstanceAttempts, maxInstanceNesting)
+	}
+	return nil, fmt.Errorf("no %s instance of %s found in %d attempts", what, rs.schemaString(rs.root), maxInstanceAttempts)
+}
+
+// An instanceGen generates random instances.
+type instanceGen struct {
+	rs       *Resolved
+	r        *rand.Rand
+	boundary bool
+	nesting  int  // number of enclosing calls to value
+	tooDeep  bool // nesting exceeded maxInstanceNesting
+}
+
+// value returns a random instance of s, which is at the given depth in the
+// instance.
+func (g *instanceGen) value(s *Schema, depth int) any {
+	if g.tooDeep || g.nesting >= maxInstanceNesting {
+		g.tooDeep = true
+		return nil
+	}
+	g.nesting++
+	defer func() { g.nesting-- }()
+	if s == nil {
+		return g.scalar()
+	}
+	if info := g.rs.resolvedInfos[s]; info != nil {
+		if info.resolvedRef != nil {
+			return g.value(info.resolvedRef, depth)
+		}
+		if info.resolvedDynamicRef != nil {
+			return g.value(info.resolvedDynamicRef, depth)
+		}
+	}
+	if s.Const != nil {
+		return copyJSON(*s.Const)
+	}
+	if len(s.Enum) > 0 {
+		return copyJSON(s.Enum[g.r.IntN(len(s.Enum))])
+	}
+	var v any
+	if alts := slices.Concat(s.AnyOf, s.OneOf); len(alts) > 0 && s.Type == "" && s.Types == nil {
+		v = g.value(alts[g.r.IntN(len(alts))], depth)
+	} else {
+		v = g.typed(s, depth)
+	}
+	// The subschemas of allOf may each describe some of the properties of
+	// an object.
+	if m, ok := v.(map[string]any); ok {
+		for _, sub := range s.AllOf {
+			if sm, ok := g.value(sub, depth).(map[string]any); ok {
+				for k, sv := range sm {
+					if _, ok := m[k]; !ok {
+						m[k] = sv
+					}
+				}
+			}
+		}
+	} else if v == nil && len(s.AllOf) > 0 && s.Type == "" && s.Types == nil {
+		v = g.value(s.AllOf[g.r.IntN(len(s.AllOf))], depth)
+	}
+	return v
+}
+
+// typed returns a random instance of one of the types of s.
+func (g *instanceGen) typed(s *Schema, depth int) any {
+	types := s.Types
+	if s.Type != "" {
+		types = []string{s.Type}
+	}
+	if len(types) == 0 {
+		// Infer the type from the keywords.
+		switch {
+		case s.Properties != nil || s.Required != nil || s.AdditionalProperties != nil || s.MinProperties != nil || s.AllOf != nil:
+			types = []string{"object"}
+		case s.Items != nil || s.PrefixItems != nil || s.ItemsArray != nil || s.MinItems != nil:
+			types = []string{"array"}
+		case s.MinLength != nil || s.MaxLength != nil || s.Pattern != "" || s.Format != "":
+			types = []string{"string"}
+		case s.Minimum != nil || s.Maximum != nil || s.ExclusiveMinimum != nil || s.ExclusiveMaximum != nil || s.MultipleOf != nil:
+			types = []string{"number"}
+		default:
+			return g.scalar()
+		}
+	}
+	switch types[g.r.IntN(len(types))] {
+	case "null":
+		return nil
+	case "boolean":
+		return g.r.IntN(2) == 0
+	case "integer":
+		return g.number(s, true)
+	case "number":
+		return g.number(s, false)
+	case "string":
+		return g.string(s)
+	case "array":
+		return g.array(s, depth)
+	case "object":
+		return g.object(s, depth)
+	default:
+		return nil
+	}
+}
+
+// scalar returns a random instance of the schema true that is not an array
+// or object.
+func (g *instanceGen) scalar() any {
+	switch g.r.IntN(4) {
+	case 0:
+		return nil
+	case 1:
+		return g.r.IntN(2) == 0
+	case 2:
+		return float64(g.r.IntN(2001) - 1000)
+	default:
+		return g.letters(g.r.IntN(10))
+	}
+}
+
+// number returns a random number that satisfies the numeric keywords of s.
+func (g *instanceGen) number(s *Schema, integer bool) float64 {
+	// Use the bounds in which Resolve translated any boolean exclusive bounds.
+	info := g.rs.resolvedInfos[s]
+	lo, hi := math.Inf(-1), math.Inf(1)
+	loExcl, hiExcl := false, false
+	if info.minimum != nil {
+		lo = *info.minimum
+	}
+	if info.exclusiveMinimum != nil && *info.exclusiveMinimum >= lo {
+		lo, loExcl = *info.exclusiveMinimum, true
+	}
+	if info.maximum != nil {
+		hi = *info.maximum
+	}
+	if info.exclusiveMaximum != nil && *info.exclusiveMaximum <= hi {
+		hi, hiExcl = *info.exclusiveMaximum, true
+	}
+	switch {
+	case math.IsInf(lo, 0) && math.IsInf(hi, 0):
+		lo, hi = -1000, 1000
+	case math.IsInf(lo, 0):
+		lo = hi - 1000
+	case math.IsInf(hi, 0):
+		hi = lo + 1000
+	}
+	step := 0.0
+	if s.MultipleOf != nil && *s.MultipleOf > 0 {
+		step = *s.MultipleOf
+	} else if integer {
+		step = 1
+	}
+	if step == 0 {
+		if loExcl {
+			lo = math.Nextafter(lo, hi)
+		}
+		if hiExcl {
+			hi = math.Nextafter(hi, lo)
+		}
+		if g.boundary {
+			return []float64{lo, hi}[g.r.IntN(2)]
+		}
+		return lo + g.r.Float64()*(hi-lo)
+	}
+	// Choose a multiple of step in the bounds.
+	kLo, kHi := math.Ceil(lo/step), math.Floor(hi/step)
+	if loExcl && kLo*step == lo {
+		kLo++
+	}
+	if hiExcl && kHi*step == hi {
+		kHi--
+	}
+	if kHi < kLo {
+		return lo // nothing fits; validation will fail
+	}
+	k := kLo
+	if g.boundary {
+		k = []float64{kLo, kHi}[g.r.IntN(2)]
+	} else if n := kHi - kLo; n < 1<<53 {
+		k += float64(g.r.Int64N(int64(n) + 1))
+	}
+	return k * step
+}
+
+// length returns a random length in the bounds min and max, where max is
+// nil if there is no upper bound.
+func (g *instanceGen) length(min int, max *int, depth int) int {
+	hi := min + 3
+	if max != nil {
+		hi = *max
+	}
+	if g.boundary || depth >= maxInstanceDepth {
+		if max != nil && g.r.IntN(2) == 0 && depth < maxInstanceDepth {
+			return hi
+		}
+		return min
+	}
+	if hi < min {
+		return min
+	}
+	return min + g.r.IntN(hi-min+1)
+}
+
+// formatSamples are strings of each format.
+var formatSamples = map[string]string{
+	"date-time":     "2025-01-02T03:04:05Z",
+	"date":          "2025-01-02",
+	"time":          "03:04:05Z",
+	"email":         "user@example.com",
+	"hostname":      "example.com",
+	"ipv4":          "192.0.2.1",
+	"ipv6":          "2001:db8::1",
+	"uri":           "https://example.com/path",
+	"uri-reference": "/path",
+	"uuid":          "123e4567-e89b-12d3-a456-426614174000",
+	"regex":         "^a+$",
+}
+
+// string returns a random string that satisfies the string keywords of s.
+func (g *instanceGen) string(s *Schema) string {
+	if sample, ok := formatSamples[s.Format]; ok {
+		return sample
+	}
+	if s.Pattern != "" {
+		if re, err := syntax.Parse(s.Pattern, syntax.Perl); err == nil {
+			var b strings.Builder
+			g.match(re, &b)
+			return b.String()
+		}
+	}
+	min := 0
+	if s.MinLength != nil {
+		min = *s.MinLength
+	}
+	max := s.MaxLength
+	if max == nil && !g.boundary {
+		max = Ptr(min + 10)
+	}
+	return g.letters(g.length(min, max, 0))
+}
+
+// letters returns a string of n random lower-case letters.
+func (g *instanceGen) letters(n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = byte('a' + g.r.IntN(26))
+	}
+	return string(b)
+}
+
+// match appends to b a random string that matches re.
+func (g *instanceGen) match(re *syntax.Regexp, b *strings.Builder) {
+	// repeat matches re.Sub[0] between min and max times.
+	repeat := func(min, max int) {
+		if max < 0 {
+			max = min + 3
+		}
+		for range min + g.r.IntN(max-min+1) {
+			g.match(re.Sub[0], b)
+		}
+	}
+	switch re.Op {
+	case syntax.OpLiteral:
+		b.WriteString(string(re.Rune))
+	case syntax.OpCharClass:
+		b.WriteRune(g.classRune(re.Rune))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteByte(byte('a' + g.r.IntN(26)))
+	case syntax.OpCapture:
+		g.match(re.Sub[0], b)
+	case syntax.OpStar:
+		repeat(0, 3)
+	case syntax.OpPlus:
+		repeat(1, 4)
+	case syntax.OpQuest:
+		repeat(0, 1)
+	case syntax.OpRepeat:
+		repeat(re.Min, re.Max)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			g.match(sub, b)
+		}
+	case syntax.OpAlternate:
+		g.match(re.Sub[g.r.IntN(len(re.Sub))], b)
+	default:
+		// Empty-width assertions match without consuming input.
+	}
+}
+
+// classRune returns a random rune in the character class given by ranges,
+// a list of pairs of inclusive bounds. It prefers printable ASCII.
+func (g *instanceGen) classRune(ranges []rune) rune {
+	if len(ranges) == 0 {
+		return 'a' // an empty class matches nothing; validation will fail
+	}
+	var printable []rune
+	for i := 0; i < len(ranges); i += 2 {
+		for c := max(ranges[i], ' '); c <= min(ranges[i+1], '~'); c++ {
+			printable = append(printable, c)
+		}
+	}
+	if len(printable) > 0 {
+		return printable[g.r.IntN(len(printable))]
+	}
+	i := 2 * g.r.IntN(len(ranges)/2)
+	lo, hi := ranges[i], min(ranges[i+1], ranges[i]+255)
+	return lo + rune(g.r.IntN(int(hi-lo+1)))
+}
+
+// array returns a random array that satisfies the array keywords of s.
+func (g *instanceGen) array(s *Schema, depth int) []any {
+	min := 0
+	if s.MinItems != nil {
+		min = *s.MinItems
+	}
+	n := g.length(min, s.MaxItems, depth)
+	a := make([]any, n)
+	for i := range a {
+		switch {
+		case i < len(s.PrefixItems):
+			a[i] = g.value(s.PrefixItems[i], depth+1)
+		case i < len(s.ItemsArray):
+			a[i] = g.value(s.ItemsArray[i], depth+1)
+		case s.ItemsArray != nil:
+			a[i] = g.value(s.AdditionalItems, depth+1)
+		default:
+			a[i] = g.value(s.Items, depth+1)
+		}
+	}
+	if s.Contains != nil && n > 0 {
+		a[g.r.IntN(n)] = g.value(s.Contains, depth+1)
+	}
+	return a
+}
+
+// object returns a random object that satisfies the object keywords of s.
+func (g *instanceGen) object(s *Schema, depth int) map[string]any {
+	m := map[string]any{}
+	var add func(k string)
+	add = func(k string) {
+		if _, ok := m[k]; ok {
+			return
+		}
+		ps := s.Properties[k]
+		if ps == nil {
+			ps = s.AdditionalProperties
+		}
+		m[k] = g.value(ps, depth+1)
+		for _, dep := range s.DependentRequired[k] {
+			add(dep)
+		}
+	}
+	for _, k := range s.Required {
+		add(k)
+	}
+	var optional []string
+	for _, k := range slices.Sorted(maps.Keys(s.Properties)) {
+		if !slices.Contains(s.Required, k) {
+			optional = append(optional, k)
+		}
+	}
+	if !g.boundary && depth < maxInstanceDepth {
+		for _, k := range optional {
+			if g.r.IntN(2) == 0 {
+				add(k)
+			}
+		}
+	}
+	if s.MinProperties != nil {
+		for _, k := range optional {
+			if len(m) >= *s.MinProperties {
+				break
+			}
+			add(k)
+		}
+		for i := 0; len(m) < *s.MinProperties; i++ {
+			add("p" + strconv.Itoa(i))
+		}
+	}
+	return m
+}
+
+// invalidate returns v after replacing a random part of it, or deleting a
+// random property, so that it probably fails validation.
+func (g *instanceGen) invalidate(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) > 0 && g.r.IntN(3) > 0 {
+			keys := slices.Sorted(maps.Keys(v))
+			k := keys[g.r.IntN(len(keys))]
+			if g.r.IntN(2) == 0 {
+				delete(v, k)
+			} else {
+				v[k] = g.invalidate(v[k])
+			}
+			return v
+		}
+	case []any:
+		if len(v) > 0 && g.r.IntN(3) > 0 {
+			i := g.r.IntN(len(v))
+			v[i] = g.invalidate(v[i])
+			return v
+		}
+	case float64:
+		if g.r.IntN(2) == 0 {
+			return []float64{v + 1e6, v - 1e6, v + 0.5}[g.r.IntN(3)]
+		}
+	case string:
+		if g.r.IntN(2) == 0 {
+			return v + g.letters(1+g.r.IntN(100))
+		}
+	}
+	// Replace v with a value of a random type.
+	switch g.r.IntN(3) {
+	case 0:
+		return []any{}
+	case 1:
+		return map[string]any{}
+	default:
+		return g.scalar()
+	}
+}
+
+// copyJSON returns a deep copy of the JSON value v.
+func copyJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			m[k] = copyJSON(e)
+		}
+		return m
+	case []any:
+		a := make([]any, len(v))
+		for i, e := range v {
+			a[i] = copyJSON(e)
+		}
+		return a
+	default:
+		return v
+	}
+}
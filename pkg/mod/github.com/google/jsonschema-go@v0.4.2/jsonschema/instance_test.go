@@ -0,0 +1,142 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRandomInstance(t *testing.T) {
+	for _, schema := range []string{
+		`true`,
+		`{"type": "integer", "minimum": 3, "exclusiveMaximum": 10}`,
+		`{"type": "number", "exclusiveMinimum": 0, "maximum": 1}`,
+		`{"type": "number", "multipleOf": 0.25, "minimum": -1, "maximum": 1}`,
+		`{"type": "string", "minLength": 2, "maxLength": 4}`,
+		`{"type": "string", "pattern": "^[A-Z]{2}-\\d{3,5}(x|yz)?$"}`,
+		`{"type": "string", "pattern": "^[^,]+$"}`,
+		`{"type": "string", "format": "date-time"}`,
+		`{"enum": ["red", "green", 3]}`,
+		`{"const": {"a": [1, 2]}}`,
+		`{"type": ["null", "boolean"]}`,
+		`{"type": "array", "items": {"type": "integer"}, "minItems": 2, "maxItems": 3, "uniqueItems": true}`,
+		`{"type": "array", "prefixItems": [{"type": "string"}, {"type": "boolean"}], "items": false}`,
+		`{"type": "array", "contains": {"const": 7}, "minItems": 1}`,
+		`{"type": "object",
+		  "properties": {"name": {"type": "string"}, "age": {"type": "integer", "minimum": 0}},
+		  "required": ["name"], "additionalProperties": false}`,
+		`{"type": "object", "minProperties": 3, "additionalProperties": {"type": "string"}}`,
+		`{"properties": {"a": true}, "dependentRequired": {"a": ["b"]}, "required": ["a"]}`,
+		`{"allOf": [{"properties": {"a": {"type": "string"}}, "required": ["a"]}, {"properties": {"b": {"type": "integer"}}, "required": ["b"]}]}`,
+		`{"oneOf": [{"type": "string", "maxLength": 1}, {"type": "integer", "minimum": 10}]}`,
+		`{"$defs": {"node": {"type": "object", "properties": {"next": {"$ref": "#/$defs/node"}}}}, "$ref": "#/$defs/node"}`,
+		`{"not": {"type": "string"}}`,
+	} {
+		var s Schema
+		if err := json.Unmarshal([]byte(schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		rs, err := s.Resolve(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		r := rand.New(rand.NewPCG(1, 2))
+		for _, opts := range []*InstanceOptions{
+			{Rand: r},
+			{Rand: r, Boundary: true},
+			{Rand: r, Invalid: true},
+		} {
+			if opts.Invalid && schema == "true" {
+				continue
+			}
+			for range 20 {
+				v, err := rs.RandomInstance(opts)
+				if err != nil {
+					t.Errorf("%s, boundary=%t, invalid=%t: %v", schema, opts.Boundary, opts.Invalid, err)
+					break
+				}
+				if err := rs.Validate(v); (err == nil) == opts.Invalid {
+					t.Errorf("%s, invalid=%t: %v validates with %v", schema, opts.Invalid, v, err)
+				}
+			}
+		}
+	}
+}
+
+func TestRandomInstanceBoundary(t *testing.T) {
+	rs, err := (&Schema{Type: "integer", Minimum: Ptr(5.0), Maximum: Ptr(9.0)}).Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := rand.New(rand.NewPCG(1, 2))
+	seen := map[any]bool{}
+	for range 20 {
+		v, err := rs.RandomInstance(&InstanceOptions{Rand: r, Boundary: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		seen[v] = true
+	}
+	if want := map[any]bool{5.0: true, 9.0: true}; !cmp.Equal(seen, want) {
+		t.Errorf("got %v, want %v", seen, want)
+	}
+}
+
+func TestRandomInstanceErrors(t *testing.T) {
+	// Nothing satisfies false, and everything satisfies true.
+	for _, tt := range []struct {
+		schema  *Schema
+		invalid bool
+	}{
+		{falseSchema(), false},
+		{&Schema{}, true},
+	} {
+		rs, err := tt.schema.Resolve(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := rs.RandomInstance(&InstanceOptions{Invalid: tt.invalid}); err == nil {
+			t.Errorf("%s, invalid=%t: got nil error, want error", tt.schema, tt.invalid)
+		}
+	}
+
+	// Every instance of a schema that requires an instance of itself is
+	// infinite.
+	var s Schema
+	if err := json.Unmarshal([]byte(`{"$defs":{"n":{"type":"object","required":["c"],"properties":{"c":{"$ref":"#/$defs/n"}}}},"$ref":"#/$defs/n"}`), &s); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := s.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs.RandomInstance(nil); err == nil {
+		t.Error("recursive required property: got nil error, want error")
+	}
+}
+
+func TestRandomInstanceReproducible(t *testing.T) {
+	rs, err := (&Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"a": {Type: "string"}, "b": {Type: "number"}, "c": {Type: "array"}},
+	}).Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	gen := func() any {
+		v, err := rs.RandomInstance(&InstanceOptions{Rand: rand.New(rand.NewPCG(3, 4))})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return v
+	}
+	if a, b := gen(), gen(); !cmp.Equal(a, b) {
+		t.Errorf("same seed, different instances:\n%v\n%v", a, b)
+	}
+}
@@ -41,11 +41,12 @@ func unescapeJSONPointerSegment(s string) string {
 	return jsonPointerUnescaper.Replace(s)
 }
 
-// parseJSONPointer splits a JSON Pointer into a sequence of segments. It doesn't
-// convert strings to numbers, because that depends on the traversal: a segment
-// is treated as a number when applied to an array, but a string when applied to
-// an object. See section 4 of the spec.
-func parseJSONPointer(ptr string) (segments []string, err error) {
+// ParseJSONPointer splits a JSON Pointer into a sequence of segments, undoing
+// the escaping of "~" and "/" within them. It doesn't convert strings to
+// numbers, because that depends on the traversal: a segment is treated as a
+// number when applied to an array, but a string when applied to an object.
+// See section 4 of the spec.
+func ParseJSONPointer(ptr string) (segments []string, err error) {
 	if ptr == "" {
 		return nil, nil
 	}
@@ -71,7 +72,7 @@ func parseJSONPointer(ptr string) (segments []string, err error) {
 func dereferenceJSONPointer(s *Schema, sptr string) (_ *Schema, err error) {
 	defer wrapf(&err, "JSON Pointer %q", sptr)
 
-	segments, err := parseJSONPointer(sptr)
+	segments, err := ParseJSONPointer(sptr)
 	if err != nil {
 		return nil, err
 	}
@@ -95,20 +96,9 @@ func dereferenceJSONPointer(s *Schema, sptr string) (_ *Schema, err error) {
 				return nil, fmt.Errorf("no schema field %q", seg)
 			}
 		case reflect.Slice, reflect.Array:
-			// The segment must be an integer without leading zeroes that refers to an item in the
-			// slice or array.
-			if seg == "-" {
-				return nil, errors.New("the JSON Pointer array segment '-' is not supported")
-			}
-			if len(seg) > 1 && seg[0] == '0' {
-				return nil, fmt.Errorf("segment %q has leading zeroes", seg)
-			}
-			n, err := strconv.Atoi(seg)
+			n, err := jsonPointerIndex(seg, v.Len())
 			if err != nil {
-				return nil, fmt.Errorf("invalid int: %q", seg)
-			}
-			if n < 0 || n >= v.Len() {
-				return nil, fmt.Errorf("index %d is out of bounds for array of length %d", n, v.Len())
+				return nil, err
 			}
 			v = v.Index(n)
 			// Cannot be invalid.
@@ -158,3 +148,250 @@ func lookupSchemaField(v reflect.Value, name string) reflect.Value {
 	}
 	return reflect.Value{}
 }
+
+// jsonPointerIndex returns the index that the segment seg refers to in an
+// array of length n. The segment must be an integer without leading zeroes that
+// refers to an item in the array.
+func jsonPointerIndex(seg string, n int) (int, error) {
+	if seg == "-" {
+		return 0, errors.New("the JSON Pointer array segment '-' is not supported")
+	}
+	if len(seg) > 1 && seg[0] == '0' {
+		return 0, fmt.Errorf("segment %q has leading zeroes", seg)
+	}
+	i, err := strconv.Atoi(seg)
+	if err != nil {
+		return 0, fmt.Errorf("invalid int: %q", seg)
+	}
+	if i < 0 || i >= n {
+		return 0, fmt.Errorf("index %d is out of bounds for array of length %d", i, n)
+	}
+	return i, nil
+}
+
+// GetJSONPointer returns the value that ptr refers to within instance, such
+// as the value at the [ValidationError.InstanceLocation] of a validation error.
+// As in validation, an object may be a map with string keys or a struct, whose
+// properties are named as by encoding/json, and an array may be a slice or
+// an array. Pointers and interfaces are followed.
+func GetJSONPointer(instance any, ptr string) (_ any, err error) {
+	defer wrapf(&err, "JSON Pointer %q", ptr)
+
+	segments, err := ParseJSONPointer(ptr)
+	if err != nil {
+		return nil, err
+	}
+	v := reflect.ValueOf(instance)
+	for _, seg := range segments {
+		v, err = jsonPointerChild(v, seg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !v.IsValid() {
+		return nil, nil
+	}
+	return v.Interface(), nil
+}
+
+// jsonPointerChild returns the property or item seg of v.
+func jsonPointerChild(v reflect.Value, seg string) (reflect.Value, error) {
+	for v.Kind() == reflect.Interface || v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("navigated to nil before %q", seg)
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Map:
+		kt := v.Type().Key()
+		if kt.Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("map key type %s is not a string", kt)
+		}
+		c := v.MapIndex(reflect.ValueOf(seg).Convert(kt))
+		if !c.IsValid() {
+			return reflect.Value{}, fmt.Errorf("no property %q", seg)
+		}
+		return c, nil
+	case reflect.Struct:
+		sf, ok := structPropertiesOf(v.Type())[seg]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no property %q", seg)
+		}
+		return v.FieldByIndex(sf.Index), nil
+	case reflect.Slice, reflect.Array:
+		n, err := jsonPointerIndex(seg, v.Len())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return v.Index(n), nil
+	case reflect.Invalid:
+		return reflect.Value{}, fmt.Errorf("navigated to nil before %q", seg)
+	default:
+		return reflect.Value{}, fmt.Errorf("value of type %s has no property or item %q", v.Type(), seg)
+	}
+}
+
+// SetJSONPointer sets the value that ptr refers to within the value that
+// instancep points to. Objects and arrays are as for [GetJSONPointer]. The
+// last segment of ptr may name a property that is not in a map, to add it,
+// or be "-" to append an item to a slice. Set creates a map that is nil, but
+// not other missing values. The value must be assignable to the Go type at
+// ptr, or be a number if that type is numeric.
+func SetJSONPointer(instancep any, ptr string, value any) (err error) {
+	defer wrapf(&err, "JSON Pointer %q", ptr)
+
+	segments, err := ParseJSONPointer(ptr)
+	if err != nil {
+		return err
+	}
+	v := reflect.ValueOf(instancep)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return fmt.Errorf("%T is not a non-nil pointer", instancep)
+	}
+	nv, err := setJSONPointer(v.Elem(), segments, value)
+	if err != nil {
+		return err
+	}
+	v.Elem().Set(nv)
+	return nil
+}
+
+// setJSONPointer returns a value of v's type that is v with the value at
+// segments replaced by value. It modifies v in place when it can.
+func setJSONPointer(v reflect.Value, segments []string, value any) (reflect.Value, error) {
+	t := v.Type()
+	if len(segments) == 0 {
+		return assignableValue(value, t)
+	}
+	seg, rest := segments[0], segments[1:]
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("navigated to nil before %q", seg)
+		}
+		e := v.Elem()
+		// The dynamic value of an interface cannot be modified in place, so
+		// copy it if it is an array or struct.
+		if e.Kind() == reflect.Array || e.Kind() == reflect.Struct {
+			c := reflect.New(e.Type()).Elem()
+			c.Set(e)
+			e = c
+		}
+		ne, err := setJSONPointer(e, segments, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		nv := reflect.New(t).Elem()
+		nv.Set(ne)
+		return nv, nil
+
+	case reflect.Pointer:
+		if v.IsNil() {
+			return reflect.Value{}, fmt.Errorf("navigated to nil before %q", seg)
+		}
+		ne, err := setJSONPointer(v.Elem(), segments, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Elem().Set(ne)
+		return v, nil
+
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return reflect.Value{}, fmt.Errorf("map key type %s is not a string", t.Key())
+		}
+		key := reflect.ValueOf(seg).Convert(t.Key())
+		c := v.MapIndex(key)
+		if !c.IsValid() {
+			if len(rest) > 0 {
+				return reflect.Value{}, fmt.Errorf("no property %q", seg)
+			}
+			c = reflect.Zero(t.Elem())
+		}
+		nc, err := setJSONPointer(c, rest, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if v.IsNil() {
+			v = reflect.MakeMap(t)
+		}
+		v.SetMapIndex(key, nc)
+		return v, nil
+
+	case reflect.Struct:
+		sf, ok := structPropertiesOf(t)[seg]
+		if !ok {
+			return reflect.Value{}, fmt.Errorf("no property %q", seg)
+		}
+		if !v.CanSet() {
+			c := reflect.New(t).Elem()
+			c.Set(v)
+			v = c
+		}
+		f := v.FieldByIndex(sf.Index)
+		nf, err := setJSONPointer(f, rest, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		f.Set(nf)
+		return v, nil
+
+	case reflect.Slice, reflect.Array:
+		if seg == "-" && len(rest) == 0 && v.Kind() == reflect.Slice {
+			e, err := assignableValue(value, t.Elem())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			return reflect.Append(v, e), nil
+		}
+		n, err := jsonPointerIndex(seg, v.Len())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		if !v.Index(n).CanSet() {
+			// An array that is not addressable.
+			c := reflect.New(t).Elem()
+			c.Set(v)
+			v = c
+		}
+		ne, err := setJSONPointer(v.Index(n), rest, value)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		v.Index(n).Set(ne)
+		return v, nil
+
+	default:
+		return reflect.Value{}, fmt.Errorf("value of type %s has no property or item %q", t, seg)
+	}
+}
+
+// assignableValue returns value as a reflect.Value that can be assigned to a
+// value of type t.
+func assignableValue(value any, t reflect.Type) (reflect.Value, error) {
+	if value == nil {
+		switch t.Kind() {
+		case reflect.Interface, reflect.Pointer, reflect.Map, reflect.Slice:
+			return reflect.Zero(t), nil
+		}
+		return reflect.Value{}, fmt.Errorf("cannot assign null to %s", t)
+	}
+	v := reflect.ValueOf(value)
+	if v.Type().AssignableTo(t) {
+		return v, nil
+	}
+	if isNumberKind(v.Kind()) && isNumberKind(t.Kind()) {
+		// Refuse conversions that lose information, like 1.5 to int.
+		if c := v.Convert(t); c.Convert(v.Type()).Equal(v) {
+			return c, nil
+		}
+		return reflect.Value{}, fmt.Errorf("%v cannot be represented as %s", value, t)
+	}
+	return reflect.Value{}, fmt.Errorf("cannot assign %T to %s", value, t)
+}
+
+// isNumberKind reports whether k is the kind of a Go number type.
+func isNumberKind(k reflect.Kind) bool {
+	return reflect.Int <= k && k <= reflect.Float64
+}
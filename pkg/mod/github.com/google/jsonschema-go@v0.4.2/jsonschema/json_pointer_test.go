@@ -5,6 +5,7 @@
 package jsonschema
 
 import (
+	"cmp"
 	"strings"
 	"testing"
 )
@@ -137,3 +138,111 @@ func TestDereferenceJSONPointerErrors(t *testing.T) {
 		}
 	}
 }
+
+func TestGetJSONPointer(t *testing.T) {
+	type point struct {
+		X int `json:"x"`
+		Y int `json:"-"`
+	}
+	type key string
+	inst := map[string]any{
+		"points": []any{map[string]any{"x": 1.0}, &point{X: 3}},
+		"a/b":    [2]string{"c", "d"},
+		"keys":   map[key]bool{"k": true},
+		"null":   nil,
+	}
+	for _, tt := range []struct {
+		ptr  string
+		want any
+	}{
+		{"/points/0/x", 1.0},
+		{"/points/1/x", 3},
+		{"/a~1b/1", "d"},
+		{"/keys/k", true},
+		{"/null", nil},
+	} {
+		got, err := GetJSONPointer(inst, tt.ptr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.ptr, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		ptr  string
+		want string // error must contain this string
+	}{
+		{"points", "does not begin"},
+		{"/points/2", "out of bounds"},
+		{"/points/1/Y", "no property \"Y\""},
+		{"/null/x", "navigated to nil"},
+		{"/points/0/x/y", "has no property or item"},
+	} {
+		_, err := GetJSONPointer(inst, tt.ptr)
+		if err == nil {
+			t.Errorf("%q: succeeded, want failure", tt.ptr)
+		} else if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error is %q, which does not contain %q", tt.ptr, err, tt.want)
+		}
+	}
+}
+
+func TestSetJSONPointer(t *testing.T) {
+	type point struct {
+		X int            `json:"x"`
+		M map[string]any `json:"m"`
+	}
+	var inst any = map[string]any{
+		"points": []any{map[string]any{"x": 1.0}, point{X: 3}},
+		"arr":    [2]string{"c", "d"},
+		"list":   []string{"e"},
+	}
+	for _, tt := range []struct {
+		ptr   string
+		value any
+		get   string // pointer to the value that was set, if not ptr
+	}{
+		{"/points/0/x", 2.0, ""},
+		{"/points/0/y", "new", ""},
+		{"/points/1/x", 4.0, ""},
+		{"/points/1/m/k", true, ""},
+		{"/arr/1", "z", ""},
+		{"/list/-", "f", "/list/1"},
+		{"/points/-", nil, "/points/2"},
+	} {
+		if err := SetJSONPointer(&inst, tt.ptr, tt.value); err != nil {
+			t.Fatal(err)
+		}
+		got, err := GetJSONPointer(inst, cmp.Or(tt.get, tt.ptr))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !Equal(got, tt.value) {
+			t.Errorf("%s: got %v, want %v", tt.ptr, got, tt.value)
+		}
+	}
+
+	for _, tt := range []struct {
+		ptr   string
+		value any
+		want  string // error must contain this string
+	}{
+		{"/points/0/z/w", 1, "no property \"z\""},
+		{"/points/1/x", 1.5, "cannot be represented"},
+		{"/points/1/x", "s", "cannot assign string"},
+		{"/arr/-", "s", "not supported"},
+		{"/points/1/nope", 1, "no property \"nope\""},
+	} {
+		err := SetJSONPointer(&inst, tt.ptr, tt.value)
+		if err == nil {
+			t.Errorf("%q: succeeded, want failure", tt.ptr)
+		} else if !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%q: error is %q, which does not contain %q", tt.ptr, err, tt.want)
+		}
+	}
+	if err := SetJSONPointer(inst, "", 1); err == nil {
+		t.Error("non-pointer: succeeded, want failure")
+	}
+}
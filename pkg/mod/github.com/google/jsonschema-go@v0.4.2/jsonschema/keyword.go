@@ -0,0 +1,75 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements custom keywords.
+
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// A Keyword is a custom keyword, such as one from an organization-specific
+// vocabulary. Pass custom keywords to [Schema.Resolve] in
+// [ResolveOptions.Keywords] to have them participate in validation.
+//
+// The value of a custom keyword in a schema is held in [Schema.Extra], so a
+// Keyword cannot change the meaning of a keyword of the specification, and its
+// value is an ordinary JSON value rather than a subschema.
+type Keyword struct {
+	// Name is the name of the keyword in a schema, such as "x-units".
+	Name string
+	// Compile checks the keyword's value in a schema and prepares it for
+	// validation. It is called by Resolve for every schema that has the keyword.
+	// Its result is passed to Validate; an error fails the resolution.
+	// If Compile is nil, the value is passed to Validate unchanged.
+	Compile func(value any) (any, error)
+	// Validate validates an instance against the result of Compile.
+	// The instance is the value passed to [Resolved.Validate], or a part of it.
+	// An error is reported as a validation failure of the keyword.
+	// Validate is called for every type of instance, even null, and must be
+	// safe for concurrent use.
+	Validate func(compiled, instance any) error
+}
+
+// A compiledKeyword is a custom keyword in a schema, ready for validation.
+type compiledKeyword struct {
+	kw    *Keyword
+	value any // the result of kw.Compile
+}
+
+// compileKeywords compiles the custom keywords of every schema in rs.
+func compileKeywords(rs *Resolved, kws []*Keyword) error {
+	seen := map[string]bool{}
+	for _, kw := range kws {
+		if kw.Name == "" || kw.Validate == nil {
+			return errors.New("custom keyword must have a Name and a Validate function")
+		}
+		if seen[kw.Name] {
+			return fmt.Errorf("duplicate custom keyword %q", kw.Name)
+		}
+		seen[kw.Name] = true
+	}
+	var errs []error
+	for s := range rs.root.all() {
+		info := rs.resolvedInfos[s]
+		for _, kw := range kws {
+			v, ok := s.Extra[kw.Name]
+			if !ok {
+				continue
+			}
+			if kw.Compile != nil {
+				var err error
+				v, err = kw.Compile(v)
+				if err != nil {
+					errs = append(errs, fmt.Errorf("%s: keyword %q: %w", info.path, kw.Name, err))
+					continue
+				}
+			}
+			info.keywords = append(info.keywords, compiledKeyword{kw, v})
+		}
+	}
+	return errors.Join(errs...)
+}
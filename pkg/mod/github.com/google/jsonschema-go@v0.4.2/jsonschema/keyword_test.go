@@ -0,0 +1,128 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// xUnits is a custom keyword whose value is a list of allowed units.
+// An instance must be an object whose "unit" property is one of them.
+var xUnits = &Keyword{
+	Name: "x-units",
+	Compile: func(value any) (any, error) {
+		vals, ok := value.([]any)
+		if !ok {
+			return nil, errors.New("want an array")
+		}
+		units := map[string]bool{}
+		for _, v := range vals {
+			s, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("unit %v is not a string", v)
+			}
+			units[s] = true
+		}
+		return units, nil
+	},
+	Validate: func(compiled, instance any) error {
+		m, ok := instance.(map[string]any)
+		if !ok {
+			return nil
+		}
+		if u, _ := m["unit"].(string); !compiled.(map[string]bool)[u] {
+			return fmt.Errorf("unit %q is not allowed", u)
+		}
+		return nil
+	},
+}
+
+func TestCustomKeyword(t *testing.T) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+		"properties": {
+			"length": {"x-units": ["m", "km"]},
+			"time": {"x-units": ["s"]},
+			"other": {"x-ignored": 1}
+		}
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := schema.Resolve(&ResolveOptions{Keywords: []*Keyword{xUnits}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		instance string
+		wantLoc  string // keyword location of the failure, or "" if valid
+	}{
+		{`{"length": {"unit": "km"}, "time": {"unit": "s"}, "other": {}}`, ""},
+		{`{"length": 3}`, ""},
+		{`{"length": null}`, ""},
+		{`{"time": {"unit": "m"}}`, "/properties/time/x-units"},
+	} {
+		var instance any
+		if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+			t.Fatal(err)
+		}
+		err := rs.ValidateAll(instance)
+		if tt.wantLoc == "" {
+			if err != nil {
+				t.Errorf("%s: %v", tt.instance, err)
+			}
+			continue
+		}
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) || len(verrs) != 1 || verrs[0].KeywordLocation != tt.wantLoc {
+			t.Errorf("%s: got %v, want a failure at %s", tt.instance, err, tt.wantLoc)
+		} else if got, want := verrs[0].Message, `unit "m" is not allowed`; got != want {
+			t.Errorf("%s: got message %q, want %q", tt.instance, got, want)
+		}
+	}
+
+	// Without the keyword, the value is carried in Extra but ignored.
+	rs, err = schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(map[string]any{"time": map[string]any{"unit": "m"}}); err != nil {
+		t.Errorf("without keyword: %v", err)
+	}
+}
+
+func TestCustomKeywordResolve(t *testing.T) {
+	// Keywords in remote schemas are compiled too.
+	loader := func(*url.URL) (*Schema, error) {
+		return &Schema{Extra: map[string]any{"x-units": []any{"s"}}}, nil
+	}
+	s := &Schema{Ref: "https://example.com/time"}
+	rs, err := s.Resolve(&ResolveOptions{Loader: loader, Keywords: []*Keyword{xUnits}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(map[string]any{"unit": "m"}); err == nil {
+		t.Error("remote keyword: got nil error, want failure")
+	}
+
+	for _, tt := range []struct {
+		schema *Schema
+		kws    []*Keyword
+		want   string
+	}{
+		{&Schema{Extra: map[string]any{"x-units": "m"}}, []*Keyword{xUnits}, `keyword "x-units": want an array`},
+		{&Schema{}, []*Keyword{xUnits, xUnits}, "duplicate"},
+		{&Schema{}, []*Keyword{{Name: "x"}}, "Validate"},
+	} {
+		_, err := tt.schema.Resolve(&ResolveOptions{Keywords: tt.kws})
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("got %v, want error containing %q", err, tt.want)
+		}
+	}
+}
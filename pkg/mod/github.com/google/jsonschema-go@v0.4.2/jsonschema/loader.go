@@ -0,0 +1,235 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements a Loader that fetches schemas over HTTP.
+
+package jsonschema
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// HTTPLoaderOptions are options for [HTTPLoader].
+type HTTPLoaderOptions struct {
+	// Client is the client used to fetch schemas.
+	// If nil, [http.DefaultClient] is used.
+	Client *http.Client
+	// Allow, if non-empty, is a list of URL prefixes, such as
+	// "https://json-schema.org/". Only URLs that begin with one of them are
+	// loaded. A URL begins with a prefix if it has the same scheme and host,
+	// and the path of the prefix is empty or a sequence of whole segments at
+	// the start of its path. For example, "https://example.com/schemas"
+	// matches "https://example.com/schemas/a.json", but neither
+	// "https://example.com/schemas2/a.json" nor
+	// "https://example.com.evil.org/schemas/a.json".
+	// Redirects are followed only to URLs that are allowed.
+	Allow []string
+	// Deny is a list of URL prefixes, matched like those of Allow. URLs that
+	// begin with one of them are not loaded, even if they are allowed by Allow.
+	Deny []string
+	// CacheDir, if non-empty, is a directory in which to cache fetched schemas.
+	// A cached schema is used instead of fetching its URL again, no matter how
+	// old it is. Remove files from the directory to refetch them.
+	// The directory is created if it does not exist.
+	CacheDir string
+	// MaxSize is the maximum size of a schema document, in bytes.
+	// If zero, the limit is 10 MiB.
+	MaxSize int64
+}
+
+// defaultMaxSchemaSize is the default value of [HTTPLoaderOptions.MaxSize].
+const defaultMaxSchemaSize = 10 << 20
+
+// HTTPLoader returns a [Loader] that fetches schemas with HTTP GET requests.
+// Only http and https URLs are loaded.
+// The context governs all requests made by the loader.
+// If opts is nil, the default values are used.
+//
+// Pass the loader in [ResolveOptions.Loader], and see
+// [ResolveOptions.MaxLoadDepth] to limit how deeply remote references are
+// followed.
+func HTTPLoader(ctx context.Context, opts *HTTPLoaderOptions) Loader {
+	load := HTTPContextLoader(opts)
+	return func(uri *url.URL) (*Schema, error) { return load(ctx, uri) }
+}
+
+// HTTPContextLoader is like [HTTPLoader], but returns a [ContextLoader],
+// whose requests are governed by the context passed to each call. Pass it in
+// [ResolveOptions.ContextLoader] and call [Schema.ResolveContext] to bound
+// the time taken to resolve a schema.
+func HTTPContextLoader(opts *HTTPLoaderOptions) ContextLoader {
+	var o HTTPLoaderOptions
+	if opts != nil {
+		o = *opts
+	}
+	if o.Client == nil {
+		o.Client = http.DefaultClient
+	}
+	if o.MaxSize == 0 {
+		o.MaxSize = defaultMaxSchemaSize
+	}
+	allow, errAllow := parsePrefixes(o.Allow)
+	deny, errDeny := parsePrefixes(o.Deny)
+	allowed := func(u *url.URL) bool {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return false
+		}
+		if slices.ContainsFunc(deny, func(p *url.URL) bool { return hasURLPrefix(u, p) }) {
+			return false
+		}
+		return len(allow) == 0 || slices.ContainsFunc(allow, func(p *url.URL) bool { return hasURLPrefix(u, p) })
+	}
+	// Check every redirect, so that a redirect cannot escape Allow or Deny.
+	client := *o.Client
+	checkRedirect := client.CheckRedirect
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		if !allowed(req.URL) {
+			return fmt.Errorf("redirect to %s is not allowed", req.URL)
+		}
+		if checkRedirect != nil {
+			return checkRedirect(req, via)
+		}
+		// The default policy of http.Client.
+		if len(via) >= 10 {
+			return errors.New("stopped after 10 redirects")
+		}
+		return nil
+	}
+	o.Client = &client
+	return func(ctx context.Context, uri *url.URL) (*Schema, error) {
+		if err := cmp.Or(errAllow, errDeny); err != nil {
+			return nil, err
+		}
+		if uri.Scheme != "http" && uri.Scheme != "https" {
+			return nil, fmt.Errorf("unsupported scheme %q", uri.Scheme)
+		}
+		u := uri.String()
+		if !allowed(uri) {
+			return nil, fmt.Errorf("%s is not allowed", u)
+		}
+		var cacheFile string
+		if o.CacheDir != "" {
+			sum := sha256.Sum256([]byte(u))
+			cacheFile = filepath.Join(o.CacheDir, hex.EncodeToString(sum[:])+".json")
+			data, err := os.ReadFile(cacheFile)
+			if err == nil {
+				return unmarshalSchema(data)
+			}
+			if !errors.Is(err, fs.ErrNotExist) {
+				return nil, err
+			}
+		}
+		data, err := o.fetch(ctx, u)
+		if err != nil {
+			return nil, err
+		}
+		s, err := unmarshalSchema(data)
+		if err != nil {
+			return nil, err
+		}
+		if cacheFile != "" {
+			if err := writeFileAtomic(cacheFile, data); err != nil {
+				return nil, fmt.Errorf("caching schema: %w", err)
+			}
+		}
+		return s, nil
+	}
+}
+
+// parsePrefixes parses the URL prefixes of [HTTPLoaderOptions.Allow] or
+// [HTTPLoaderOptions.Deny]. Each must have a scheme and a host.
+func parsePrefixes(prefixes []string) ([]*url.URL, error) {
+	var us []*url.URL
+	for _, p := range prefixes {
+		u, err := url.Parse(p)
+		if err != nil {
+			return nil, fmt.Errorf("URL prefix %q: %w", p, err)
+		}
+		if u.Scheme == "" || u.Host == "" {
+			return nil, fmt.Errorf("URL prefix %q must have a scheme and a host", p)
+		}
+		us = append(us, u)
+	}
+	return us, nil
+}
+
+// hasURLPrefix reports whether u begins with the URL prefix p, as described
+// for [HTTPLoaderOptions.Allow].
+func hasURLPrefix(u, p *url.URL) bool {
+	if !strings.EqualFold(u.Scheme, p.Scheme) || !strings.EqualFold(u.Host, p.Host) {
+		return false
+	}
+	prefix := strings.TrimSuffix(p.EscapedPath(), "/")
+	path := u.EscapedPath()
+	return prefix == "" || path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// fetch returns the body of the document at u.
+func (o *HTTPLoaderOptions) fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/schema+json, application/json")
+	resp, err := o.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("GET %s: %s", u, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, o.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > o.MaxSize {
+		return nil, fmt.Errorf("GET %s: schema is larger than %d bytes", u, o.MaxSize)
+	}
+	return data, nil
+}
+
+func unmarshalSchema(data []byte) (*Schema, error) {
+	var s Schema
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// writeFileAtomic writes data to name by writing a temporary file in the same
+// directory and renaming it, so that readers never see a partial file.
+func writeFileAtomic(name string, data []byte) error {
+	dir := filepath.Dir(name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, filepath.Base(name)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // fails harmlessly after the rename
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
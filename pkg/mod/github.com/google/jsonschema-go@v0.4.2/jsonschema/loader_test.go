@@ -0,0 +1,187 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPLoader(t *testing.T) {
+	var requests atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/string.json", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"type": "string"}`))
+	})
+	mux.HandleFunc("/big.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"description": "` + strings.Repeat("x", 100) + `"}`))
+	})
+	// /slow.json never responds.
+	mux.HandleFunc("/slow.json", func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	// /chain/N refers to /chain/N+1, forever.
+	mux.HandleFunc("/chain/{n}", func(w http.ResponseWriter, r *http.Request) {
+		n, _ := strconv.Atoi(r.PathValue("n"))
+		fmt.Fprintf(w, `{"$ref": "%d"}`, n+1)
+	})
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+
+	ctx := context.Background()
+	resolve := func(loader Loader, ref string) error {
+		t.Helper()
+		s := &Schema{Ref: srv.URL + ref}
+		rs, err := s.Resolve(&ResolveOptions{Loader: loader})
+		if err != nil {
+			return err
+		}
+		if err := rs.Validate(1); err == nil {
+			t.Errorf("%s: validated 1, want error", ref)
+		}
+		return nil
+	}
+
+	t.Run("load", func(t *testing.T) {
+		if err := resolve(HTTPLoader(ctx, nil), "/string.json"); err != nil {
+			t.Fatal(err)
+		}
+	})
+	t.Run("not found", func(t *testing.T) {
+		if err := resolve(HTTPLoader(ctx, nil), "/missing.json"); err == nil || !strings.Contains(err.Error(), "404") {
+			t.Errorf("got %v, want 404 error", err)
+		}
+	})
+	t.Run("scheme", func(t *testing.T) {
+		if _, err := HTTPLoader(ctx, nil)(&url.URL{Scheme: "file", Path: "/etc/passwd"}); err == nil {
+			t.Error("loaded file URL, want error")
+		}
+	})
+	t.Run("allow and deny", func(t *testing.T) {
+		loader := HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{"https://example.com/"}})
+		if err := resolve(loader, "/string.json"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("not in Allow: got %v, want error", err)
+		}
+		loader = HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{srv.URL}, Deny: []string{srv.URL + "/string.json"}})
+		if err := resolve(loader, "/string.json"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("in Deny: got %v, want error", err)
+		}
+		// A prefix matches whole path segments.
+		loader = HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{srv.URL}, Deny: []string{srv.URL + "/string"}})
+		if err := resolve(loader, "/string.json"); err != nil {
+			t.Errorf("partial segment in Deny: got %v, want nil", err)
+		}
+		// A redirect must also be allowed.
+		other := httptest.NewServer(mux)
+		defer other.Close()
+		mux.HandleFunc("/redirect.json", func(w http.ResponseWriter, r *http.Request) {
+			http.Redirect(w, r, other.URL+"/string.json", http.StatusFound)
+		})
+		loader = HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{srv.URL}})
+		if err := resolve(loader, "/redirect.json"); err == nil || !strings.Contains(err.Error(), "not allowed") {
+			t.Errorf("redirect outside Allow: got %v, want error", err)
+		}
+		loader = HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{srv.URL, other.URL}})
+		if err := resolve(loader, "/redirect.json"); err != nil {
+			t.Errorf("redirect inside Allow: got %v, want nil", err)
+		}
+		loader = HTTPLoader(ctx, &HTTPLoaderOptions{Allow: []string{"/relative"}})
+		if err := resolve(loader, "/string.json"); err == nil {
+			t.Error("prefix without host: got nil error, want error")
+		}
+	})
+	t.Run("max size", func(t *testing.T) {
+		if err := resolve(HTTPLoader(ctx, &HTTPLoaderOptions{MaxSize: 50}), "/big.json"); err == nil || !strings.Contains(err.Error(), "larger") {
+			t.Errorf("got %v, want size error", err)
+		}
+	})
+	t.Run("cache", func(t *testing.T) {
+		dir := t.TempDir()
+		loader := HTTPLoader(ctx, &HTTPLoaderOptions{CacheDir: dir})
+		before := requests.Load()
+		for range 2 {
+			if err := resolve(loader, "/string.json"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := requests.Load() - before; got != 1 {
+			t.Errorf("got %d requests, want 1", got)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) != 1 {
+			t.Errorf("cache dir has %d entries (err %v), want 1", len(entries), err)
+		}
+	})
+	t.Run("max depth", func(t *testing.T) {
+		s := &Schema{Ref: srv.URL + "/chain/0"}
+		_, err := s.Resolve(&ResolveOptions{Loader: HTTPLoader(ctx, nil), MaxLoadDepth: 5})
+		if err == nil || !strings.Contains(err.Error(), "more than 5 deep") {
+			t.Errorf("got %v, want depth error", err)
+		}
+	})
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if err := resolve(HTTPLoader(ctx, nil), "/string.json"); err == nil {
+			t.Error("got nil error, want error")
+		}
+	})
+	t.Run("resolve context", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+		defer cancel()
+		s := &Schema{Ref: srv.URL + "/slow.json"}
+		_, err := s.ResolveContext(ctx, &ResolveOptions{ContextLoader: HTTPContextLoader(nil)})
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("got %v, want context.DeadlineExceeded", err)
+		}
+	})
+	t.Run("resolve canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		loader := func(uri *url.URL) (*Schema, error) {
+			t.Errorf("loader called for %s", uri)
+			return &Schema{}, nil
+		}
+		s := &Schema{Ref: srv.URL + "/string.json"}
+		if _, err := s.ResolveContext(ctx, &ResolveOptions{Loader: loader}); !errors.Is(err, context.Canceled) {
+			t.Errorf("got %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestHasURLPrefix(t *testing.T) {
+	for _, tt := range []struct {
+		u, prefix string
+		want      bool
+	}{
+		{"https://example.com/a.json", "https://example.com", true},
+		{"https://example.com/a.json", "https://example.com/", true},
+		{"https://EXAMPLE.com/a.json", "https://example.com/", true},
+		{"https://example.com.evil.org/a.json", "https://example.com", false},
+		{"https://example.com:8443/a.json", "https://example.com", false},
+		{"http://example.com/a.json", "https://example.com/", false},
+		{"https://example.com/schemas/a.json", "https://example.com/schemas", true},
+		{"https://example.com/schemas/a.json", "https://example.com/schemas/", true},
+		{"https://example.com/schemas", "https://example.com/schemas/", true},
+		{"https://example.com/schemas2/a.json", "https://example.com/schemas", false},
+	} {
+		u, _ := url.Parse(tt.u)
+		p, _ := url.Parse(tt.prefix)
+		if got := hasURLPrefix(u, p); got != tt.want {
+			t.Errorf("hasURLPrefix(%s, %s) = %t, want %t", tt.u, tt.prefix, got, tt.want)
+		}
+	}
+}
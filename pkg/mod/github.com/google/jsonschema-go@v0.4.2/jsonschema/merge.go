@@ -0,0 +1,388 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the merging of allOf branches.
+
+package jsonschema
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"reflect"
+	"slices"
+)
+
+// Merge returns a copy of s in which the branches of each "allOf" are merged
+// into the schema containing the allOf, where that does not change which
+// instances the schema validates. It is intended to simplify schemas, such as
+// those produced by code generators, for display.
+//
+// Merging intersects types, enums and bounds, and unions required
+// properties and properties. A property in more than one branch gets the
+// merge of its schemas. A branch stays in the allOf if merging it would
+// change the schema's meaning, for example because it has a "$ref", or
+// an "additionalProperties" that depends on the properties beside it.
+//
+// If the constraints of some branches conflict, so that no instance can
+// satisfy them all, Merge leaves the branches in the allOf and returns the
+// schema together with an error describing the conflicts.
+func Merge(s *Schema) (*Schema, error) {
+	c := s.CloneSchemas()
+	mg := &merger{}
+	mg.mergeAllOf(c, "")
+	return c, errors.Join(mg.errs...)
+}
+
+// A merger merges allOf branches.
+type merger struct {
+	errs []error // conflicts
+}
+
+// conflict records a conflict in the schema at the JSON Pointer path.
+func (mg *merger) conflict(path, format string, args ...any) {
+	mg.errs = append(mg.errs, fmt.Errorf("%s: %s", path, fmt.Sprintf(format, args...)))
+}
+
+// mergeAllOf merges the allOf branches of s and its subschemas. The JSON
+// Pointer to s is path.
+func (mg *merger) mergeAllOf(s *Schema, path string) {
+	// Merge subschemas first, so merged branches are as simple as possible.
+	for token, c := range s.childPaths() {
+		mg.mergeAllOf(c, path+"/"+token)
+	}
+	if s.UnevaluatedItems != nil || s.UnevaluatedProperties != nil {
+		// Unevaluated keywords see the annotations of allOf branches.
+		return
+	}
+	var rest []*Schema
+	for i, b := range s.AllOf {
+		m := *s
+		if !mg.mergeInto(&m, b, fmt.Sprintf("%s/allOf/%d", path, i)) {
+			rest = append(rest, b)
+			continue
+		}
+		*s = m
+		// The branches of a merged branch belong to s.
+		rest = append(rest, b.AllOf...)
+	}
+	s.AllOf = rest
+}
+
+// both returns a schema that requires both a and b, either of which may be
+// nil, merging them if possible. The JSON Pointer to b is path.
+func (mg *merger) both(a, b *Schema, path string) *Schema {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	}
+	if a.UnevaluatedItems == nil && a.UnevaluatedProperties == nil {
+		m := *a
+		if mg.mergeInto(&m, b, path) {
+			m.AllOf = append(slices.Clip(m.AllOf), b.AllOf...)
+			return &m
+		}
+	}
+	return &Schema{AllOf: []*Schema{a, b}}
+}
+
+// unmergeableFields are the fields of Schema that prevent a branch from
+// being merged, because they identify the branch or depend on its
+// location.
+var unmergeableFields = []string{
+	"ID", "Schema", "Ref", "Anchor", "DynamicAnchor", "DynamicRef", "RecursiveAnchor",
+	"RecursiveRef", "Defs", "Definitions", "Vocabulary", "UnevaluatedItems", "UnevaluatedProperties",
+}
+
+// mergeInto merges the branch b, whose JSON Pointer is path, into d, except
+// for the allOf of b. It reports whether b could be merged, recording a
+// conflict if b and d are contradictory. It does not modify the slices or
+// maps of d, so d can be a shallow copy of another schema. If mergeInto
+// returns false, d may be partly modified.
+func (mg *merger) mergeInto(d, b *Schema, path string) bool {
+	bv := reflect.ValueOf(b).Elem()
+	for _, name := range unmergeableFields {
+		if !bv.FieldByName(name).IsZero() {
+			return false
+		}
+	}
+
+	// Annotations of d take precedence.
+	d.Title = cmp.Or(d.Title, b.Title)
+	d.Description = cmp.Or(d.Description, b.Description)
+	d.Comment = cmp.Or(d.Comment, b.Comment)
+	if d.Default == nil {
+		d.Default = b.Default
+	}
+	if d.Examples == nil {
+		d.Examples = b.Examples
+	}
+	d.Deprecated = d.Deprecated || b.Deprecated
+	d.ReadOnly = d.ReadOnly || b.ReadOnly
+	d.WriteOnly = d.WriteOnly || b.WriteOnly
+
+	// Types.
+	if dt, bt := schemaTypes(d), schemaTypes(b); bt != nil {
+		types := bt
+		if dt != nil {
+			types = nil
+			for _, t := range dt {
+				switch {
+				case slices.Contains(bt, t):
+					types = append(types, t)
+				case t == "number" && slices.Contains(bt, "integer"):
+					types = append(types, "integer")
+				case t == "integer" && slices.Contains(bt, "number"):
+					types = append(types, "integer")
+				}
+			}
+			types = sortedSet(types)
+			if len(types) == 0 {
+				mg.conflict(path, "no type is allowed by both %q and %q", dt, bt)
+				return false
+			}
+		}
+		d.Type, d.Types = "", nil
+		if len(types) == 1 {
+			d.Type = types[0]
+		} else {
+			d.Types = types
+		}
+	}
+
+	// Values.
+	if b.Enum != nil {
+		if d.Enum == nil {
+			d.Enum = b.Enum
+		} else {
+			var enum []any
+			for _, v := range d.Enum {
+				if slices.ContainsFunc(b.Enum, func(w any) bool { return Equal(v, w) }) {
+					enum = append(enum, v)
+				}
+			}
+			if len(enum) == 0 {
+				mg.conflict(path, "the enums have no values in common")
+				return false
+			}
+			d.Enum = enum
+		}
+	}
+	if b.Const != nil {
+		if d.Const != nil && !Equal(*d.Const, *b.Const) {
+			mg.conflict(path, "different consts %v and %v", *d.Const, *b.Const)
+			return false
+		}
+		d.Const = b.Const
+	}
+
+	// Numbers.
+	if b.MultipleOf != nil {
+		if d.MultipleOf != nil {
+			lo, hi := min(*d.MultipleOf, *b.MultipleOf), max(*d.MultipleOf, *b.MultipleOf)
+			if q := hi / lo; q != math.Trunc(q) {
+				return false
+			}
+			d.MultipleOf = &hi
+		} else {
+			d.MultipleOf = b.MultipleOf
+		}
+	}
+	// The boolean forms of exclusiveMinimum and exclusiveMaximum change the
+	// meaning of minimum and maximum, and the numeric forms are not valid
+	// beside them, so a schema with them is merged only with one without
+	// bounds.
+	hasBools := func(s *Schema) bool { return s.ExclusiveMinimumBool != nil || s.ExclusiveMaximumBool != nil }
+	hasBounds := func(s *Schema) bool {
+		return s.Minimum != nil || s.Maximum != nil || s.ExclusiveMinimum != nil || s.ExclusiveMaximum != nil || hasBools(s)
+	}
+	if hasBools(b) && hasBounds(d) || hasBools(d) && hasBounds(b) {
+		return false
+	}
+	if hasBools(b) {
+		d.ExclusiveMinimumBool, d.ExclusiveMaximumBool = b.ExclusiveMinimumBool, b.ExclusiveMaximumBool
+	}
+	d.Minimum = mergeBound(d.Minimum, b.Minimum, false)
+	d.ExclusiveMinimum = mergeBound(d.ExclusiveMinimum, b.ExclusiveMinimum, false)
+	d.Maximum = mergeBound(d.Maximum, b.Maximum, true)
+	d.ExclusiveMaximum = mergeBound(d.ExclusiveMaximum, b.ExclusiveMaximum, true)
+	if lower, lowerExcl, upper, upperExcl := numberBounds(d); lower != nil && upper != nil && (*lower > *upper || *lower == *upper && (lowerExcl || upperExcl)) {
+		mg.conflict(path, "no number is within the bounds %v and %v", *lower, *upper)
+		return false
+	}
+
+	// Lengths and counts.
+	for _, p := range []struct {
+		what       string
+		dMin, dMax **int
+		bMin, bMax *int
+	}{
+		{"string length", &d.MinLength, &d.MaxLength, b.MinLength, b.MaxLength},
+		{"array length", &d.MinItems, &d.MaxItems, b.MinItems, b.MaxItems},
+		{"number of properties", &d.MinProperties, &d.MaxProperties, b.MinProperties, b.MaxProperties},
+	} {
+		*p.dMin = mergeBound(*p.dMin, p.bMin, false)
+		*p.dMax = mergeBound(*p.dMax, p.bMax, true)
+		if *p.dMin != nil && *p.dMax != nil && **p.dMin > **p.dMax {
+			mg.conflict(path, "no %s is within the bounds %d and %d", p.what, **p.dMin, **p.dMax)
+			return false
+		}
+	}
+
+	// Strings.
+	for _, p := range []struct{ d, b *string }{
+		{&d.Pattern, &b.Pattern},
+		{&d.Format, &b.Format},
+		{&d.ContentEncoding, &b.ContentEncoding},
+		{&d.ContentMediaType, &b.ContentMediaType},
+	} {
+		if *p.d != "" && *p.b != "" && *p.d != *p.b {
+			return false
+		}
+		*p.d = cmp.Or(*p.d, *p.b)
+	}
+
+	// Objects. An additionalProperties applies to the properties that the
+	// properties and patternProperties beside it do not, so merging would
+	// change its meaning.
+	if b.AdditionalProperties != nil && (d.Properties != nil || d.PatternProperties != nil || d.AdditionalProperties != nil) ||
+		d.AdditionalProperties != nil && (b.Properties != nil || b.PatternProperties != nil) {
+		return false
+	}
+	if d.AdditionalProperties == nil {
+		d.AdditionalProperties = b.AdditionalProperties
+	}
+	d.Properties = mg.schemaMaps(d.Properties, b.Properties, path+"/properties")
+	d.PatternProperties = mg.schemaMaps(d.PatternProperties, b.PatternProperties, path+"/patternProperties")
+	d.DependentSchemas = mg.schemaMaps(d.DependentSchemas, b.DependentSchemas, path+"/dependentSchemas")
+	d.DependencySchemas = mg.schemaMaps(d.DependencySchemas, b.DependencySchemas, path+"/dependencies")
+	d.PropertyNames = mg.both(d.PropertyNames, b.PropertyNames, path+"/propertyNames")
+	d.Required = mergeStrings(d.Required, b.Required)
+	d.DependentRequired = mergeStringMaps(d.DependentRequired, b.DependentRequired)
+	d.DependencyStrings = mergeStringMaps(d.DependencyStrings, b.DependencyStrings)
+	for _, k := range b.PropertyOrder {
+		if !slices.Contains(d.PropertyOrder, k) {
+			d.PropertyOrder = append(slices.Clip(d.PropertyOrder), k)
+		}
+	}
+
+	// Arrays. Keywords for positional items interact like those for
+	// properties.
+	positional := func(s *Schema) bool {
+		return s.PrefixItems != nil || s.ItemsArray != nil || s.AdditionalItems != nil
+	}
+	if (positional(d) || positional(b)) && (positional(d) || d.Items != nil) && (positional(b) || b.Items != nil) {
+		return false
+	}
+	if d.PrefixItems == nil && d.ItemsArray == nil && d.AdditionalItems == nil {
+		d.PrefixItems, d.ItemsArray, d.AdditionalItems = b.PrefixItems, b.ItemsArray, b.AdditionalItems
+	}
+	d.Items = mg.both(d.Items, b.Items, path+"/items")
+	d.UniqueItems = d.UniqueItems || b.UniqueItems
+
+	// Keywords that cannot be combined if both schemas have them.
+	for _, group := range [][]string{
+		{"Contains", "MinContains", "MaxContains"},
+		{"If", "Then", "Else"},
+		{"AnyOf"},
+		{"OneOf"},
+		{"Not"},
+		{"ContentSchema"},
+	} {
+		dv := reflect.ValueOf(d).Elem()
+		if slices.ContainsFunc(group, func(f string) bool { return !dv.FieldByName(f).IsZero() }) {
+			if slices.ContainsFunc(group, func(f string) bool { return !bv.FieldByName(f).IsZero() }) {
+				return false
+			}
+			continue
+		}
+		for _, f := range group {
+			dv.FieldByName(f).Set(bv.FieldByName(f))
+		}
+	}
+
+	// Custom keywords.
+	for k, v := range b.Extra {
+		if dv, ok := d.Extra[k]; ok {
+			if !Equal(dv, v) {
+				return false
+			}
+			continue
+		}
+		d.Extra = maps.Clone(d.Extra)
+		if d.Extra == nil {
+			d.Extra = map[string]any{}
+		}
+		d.Extra[k] = v
+	}
+	return true
+}
+
+// mergeBound returns the tighter of the lower or upper bounds a and b,
+// or whichever is non-nil.
+func mergeBound[T int | float64](a, b *T, upper bool) *T {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case upper:
+		return Ptr(min(*a, *b))
+	default:
+		return Ptr(max(*a, *b))
+	}
+}
+
+// schemaMaps returns the union of a and b, merging the schemas of keys in
+// both. The JSON Pointer to b is path.
+func (mg *merger) schemaMaps(a, b map[string]*Schema, path string) map[string]*Schema {
+	if b == nil {
+		return a
+	}
+	m := maps.Clone(a)
+	if m == nil {
+		m = map[string]*Schema{}
+	}
+	for _, k := range slices.Sorted(maps.Keys(b)) {
+		m[k] = mg.both(m[k], b[k], path+"/"+escapeJSONPointerSegment(k))
+	}
+	return m
+}
+
+// mergeStrings returns a followed by the elements of b not in a.
+func mergeStrings(a, b []string) []string {
+	if b == nil {
+		return a
+	}
+	r := slices.Clone(a)
+	if r == nil {
+		r = []string{}
+	}
+	for _, s := range b {
+		if !slices.Contains(r, s) {
+			r = append(r, s)
+		}
+	}
+	return r
+}
+
+// mergeStringMaps returns the union of a and b, merging the slices of keys
+// in both.
+func mergeStringMaps(a, b map[string][]string) map[string][]string {
+	if b == nil {
+		return a
+	}
+	m := maps.Clone(a)
+	if m == nil {
+		m = map[string][]string{}
+	}
+	for k, ss := range b {
+		m[k] = mergeStrings(m[k], ss)
+	}
+	return m
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestMerge(t *testing.T) {
+	for _, tt := range []struct {
+		schema  string
+		want    string
+		wantErr string // substring of the error, if any
+	}{
+		{
+			`{"allOf": [{"type": ["number", "string"]}, {"type": ["integer", "null"]}]}`,
+			`{"type":"integer"}`,
+			"",
+		},
+		{
+			`{"type": "object", "required": ["a"], "properties": {"a": {"type": "string"}},
+			  "allOf": [
+			    {"required": ["b"], "properties": {"a": {"minLength": 1}, "b": {"type": "integer"}}},
+			    {"title": "T", "properties": {"a": {"maxLength": 3}}}
+			  ]}`,
+			`{"properties":{"a":{"maxLength":3,"minLength":1,"type":"string"},"b":{"type":"integer"}},"required":["a","b"],"title":"T","type":"object"}`,
+			"",
+		},
+		{
+			`{"allOf": [{"allOf": [{"minimum": 1}, {"minimum": 3}]}, {"exclusiveMaximum": 5}]}`,
+			`{"exclusiveMaximum":5,"minimum":3}`,
+			"",
+		},
+		{
+			// A $ref depends on the location of the branch.
+			`{"$defs": {"d": {"type": "string"}}, "allOf": [{"$ref": "#/$defs/d"}, {"maxLength": 2}]}`,
+			`{"$defs":{"d":{"type":"string"}},"allOf":[{"$ref":"#/$defs/d"}],"maxLength":2}`,
+			"",
+		},
+		{
+			// additionalProperties depends on the properties beside it.
+			`{"properties": {"a": true}, "allOf": [{"additionalProperties": false}]}`,
+			`{"allOf":[{"additionalProperties":false}],"properties":{"a":true}}`,
+			"",
+		},
+		{
+			`{"type": "string", "allOf": [{"type": "integer"}, {"maxLength": 2}]}`,
+			`{"allOf":[{"type":"integer"}],"maxLength":2,"type":"string"}`,
+			`/allOf/0: no type is allowed by both ["string"] and ["integer"]`,
+		},
+		{
+			// A draft-04 boolean exclusiveMinimum stays with its minimum.
+			`{"allOf": [{"minimum": 5, "exclusiveMinimum": true}]}`,
+			`{"exclusiveMinimum":true,"minimum":5}`,
+			"",
+		},
+		{
+			// Merging would make the exclusive minimum apply to the other one.
+			`{"minimum": 7, "allOf": [{"minimum": 5, "exclusiveMinimum": true}]}`,
+			`{"allOf":[{"exclusiveMinimum":true,"minimum":5}],"minimum":7}`,
+			"",
+		},
+		{
+			`{"maximum": 5, "exclusiveMaximum": true, "allOf": [{"maximum": 3}]}`,
+			`{"allOf":[{"maximum":3}],"exclusiveMaximum":true,"maximum":5}`,
+			"",
+		},
+		{
+			`{"properties": {"a": {"minimum": 4}}, "allOf": [{"properties": {"a": {"exclusiveMaximum": 4}}}]}`,
+			`{"properties":{"a":{"allOf":[{"minimum":4},{"exclusiveMaximum":4}]}}}`,
+			`/allOf/0/properties/a: no number is within the bounds 4 and 4`,
+		},
+	} {
+		var s Schema
+		if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		before, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m, err := Merge(&s)
+		if tt.wantErr == "" && err != nil {
+			t.Errorf("%s: %v", tt.schema, err)
+		}
+		if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+			t.Errorf("%s: got error %v, want it to contain %q", tt.schema, err, tt.wantErr)
+		}
+		got, err := m.Canonical()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != tt.want {
+			t.Errorf("%s:\ngot  %s\nwant %s", tt.schema, got, tt.want)
+		}
+		after, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(after) != string(before) {
+			t.Errorf("%s: Merge modified its argument:\n%s", tt.schema, after)
+		}
+	}
+}
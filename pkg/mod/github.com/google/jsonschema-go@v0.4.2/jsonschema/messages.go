@@ -0,0 +1,47 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements catalogs of validation error messages.
+
+package jsonschema
+
+import "fmt"
+
+// A MessageCatalog produces the messages of validation errors, for example to
+// show them to users in their own language. Pass a catalog to [Schema.Resolve]
+// in [ResolveOptions.Messages]. To validate for users with different
+// languages, resolve the schema once for each language.
+//
+// A catalog receives each message in English, as a format string for
+// [fmt.Sprintf] and its arguments. The format string identifies the message,
+// in the manner of golang.org/x/text/message. For example, the message for a
+// failure of "minLength" has the format
+//
+//	%q contains %d Unicode code points, fewer than %d
+//
+// and its arguments are the string, its length and the minimum length.
+// A number from the instance is a *[math/big.Rat].
+type MessageCatalog interface {
+	// Message returns the message for a failure of keyword, whose English
+	// message is fmt.Sprintf(format, args...).
+	// It must be safe for concurrent use.
+	Message(keyword, format string, args []any) string
+}
+
+// A MessageMap is a [MessageCatalog] that maps the English format strings of
+// messages to format strings in another language. The format strings can use
+// explicit argument indexes, such as %[2]d, to change the order of the
+// arguments. Messages whose format strings are not in the map are in English.
+type MessageMap map[string]string
+
+// Message implements [MessageCatalog.Message].
+func (m MessageMap) Message(keyword, format string, args []any) string {
+	if f, ok := m[format]; ok {
+		format = f
+	}
+	return fmt.Sprintf(format, args...)
+}
+
+// English is the default catalog, whose messages are in English.
+var English MessageCatalog = MessageMap(nil)
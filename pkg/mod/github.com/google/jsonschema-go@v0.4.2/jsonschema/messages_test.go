@@ -0,0 +1,71 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// keywordCatalog is a MessageCatalog that reports the keyword and arguments.
+type keywordCatalog struct{}
+
+func (keywordCatalog) Message(keyword, format string, args []any) string {
+	return fmt.Sprintf("%s%v", keyword, args)
+}
+
+func TestMessages(t *testing.T) {
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MinLength: Ptr(3)},
+			"age":  {Type: "integer", Maximum: Ptr(150.0)},
+		},
+	}
+	instance := map[string]any{"name": "Al", "age": 200}
+	for _, tt := range []struct {
+		catalog MessageCatalog
+		want    map[string]string // from keyword to message
+	}{
+		{nil, map[string]string{
+			"minLength": `"Al" contains 2 Unicode code points, fewer than 3`,
+			"maximum":   "200/1 is greater than 150.000000",
+		}},
+		{English, map[string]string{
+			"minLength": `"Al" contains 2 Unicode code points, fewer than 3`,
+			"maximum":   "200/1 is greater than 150.000000",
+		}},
+		{MessageMap{
+			"%q contains %d Unicode code points, fewer than %d": "il faut au moins %[3]d caractères",
+		}, map[string]string{
+			"minLength": "il faut au moins 3 caractères",
+			"maximum":   "200/1 is greater than 150.000000",
+		}},
+		{keywordCatalog{}, map[string]string{
+			"minLength": "minLength[Al 2 3]",
+			"maximum":   "maximum[200/1 150]",
+		}},
+	} {
+		rs, err := schema.Resolve(&ResolveOptions{Messages: tt.catalog})
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = rs.ValidateAll(instance)
+		var verrs ValidationErrors
+		if !errors.As(err, &verrs) {
+			t.Fatalf("got %v, want ValidationErrors", err)
+		}
+		got := map[string]string{}
+		for _, e := range verrs {
+			got[e.Keyword] = e.Message
+		}
+		for kw, w := range tt.want {
+			if got[kw] != w {
+				t.Errorf("%T: %s: got %q, want %q", tt.catalog, kw, got[kw], w)
+			}
+		}
+	}
+}
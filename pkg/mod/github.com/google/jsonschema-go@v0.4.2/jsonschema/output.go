@@ -0,0 +1,130 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the standard output formats for validation results.
+// See https://json-schema.org/draft/2020-12/json-schema-core#section-12.
+
+package jsonschema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// An OutputFormat is one of the standard formats for reporting the result of
+// validation.
+type OutputFormat string
+
+const (
+	// OutputFlag reports only whether the instance is valid.
+	OutputFlag OutputFormat = "flag"
+	// OutputBasic reports the failures as a flat list.
+	OutputBasic OutputFormat = "basic"
+	// OutputDetailed reports the failures in a hierarchy that follows the
+	// structure of the schema. Branches of the hierarchy that lead to a single
+	// failure are condensed into that failure.
+	OutputDetailed OutputFormat = "detailed"
+)
+
+// An OutputUnit is a node of validation output. It marshals to JSON as
+// described by the specification.
+//
+// The root unit of the output describes the entire instance and schema.
+// In the basic and detailed formats, its Errors hold the failures.
+// OutputUnits do not report annotations.
+type OutputUnit struct {
+	// Valid reports whether the instance location satisfies the keyword
+	// location.
+	Valid bool `json:"valid"`
+	// KeywordLocation is the JSON Pointer to a keyword or subschema, along the
+	// path that validation took from the root schema. See
+	// [ValidationError.KeywordLocation].
+	KeywordLocation string `json:"keywordLocation"`
+	// AbsoluteKeywordLocation is the absolute URI of the failed keyword,
+	// if known. It is set only on failures.
+	AbsoluteKeywordLocation string `json:"absoluteKeywordLocation,omitempty"`
+	// InstanceLocation is the JSON Pointer to a value in the instance.
+	InstanceLocation string `json:"instanceLocation"`
+	// Error describes a failure.
+	Error string `json:"error,omitempty"`
+	// Errors holds the units nested under this one.
+	Errors []*OutputUnit `json:"errors,omitempty"`
+}
+
+// ValidateOutput validates the instance like [Resolved.ValidateAll], and
+// returns the result in the given output format.
+// The verbose format, which also reports successful validations, is not
+// supported.
+//
+// ValidateOutput returns an error only if the instance cannot be validated,
+// for example because it is not a JSON value.
+func (rs *Resolved) ValidateOutput(instance any, format OutputFormat) (*OutputUnit, error) {
+	switch format {
+	case OutputFlag, OutputBasic, OutputDetailed:
+	default:
+		return nil, fmt.Errorf("unsupported output format %q", format)
+	}
+	err := rs.ValidateAll(instance)
+	root := &OutputUnit{Valid: err == nil}
+	if err == nil {
+		return root, nil
+	}
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil, err
+	}
+	switch format {
+	case OutputBasic:
+		for _, e := range verrs {
+			root.Errors = append(root.Errors, e.outputUnit())
+		}
+	case OutputDetailed:
+		for _, e := range verrs {
+			// Find or create the unit for each scope below the root,
+			// then add the failure to the innermost one.
+			u := root
+			for _, sc := range e.scopes[1:] {
+				u = u.child(sc)
+			}
+			u.Errors = append(u.Errors, e.outputUnit())
+		}
+		root.condense()
+	}
+	return root, nil
+}
+
+// outputUnit returns the output unit for the failure.
+func (e *ValidationError) outputUnit() *OutputUnit {
+	return &OutputUnit{
+		KeywordLocation:         e.KeywordLocation,
+		AbsoluteKeywordLocation: e.AbsoluteKeywordLocation,
+		InstanceLocation:        e.InstanceLocation,
+		Error:                   e.Message,
+	}
+}
+
+// child returns the unit nested under u for the given scope, adding it to
+// u.Errors if it isn't there.
+func (u *OutputUnit) child(sc scope) *OutputUnit {
+	for _, c := range u.Errors {
+		if c.Error == "" && c.KeywordLocation == sc.keywordLoc && c.InstanceLocation == sc.instanceLoc {
+			return c
+		}
+	}
+	c := &OutputUnit{KeywordLocation: sc.keywordLoc, InstanceLocation: sc.instanceLoc}
+	u.Errors = append(u.Errors, c)
+	return c
+}
+
+// condense replaces each unit nested under u that has a single nested unit
+// with that unit.
+func (u *OutputUnit) condense() {
+	for i, c := range u.Errors {
+		for len(c.Errors) == 1 {
+			c = c.Errors[0]
+		}
+		c.condense()
+		u.Errors[i] = c
+	}
+}
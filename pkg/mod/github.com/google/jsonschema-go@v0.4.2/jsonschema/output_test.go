@@ -0,0 +1,131 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValidateOutput(t *testing.T) {
+	// The example from the specification.
+	// See https://json-schema.org/draft/2020-12/json-schema-core#section-12.4.
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+		"$id": "https://example.com/polygon",
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"$defs": {
+			"point": {
+				"type": "object",
+				"properties": {
+					"x": { "type": "number" },
+					"y": { "type": "number" }
+				},
+				"additionalProperties": false,
+				"required": [ "x", "y" ]
+			}
+		},
+		"type": "array",
+		"items": { "$ref": "#/$defs/point" },
+		"minItems": 3
+	}`), &schema); err != nil {
+		t.Fatal(err)
+	}
+	rs, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := []any{
+		map[string]any{"x": 2.5, "y": 1.3},
+		map[string]any{"x": 1, "z": 6.7},
+	}
+
+	const (
+		additional = `{
+			"valid": false,
+			"keywordLocation": "/items/$ref/additionalProperties",
+			"absoluteKeywordLocation": "https://example.com/polygon#/$defs/point/additionalProperties",
+			"instanceLocation": "/1",
+			"error": "unexpected additional properties [\"z\"]"
+		}`
+		required = `{
+			"valid": false,
+			"keywordLocation": "/items/$ref/required",
+			"absoluteKeywordLocation": "https://example.com/polygon#/$defs/point/required",
+			"instanceLocation": "/1",
+			"error": "missing properties: [\"y\"]"
+		}`
+		minItems = `{
+			"valid": false,
+			"keywordLocation": "/minItems",
+			"absoluteKeywordLocation": "https://example.com/polygon#/minItems",
+			"instanceLocation": "",
+			"error": "array length 2 is less than 3"
+		}`
+	)
+
+	for _, test := range []struct {
+		format OutputFormat
+		want   string
+	}{
+		{OutputFlag, `{"valid": false, "keywordLocation": "", "instanceLocation": ""}`},
+		{OutputBasic, `{
+			"valid": false,
+			"keywordLocation": "",
+			"instanceLocation": "",
+			"errors": [` + additional + `,` + required + `,` + minItems + `]
+		}`},
+		{OutputDetailed, `{
+			"valid": false,
+			"keywordLocation": "",
+			"instanceLocation": "",
+			"errors": [
+				{
+					"valid": false,
+					"keywordLocation": "/items/$ref",
+					"instanceLocation": "/1",
+					"errors": [` + additional + `,` + required + `]
+				},
+				` + minItems + `
+			]
+		}`},
+	} {
+		t.Run(string(test.format), func(t *testing.T) {
+			out, err := rs.ValidateOutput(instance, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, want any
+			if err := json.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(test.want), &want); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("mismatch (-want, +got):\n%s", diff)
+			}
+
+			// A valid instance produces only the root unit.
+			out, err = rs.ValidateOutput([]any{instance[0], instance[0], instance[0]}, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !out.Valid || len(out.Errors) > 0 {
+				t.Errorf("got %+v, want a valid result", out)
+			}
+		})
+	}
+
+	if _, err := rs.ValidateOutput(instance, "verbose"); err == nil {
+		t.Error("verbose format: got nil error, want error")
+	}
+}
@@ -8,6 +8,8 @@
 package jsonschema
 
 import (
+	"cmp"
+	"context"
 	"errors"
 	"fmt"
 	"net/url"
@@ -24,6 +26,13 @@ import (
 type Resolved struct {
 	root  *Schema
 	draft draft
+	// whether to assert the content keywords; see [ResolveOptions.AssertContent]
+	assertContent bool
+	// the catalog for validation error messages; see [ResolveOptions.Messages]
+	messages MessageCatalog
+	// whether validation must track annotations, because some schema has
+	// unevaluatedItems or unevaluatedProperties
+	annotate bool
 	// map from $ids to their schemas
 	resolvedURIs map[string]*Schema
 	// map from schemas to additional info computed during resolution
@@ -32,8 +41,13 @@ type Resolved struct {
 
 type draft int
 
+// Drafts are ordered by publication, so that "draft <= draft7" holds for
+// draft-07 and the drafts before it.
 const (
-	draft7 = iota
+	draft4 = iota
+	draft6
+	draft7
+	draft2019
 	draft2020
 )
 
@@ -50,8 +64,14 @@ func newResolved(s *Schema) *Resolved {
 func detectDraft(s *Schema) draft {
 	// Check explicit $schema declaration
 	switch s.Schema {
+	case draft4SchemaVersion:
+		return draft4
+	case draft6SchemaVersion:
+		return draft6
 	case draft7SchemaVersion, draft7SecSchemaVersion:
 		return draft7
+	case draft201909SchemaVersion:
+		return draft2019
 	case draft202012SchemaVersion:
 		return draft2020
 	default:
@@ -89,18 +109,35 @@ type resolvedInfo struct {
 	// The anchor to look up on the stack when the dynamic ref acts dynamically.
 	dynamicRefAnchor string
 
+	// The schema to which RecursiveRef initially refers (draft 2019-09).
+	resolvedRecursiveRef *Schema
+
 	// The following fields are independent of arguments to Schema.Resolved,
 	// so they could live on the Schema. We put them here for simplicity.
 
 	// The set of required properties.
 	isRequired map[string]bool
 
+	// The bounds of the number keywords, with the boolean forms of
+	// exclusiveMinimum and exclusiveMaximum translated to the numeric forms.
+	minimum, maximum                   *float64
+	exclusiveMinimum, exclusiveMaximum *float64
+
 	// Compiled regexps.
 	pattern           *regexp.Regexp
 	patternProperties map[*regexp.Regexp]*Schema
 
 	// Map from anchors to subschemas.
 	anchors map[string]anchorInfo
+
+	// Custom keywords in the schema.
+	keywords []compiledKeyword
+
+	// The assertions for the keywords that see only the instance; see
+	// [compileSchemas].
+	assertions []assertion
+	// Whether additionalProperties is false.
+	additionalPropertiesFalse bool
 }
 
 // Schema returns the schema that was resolved.
@@ -122,6 +159,10 @@ func (r *Resolved) schemaString(s *Schema) string {
 // A Loader reads and unmarshals the schema at uri, if any.
 type Loader func(uri *url.URL) (*Schema, error)
 
+// A ContextLoader is a [Loader] that takes a context. It should stop loading
+// and return an error when the context is done.
+type ContextLoader func(ctx context.Context, uri *url.URL) (*Schema, error)
+
 // ResolveOptions are options for [Schema.Resolve].
 type ResolveOptions struct {
 	// BaseURI is the URI relative to which the root schema should be resolved.
@@ -134,7 +175,17 @@ type ResolveOptions struct {
 	// Loader loads schemas that are referred to by a $ref but are not under the
 	// root schema (remote references).
 	// If nil, resolving a remote reference will return an error.
+	// See [HTTPLoader] for a Loader that fetches schemas over HTTP.
 	Loader Loader
+	// ContextLoader is like Loader, but receives the context passed to
+	// [Schema.ResolveContext]. If it is set, Loader is ignored.
+	// See [HTTPContextLoader] for a ContextLoader that fetches schemas over HTTP.
+	ContextLoader ContextLoader
+	// MaxLoadDepth is the maximum length of a chain of remote schemas, each
+	// loaded by a reference in the previous one. It protects against servers
+	// that generate an endless sequence of schemas.
+	// If zero, the limit is 32.
+	MaxLoadDepth int
 	// ValidateDefaults determines whether to validate values of "default" keywords
 	// against their schemas.
 	// The [JSON Schema specification] does not require this, but it is recommended
@@ -142,6 +193,31 @@ type ResolveOptions struct {
 	//
 	// [JSON Schema specification]: https://json-schema.org/understanding-json-schema/reference/annotations
 	ValidateDefaults bool
+	// AssertFormats determines whether validation checks the "format" keyword,
+	// as in the [format-assertion vocabulary]. By default, "format" is only an
+	// annotation. When AssertFormats is true, a string instance fails validation
+	// if it does not have its schema's format. The formats checked are
+	// date-time, date, time, email, hostname, ipv4, ipv6, uri, uri-reference,
+	// uuid, and regex (in Go syntax, like "pattern"). Other formats are ignored.
+	//
+	// [format-assertion vocabulary]: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-7.2.2
+	AssertFormats bool
+	// AssertContent determines whether validation checks the content keywords
+	// described in [section 8 of the validation spec]. By default they are only
+	// annotations. When AssertContent is true, a string instance whose schema has a
+	// "contentEncoding" of base64 fails validation if it cannot be decoded, and one
+	// whose schema has a "contentMediaType" of application/json fails if its
+	// (decoded) content is not JSON or does not validate against the schema's
+	// "contentSchema". Other encodings and media types are ignored.
+	//
+	// [section 8 of the validation spec]: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-8
+	AssertContent bool
+	// Keywords are custom keywords to validate. Their values are taken from
+	// [Schema.Extra]. Keywords in Extra that are not listed here are ignored.
+	Keywords []*Keyword
+	// Messages produces the messages of validation errors. If nil, messages
+	// are in English.
+	Messages MessageCatalog
 }
 
 // Resolve resolves all references within the schema and performs other tasks that
@@ -149,7 +225,17 @@ type ResolveOptions struct {
 // If opts is nil, the default values are used.
 // The schema must not be changed after Resolve is called.
 // The same schema may be resolved multiple times.
+//
+// Resolve calls [Schema.ResolveContext] with [context.Background].
 func (root *Schema) Resolve(opts *ResolveOptions) (*Resolved, error) {
+	return root.ResolveContext(context.Background(), opts)
+}
+
+// ResolveContext is like [Schema.Resolve], but stops resolving when ctx is
+// done and returns the context's error. The context is passed to
+// [ResolveOptions.ContextLoader]. A [ResolveOptions.Loader] does not receive
+// the context, so ResolveContext can only stop before or after it is called.
+func (root *Schema) ResolveContext(ctx context.Context, opts *ResolveOptions) (*Resolved, error) {
 	// There are up to five steps required to prepare a schema to validate.
 	// 1. Load: read the schema from somewhere and unmarshal it.
 	//    This schema (root) may have been loaded or created in memory, but other schemas that
@@ -161,7 +247,10 @@ func (root *Schema) Resolve(opts *ResolveOptions) (*Resolved, error) {
 	//    in a map from URIs to schemas within root.
 	// 4. Resolve references: all refs in the schemas are replaced with the schema they refer to.
 	// 5. (Optional.) If opts.ValidateDefaults is true, validate the defaults.
-	r := &resolver{loaded: map[string]*Resolved{}}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	r := &resolver{ctx: ctx, loaded: map[string]*Resolved{}}
 	if opts != nil {
 		r.opts = *opts
 	}
@@ -176,9 +265,13 @@ func (root *Schema) Resolve(opts *ResolveOptions) (*Resolved, error) {
 		}
 	}
 
-	if r.opts.Loader == nil {
-		r.opts.Loader = func(uri *url.URL) (*Schema, error) {
-			return nil, errors.New("cannot resolve remote schemas: no loader passed to Schema.Resolve")
+	if r.opts.ContextLoader == nil {
+		if load := r.opts.Loader; load != nil {
+			r.opts.ContextLoader = func(_ context.Context, uri *url.URL) (*Schema, error) { return load(uri) }
+		} else {
+			r.opts.ContextLoader = func(context.Context, *url.URL) (*Schema, error) {
+				return nil, errors.New("cannot resolve remote schemas: no loader passed to Schema.Resolve")
+			}
 		}
 	}
 
@@ -186,6 +279,14 @@ func (root *Schema) Resolve(opts *ResolveOptions) (*Resolved, error) {
 	if err != nil {
 		return nil, err
 	}
+	resolved.assertContent = r.opts.AssertContent
+	resolved.messages = r.opts.Messages
+	for s := range resolved.resolvedInfos {
+		if s.UnevaluatedItems != nil || s.UnevaluatedProperties != nil {
+			resolved.annotate = true
+			break
+		}
+	}
 	if r.opts.ValidateDefaults {
 		if err := resolved.validateDefaults(); err != nil {
 			return nil, err
@@ -197,13 +298,19 @@ func (root *Schema) Resolve(opts *ResolveOptions) (*Resolved, error) {
 
 // A resolver holds the state for resolution.
 type resolver struct {
+	ctx  context.Context
 	opts ResolveOptions
 	// A cache of loaded and partly resolved schemas. (They may not have had their
 	// refs resolved.) The cache ensures that the loader will never be called more
 	// than once with the same URI, and that reference cycles are handled properly.
 	loaded map[string]*Resolved
+	// The number of remote schemas currently being resolved.
+	depth int
 }
 
+// defaultMaxLoadDepth is the default value of [ResolveOptions.MaxLoadDepth].
+const defaultMaxLoadDepth = 32
+
 func (r *resolver) resolve(s *Schema, baseURI *url.URL) (*Resolved, error) {
 	if baseURI.Fragment != "" {
 		return nil, fmt.Errorf("base URI %s must not have a fragment", baseURI)
@@ -214,10 +321,19 @@ func (r *resolver) resolve(s *Schema, baseURI *url.URL) (*Resolved, error) {
 		return nil, err
 	}
 
+	if err := resolveBounds(rs); err != nil {
+		return nil, err
+	}
+	compileSchemas(rs, &r.opts)
+
 	if err := resolveURIs(rs, baseURI); err != nil {
 		return nil, err
 	}
 
+	if err := compileKeywords(rs, r.opts.Keywords); err != nil {
+		return nil, err
+	}
+
 	// Remember the schema by both the URI we loaded it from and its canonical name,
 	// which may differ if the schema has an $id.
 	// We must set the map before calling resolveRefs, or ref cycles will cause unbounded recursion.
@@ -334,8 +450,8 @@ func (s *Schema) checkLocal(report func(error), infos map[*Schema]*resolvedInfo)
 	// Some properties are present so that Schemas can round-trip, but we do not
 	// validate them.
 	// Currently, it's just the $vocabulary property.
-	// As a special case, we can validate the 2020-12 meta-schema.
-	if s.Vocabulary != nil && s.Schema != draft202012SchemaVersion {
+	// As a special case, we can validate the 2019-09 and 2020-12 meta-schemas.
+	if s.Vocabulary != nil && s.Schema != draft202012SchemaVersion && s.Schema != draft201909SchemaVersion {
 		addf("cannot validate a schema with $vocabulary")
 	}
 
@@ -372,6 +488,33 @@ func (s *Schema) checkLocal(report func(error), infos map[*Schema]*resolvedInfo)
 	}
 }
 
+// resolveBounds sets the bounds of the number keywords in the infos of rs.
+// In draft-07 and earlier, a true ExclusiveMinimumBool or ExclusiveMaximumBool
+// makes Minimum or Maximum exclusive. Later drafts do not allow the boolean
+// forms.
+func resolveBounds(rs *Resolved) error {
+	var errs []error
+	for s := range rs.root.all() {
+		info := rs.resolvedInfos[s]
+		info.minimum, info.maximum = s.Minimum, s.Maximum
+		info.exclusiveMinimum, info.exclusiveMaximum = s.ExclusiveMinimum, s.ExclusiveMaximum
+		if s.ExclusiveMinimumBool == nil && s.ExclusiveMaximumBool == nil {
+			continue
+		}
+		if rs.draft > draft7 {
+			errs = append(errs, fmt.Errorf("jsonschema.Schema: %s: boolean exclusiveMinimum or exclusiveMaximum is not allowed after draft-07", rs.schemaString(s)))
+			continue
+		}
+		if b := s.ExclusiveMinimumBool; b != nil && *b && s.Minimum != nil {
+			info.minimum, info.exclusiveMinimum = nil, s.Minimum
+		}
+		if b := s.ExclusiveMaximumBool; b != nil && *b && s.Maximum != nil {
+			info.maximum, info.exclusiveMaximum = nil, s.Maximum
+		}
+	}
+	return errors.Join(errs...)
+}
+
 // resolveURIs resolves the ids and anchors in all the schemas of root, relative
 // to baseURI.
 // See https://json-schema.org/draft/2020-12/json-schema-core#section-8.2, section
@@ -427,30 +570,35 @@ func resolveURIs(rs *Resolved, baseURI *url.URL) error {
 		baseInfo := rs.resolvedInfos[base]
 
 		// ids are scoped to the root.
-		if s.ID != "" {
+		id := s.ID
+		if rs.draft == draft4 && id == "" {
+			// Draft-04 spells $id as "id", which unmarshals into Extra.
+			id, _ = s.Extra["id"].(string)
+		}
+		if id != "" {
 			// draft-7 specific
 			// https://json-schema.org/draft-07/draft-handrews-json-schema-01#rfc.section.8.3
 			// "All other properties in a "$ref" object MUST be ignored."
-			ignore := rs.draft == draft7 && s.Ref != ""
+			ignore := rs.draft <= draft7 && s.Ref != ""
 			if !ignore {
 				// A non-empty ID establishes a new base.
-				idURI, err := url.Parse(s.ID)
+				idURI, err := url.Parse(id)
 				if err != nil {
 					return err
 				}
-				if rs.draft == draft2020 && idURI.Fragment != "" {
-					return fmt.Errorf("$id %s must not have a fragment", s.ID)
+				if rs.draft > draft7 && idURI.Fragment != "" {
+					return fmt.Errorf("$id %s must not have a fragment", id)
 				}
-				if rs.draft == draft7 && idURI.Fragment != "" {
+				if rs.draft <= draft7 && idURI.Fragment != "" {
 					// anchor did not exist in draft 7, id was used for base uri and document navigation
 					// https://json-schema.org/draft-07/draft-handrews-json-schema-01#id-keyword
-					anchorName := strings.TrimPrefix(s.ID, "#")
+					anchorName := strings.TrimPrefix(id, "#")
 					setAnchor(s, baseInfo, anchorName, false)
 				} else {
 					// The base URI for this schema is its $id resolved against the parent base.
 					info.uri = baseInfo.uri.ResolveReference(idURI)
 					if !info.uri.IsAbs() {
-						return fmt.Errorf("$id %s does not resolve to an absolute URI (base is %q)", s.ID, baseInfo.uri)
+						return fmt.Errorf("$id %s does not resolve to an absolute URI (base is %q)", id, baseInfo.uri)
 					}
 					rs.resolvedURIs[info.uri.String()] = s
 					base = s // needed for anchors
@@ -459,8 +607,10 @@ func resolveURIs(rs *Resolved, baseURI *url.URL) error {
 			}
 		}
 		info.base = base
-		if rs.draft == draft2020 {
+		if rs.draft > draft7 {
 			setAnchor(s, baseInfo, s.Anchor, false)
+		}
+		if rs.draft == draft2020 {
 			setAnchor(s, baseInfo, s.DynamicAnchor, true)
 		}
 
@@ -510,6 +660,15 @@ func (r *resolver) resolveRefs(rs *Resolved) error {
 				info.resolvedDynamicRef = refSchema
 			}
 		}
+		if s.RecursiveRef != "" {
+			// The ref is resolved lexically here. If the schema it refers to
+			// has a recursive anchor, it is resolved again at validation time.
+			refSchema, _, err := r.resolveRef(rs, s, s.RecursiveRef)
+			if err != nil {
+				return err
+			}
+			info.resolvedRecursiveRef = refSchema
+		}
 	}
 	return nil
 }
@@ -542,7 +701,18 @@ func (r *resolver) resolveRef(rs *Resolved, s *Schema, ref string) (_ *Schema, d
 			referencedSchema = lrs.root
 		} else {
 			// Try to load the schema.
-			ls, err := r.opts.Loader(fraglessRefURI)
+			if limit := cmp.Or(r.opts.MaxLoadDepth, defaultMaxLoadDepth); r.depth >= limit {
+				return nil, "", fmt.Errorf("loading %s: remote schemas nested more than %d deep", fraglessRefURI, limit)
+			}
+			if err := r.ctx.Err(); err != nil {
+				return nil, "", err
+			}
+			ls, err := r.opts.ContextLoader(r.ctx, fraglessRefURI)
+			if err == nil {
+				// Prefer the context's error to the result of a loader
+				// that ignores it.
+				err = r.ctx.Err()
+			}
 			if err != nil {
 				return nil, "", fmt.Errorf("loading %s: %w", fraglessRefURI, err)
 			}
@@ -550,7 +720,9 @@ func (r *resolver) resolveRef(rs *Resolved, s *Schema, ref string) (_ *Schema, d
 			if ls.Schema == "" {
 				ls.Schema = s.Schema
 			}
+			r.depth++
 			lrs, err := r.resolve(ls, fraglessRefURI)
+			r.depth--
 			if err != nil {
 				return nil, "", err
 			}
@@ -15,12 +15,15 @@ import (
 	"math"
 	"reflect"
 	"slices"
+	"strconv"
 )
 
 // A Schema is a JSON schema object.
-// It supports both draft-07 and the 2020-12 draft specifications:
+// It supports the draft-07, 2019-09 and 2020-12 draft specifications:
 //   - Draft-07: https://json-schema.org/draft-07/draft-handrews-json-schema-01
 //     and https://json-schema.org/draft-07/draft-handrews-json-schema-validation-01
+//   - Draft 2019-09: https://json-schema.org/draft/2019-09/json-schema-core
+//     and https://json-schema.org/draft/2019-09/json-schema-validation
 //   - Draft 2020-12: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-01
 //     and https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01
 //
@@ -58,6 +61,10 @@ type Schema struct {
 	DynamicRef    string          `json:"$dynamicRef,omitempty"`
 	Vocabulary    map[string]bool `json:"$vocabulary,omitempty"`
 
+	// draft 2019-09 predecessors of DynamicAnchor and DynamicRef
+	RecursiveAnchor bool   `json:"$recursiveAnchor,omitempty"`
+	RecursiveRef    string `json:"$recursiveRef,omitempty"`
+
 	// metadata
 	Title       string          `json:"title,omitempty"`
 	Description string          `json:"description,omitempty"`
@@ -79,9 +86,15 @@ type Schema struct {
 	Maximum          *float64 `json:"maximum,omitempty"`
 	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
 	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
-	MinLength        *int     `json:"minLength,omitempty"`
-	MaxLength        *int     `json:"maxLength,omitempty"`
-	Pattern          string   `json:"pattern,omitempty"`
+	// The draft-04 boolean forms of exclusiveMinimum and exclusiveMaximum,
+	// which make Minimum and Maximum exclusive. Use either these or the
+	// numeric forms above; never both. They are only valid in draft-07 and
+	// earlier.
+	ExclusiveMinimumBool *bool  `json:"-"`
+	ExclusiveMaximumBool *bool  `json:"-"`
+	MinLength            *int   `json:"minLength,omitempty"`
+	MaxLength            *int   `json:"maxLength,omitempty"`
+	Pattern              string `json:"pattern,omitempty"`
 
 	// arrays
 	PrefixItems      []*Schema `json:"prefixItems,omitempty"`
@@ -213,6 +226,12 @@ func (s *Schema) basicChecks() error {
 	if s.Items != nil && s.ItemsArray != nil {
 		return errors.New("both Items and ItemsArray are set; at most one should be")
 	}
+	if s.ExclusiveMinimum != nil && s.ExclusiveMinimumBool != nil {
+		return errors.New("both ExclusiveMinimum and ExclusiveMinimumBool are set; at most one should be")
+	}
+	if s.ExclusiveMaximum != nil && s.ExclusiveMaximumBool != nil {
+		return errors.New("both ExclusiveMaximum and ExclusiveMaximumBool are set; at most one should be")
+	}
 	propertyOrderSeen := make(map[string]bool)
 	for _, val := range s.PropertyOrder {
 		if _, ok := propertyOrderSeen[val]; ok {
@@ -273,16 +292,31 @@ func (s Schema) MarshalJSON() ([]byte, error) {
 		}
 	}
 
+	// Marshal either the numeric or the boolean form of the exclusive bounds.
+	exclusive := func(num *float64, b *bool) any {
+		if b != nil {
+			return *b
+		}
+		if num != nil {
+			return *num
+		}
+		return nil
+	}
+
 	ms := struct {
-		Type         any            `json:"type,omitempty"`
-		Properties   json.Marshaler `json:"properties,omitempty"`
-		Dependencies map[string]any `json:"dependencies,omitempty"`
-		Items        any            `json:"items,omitempty"`
+		Type             any            `json:"type,omitempty"`
+		Properties       json.Marshaler `json:"properties,omitempty"`
+		Dependencies     map[string]any `json:"dependencies,omitempty"`
+		Items            any            `json:"items,omitempty"`
+		ExclusiveMinimum any            `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum any            `json:"exclusiveMaximum,omitempty"`
 		*schemaWithoutMethods
 	}{
 		Type:                 typ,
 		Dependencies:         dep,
 		Items:                items,
+		ExclusiveMinimum:     exclusive(s.ExclusiveMinimum, s.ExclusiveMinimumBool),
+		ExclusiveMaximum:     exclusive(s.ExclusiveMaximum, s.ExclusiveMaximumBool),
 		schemaWithoutMethods: (*schemaWithoutMethods)(&s),
 	}
 	// Marshal properties, even if the empty map (but not nil).
@@ -405,6 +439,12 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		MinContains   *integer                   `json:"minContains,omitempty"`
 		MaxContains   *integer                   `json:"maxContains,omitempty"`
 
+		// Booleans in draft-04, numbers in later drafts.
+		// Unmarshal as either ExclusiveMinimum or ExclusiveMinimumBool, and
+		// likewise for the maximum.
+		ExclusiveMinimum json.RawMessage `json:"exclusiveMinimum,omitempty"`
+		ExclusiveMaximum json.RawMessage `json:"exclusiveMaximum,omitempty"`
+
 		*schemaWithoutMethods
 	}{
 		schemaWithoutMethods: (*schemaWithoutMethods)(s),
@@ -488,6 +528,26 @@ func (s *Schema) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
+	// In draft-04, exclusiveMinimum and exclusiveMaximum are booleans that make
+	// minimum and maximum exclusive. Keep them as they are, so that the schema
+	// round-trips; Resolve translates them for the drafts that allow them.
+	unmarshalExclusive := func(num **float64, b **bool, raw json.RawMessage) error {
+		switch {
+		case len(raw) == 0:
+			return nil
+		case raw[0] == 't' || raw[0] == 'f':
+			return json.Unmarshal(raw, b)
+		default:
+			return json.Unmarshal(raw, num)
+		}
+	}
+	if err := unmarshalExclusive(&s.ExclusiveMinimum, &s.ExclusiveMinimumBool, ms.ExclusiveMinimum); err != nil {
+		return err
+	}
+	if err := unmarshalExclusive(&s.ExclusiveMaximum, &s.ExclusiveMaximumBool, ms.ExclusiveMaximum); err != nil {
+		return err
+	}
+
 	set := func(dst **int, src *integer) {
 		if src != nil {
 			*dst = Ptr(int(*src))
@@ -592,6 +652,62 @@ func (s *Schema) children() iter.Seq[*Schema] {
 	return func(yield func(*Schema) bool) { s.everyChild(yield) }
 }
 
+// Walk calls f on s and each of its subschemas, in depth-first order. The
+// path argument to f is the JSON Pointer to the schema relative to s, such as
+// "/properties/a/items"; the path to s itself is "". If f returns false, Walk
+// does not visit the subschemas of the schema passed to f.
+//
+// Subschemas are visited in the order of their keywords' names, and the
+// schemas of a keyword like "properties" in the order of their keys, so the
+// order is deterministic. Walk does not follow references.
+//
+// The function f may modify the schema passed to it, including replacing its
+// subschemas. Walk visits the subschemas that the schema has after f returns.
+func (s *Schema) Walk(f func(path string, s *Schema) bool) {
+	s.walk("", f)
+}
+
+func (s *Schema) walk(path string, f func(string, *Schema) bool) {
+	if !f(path, s) {
+		return
+	}
+	for token, c := range s.childPaths() {
+		c.walk(path+"/"+token, f)
+	}
+}
+
+// childPaths returns an iterator over the immediate child schemas of s and
+// the JSON Pointers to them relative to s, in the order of [Schema.Walk].
+func (s *Schema) childPaths() iter.Seq2[string, *Schema] {
+	return func(yield func(string, *Schema) bool) {
+		v := reflect.ValueOf(s).Elem()
+		for _, info := range schemaFieldInfos {
+			fv := v.FieldByIndex(info.sf.Index)
+			switch info.sf.Type {
+			case schemaType:
+				if c := fv.Interface().(*Schema); c != nil && !yield(info.jsonName, c) {
+					return
+				}
+
+			case schemaSliceType:
+				for i, c := range fv.Interface().([]*Schema) {
+					if !yield(info.jsonName+"/"+strconv.Itoa(i), c) {
+						return
+					}
+				}
+
+			case schemaMapType:
+				m := fv.Interface().(map[string]*Schema)
+				for _, k := range slices.Sorted(maps.Keys(m)) {
+					if !yield(info.jsonName+"/"+escapeJSONPointerSegment(k), m[k]) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
 var (
 	schemaType      = reflect.TypeFor[*Schema]()
 	schemaSliceType = reflect.TypeFor[[]*Schema]()
@@ -410,6 +410,39 @@ func TestCloneSchemas(t *testing.T) {
 	}
 }
 
+func TestWalk(t *testing.T) {
+	s := &Schema{
+		Type:        "object",
+		Properties:  map[string]*Schema{"b": {Type: "string"}, "a/c": {Items: &Schema{}}},
+		AnyOf:       []*Schema{{Not: &Schema{}}, {}},
+		PrefixItems: []*Schema{{}},
+	}
+	var got []string
+	s.Walk(func(path string, ss *Schema) bool {
+		got = append(got, path)
+		return path != "/anyOf/0"
+	})
+	want := []string{"", "/anyOf/0", "/anyOf/1", "/prefixItems/0", "/properties/a~1c", "/properties/a~1c/items", "/properties/b"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("paths mismatch (-want +got):\n%s", diff)
+	}
+
+	// Replace a subschema, and check that Walk visits the replacement.
+	got = nil
+	s.Walk(func(path string, ss *Schema) bool {
+		got = append(got, path)
+		if p, ok := ss.Properties["b"]; ok && p.Type == "string" {
+			ss.Properties["b"] = &Schema{AllOf: []*Schema{{Type: "string"}, {MinLength: Ptr(1)}}}
+		}
+		return true
+	})
+	want = []string{"", "/anyOf/0", "/anyOf/0/not", "/anyOf/1", "/prefixItems/0", "/properties/a~1c", "/properties/a~1c/items",
+		"/properties/b", "/properties/b/allOf/0", "/properties/b/allOf/1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("paths mismatch (-want +got):\n%s", diff)
+	}
+}
+
 func TestCloneMarshalDraft2020_12(t *testing.T) {
 	files, err := filepath.Glob(filepath.FromSlash("testdata/draft2020-12/*.json"))
 	if err != nil {
@@ -0,0 +1,77 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements validation of streams of JSON values.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ValidateStream validates each JSON value read from r against the schema.
+// The values may be separated by whitespace, as in NDJSON (newline-delimited
+// JSON). Only one value is held in memory at a time, so the input can be
+// arbitrarily large. Numbers are decoded as [json.Number]s, so integers too
+// large for a float64 are validated exactly.
+//
+// ValidateStream stops at the first value that is not valid JSON or fails
+// validation. Its error reports the zero-based index of the value; use
+// [errors.As] to obtain the [ValidationError].
+func (rs *Resolved) ValidateStream(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber() // validate large integers exactly
+	for i := 0; ; i++ {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			if err == io.EOF {
+				return nil
+			}
+			return fmt.Errorf("value %d: %w", i, err)
+		}
+		if err := rs.Validate(v); err != nil {
+			return fmt.Errorf("value %d: %w", i, err)
+		}
+	}
+}
+
+// ValidateArrayStream validates each element of the JSON array read from r
+// against the schema. The schema describes the elements, not the array.
+// Only one element is held in memory at a time, so the array can be
+// arbitrarily large. As with [Resolved.ValidateStream], numbers are decoded
+// as [json.Number]s.
+//
+// ValidateArrayStream stops at the first element that fails validation, or
+// when the input is not a single JSON array. Its error reports the index of the
+// element; use [errors.As] to obtain the [ValidationError].
+func (rs *Resolved) ValidateArrayStream(r io.Reader) error {
+	dec := json.NewDecoder(r)
+	dec.UseNumber() // validate large integers exactly
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("reading JSON array: %w", err)
+	}
+	if tok != json.Delim('[') {
+		return fmt.Errorf("input is not a JSON array: begins with %v", tok)
+	}
+	for i := 0; dec.More(); i++ {
+		var v any
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+		if err := rs.Validate(v); err != nil {
+			return fmt.Errorf("element %d: %w", i, err)
+		}
+	}
+	if _, err := dec.Token(); err != nil { // the closing ']'
+		return fmt.Errorf("reading JSON array: %w", err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return errors.New("input has data after the JSON array")
+	}
+	return nil
+}
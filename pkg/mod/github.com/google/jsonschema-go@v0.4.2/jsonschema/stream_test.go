@@ -0,0 +1,67 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateStream(t *testing.T) {
+	schema := &Schema{
+		Type:       "object",
+		Properties: map[string]*Schema{"n": {Type: "integer"}},
+		Required:   []string{"n"},
+	}
+	rs, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		array bool
+		input string
+		want  string // substring of error, or "" for success
+	}{
+		{"ndjson", false, "{\"n\": 1}\n{\"n\": 2}\n", ""},
+		{"ndjson empty", false, "", ""},
+		{"ndjson invalid", false, "{\"n\": 1}\n{\"n\": \"x\"}\n{\"n\": 3}\n", "value 1: "},
+		{"ndjson large non-integer", false, `{"n": 9007199254740992.5}`, "value 0: "},
+		{"ndjson bad JSON", false, "{\"n\": 1}\n{\"n\":", "value 1: "},
+		{"array", true, `[{"n": 1}, {"n": 2}]`, ""},
+		{"array empty", true, ` [ ] `, ""},
+		{"array invalid", true, `[{"n": 1}, {"n": 2}, {}]`, "element 2: "},
+		{"not an array", true, `{"n": 1}`, "not a JSON array"},
+		{"empty input", true, ``, "reading JSON array"},
+		{"unterminated", true, `[{"n": 1}`, "element 1: "},
+		{"trailing data", true, `[{"n": 1}] []`, "after the JSON array"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			validate := rs.ValidateStream
+			if tt.array {
+				validate = rs.ValidateArrayStream
+			}
+			err := validate(strings.NewReader(tt.input))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("got %v, want error containing %q", err, tt.want)
+			}
+		})
+	}
+
+	// The validation failure can be extracted.
+	err = rs.ValidateArrayStream(strings.NewReader(`[{"n": 1.5}]`))
+	var verr *ValidationError
+	if !errors.As(err, &verr) || verr.InstanceLocation != "/n" {
+		t.Errorf("got %v, want a ValidationError at /n", err)
+	}
+}
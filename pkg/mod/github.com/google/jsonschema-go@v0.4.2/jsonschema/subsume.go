@@ -0,0 +1,451 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements a check that one schema accepts every instance of another.
+
+package jsonschema
+
+import (
+	"cmp"
+	"encoding/json"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+)
+
+// Subsumes reports whether every instance that is valid under b is also valid
+// under a. For example, if the new version of a tool's input schema subsumes
+// the old one, arguments that clients built for the old version remain valid.
+//
+// Unlike [BreakingChanges], which compares the keywords at each location,
+// Subsumes compares what the keywords allow, so it finds that
+// {"type": "integer", "minimum": 1} subsumes {"enum": [1, 2]}, and that
+// {"anyOf": [{"type": "string"}, {"type": "number"}]} subsumes
+// {"type": "integer"}. It understands the type, value, numeric, string, array
+// and object keywords and the common applicators.
+//
+// The check is conservative: if it cannot decide, it reports false. That
+// happens when a has a keyword whose meaning depends on where it appears, such
+// as "$ref" or "unevaluatedProperties", and when a and b differ in ways that
+// cannot be compared, such as different "pattern"s. Keywords in [Schema.Extra]
+// are ignored, and "format" and the content keywords are compared as strings.
+func Subsumes(a, b *Schema) bool {
+	if a == nil || isTrueSchema(a) {
+		return true
+	}
+	if b == nil {
+		b = &Schema{}
+	}
+	if b.Not != nil && isTrueSchema(b.Not) {
+		// b is false.
+		return true
+	}
+	if vals, ok := finiteValues(b); ok {
+		return acceptsAll(a, b, vals)
+	}
+	// b allows no more than any of its allOf branches, and no more than all of
+	// its anyOf or oneOf branches together.
+	if slices.ContainsFunc(b.AllOf, func(br *Schema) bool { return Subsumes(a, br) }) {
+		return true
+	}
+	for _, brs := range [][]*Schema{b.AnyOf, b.OneOf} {
+		if len(brs) > 0 && !slices.ContainsFunc(brs, func(br *Schema) bool { return !Subsumes(a, br) }) {
+			return true
+		}
+	}
+	return subsumesKeywords(a, b)
+}
+
+// finiteValues returns the values that b may allow, and reports whether
+// there are finitely many of them.
+func finiteValues(b *Schema) ([]any, bool) {
+	switch {
+	case b.Const != nil:
+		return []any{*b.Const}, true
+	case b.Enum != nil:
+		return b.Enum, true
+	}
+	types := schemaTypes(b)
+	if types == nil {
+		return nil, false
+	}
+	var vals []any
+	for _, t := range types {
+		switch t {
+		case "null":
+			vals = append(vals, nil)
+		case "boolean":
+			vals = append(vals, false, true)
+		default:
+			return nil, false
+		}
+	}
+	return vals, true
+}
+
+// acceptsAll reports whether a validates each of vals that b validates.
+func acceptsAll(a, b *Schema, vals []any) bool {
+	ra, err := a.Resolve(nil)
+	if err != nil {
+		return false
+	}
+	// If b cannot be resolved, for example because it refers to a schema
+	// outside it, assume that it validates all of vals.
+	rb, _ := b.Resolve(nil)
+	for _, v := range vals {
+		if rb != nil && rb.Validate(v) != nil {
+			continue
+		}
+		if ra.Validate(v) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// subsumesKeywords reports whether each keyword of a allows every instance
+// that b allows.
+func subsumesKeywords(a, b *Schema) bool {
+	if a.Ref != "" || a.DynamicRef != "" || a.RecursiveRef != "" ||
+		a.UnevaluatedItems != nil || a.UnevaluatedProperties != nil {
+		return false
+	}
+	bTypes := schemaTypes(b)
+	// allows reports whether b may allow instances of any of types.
+	allows := func(types ...string) bool {
+		return bTypes == nil || slices.ContainsFunc(types, func(t string) bool { return slices.Contains(bTypes, t) })
+	}
+
+	// Validation keywords for any instance type.
+	if aTypes := schemaTypes(a); aTypes != nil {
+		if bTypes == nil {
+			return false
+		}
+		for _, t := range bTypes {
+			if !slices.Contains(aTypes, t) && !(t == "integer" && slices.Contains(aTypes, "number")) {
+				return false
+			}
+		}
+	}
+	if a.Enum != nil || a.Const != nil {
+		// b does not have finitely many values.
+		return false
+	}
+
+	// Numbers.
+	if allows("number", "integer") {
+		if a.MultipleOf != nil {
+			if b.MultipleOf == nil {
+				return false
+			}
+			if q := *b.MultipleOf / *a.MultipleOf; q != math.Trunc(q) {
+				return false
+			}
+		}
+		lo, loExcl, hi, hiExcl := numberBounds(b)
+		na := numericBounds(a)
+		if !withinBound(na.Minimum, false, lo, loExcl, false) ||
+			!withinBound(na.ExclusiveMinimum, true, lo, loExcl, false) ||
+			!withinBound(na.Maximum, false, hi, hiExcl, true) ||
+			!withinBound(na.ExclusiveMaximum, true, hi, hiExcl, true) {
+			return false
+		}
+	}
+
+	// Strings.
+	if allows("string") {
+		if !withinMin(a.MinLength, b.MinLength) || !withinMax(a.MaxLength, b.MaxLength) {
+			return false
+		}
+		for _, p := range []struct{ a, b string }{
+			{a.Pattern, b.Pattern},
+			{a.Format, b.Format},
+			{a.ContentEncoding, b.ContentEncoding},
+			{a.ContentMediaType, b.ContentMediaType},
+		} {
+			if p.a != "" && p.a != p.b {
+				return false
+			}
+		}
+		if !Subsumes(a.ContentSchema, b.ContentSchema) {
+			return false
+		}
+	}
+
+	// Arrays.
+	if allows("array") {
+		aPrefix, aRest := itemSchemas(a)
+		bPrefix, bRest := itemSchemas(b)
+		for i := range max(len(aPrefix), len(bPrefix)) {
+			as, bs := aRest, bRest
+			if i < len(aPrefix) {
+				as = aPrefix[i]
+			}
+			if i < len(bPrefix) {
+				bs = bPrefix[i]
+			}
+			if !Subsumes(as, bs) {
+				return false
+			}
+		}
+		if !Subsumes(aRest, bRest) {
+			return false
+		}
+		if !withinMin(a.MinItems, b.MinItems) || !withinMax(a.MaxItems, b.MaxItems) {
+			return false
+		}
+		if a.UniqueItems && !b.UniqueItems {
+			return false
+		}
+		if a.Contains != nil {
+			// The default of minContains is 1.
+			if b.Contains == nil || !Subsumes(a.Contains, b.Contains) ||
+				!withinMin(cmp.Or(a.MinContains, Ptr(1)), cmp.Or(b.MinContains, Ptr(1))) {
+				return false
+			}
+			// Elements that match b's contains may not match a's, so
+			// maxContains can only be compared for the same contains.
+			if a.MaxContains != nil && (!sameSchema(a.Contains, b.Contains) || !withinMax(a.MaxContains, b.MaxContains)) {
+				return false
+			}
+		}
+	}
+
+	// Objects.
+	if allows("object") && !subsumesObject(a, b) {
+		return false
+	}
+
+	// Logic.
+	if slices.ContainsFunc(a.AllOf, func(br *Schema) bool { return !Subsumes(br, b) }) {
+		return false
+	}
+	if a.AnyOf != nil && !slices.ContainsFunc(a.AnyOf, func(br *Schema) bool { return Subsumes(br, b) }) {
+		return false
+	}
+	if a.OneOf != nil {
+		if len(a.OneOf) == 1 {
+			if !Subsumes(a.OneOf[0], b) {
+				return false
+			}
+		} else if !sameSchemas(a.OneOf, b.OneOf) {
+			return false
+		}
+	}
+	// An instance that b allows fails b's not, so it fails a's not if every
+	// instance that a's not allows is allowed by b's not.
+	if a.Not != nil && (b.Not == nil || !Subsumes(b.Not, a.Not)) {
+		return false
+	}
+	if a.If != nil && (!sameSchema(a.If, b.If) || !Subsumes(a.Then, b.Then) || !Subsumes(a.Else, b.Else)) {
+		return false
+	}
+	return true
+}
+
+// subsumesObject reports whether the object keywords of a allow every object
+// that b allows.
+func subsumesObject(a, b *Schema) bool {
+	for _, name := range a.Required {
+		if !slices.Contains(b.Required, name) {
+			return false
+		}
+	}
+	if !withinMin(a.MinProperties, b.MinProperties) || !withinMax(a.MaxProperties, b.MaxProperties) {
+		return false
+	}
+	for _, deps := range []struct{ a, b map[string][]string }{
+		{a.DependentRequired, b.DependentRequired},
+		{a.DependencyStrings, b.DependencyStrings},
+	} {
+		for name, rs := range deps.a {
+			for _, r := range rs {
+				if !slices.Contains(deps.b[name], r) && !slices.Contains(b.Required, r) {
+					return false
+				}
+			}
+		}
+	}
+	for _, deps := range []struct{ a, b map[string]*Schema }{
+		{a.DependentSchemas, b.DependentSchemas},
+		{a.DependencySchemas, b.DependencySchemas},
+	} {
+		for name, s := range deps.a {
+			if !Subsumes(s, deps.b[name]) {
+				return false
+			}
+		}
+	}
+	if !Subsumes(a.PropertyNames, b.PropertyNames) {
+		return false
+	}
+
+	// Each property of an object that b allows is valid under one of the
+	// property schemas of b.
+	for name, s := range a.Properties {
+		bs, ok := propertySchema(b, name)
+		if !ok || !Subsumes(s, bs) {
+			return false
+		}
+	}
+	for pat, s := range a.PatternProperties {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return false
+		}
+		for name, bs := range b.Properties {
+			if re.MatchString(name) && !Subsumes(s, bs) {
+				return false
+			}
+		}
+		for _, bs := range b.PatternProperties {
+			if !Subsumes(s, bs) {
+				return false
+			}
+		}
+		if !Subsumes(s, b.AdditionalProperties) {
+			return false
+		}
+	}
+	if a.AdditionalProperties != nil {
+		for name, bs := range b.Properties {
+			if _, ok := a.Properties[name]; ok {
+				continue
+			}
+			m, ok := matchesPattern(a.PatternProperties, name)
+			if !ok {
+				return false
+			}
+			if m == nil && !Subsumes(a.AdditionalProperties, bs) {
+				return false
+			}
+		}
+		for _, bs := range b.PatternProperties {
+			if !Subsumes(a.AdditionalProperties, bs) {
+				return false
+			}
+		}
+		if !Subsumes(a.AdditionalProperties, b.AdditionalProperties) {
+			return false
+		}
+	}
+	return true
+}
+
+// propertySchema returns a schema of s that the property name must satisfy,
+// or nil if any value is allowed. It reports false if a pattern of s is not
+// a valid regexp.
+func propertySchema(s *Schema, name string) (*Schema, bool) {
+	if ps, ok := s.Properties[name]; ok {
+		return ps, true
+	}
+	ps, ok := matchesPattern(s.PatternProperties, name)
+	if !ok {
+		return nil, false
+	}
+	if ps != nil {
+		return ps, true
+	}
+	return s.AdditionalProperties, true
+}
+
+// matchesPattern returns the schema of the first pattern in pats, in sorted
+// order, that matches name, or nil if none does. It reports false if a
+// pattern is not a valid regexp.
+func matchesPattern(pats map[string]*Schema, name string) (*Schema, bool) {
+	for _, pat := range slices.Sorted(maps.Keys(pats)) {
+		re, err := regexp.Compile(pat)
+		if err != nil {
+			return nil, false
+		}
+		if re.MatchString(name) {
+			return pats[pat], true
+		}
+	}
+	return nil, true
+}
+
+// itemSchemas returns the schemas of s for the leading elements of an array,
+// and the schema for the rest, which is nil if any value is allowed.
+func itemSchemas(s *Schema) (prefix []*Schema, rest *Schema) {
+	if s.ItemsArray != nil {
+		return s.ItemsArray, s.AdditionalItems
+	}
+	return s.PrefixItems, s.Items
+}
+
+// numericBounds returns s, or if s has the draft-04 boolean forms of
+// exclusiveMinimum or exclusiveMaximum, a shallow copy of s in which they are
+// replaced by the numeric forms, as [resolveBounds] does.
+func numericBounds(s *Schema) *Schema {
+	if s.ExclusiveMinimumBool == nil && s.ExclusiveMaximumBool == nil {
+		return s
+	}
+	c := *s
+	c.ExclusiveMinimumBool, c.ExclusiveMaximumBool = nil, nil
+	if b := s.ExclusiveMinimumBool; b != nil && *b && s.Minimum != nil {
+		c.Minimum, c.ExclusiveMinimum = nil, s.Minimum
+	}
+	if b := s.ExclusiveMaximumBool; b != nil && *b && s.Maximum != nil {
+		c.Maximum, c.ExclusiveMaximum = nil, s.Maximum
+	}
+	return &c
+}
+
+// numberBounds returns the tightest lower and upper bounds of s on numbers,
+// and whether each is exclusive. A nil bound is absent.
+func numberBounds(s *Schema) (lower *float64, lowerExcl bool, upper *float64, upperExcl bool) {
+	s = numericBounds(s)
+	lower, upper = s.Minimum, s.Maximum
+	if s.ExclusiveMinimum != nil && (lower == nil || *s.ExclusiveMinimum >= *lower) {
+		lower, lowerExcl = s.ExclusiveMinimum, true
+	}
+	if s.ExclusiveMaximum != nil && (upper == nil || *s.ExclusiveMaximum <= *upper) {
+		upper, upperExcl = s.ExclusiveMaximum, true
+	}
+	return lower, lowerExcl, upper, upperExcl
+}
+
+// withinBound reports whether every number within the bound b is within the
+// bound a. A nil bound is absent. The excl arguments report whether the bounds
+// are exclusive, and upper whether they are maximums rather than minimums.
+func withinBound(a *float64, aExcl bool, b *float64, bExcl, upper bool) bool {
+	switch {
+	case a == nil:
+		return true
+	case b == nil:
+		return false
+	case *a == *b:
+		return bExcl || !aExcl
+	case upper:
+		return *b < *a
+	default:
+		return *b > *a
+	}
+}
+
+// withinMin reports whether the minimum count or length b is at least the
+// minimum a. A nil minimum is zero.
+func withinMin(a, b *int) bool {
+	return a == nil || *a <= 0 || b != nil && *b >= *a
+}
+
+// withinMax reports whether the maximum count or length b is at most the
+// maximum a. A nil maximum is absent.
+func withinMax(a, b *int) bool {
+	return a == nil || b != nil && *b <= *a
+}
+
+// sameSchema reports whether a and b have the same JSON encoding.
+func sameSchema(a, b *Schema) bool {
+	aj, err1 := json.Marshal(a)
+	bj, err2 := json.Marshal(b)
+	return err1 == nil && err2 == nil && string(aj) == string(bj)
+}
+
+// sameSchemas reports whether a and b have the same schemas in the same order.
+func sameSchemas(a, b []*Schema) bool {
+	return slices.EqualFunc(a, b, sameSchema)
+}
@@ -0,0 +1,68 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestSubsumes(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"identical", `{"type": "object", "properties": {"a": {"type": "string"}}}`, `{"type": "object", "properties": {"a": {"type": "string"}}}`, true},
+		{"true", `true`, `{"type": "string"}`, true},
+		{"false", `{"type": "string"}`, `false`, true},
+		{"type widened", `{"type": "number"}`, `{"type": "integer"}`, true},
+		{"type narrowed", `{"type": "integer"}`, `{"type": "number"}`, false},
+		{"type added", `{"type": "string"}`, `{}`, false},
+		{"enum within bounds", `{"type": "integer", "minimum": 1}`, `{"enum": [1, 2]}`, true},
+		{"enum outside bounds", `{"type": "integer", "minimum": 1}`, `{"enum": [0, 1]}`, false},
+		{"enum subset", `{"enum": ["a", "b", "c"]}`, `{"enum": ["c", "a"]}`, true},
+		{"const", `{"const": 3}`, `{"type": "integer", "minimum": 3, "maximum": 3}`, false},
+		{"boolean", `{"enum": [true, false]}`, `{"type": "boolean"}`, true},
+		{"bounds", `{"minimum": 0, "exclusiveMaximum": 10}`, `{"exclusiveMinimum": 0, "maximum": 9}`, true},
+		{"exclusive bound", `{"exclusiveMinimum": 0}`, `{"minimum": 0}`, false},
+		{"boolean exclusive bound", `{"minimum": 0, "exclusiveMinimum": true}`, `{"minimum": 0}`, false},
+		{"boolean exclusive bound within", `{"minimum": 0}`, `{"maximum": 3, "minimum": 0, "exclusiveMinimum": true}`, true},
+		{"bounds on other type", `{"minimum": 0, "minLength": 1}`, `{"type": "boolean"}`, true},
+		{"multipleOf", `{"multipleOf": 2}`, `{"multipleOf": 6}`, true},
+		{"lengths", `{"minLength": 1, "maxLength": 10}`, `{"type": "string", "minLength": 2, "maxLength": 5}`, true},
+		{"maxLength added", `{"type": "string", "maxLength": 10}`, `{"type": "string"}`, false},
+		{"pattern changed", `{"pattern": "a"}`, `{"pattern": "b"}`, false},
+		{"required removed", `{"required": ["a"]}`, `{"required": ["a", "b"]}`, true},
+		{"required added", `{"required": ["a", "b"]}`, `{"required": ["a"]}`, false},
+		{"property widened", `{"properties": {"a": {"type": ["string", "null"]}}}`, `{"properties": {"a": {"type": "string"}}}`, true},
+		{"property narrowed", `{"properties": {"a": {"type": "string"}}}`, `{"properties": {"a": {"type": ["string", "null"]}}}`, false},
+		{"property added", `{"properties": {"a": {}, "b": {"type": "string"}}}`, `{"properties": {"a": {}}}`, false},
+		{"property added to closed object", `{"properties": {"a": {}, "b": {"type": "string"}}, "additionalProperties": false}`, `{"properties": {"a": {}}, "additionalProperties": false}`, true},
+		{"object closed", `{"properties": {"a": {}}, "additionalProperties": false}`, `{"properties": {"a": {}}}`, false},
+		{"pattern property", `{"patternProperties": {"^x": {"type": "string"}}}`, `{"properties": {"xa": {"type": "string"}}, "additionalProperties": false}`, true},
+		{"items", `{"items": {"type": "number"}}`, `{"prefixItems": [{"type": "integer"}], "items": {"type": "number"}}`, true},
+		{"prefixItems", `{"prefixItems": [{"type": "integer"}]}`, `{"items": {"type": "string"}}`, false},
+		{"anyOf in a", `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, `{"type": "integer"}`, true},
+		{"anyOf in b", `{"type": ["string", "number"]}`, `{"anyOf": [{"type": "string"}, {"type": "integer"}]}`, true},
+		{"anyOf alternative removed", `{"anyOf": [{"type": "string"}]}`, `{"anyOf": [{"type": "string"}, {"type": "number"}]}`, false},
+		{"allOf in b", `{"type": "string"}`, `{"allOf": [{"minLength": 1}, {"type": "string"}]}`, true},
+		{"not", `{"not": {"type": "string"}}`, `{"not": {"type": ["string", "null"]}}`, true},
+		{"ref", `{"$defs": {"d": {}}, "$ref": "#/$defs/d"}`, `{}`, false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var a, b Schema
+			if err := json.Unmarshal([]byte(tt.a), &a); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tt.b), &b); err != nil {
+				t.Fatal(err)
+			}
+			if got := Subsumes(&a, &b); got != tt.want {
+				t.Errorf("Subsumes(%s, %s) = %t, want %t", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
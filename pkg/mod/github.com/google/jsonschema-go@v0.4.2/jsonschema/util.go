@@ -247,6 +247,15 @@ func jsonNumber(v reflect.Value) (*big.Rat, bool) {
 	return r, true
 }
 
+// jsonNumberType is the type of a [json.Number].
+var jsonNumberType = reflect.TypeFor[json.Number]()
+
+// isJSONNumber reports whether v holds a [json.Number], which is a JSON number
+// even though it is a string in Go.
+func isJSONNumber(v reflect.Value) bool {
+	return v.IsValid() && v.Type() == jsonNumberType
+}
+
 // jsonType returns a string describing the type of the JSON value,
 // as described in the JSON Schema specification:
 // https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.1.
@@ -265,6 +274,14 @@ func jsonType(v reflect.Value) (string, bool) {
 		}
 		return "number", true
 	}
+	if isJSONNumber(v) {
+		// Decide with the exact value: a float64 cannot tell whether a large
+		// number has a fractional part.
+		if r, ok := jsonNumber(v); ok && r.IsInt() {
+			return "integer", true
+		}
+		return "number", true
+	}
 	switch v.Kind() {
 	case reflect.Bool:
 		return "boolean", true
@@ -5,6 +5,7 @@
 package jsonschema
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -12,36 +13,154 @@ import (
 	"iter"
 	"math"
 	"math/big"
+	"mime"
 	"reflect"
-	"slices"
+	"strconv"
 	"strings"
 	"sync"
-	"unicode/utf8"
 )
 
 // The values of the "$schema" keyword for the versions that we can validate.
 const (
+	draft4SchemaVersion      = "http://json-schema.org/draft-04/schema#"
+	draft6SchemaVersion      = "http://json-schema.org/draft-06/schema#"
 	draft7SchemaVersion      = "http://json-schema.org/draft-07/schema#"
 	draft7SecSchemaVersion   = "https://json-schema.org/draft-07/schema#"
+	draft201909SchemaVersion = "https://json-schema.org/draft/2019-09/schema"
 	draft202012SchemaVersion = "https://json-schema.org/draft/2020-12/schema"
 )
 
 // isValidSchemaVersion checks if the given schema version is supported
 func isValidSchemaVersion(version string) bool {
-	return version == "" || version == draft7SchemaVersion || version == draft7SecSchemaVersion || version == draft202012SchemaVersion
+	return version == "" || version == draft4SchemaVersion || version == draft6SchemaVersion ||
+		version == draft7SchemaVersion || version == draft7SecSchemaVersion ||
+		version == draft201909SchemaVersion || version == draft202012SchemaVersion
 }
 
 // Validate validates the instance, which must be a JSON value, against the schema.
 // It returns nil if validation is successful or an error if it is not.
 // If the schema type is "object", instance should be a map[string]any.
+//
+// If the instance does not satisfy the schema, the error wraps a
+// *[ValidationError] describing the failure, which can be retrieved with
+// [errors.As].
 func (rs *Resolved) Validate(instance any) error {
 	if s := rs.root.Schema; !isValidSchemaVersion(s) {
-		return fmt.Errorf("cannot validate version %s, supported versions: draft-07 and draft 2020-12", s)
+		return fmt.Errorf("cannot validate version %s, supported versions: draft-04, draft-06, draft-07, draft 2019-09 and draft 2020-12", s)
 	}
-	st := &state{rs: rs}
+	st := newState(rs, false)
+	defer st.release()
 	return st.validate(reflect.ValueOf(instance), st.rs.root, nil)
 }
 
+// ValidateAll is like [Resolved.Validate], but instead of stopping at the first
+// failure, it reports every keyword that the instance fails to satisfy.
+// If the instance does not satisfy the schema, the error is a [ValidationErrors]
+// holding one [ValidationError] per failure, in the order they were found.
+// Other errors, such as an instance that is not a JSON value, are returned
+// as they are by Validate.
+//
+// Keywords like anyOf, oneOf and not that apply subschemas only to decide
+// whether they themselves are satisfied report a single failure, not the
+// failures of those subschemas.
+func (rs *Resolved) ValidateAll(instance any) error {
+	if s := rs.root.Schema; !isValidSchemaVersion(s) {
+		return fmt.Errorf("cannot validate version %s, supported versions: draft-04, draft-06, draft-07, draft 2019-09 and draft 2020-12", s)
+	}
+	st := newState(rs, true)
+	defer st.release()
+	if err := st.validate(reflect.ValueOf(instance), st.rs.root, nil); err != nil {
+		return err
+	}
+	if len(st.errs) == 0 {
+		return nil
+	}
+	return st.errs
+}
+
+// A ValidationError describes the failure of an instance to satisfy a
+// schema keyword.
+type ValidationError struct {
+	// InstanceLocation is the JSON Pointer to the value in the instance that
+	// failed validation. It is empty for the instance itself.
+	InstanceLocation string
+	// KeywordLocation is the JSON Pointer to the failed keyword, along the path
+	// that validation took from the root schema, including any "$ref" or
+	// "$dynamicRef" keywords that it followed.
+	KeywordLocation string
+	// AbsoluteKeywordLocation is the absolute URI of the failed keyword,
+	// without following references. It is empty if the schema containing the
+	// keyword does not have an absolute base URI.
+	AbsoluteKeywordLocation string
+	// Keyword is the name of the failed keyword, such as "minLength".
+	Keyword string
+	// Message describes the failure.
+	Message string
+
+	// The schemas that validation passed through on the way to the keyword,
+	// starting with the root. Set only by ValidateAll, for building output.
+	scopes []scope
+}
+
+// A scope is a schema applied to a value of the instance during validation.
+type scope struct {
+	keywordLoc, instanceLoc string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Keyword + ": " + e.Message
+}
+
+// Is reports whether target is a *ValidationError whose non-empty fields equal
+// those of e. It lets [errors.Is] look for a kind of failure, as in
+//
+//	errors.Is(err, &ValidationError{Keyword: "required"})
+//
+// Since an empty field matches anything, a target cannot distinguish a failure
+// at the root of the instance by its InstanceLocation.
+func (e *ValidationError) Is(target error) bool {
+	t, ok := target.(*ValidationError)
+	if !ok {
+		return false
+	}
+	match := func(want, got string) bool { return want == "" || want == got }
+	return match(t.InstanceLocation, e.InstanceLocation) &&
+		match(t.KeywordLocation, e.KeywordLocation) &&
+		match(t.AbsoluteKeywordLocation, e.AbsoluteKeywordLocation) &&
+		match(t.Keyword, e.Keyword) &&
+		match(t.Message, e.Message)
+}
+
+// ValidationErrors is the error returned by [Resolved.ValidateAll].
+type ValidationErrors []*ValidationError
+
+// Error returns the failures, one per line, each preceded by its instance
+// location, or "root" for the instance itself.
+func (es ValidationErrors) Error() string {
+	var b strings.Builder
+	for i, e := range es {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		loc := e.InstanceLocation
+		if loc == "" {
+			loc = "root"
+		}
+		fmt.Fprintf(&b, "%s: %s", loc, e)
+	}
+	return b.String()
+}
+
+// Unwrap returns the individual errors, so that [errors.As] and [errors.Is]
+// can find a *ValidationError in es.
+func (es ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(es))
+	for i, e := range es {
+		errs[i] = e
+	}
+	return errs
+}
+
 // validateDefaults walks the schema tree. If it finds a default, it validates it
 // against the schema containing it.
 //
@@ -49,13 +168,13 @@ func (rs *Resolved) Validate(instance any) error {
 // treats each schema with a default as its own root.
 func (rs *Resolved) validateDefaults() error {
 	if s := rs.root.Schema; !isValidSchemaVersion(s) {
-		return fmt.Errorf("cannot validate version %s, supported versions: draft-07 and draft 2020-12", s)
+		return fmt.Errorf("cannot validate version %s, supported versions: draft-04, draft-06, draft-07, draft 2019-09 and draft 2020-12", s)
 	}
 	st := &state{rs: rs}
 	for s := range rs.root.all() {
 		// We checked for nil schemas in [Schema.Resolve].
 		assert(s != nil, "nil schema")
-		if s.DynamicRef != "" {
+		if s.DynamicRef != "" || s.RecursiveRef != "" {
 			return fmt.Errorf("jsonschema: %s: validateDefaults does not support dynamic refs", rs.schemaString(s))
 		}
 		if s.Default != nil {
@@ -74,22 +193,244 @@ func (rs *Resolved) validateDefaults() error {
 // state is the state of single call to ResolvedSchema.Validate.
 type state struct {
 	rs *Resolved
-	// stack holds the schemas from recursive calls to validate.
-	// These are the "dynamic scopes" used to resolve dynamic references.
+	// stack holds the frames from recursive calls to validate.
+	// Its schemas are the "dynamic scopes" used to resolve dynamic references.
 	// https://json-schema.org/draft/2020-12/json-schema-core#scopes
-	stack []*Schema
+	stack []frame
+	// If collect is true, validation continues past failures, which are
+	// accumulated in errs.
+	collect bool
+	errs    ValidationErrors
+	// props holds the properties of the objects being validated; see
+	// [state.objectProperties].
+	props []propertyValue
+	// propSets holds empty sets of property names for reuse.
+	propSets []map[string]bool
+}
+
+// A propertyValue is a property of an object instance.
+type propertyValue struct {
+	name string
+	val  reflect.Value
+}
+
+// statePool holds states for reuse, so that validation does not allocate
+// them, or the buffers they hold, for every call.
+var statePool = sync.Pool{New: func() any { return new(state) }}
+
+// maxPooledLen is the largest length of a buffer that is kept for reuse
+// in a state, so that validating a large instance does not retain memory.
+const maxPooledLen = 1024
+
+// newState returns a state for validating against rs.
+// Call [state.release] when it is no longer needed.
+func newState(rs *Resolved, collect bool) *state {
+	st := statePool.Get().(*state)
+	st.rs = rs
+	st.collect = collect
+	return st
+}
+
+// release returns st to statePool, keeping its buffers but not their
+// contents. The caller must not use st or st.errs after calling release.
+func (st *state) release() {
+	if cap(st.stack) > maxPooledLen || cap(st.props) > maxPooledLen {
+		return
+	}
+	clear(st.stack[:cap(st.stack)])
+	clear(st.props[:cap(st.props)])
+	*st = state{stack: st.stack[:0], props: st.props[:0], propSets: st.propSets}
+	statePool.Put(st)
+}
+
+// objectProperties appends the names and values of the properties of
+// instance, which must be a map or struct, to st.props, and returns them.
+// Looping over the result avoids the allocations of an iterator like
+// [properties]. When the loop is done, the caller should truncate st.props to
+// its length before the call. A caller that returns early need not, because
+// callers further up truncate st.props in turn.
+func (st *state) objectProperties(instance reflect.Value) []propertyValue {
+	start := len(st.props)
+	if m, ok := jsonObject(instance); ok {
+		for k, e := range m {
+			st.props = append(st.props, propertyValue{k, elemValue(e)})
+		}
+	} else {
+		for k, v := range properties(instance) {
+			st.props = append(st.props, propertyValue{k, v})
+		}
+	}
+	return st.props[start:]
+}
+
+// propertySet returns an empty set of property names.
+// Pass it to [state.releasePropertySet] when it is no longer needed.
+func (st *state) propertySet() map[string]bool {
+	if n := len(st.propSets); n > 0 {
+		m := st.propSets[n-1]
+		st.propSets = st.propSets[:n-1]
+		return m
+	}
+	return map[string]bool{}
+}
+
+// releasePropertySet makes m available for reuse by [state.propertySet].
+func (st *state) releasePropertySet(m map[string]bool) {
+	if len(m) > maxPooledLen {
+		return
+	}
+	clear(m)
+	st.propSets = append(st.propSets, m)
+}
+
+// A frame records a recursive call to validate.
+type frame struct {
+	schema *Schema
+	// ref is the keyword, "$ref" or "$dynamicRef", by which schema was reached
+	// from the schema of the previous frame. If empty, schema is a subschema
+	// of the previous frame's schema.
+	ref string
+	// If elem is true, the instance is the element of the previous frame's
+	// instance named by token: an array index or a property name.
+	// Locations are built from the tokens only when they are needed.
+	elem  bool
+	token string
+}
+
+// fail reports that the instance failed to satisfy the given keyword of the
+// schema being validated.
+// If st is collecting errors, fail records the failure and returns nil so
+// that validation continues. Otherwise it returns the failure as a
+// *ValidationError.
+func (st *state) fail(keyword, format string, args ...any) error {
+	scopes := st.scopes()
+	err := &ValidationError{
+		InstanceLocation:        st.instanceLoc(),
+		KeywordLocation:         scopes[len(scopes)-1].keywordLoc + "/" + keyword,
+		AbsoluteKeywordLocation: st.absoluteKeywordLoc(keyword),
+		Keyword:                 keyword,
+	}
+	if st.rs.messages != nil {
+		for i, a := range args {
+			if v, ok := a.(reflect.Value); ok && v.IsValid() && v.CanInterface() {
+				args[i] = v.Interface()
+			}
+		}
+		err.Message = st.rs.messages.Message(keyword, format, args)
+	} else {
+		err.Message = fmt.Sprintf(format, args...)
+	}
+	if !st.collect {
+		return err
+	}
+	err.scopes = scopes
+	st.errs = append(st.errs, err)
+	return nil
+}
+
+// instanceLoc returns the JSON Pointer to the instance being validated.
+func (st *state) instanceLoc() string {
+	loc := ""
+	for _, f := range st.stack {
+		loc = f.instanceLoc(loc)
+	}
+	return loc
+}
+
+// instanceLoc returns the JSON Pointer to the instance of f, given the
+// JSON Pointer to the instance of the previous frame.
+func (f frame) instanceLoc(prev string) string {
+	if !f.elem {
+		return prev
+	}
+	return prev + "/" + escapeJSONPointerSegment(f.token)
+}
+
+// scopes returns a scope for each frame on the stack. The keyword location of
+// a scope is the JSON Pointer to its schema, along the path that validation
+// took from the first schema on the stack.
+func (st *state) scopes() []scope {
+	scopes := make([]scope, len(st.stack))
+	loc, iloc := "", ""
+	for i, f := range st.stack {
+		if f.ref != "" {
+			loc += "/" + f.ref
+		} else if i > 0 {
+			// The paths of the root schema and its subschemas are "root" and JSON
+			// Pointers from it. So the path of a subschema that isn't the root is an
+			// extension of the path of any schema above it, except the root.
+			parentPath := st.rs.resolvedInfos[st.stack[i-1].schema].path
+			loc += strings.TrimPrefix(st.rs.resolvedInfos[f.schema].path, parentPath)
+		}
+		iloc = f.instanceLoc(iloc)
+		scopes[i] = scope{keywordLoc: loc, instanceLoc: iloc}
+	}
+	return scopes
+}
+
+// absoluteKeywordLoc returns the absolute URI of the given keyword of the
+// schema being validated, or "" if the schema's base URI is not absolute.
+func (st *state) absoluteKeywordLoc(keyword string) string {
+	info := st.rs.resolvedInfos[st.stack[len(st.stack)-1].schema]
+	baseInfo := st.rs.resolvedInfos[info.base]
+	if baseInfo.uri == nil || !baseInfo.uri.IsAbs() {
+		return ""
+	}
+	// The path of the base is a prefix of the path of the schema, unless
+	// the schema is the root, whose path is "root".
+	ptr := ""
+	if info.base != info.s {
+		ptr = strings.TrimPrefix(info.path, baseInfo.path)
+	}
+	u := *baseInfo.uri
+	u.Fragment = ptr + "/" + keyword
+	return u.String()
+}
+
+// check is like validate, but always stops at the first failure.
+// It is used for subschemas whose only role is to decide whether a keyword
+// like anyOf is satisfied.
+func (st *state) check(instance reflect.Value, schema *Schema, anns *annotations) error {
+	defer func(collect bool) { st.collect = collect }(st.collect)
+	st.collect = false
+	return st.validate(instance, schema, anns)
 }
 
 // validate validates the reflected value of the instance.
-func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *annotations) (err error) {
-	defer wrapf(&err, "validating %s", st.rs.schemaString(schema))
+func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *annotations) error {
+	return st.validateFrame(frame{schema: schema}, instance, callerAnns)
+}
+
+// validateRef validates the instance against schema, which the schema being
+// validated refers to with keyword, either "$ref" or "$dynamicRef".
+func (st *state) validateRef(keyword string, instance reflect.Value, schema *Schema, callerAnns *annotations) error {
+	return st.validateFrame(frame{schema: schema, ref: keyword}, instance, callerAnns)
+}
 
+// validateElem validates elem, the array item or object property of the
+// instance named by token, against schema.
+func (st *state) validateElem(token string, elem reflect.Value, schema *Schema) error {
+	return st.validateFrame(frame{schema: schema, elem: true, token: token}, elem, nil)
+}
+
+// validateFrame validates the reflected value of the instance against the
+// schema of f.
+func (st *state) validateFrame(f frame, instance reflect.Value, callerAnns *annotations) error {
 	// Maintain a stack for dynamic schema resolution.
-	st.stack = append(st.stack, schema) // push
-	defer func() {
-		st.stack = st.stack[:len(st.stack)-1] // pop
-	}()
+	// This function is called for every subschema, so it avoids defer and
+	// formats the error only on failure.
+	st.stack = append(st.stack, f) // push
+	err := st.validateSchema(f.schema, instance, callerAnns)
+	st.stack = st.stack[:len(st.stack)-1] // pop
+	if err != nil {
+		return fmt.Errorf("validating %s: %w", st.rs.schemaString(f.schema), err)
+	}
+	return nil
+}
 
+// validateSchema validates the reflected value of the instance against schema,
+// which is the schema of the top frame of the stack.
+func (st *state) validateSchema(schema *Schema, instance reflect.Value, callerAnns *annotations) error {
 	// We checked for nil schemas in [Schema.Resolve].
 	assert(schema != nil, "nil schema")
 
@@ -100,108 +441,49 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 
 	schemaInfo := st.rs.resolvedInfos[schema]
 
-	var anns annotations // all the annotations for this call and child calls
+	// All the annotations for this call and child calls.
+	// Nil if no schema needs them; see [Resolved.annotate].
+	var anns *annotations
+	if st.rs.annotate {
+		anns = new(annotations)
+	}
 	// $ref: https://json-schema.org/draft/2020-12/json-schema-core#section-8.2.3.1
 	if schema.Ref != "" {
-		if err := st.validate(instance, schemaInfo.resolvedRef, &anns); err != nil {
+		if err := st.validateRef("$ref", instance, schemaInfo.resolvedRef, anns); err != nil {
 			return err
 		}
 		// https://json-schema.org/draft-07/draft-handrews-json-schema-01#rfc.section.8.3
 		// "All other properties in a "$ref" object MUST be ignored."
-		if st.rs.draft == draft7 {
+		if st.rs.draft <= draft7 {
 			return nil
 		}
 	}
 
-	// type: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.1
-	if schema.Type != "" || schema.Types != nil {
-		gotType, ok := jsonType(instance)
-		if !ok {
-			return fmt.Errorf("type: %v of type %[1]T is not a valid JSON value", instance)
-		}
-		if schema.Type != "" {
-			// "number" subsumes integers
-			if !(gotType == schema.Type ||
-				gotType == "integer" && schema.Type == "number") {
-				return fmt.Errorf("type: %v has type %q, want %q", instance, gotType, schema.Type)
-			}
-		} else {
-			if !(slices.Contains(schema.Types, gotType) || (gotType == "integer" && slices.Contains(schema.Types, "number"))) {
-				return fmt.Errorf("type: %v has type %q, want one of %q",
-					instance, gotType, strings.Join(schema.Types, ", "))
-			}
-		}
-	}
-	// enum: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.2
-	if schema.Enum != nil {
-		ok := false
-		for _, e := range schema.Enum {
-			if equalValue(reflect.ValueOf(e), instance) {
-				ok = true
-				break
-			}
-		}
-		if !ok {
-			return fmt.Errorf("enum: %v does not equal any of: %v", instance, schema.Enum)
+	// type, enum, const, numbers, strings and format: see [compileSchemas]
+	for _, a := range schemaInfo.assertions {
+		if err := a(st, instance); err != nil {
+			return err
 		}
 	}
 
-	// const: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.1.3
-	if schema.Const != nil {
-		if !equalValue(reflect.ValueOf(*schema.Const), instance) {
-			return fmt.Errorf("const: %v does not equal %v", instance, *schema.Const)
+	// content keywords: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-8
+	if st.rs.assertContent && instance.Kind() == reflect.String && !isJSONNumber(instance) {
+		if err := st.validateContent(instance.String(), schema); err != nil {
+			return err
 		}
 	}
 
-	// numbers: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.2
-	if schema.MultipleOf != nil || schema.Minimum != nil || schema.Maximum != nil || schema.ExclusiveMinimum != nil || schema.ExclusiveMaximum != nil {
-		n, ok := jsonNumber(instance)
-		if ok { // these keywords don't apply to non-numbers
-			if schema.MultipleOf != nil {
-				// TODO: validate MultipleOf as non-zero.
-				// The test suite assumes floats.
-				nf, _ := n.Float64() // don't care if it's exact or not
-				if _, f := math.Modf(nf / *schema.MultipleOf); f != 0 {
-					return fmt.Errorf("multipleOf: %s is not a multiple of %f", n, *schema.MultipleOf)
-				}
-			}
-
-			m := new(big.Rat) // reuse for all of the following
-			cmp := func(f float64) int { return n.Cmp(m.SetFloat64(f)) }
-
-			if schema.Minimum != nil && cmp(*schema.Minimum) < 0 {
-				return fmt.Errorf("minimum: %s is less than %f", n, *schema.Minimum)
-			}
-			if schema.Maximum != nil && cmp(*schema.Maximum) > 0 {
-				return fmt.Errorf("maximum: %s is greater than %f", n, *schema.Maximum)
-			}
-			if schema.ExclusiveMinimum != nil && cmp(*schema.ExclusiveMinimum) <= 0 {
-				return fmt.Errorf("exclusiveMinimum: %s is less than or equal to %f", n, *schema.ExclusiveMinimum)
-			}
-			if schema.ExclusiveMaximum != nil && cmp(*schema.ExclusiveMaximum) >= 0 {
-				return fmt.Errorf("exclusiveMaximum: %s is greater than or equal to %f", n, *schema.ExclusiveMaximum)
-			}
+	// custom keywords: see [Keyword]
+	for _, ck := range schemaInfo.keywords {
+		var inst any
+		if instance.IsValid() {
+			inst = instance.Interface()
 		}
-	}
-
-	// strings: https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.3
-	if instance.Kind() == reflect.String && (schema.MinLength != nil || schema.MaxLength != nil || schema.Pattern != "") {
-		str := instance.String()
-		n := utf8.RuneCountInString(str)
-		if schema.MinLength != nil {
-			if m := *schema.MinLength; n < m {
-				return fmt.Errorf("minLength: %q contains %d Unicode code points, fewer than %d", str, n, m)
-			}
-		}
-		if schema.MaxLength != nil {
-			if m := *schema.MaxLength; n > m {
-				return fmt.Errorf("maxLength: %q contains %d Unicode code points, more than %d", str, n, m)
+		if kerr := ck.kw.Validate(ck.value, inst); kerr != nil {
+			if err := st.fail(ck.kw.Name, "%v", kerr); err != nil {
+				return err
 			}
 		}
-
-		if schema.Pattern != "" && !schemaInfo.pattern.MatchString(str) {
-			return fmt.Errorf("pattern: %q does not match regular expression %q", str, schema.Pattern)
-		}
 	}
 
 	// $dynamicRef: https://json-schema.org/draft/2020-12/json-schema-core#section-8.2.3.2
@@ -211,7 +493,7 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			"DynamicRef not resolved properly")
 		if schemaInfo.resolvedDynamicRef != nil {
 			// Same as $ref.
-			if err := st.validate(instance, schemaInfo.resolvedDynamicRef, &anns); err != nil {
+			if err := st.validateRef("$dynamicRef", instance, schemaInfo.resolvedDynamicRef, anns); err != nil {
 				return err
 			}
 		} else {
@@ -225,8 +507,8 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			// on the stack.
 			// For an example, search for "detached" in testdata/draft2020-12/dynamicRef.json.
 			var dynamicSchema *Schema
-			for _, s := range st.stack {
-				base := st.rs.resolvedInfos[s].base
+			for _, f := range st.stack {
+				base := st.rs.resolvedInfos[f.schema].base
 				info, ok := st.rs.resolvedInfos[base].anchors[schemaInfo.dynamicRefAnchor]
 				if ok && info.dynamic {
 					dynamicSchema = info.schema
@@ -236,12 +518,19 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			if dynamicSchema == nil {
 				return fmt.Errorf("missing dynamic anchor %q", schemaInfo.dynamicRefAnchor)
 			}
-			if err := st.validate(instance, dynamicSchema, &anns); err != nil {
+			if err := st.validateRef("$dynamicRef", instance, dynamicSchema, anns); err != nil {
 				return err
 			}
 		}
 	}
 
+	// $recursiveRef: https://json-schema.org/draft/2019-09/json-schema-core#rfc.section.8.2.4.2
+	if schema.RecursiveRef != "" && st.rs.draft == draft2019 {
+		if err := st.validateRef("$recursiveRef", instance, st.resolveRecursiveRef(schemaInfo.resolvedRecursiveRef), anns); err != nil {
+			return err
+		}
+	}
+
 	// logic
 	// https://json-schema.org/draft/2020-12/json-schema-core#section-10.2
 	// These must happen before arrays and objects because if they evaluate an item or property,
@@ -251,11 +540,11 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 	// If any of these fail, then validation fails, even if there is an unevaluatedXXX
 	// keyword in the schema. The spec is unclear about this, but that is the intention.
 
-	valid := func(s *Schema, anns *annotations) bool { return st.validate(instance, s, anns) == nil }
+	valid := func(s *Schema, anns *annotations) bool { return st.check(instance, s, anns) == nil }
 
 	if schema.AllOf != nil {
 		for _, ss := range schema.AllOf {
-			if err := st.validate(instance, ss, &anns); err != nil {
+			if err := st.validate(instance, ss, anns); err != nil {
 				return err
 			}
 		}
@@ -264,44 +553,54 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 		// We must visit them all, to collect annotations.
 		ok := false
 		for _, ss := range schema.AnyOf {
-			if valid(ss, &anns) {
+			if valid(ss, anns) {
 				ok = true
 			}
 		}
 		if !ok {
-			return fmt.Errorf("anyOf: did not validate against any of %v", schema.AnyOf)
+			if err := st.fail("anyOf", "did not validate against any of %v", schema.AnyOf); err != nil {
+				return err
+			}
 		}
 	}
 	if schema.OneOf != nil {
 		// Exactly one.
 		var okSchema *Schema
 		for _, ss := range schema.OneOf {
-			if valid(ss, &anns) {
+			if valid(ss, anns) {
 				if okSchema != nil {
-					return fmt.Errorf("oneOf: validated against both %v and %v", okSchema, ss)
+					if err := st.fail("oneOf", "validated against both %v and %v", okSchema, ss); err != nil {
+						return err
+					}
+					break
 				}
 				okSchema = ss
 			}
 		}
 		if okSchema == nil {
-			return fmt.Errorf("oneOf: did not validate against any of %v", schema.OneOf)
+			if err := st.fail("oneOf", "did not validate against any of %v", schema.OneOf); err != nil {
+				return err
+			}
 		}
 	}
 	if schema.Not != nil {
 		// Ignore annotations from "not".
 		if valid(schema.Not, nil) {
-			return fmt.Errorf("not: validated against %v", schema.Not)
+			if err := st.fail("not", "validated against %v", schema.Not); err != nil {
+				return err
+			}
 		}
 	}
-	if schema.If != nil {
+	// if, then and else were added in draft-07.
+	if schema.If != nil && st.rs.draft >= draft7 {
 		var ss *Schema
-		if valid(schema.If, &anns) {
+		if valid(schema.If, anns) {
 			ss = schema.Then
 		} else {
 			ss = schema.Else
 		}
 		if ss != nil {
-			if err := st.validate(instance, ss, &anns); err != nil {
+			if err := st.validate(instance, ss, anns); err != nil {
 				return err
 			}
 		}
@@ -309,40 +608,40 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 
 	// arrays
 	if instance.Kind() == reflect.Array || instance.Kind() == reflect.Slice {
-		// Handle both draft-07 and draft 2020-12
+		// Handle draft-07, draft 2019-09 and draft 2020-12
 		// https://json-schema.org/draft/2020-12/json-schema-core#section-10.3.1
 		// This validate call doesn't collect annotations for the items of the instance; they are separate
 		// instances in their own right.
 		// TODO(jba): if the test suite doesn't cover this case, add a test. For example, nested arrays.
-		if st.rs.draft == draft7 {
-			// For draft-07: additionalItems applies to remaining items after items array.
+		if st.rs.draft <= draft2019 {
+			// For draft 2019-09 and earlier: additionalItems applies to remaining items after items array.
 			// If items is a Schema or if items is not set, additionalItems should be ignored
 			if schema.ItemsArray != nil {
 				for i, ischema := range schema.ItemsArray {
 					if i >= instance.Len() {
 						break // shorter is OK
 					}
-					if err := st.validate(instance.Index(i), ischema, nil); err != nil {
+					if err := st.validateElem(strconv.Itoa(i), instance.Index(i), ischema); err != nil {
 						return err
 					}
 				}
 				anns.noteEndIndex(min(len(schema.ItemsArray), instance.Len()))
 				if schema.AdditionalItems != nil {
 					for i := len(schema.ItemsArray); i < instance.Len(); i++ {
-						if err := st.validate(instance.Index(i), schema.AdditionalItems, nil); err != nil {
+						if err := st.validateElem(strconv.Itoa(i), instance.Index(i), schema.AdditionalItems); err != nil {
 							return err
 						}
 					}
-					anns.allItems = true
+					anns.noteAllItems()
 				}
 			} else if schema.Items != nil {
 				for i := 0; i < instance.Len(); i++ {
-					if err := st.validate(instance.Index(i), schema.Items, nil); err != nil {
+					if err := st.validateElem(strconv.Itoa(i), instance.Index(i), schema.Items); err != nil {
 						return err
 					}
 				}
 				// Note that all the items in this array have been validated.
-				anns.allItems = true
+				anns.noteAllItems()
 			}
 		} else if st.rs.draft == draft2020 {
 			// For draft 2020-12: items applies to remaining items after prefixItems
@@ -350,31 +649,33 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 				if i >= instance.Len() {
 					break // shorter is OK
 				}
-				if err := st.validate(instance.Index(i), ischema, nil); err != nil {
+				if err := st.validateElem(strconv.Itoa(i), instance.Index(i), ischema); err != nil {
 					return err
 				}
 			}
 			anns.noteEndIndex(min(len(schema.PrefixItems), instance.Len()))
 			if schema.Items != nil {
 				for i := len(schema.PrefixItems); i < instance.Len(); i++ {
-					if err := st.validate(instance.Index(i), schema.Items, nil); err != nil {
+					if err := st.validateElem(strconv.Itoa(i), instance.Index(i), schema.Items); err != nil {
 						return err
 					}
 				}
 				// Note that all the items in this array have been validated.
-				anns.allItems = true
+				anns.noteAllItems()
 			}
 		}
 		nContains := 0
 		if schema.Contains != nil {
 			for i := range instance.Len() {
-				if err := st.validate(instance.Index(i), schema.Contains, nil); err == nil {
+				if err := st.check(instance.Index(i), schema.Contains, nil); err == nil {
 					nContains++
 					anns.noteIndex(i)
 				}
 			}
 			if nContains == 0 && (schema.MinContains == nil || *schema.MinContains > 0) {
-				return fmt.Errorf("contains: %s does not have an item matching %s", instance, schema.Contains)
+				if err := st.fail("contains", "%s does not have an item matching %s", instance, schema.Contains); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -382,22 +683,30 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 		// TODO(jba): check that these next four keywords' values are integers.
 		if schema.MinContains != nil && schema.Contains != nil {
 			if m := *schema.MinContains; nContains < m {
-				return fmt.Errorf("minContains: contains validated %d items, less than %d", nContains, m)
+				if err := st.fail("minContains", "contains validated %d items, less than %d", nContains, m); err != nil {
+					return err
+				}
 			}
 		}
 		if schema.MaxContains != nil && schema.Contains != nil {
 			if m := *schema.MaxContains; nContains > m {
-				return fmt.Errorf("maxContains: contains validated %d items, greater than %d", nContains, m)
+				if err := st.fail("maxContains", "contains validated %d items, greater than %d", nContains, m); err != nil {
+					return err
+				}
 			}
 		}
 		if schema.MinItems != nil {
 			if m := *schema.MinItems; instance.Len() < m {
-				return fmt.Errorf("minItems: array length %d is less than %d", instance.Len(), m)
+				if err := st.fail("minItems", "array length %d is less than %d", instance.Len(), m); err != nil {
+					return err
+				}
 			}
 		}
 		if schema.MaxItems != nil {
 			if m := *schema.MaxItems; instance.Len() > m {
-				return fmt.Errorf("maxItems: array length %d is greater than %d", instance.Len(), m)
+				if err := st.fail("maxItems", "array length %d is greater than %d", instance.Len(), m); err != nil {
+					return err
+				}
 			}
 		}
 		if schema.UniqueItems {
@@ -418,7 +727,10 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 					if sames := hashes[hv]; len(sames) > 0 {
 						for _, j := range sames {
 							if equalValue(item, instance.Index(j)) {
-								return fmt.Errorf("uniqueItems: array items %d and %d are equal", i, j)
+								if err := st.fail("uniqueItems", "array items %d and %d are equal", i, j); err != nil {
+									return err
+								}
+								break
 							}
 						}
 					}
@@ -433,12 +745,12 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			// That includes validations by subschemas on the same instance, like allOf.
 			for i := anns.endIndex; i < instance.Len(); i++ {
 				if !anns.evaluatedIndexes[i] {
-					if err := st.validate(instance.Index(i), schema.UnevaluatedItems, nil); err != nil {
+					if err := st.validateElem(strconv.Itoa(i), instance.Index(i), schema.UnevaluatedItems); err != nil {
 						return err
 					}
 				}
 			}
-			anns.allItems = true
+			anns.noteAllItems()
 		}
 	}
 
@@ -455,7 +767,7 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 		// Track the evaluated properties for just this schema, to support additionalProperties.
 		// If we used anns here, then we'd be including properties evaluated in subschemas
 		// from allOf, etc., which additionalProperties shouldn't observe.
-		evalProps := map[string]bool{}
+		evalProps := st.propertySet()
 		for prop, subschema := range schema.Properties {
 			val := property(instance, prop)
 			if !val.IsValid() {
@@ -468,23 +780,28 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			if instance.Kind() == reflect.Struct && val.IsZero() && !schemaInfo.isRequired[prop] {
 				continue
 			}
-			if err := st.validate(val, subschema, nil); err != nil {
+			if err := st.validateElem(prop, val, subschema); err != nil {
 				return err
 			}
 			evalProps[prop] = true
 		}
+		// The loops over properties use st.objectProperties rather than an
+		// iterator, which would cost allocations for every call of
+		// validateSchema.
+		propsStart := len(st.props)
 		if len(schema.PatternProperties) > 0 {
-			for prop, val := range properties(instance) {
+			for _, p := range st.objectProperties(instance) {
 				// Check every matching pattern.
 				for re, schema := range schemaInfo.patternProperties {
-					if re.MatchString(prop) {
-						if err := st.validate(val, schema, nil); err != nil {
+					if re.MatchString(p.name) {
+						if err := st.validateElem(p.name, p.val, schema); err != nil {
 							return err
 						}
-						evalProps[prop] = true
+						evalProps[p.name] = true
 					}
 				}
 			}
+			st.props = st.props[:propsStart]
 		}
 		if schema.AdditionalProperties != nil {
 			// Special case for a better error message when additional properties is
@@ -495,39 +812,44 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			// summarizes all the extra properties. Otherwise, we fall back to the
 			// default validation.
 			//
-			// Note: this is much faster than comparing with falseSchema using Equal.
-			isFalsy := schema.AdditionalProperties.Not != nil && reflect.ValueOf(*schema.AdditionalProperties.Not).IsZero()
-			if isFalsy {
+			// The check is made once, in [compileSchemas].
+			if schemaInfo.additionalPropertiesFalse {
 				var disallowed []string
-				for prop := range properties(instance) {
-					if !evalProps[prop] {
-						disallowed = append(disallowed, prop)
+				for _, p := range st.objectProperties(instance) {
+					if !evalProps[p.name] {
+						disallowed = append(disallowed, p.name)
 					}
 				}
+				st.props = st.props[:propsStart]
 				if len(disallowed) > 0 {
-					return fmt.Errorf("unexpected additional properties %q", disallowed)
+					if err := st.fail("additionalProperties", "unexpected additional properties %q", disallowed); err != nil {
+						return err
+					}
 				}
 			} else {
 				// Apply to all properties not handled above.
-				for prop, val := range properties(instance) {
-					if !evalProps[prop] {
-						if err := st.validate(val, schema.AdditionalProperties, nil); err != nil {
+				for _, p := range st.objectProperties(instance) {
+					if !evalProps[p.name] {
+						if err := st.validateElem(p.name, p.val, schema.AdditionalProperties); err != nil {
 							return err
 						}
-						evalProps[prop] = true
+						evalProps[p.name] = true
 					}
 				}
+				st.props = st.props[:propsStart]
 			}
 		}
 		anns.noteProperties(evalProps)
+		st.releasePropertySet(evalProps)
 		if schema.PropertyNames != nil {
-			// Note: properties unnecessarily fetches each value. We could define a propertyNames function
+			// Note: objectProperties unnecessarily fetches each value. We could define a propertyNames function
 			// if performance ever matters.
-			for prop := range properties(instance) {
-				if err := st.validate(reflect.ValueOf(prop), schema.PropertyNames, nil); err != nil {
+			for _, p := range st.objectProperties(instance) {
+				if err := st.validate(reflect.ValueOf(p.name), schema.PropertyNames, nil); err != nil {
 					return err
 				}
 			}
+			st.props = st.props[:propsStart]
 		}
 
 		// https://json-schema.org/draft/2020-12/draft-bhutton-json-schema-validation-01#section-6.5
@@ -537,12 +859,16 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 		}
 		if schema.MinProperties != nil {
 			if n, m := max, *schema.MinProperties; n < m {
-				return fmt.Errorf("minProperties: object has %d properties, less than %d", n, m)
+				if err := st.fail("minProperties", "object has %d properties, less than %d", n, m); err != nil {
+					return err
+				}
 			}
 		}
 		if schema.MaxProperties != nil {
 			if n, m := min, *schema.MaxProperties; n > m {
-				return fmt.Errorf("maxProperties: object has %d properties, greater than %d", n, m)
+				if err := st.fail("maxProperties", "object has %d properties, greater than %d", n, m); err != nil {
+					return err
+				}
 			}
 		}
 
@@ -562,16 +888,20 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 
 		if schema.Required != nil {
 			if m := missingProperties(schema.Required); len(m) > 0 {
-				return fmt.Errorf("required: missing properties: %q", m)
+				if err := st.fail("required", "missing properties: %q", m); err != nil {
+					return err
+				}
 			}
 		}
 
-		if st.rs.draft == draft7 {
+		if st.rs.draft <= draft7 {
 			if schema.DependencyStrings != nil {
 				for dprop, dstrings := range schema.DependencyStrings {
 					if hasProperty(dprop) {
 						if m := missingProperties(dstrings); len(m) > 0 {
-							return fmt.Errorf("dependentRequired[%q]: missing properties %q", dprop, m)
+							if err := st.fail("dependencies", "property %q requires missing properties %q", dprop, m); err != nil {
+								return err
+							}
 						}
 					}
 				}
@@ -579,14 +909,15 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 			if schema.DependencySchemas != nil {
 				for dprop, dschema := range schema.DependencySchemas {
 					if hasProperty(dprop) {
-						err := st.validate(instance, dschema, &anns)
+						err := st.validate(instance, dschema, anns)
 						if err != nil {
 							return err
 						}
 					}
 				}
 			}
-		} else if st.rs.draft == draft2020 {
+		} else {
+			// draft 2019-09 and 2020-12
 			if schema.DependentRequired != nil {
 				// "Validation succeeds if, for each name that appears in both the instance
 				// and as a name within this keyword's value, every item in the corresponding
@@ -594,7 +925,9 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 				for dprop, reqs := range schema.DependentRequired {
 					if hasProperty(dprop) {
 						if m := missingProperties(reqs); len(m) > 0 {
-							return fmt.Errorf("dependentRequired[%q]: missing properties %q", dprop, m)
+							if err := st.fail("dependentRequired", "property %q requires missing properties %q", dprop, m); err != nil {
+								return err
+							}
 						}
 					}
 				}
@@ -606,7 +939,7 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 				for dprop, ss := range schema.DependentSchemas {
 					if hasProperty(dprop) {
 						// TODO: include dependentSchemas[dprop] in the errors.
-						err := st.validate(instance, ss, &anns)
+						err := st.validate(instance, ss, anns)
 						if err != nil {
 							return err
 						}
@@ -618,27 +951,48 @@ func (st *state) validate(instance reflect.Value, schema *Schema, callerAnns *an
 		if schema.UnevaluatedProperties != nil && !anns.allProperties {
 			// This looks a lot like AdditionalProperties, but depends on in-place keywords like allOf
 			// in addition to sibling keywords.
-			for prop, val := range properties(instance) {
-				if !anns.evaluatedProperties[prop] {
-					if err := st.validate(val, schema.UnevaluatedProperties, nil); err != nil {
+			for _, p := range st.objectProperties(instance) {
+				if !anns.evaluatedProperties[p.name] {
+					if err := st.validateElem(p.name, p.val, schema.UnevaluatedProperties); err != nil {
 						return err
 					}
 				}
 			}
+			st.props = st.props[:propsStart]
 			// The spec says the annotation should be the set of evaluated properties, but we can optimize
 			// by setting a single boolean, since after this succeeds all properties will be validated.
 			// See https://json-schema.slack.com/archives/CT7FF623C/p1745592564381459.
-			anns.allProperties = true
+			anns.noteAllProperties()
 		}
 	}
 
 	if callerAnns != nil {
 		// Our caller wants to know what we've validated.
-		callerAnns.merge(&anns)
+		callerAnns.merge(anns)
 	}
 	return nil
 }
 
+// resolveRecursiveRef returns the schema that a $recursiveRef refers to,
+// given the schema it refers to lexically.
+// If that schema has "$recursiveAnchor": true, the reference instead refers to
+// the outermost schema resource in the dynamic scope that is reached from the
+// current one through resources that all have "$recursiveAnchor": true.
+// See https://json-schema.org/draft/2019-09/json-schema-core#rfc.section.8.2.4.2.2.
+func (st *state) resolveRecursiveRef(target *Schema) *Schema {
+	if !target.RecursiveAnchor {
+		return target
+	}
+	for i := len(st.stack) - 1; i >= 0; i-- {
+		base := st.rs.resolvedInfos[st.stack[i].schema].base
+		if !base.RecursiveAnchor {
+			break
+		}
+		target = base
+	}
+	return target
+}
+
 // resolveDynamicRef returns the schema referred to by the argument schema's
 // $dynamicRef value.
 // It returns an error if the dynamic reference has no referent.
@@ -665,8 +1019,8 @@ func (st *state) resolveDynamicRef(schema *Schema) (*Schema, error) {
 	// refer to a schema that is not on the stack, but a child of some base
 	// on the stack.
 	// For an example, search for "detached" in testdata/draft2020-12/dynamicRef.json.
-	for _, s := range st.stack {
-		base := st.rs.resolvedInfos[s].base
+	for _, f := range st.stack {
+		base := st.rs.resolvedInfos[f.schema].base
 		info, ok := st.rs.resolvedInfos[base].anchors[info.dynamicRefAnchor]
 		if ok && info.dynamic {
 			return info.schema, nil
@@ -675,6 +1029,38 @@ func (st *state) resolveDynamicRef(schema *Schema) (*Schema, error) {
 	return nil, fmt.Errorf("missing dynamic anchor %q", info.dynamicRefAnchor)
 }
 
+// validateContent validates the string instance s against the content keywords
+// of schema. See [ResolveOptions.AssertContent].
+func (st *state) validateContent(s string, schema *Schema) error {
+	data := []byte(s)
+	switch schema.ContentEncoding {
+	case "":
+	case "base64":
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return st.fail("contentEncoding", "invalid base64: %v", err)
+		}
+		data = b
+	default:
+		// We can't decode the content, so we can't check it.
+		return nil
+	}
+	if schema.ContentMediaType == "" {
+		return nil
+	}
+	if mt, _, err := mime.ParseMediaType(schema.ContentMediaType); err != nil || mt != "application/json" {
+		return nil
+	}
+	var doc any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return st.fail("contentMediaType", "invalid JSON: %v", err)
+	}
+	if schema.ContentSchema == nil {
+		return nil
+	}
+	return st.validate(reflect.ValueOf(doc), schema.ContentSchema, nil)
+}
+
 // ApplyDefaults modifies an instance by applying the schema's defaults to it. If
 // a schema or sub-schema has a default, then a corresponding missing instance value
 // is set to the default.
@@ -683,8 +1069,12 @@ func (st *state) resolveDynamicRef(schema *Schema) (*Schema, error) {
 // This method honors defaults only on properties, and only those that are not required.
 // If the instance is a map and the property is missing, the property is added to
 // the map with the default.
-// ApplyDefaults does not support structs, because it cannot know whether a field
-// is missing in the JSON, or was explicitly set to its zero value.
+// If the instance is a struct, a field with its zero value is treated as missing
+// and set to the default, because a struct cannot record whether a field was
+// present in the JSON. So a default replaces an explicit zero value such as 0 or
+// false; use a pointer field to tell the two apart. Nil pointer, map and
+// interface fields whose schemas have defaults in their properties are populated
+// like missing map properties.
 //
 // ApplyDefaults can panic if a default cannot be assigned to a field.
 //
@@ -771,7 +1161,42 @@ func (st *state) applyDefaults(instancep reflect.Value, schema *Schema) (err err
 					}
 				}
 			case reflect.Struct:
-				return errors.New("cannot apply defaults to a struct")
+				if !val.IsValid() {
+					// The struct has no field for the property.
+					continue
+				}
+				if !val.CanAddr() {
+					return errors.New("cannot apply defaults to a struct that is not addressable")
+				}
+				if subschema.Default != nil && val.IsZero() {
+					if err := json.Unmarshal(subschema.Default, val.Addr().Interface()); err != nil {
+						return err
+					}
+				} else if val.IsZero() && schemaHasDefaultsInProperties(subschema) {
+					// The field is missing, but descendants still have some defaults.
+					// Create an empty container to populate.
+					switch val.Kind() {
+					case reflect.Interface:
+						val.Set(reflect.ValueOf(map[string]any{}))
+					case reflect.Map:
+						val.Set(reflect.MakeMap(val.Type()))
+					case reflect.Pointer:
+						if val.Type().Elem().Kind() == reflect.Struct {
+							val.Set(reflect.New(val.Type().Elem()))
+						}
+					}
+				}
+				fieldp := val.Addr()
+				if val.Kind() == reflect.Pointer {
+					if val.IsNil() {
+						continue
+					}
+					// Recurse into the pointed-to value, which is addressable.
+					fieldp = val
+				}
+				if err := st.applyDefaults(fieldp, subschema); err != nil {
+					return err
+				}
 			default:
 				panic(fmt.Sprintf("applyDefaults: property %s: bad value %s of kind %s",
 					prop, instance, instance.Kind()))
@@ -809,6 +1234,13 @@ func schemaHasDefaultsInProperties(s *Schema) bool {
 func property(v reflect.Value, name string) reflect.Value {
 	switch v.Kind() {
 	case reflect.Map:
+		if m, ok := jsonObject(v); ok {
+			e, ok := m[name]
+			if !ok {
+				return reflect.Value{}
+			}
+			return elemValue(e)
+		}
 		return v.MapIndex(reflect.ValueOf(name))
 	case reflect.Struct:
 		props := structPropertiesOf(v.Type())
@@ -830,6 +1262,14 @@ func properties(v reflect.Value) iter.Seq2[string, reflect.Value] {
 	return func(yield func(string, reflect.Value) bool) {
 		switch v.Kind() {
 		case reflect.Map:
+			if m, ok := jsonObject(v); ok {
+				for k, e := range m {
+					if !yield(k, elemValue(e)) {
+						return
+					}
+				}
+				return
+			}
 			for k, e := range v.Seq2() {
 				if !yield(k.String(), e) {
 					return
@@ -854,6 +1294,45 @@ func properties(v reflect.Value) iter.Seq2[string, reflect.Value] {
 	}
 }
 
+// jsonObject returns the map in v, which must be a map, if it is the
+// map[string]any produced by unmarshaling JSON. Accessing such a map directly
+// avoids the allocations of reflection.
+func jsonObject(v reflect.Value) (map[string]any, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	m, ok := v.Interface().(map[string]any)
+	return m, ok
+}
+
+// anyType is the type of the values of a map[string]any.
+var anyType = reflect.TypeFor[any]()
+
+// elemValue returns the reflect.Value of e, a value of a map[string]any.
+// A nil e is valid, like the result of [reflect.Value.MapIndex].
+func elemValue(e any) reflect.Value {
+	if e == nil {
+		return reflect.Zero(anyType)
+	}
+	return reflect.ValueOf(e)
+}
+
+// isMultiple reports whether a number instance is a multiple of m.
+// Floats are divided as floats, as the test suite expects. Other numbers, such
+// as large integers and json.Numbers, are given exactly by r and are divided
+// exactly, with m taken as the decimal number it was written as.
+func isMultiple(f float64, r *big.Rat, m float64) bool {
+	if r == nil {
+		_, frac := math.Modf(f / m)
+		return frac == 0
+	}
+	rm, ok := new(big.Rat).SetString(strconv.FormatFloat(m, 'g', -1, 64))
+	if !ok || rm.Sign() == 0 {
+		return false
+	}
+	return rm.Quo(r, rm).IsInt()
+}
+
 // numPropertiesBounds returns bounds on the number of v's properties.
 // v must be a map or a struct.
 // If v is a map, both bounds are the map's size.
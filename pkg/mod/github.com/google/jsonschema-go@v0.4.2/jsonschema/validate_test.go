@@ -5,12 +5,15 @@
 package jsonschema
 
 import (
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"path/filepath"
 	"reflect"
+	"slices"
 	"strings"
 	"testing"
 
@@ -93,6 +96,10 @@ func testValidate(t *testing.T, files []string, draft string) {
 									t.Errorf("got error %q, want containing %q", err, test.ErrContains)
 								}
 							}
+							// ValidateAll must agree with Validate.
+							if errAll := rs.ValidateAll(test.Data); (errAll == nil) != (err == nil) {
+								t.Errorf("Validate returned %v, but ValidateAll returned %v", err, errAll)
+							}
 							if t.Failed() {
 								t.Errorf("schema: %s", g.Schema.json())
 								t.Fatalf("instance: %v (%[1]T)", test.Data)
@@ -120,6 +127,224 @@ func TestValidateErrors(t *testing.T) {
 	}
 }
 
+func TestValidateAll(t *testing.T) {
+	schema := &Schema{
+		Type:     "object",
+		Required: []string{"name", "email"},
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MinLength: Ptr(1)},
+			"age":  {Ref: "#/$defs/nonNegative"},
+			"tags": {Type: "array", Items: &Schema{Type: "string"}, UniqueItems: true},
+			"kind": {AnyOf: []*Schema{{Const: Ptr[any]("a")}, {Const: Ptr[any]("b")}}},
+		},
+		Defs: map[string]*Schema{
+			"nonNegative": {Type: "integer", Minimum: Ptr(0.0)},
+		},
+	}
+	rs, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := map[string]any{
+		"name": "",
+		"age":  -1,
+		"tags": []any{"x", 1, "x"},
+		"kind": "c",
+	}
+	err = rs.ValidateAll(instance)
+	var got ValidationErrors
+	if !errors.As(err, &got) {
+		t.Fatalf("got %v, want ValidationErrors", err)
+	}
+	want := ValidationErrors{
+		{InstanceLocation: "/age", KeywordLocation: "/properties/age/$ref/minimum", Keyword: "minimum"},
+		{InstanceLocation: "/kind", KeywordLocation: "/properties/kind/anyOf", Keyword: "anyOf"},
+		{InstanceLocation: "/name", KeywordLocation: "/properties/name/minLength", Keyword: "minLength"},
+		{InstanceLocation: "/tags/1", KeywordLocation: "/properties/tags/items/type", Keyword: "type"},
+		{InstanceLocation: "/tags", KeywordLocation: "/properties/tags/uniqueItems", Keyword: "uniqueItems"},
+		{InstanceLocation: "", KeywordLocation: "/required", Keyword: "required"},
+	}
+	// Properties are validated in map order.
+	slices.SortFunc(got, func(a, b *ValidationError) int { return strings.Compare(a.KeywordLocation, b.KeywordLocation) })
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(ValidationError{}, "Message"), cmpopts.IgnoreUnexported(ValidationError{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	for _, e := range got {
+		if e.Message == "" {
+			t.Errorf("%s: empty message", e.KeywordLocation)
+		}
+	}
+
+	// errors.Is finds individual failures.
+	wrapped := fmt.Errorf("request: %w", err)
+	for _, target := range []*ValidationError{
+		{Keyword: "required"},
+		{InstanceLocation: "/tags", Keyword: "uniqueItems"},
+		{KeywordLocation: "/properties/age/$ref/minimum"},
+	} {
+		if !errors.Is(wrapped, target) {
+			t.Errorf("errors.Is(%+v) = false, want true", target)
+		}
+	}
+	if errors.Is(wrapped, &ValidationError{Keyword: "maxLength"}) {
+		t.Error("errors.Is(maxLength) = true, want false")
+	}
+
+	if err := rs.ValidateAll(map[string]any{"name": "n", "email": "e", "kind": "b"}); err != nil {
+		t.Errorf("got %v, want success", err)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	schema := &Schema{
+		Properties: map[string]*Schema{
+			"a/b": {Items: &Schema{Type: "string"}},
+		},
+	}
+	rs, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = rs.Validate(map[string]any{"a/b": []any{"x", 1}})
+	var ve *ValidationError
+	if !errors.As(err, &ve) {
+		t.Fatalf("got %v, want a ValidationError", err)
+	}
+	want := &ValidationError{
+		InstanceLocation: "/a~1b/1",
+		KeywordLocation:  "/properties/a~1b/items/type",
+		Keyword:          "type",
+		Message:          `1 has type "integer", want "string"`,
+	}
+	if diff := cmp.Diff(want, ve, cmpopts.IgnoreUnexported(ValidationError{})); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+	// The error text still describes the path through the schema.
+	if got, want := err.Error(), "validating /properties/a~1b/items: type: "; !strings.Contains(got, want) {
+		t.Errorf("error %q does not contain %q", got, want)
+	}
+}
+
+func TestFormatAssertion(t *testing.T) {
+	for _, test := range []struct {
+		format string
+		valid  []string
+		bad    []string
+	}{
+		{"date-time", []string{"2025-01-02T03:04:05Z", "2025-01-02t03:04:05.5+01:00"}, []string{"2025-01-02", "2025-01-02T03:04:05", "2025-13-02T03:04:05Z"}},
+		{"date", []string{"2025-01-02"}, []string{"2025-1-2", "2025-02-30"}},
+		{"time", []string{"03:04:05Z", "03:04:05.123-07:00"}, []string{"03:04:05", "25:00:00Z"}},
+		{"email", []string{"gopher@example.com"}, []string{"gopher", "Gopher <gopher@example.com>"}},
+		{"hostname", []string{"example.com", "a-b.c", "localhost"}, []string{"-a.com", "a..b", "a_b.com", strings.Repeat("a", 64) + ".com"}},
+		{"ipv4", []string{"192.168.0.1"}, []string{"192.168.0", "::1", "192.168.00.1"}},
+		{"ipv6", []string{"::1", "2001:db8::8a2e:370:7334"}, []string{"192.168.0.1", "fe80::1%eth0", "1:::2"}},
+		{"uri", []string{"https://example.com/a?b#c", "urn:isbn:0451450523"}, []string{"/relative/path", "http://[::1"}},
+		{"uri-reference", []string{"/relative/path", "https://example.com"}, []string{"http://[::1"}},
+		{"uuid", []string{"123e4567-e89b-12d3-a456-426614174000"}, []string{"123e4567e89b12d3a456426614174000", "123e4567-e89b-12d3-a456-42661417400g"}},
+		{"regex", []string{"^a+$"}, []string{"(a"}},
+	} {
+		t.Run(test.format, func(t *testing.T) {
+			schema := &Schema{Type: "string", Format: test.format}
+			assert, err := schema.Resolve(&ResolveOptions{AssertFormats: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+			annotate, err := schema.Resolve(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, v := range test.valid {
+				if err := assert.Validate(v); err != nil {
+					t.Errorf("%q: %v", v, err)
+				}
+			}
+			for _, v := range test.bad {
+				if err := assert.Validate(v); err == nil || !strings.Contains(err.Error(), "format") {
+					t.Errorf("%q: got error %v, want format error", v, err)
+				}
+				// By default, format is only an annotation.
+				if err := annotate.Validate(v); err != nil {
+					t.Errorf("%q without AssertFormats: %v", v, err)
+				}
+			}
+		})
+	}
+
+	// Unknown formats and non-string instances are not checked.
+	schema := &Schema{Properties: map[string]*Schema{
+		"a": {Format: "unknown-format"},
+		"b": {Format: "email"},
+	}}
+	rs, err := schema.Resolve(&ResolveOptions{AssertFormats: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(map[string]any{"a": "anything", "b": 1}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestContentAssertion(t *testing.T) {
+	b64 := base64.StdEncoding.EncodeToString
+	schema := &Schema{Properties: map[string]*Schema{
+		"enc":  {ContentEncoding: "base64"},
+		"json": {ContentMediaType: "application/json"},
+		"both": {
+			ContentEncoding:  "base64",
+			ContentMediaType: "application/json; charset=utf-8",
+			ContentSchema:    &Schema{Type: "object", Required: []string{"x"}},
+		},
+		"other": {ContentEncoding: "base32", ContentMediaType: "text/plain"},
+	}}
+	assert, err := schema.Resolve(&ResolveOptions{AssertContent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	annotate, err := schema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		instance map[string]any
+		keyword  string // of the failure, or "" if valid
+	}{
+		{map[string]any{"enc": b64([]byte("hello"))}, ""},
+		{map[string]any{"enc": "not base64!"}, "contentEncoding"},
+		{map[string]any{"json": `{"a": [1, 2]}`}, ""},
+		{map[string]any{"json": `{"a": `}, "contentMediaType"},
+		{map[string]any{"both": b64([]byte(`{"x": 1}`))}, ""},
+		{map[string]any{"both": b64([]byte(`{"y": 1}`))}, "required"},
+		{map[string]any{"both": `{"x": 1}`}, "contentEncoding"},
+		{map[string]any{"other": "anything", "json": 1}, ""},
+	} {
+		err := assert.Validate(tt.instance)
+		if tt.keyword == "" {
+			if err != nil {
+				t.Errorf("%v: %v", tt.instance, err)
+			}
+		} else {
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Keyword != tt.keyword {
+				t.Errorf("%v: got error %v, want %s failure", tt.instance, err, tt.keyword)
+			}
+		}
+		// By default, the content keywords are only annotations.
+		if err := annotate.Validate(tt.instance); err != nil {
+			t.Errorf("%v without AssertContent: %v", tt.instance, err)
+		}
+	}
+
+	// Failures in the embedded document are located under contentSchema.
+	err = assert.ValidateAll(map[string]any{"both": b64([]byte(`{}`))})
+	var verrs ValidationErrors
+	if !errors.As(err, &verrs) || len(verrs) != 1 {
+		t.Fatalf("got %v, want one failure", err)
+	}
+	if got, want := verrs[0].KeywordLocation, "/properties/both/contentSchema/required"; got != want {
+		t.Errorf("KeywordLocation: got %q, want %q", got, want)
+	}
+}
+
 func TestValidateDefaults(t *testing.T) {
 	s := &Schema{
 		Properties: map[string]*Schema{
@@ -264,6 +489,82 @@ func TestApplyNestedDefaults(t *testing.T) {
 	}
 }
 
+func TestApplyDefaultsStruct(t *testing.T) {
+	type inner struct {
+		B string `json:"b"`
+	}
+	type S struct {
+		A     int            `json:"a"`
+		P     *int           `json:"p"`
+		Req   string         `json:"req"`
+		In    inner          `json:"in"`
+		InP   *inner         `json:"inp"`
+		M     map[string]any `json:"m"`
+		Other string         `json:"other"`
+	}
+	withB := func() *Schema {
+		return &Schema{Type: "object", Properties: map[string]*Schema{"b": {Type: "string", Default: mustMarshal("foo")}}}
+	}
+	schema := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"a":   {Type: "integer", Default: mustMarshal(1)},
+			"p":   {Type: "integer", Default: mustMarshal(2)},
+			"req": {Type: "string", Default: mustMarshal("x")},
+			"in":  withB(),
+			"inp": withB(),
+			"m":   withB(),
+			"nf":  {Default: mustMarshal(3)}, // no field
+		},
+		Required: []string{"req"},
+	}
+	rs, err := schema.Resolve(&ResolveOptions{ValidateDefaults: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, tt := range []struct {
+		name string
+		in   S
+		want S
+	}{
+		{
+			name: "zero",
+			in:   S{},
+			want: S{A: 1, P: Ptr(2), In: inner{"foo"}, InP: &inner{"foo"}, M: map[string]any{"b": "foo"}},
+		},
+		{
+			name: "set",
+			in:   S{A: 5, P: Ptr(0), In: inner{"bar"}, InP: &inner{}, M: map[string]any{"b": "bar"}, Other: "o"},
+			want: S{A: 5, P: Ptr(0), In: inner{"bar"}, InP: &inner{"foo"}, M: map[string]any{"b": "bar"}, Other: "o"},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			if err := rs.ApplyDefaults(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("\ngot  %#v\nwant %#v", got, tt.want)
+			}
+		})
+	}
+
+	// A struct in a map is copied, so it is addressable.
+	m := map[string]S{"k": {}}
+	mschema := &Schema{Properties: map[string]*Schema{"k": schema}}
+	mrs, err := mschema.Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mrs.ApplyDefaults(&m); err != nil {
+		t.Fatal(err)
+	}
+	if got := m["k"].A; got != 1 {
+		t.Errorf(`m["k"].A = %d, want 1`, got)
+	}
+}
+
 func TestStructInstance(t *testing.T) {
 	instance := struct {
 		I int
@@ -609,3 +910,219 @@ func loadSchemaFromFile(filename string) (*Schema, error) {
 	}
 	return &s, nil
 }
+
+// TestValidateSpecialCases tests cases that validation handles specially
+// for speed.
+func TestValidateSpecialCases(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		schema   string
+		instance string
+		valid    bool
+	}{
+		{"null property", `{"properties": {"a": {"type": "null"}}, "required": ["a"]}`, `{"a": null}`, true},
+		{"null property invalid", `{"properties": {"a": {"type": "string"}}}`, `{"a": null}`, false},
+		{"float bound", `{"maximum": 1.5}`, `1.5`, true},
+		{"float exclusive bound", `{"exclusiveMaximum": 1.5}`, `1.5`, false},
+		{"float multipleOf", `{"multipleOf": 0.5}`, `2.5`, true},
+		{"unevaluatedProperties through allOf", `{"allOf": [{"properties": {"a": true}}], "unevaluatedProperties": false}`, `{"a": 1}`, true},
+		{"unevaluatedProperties through allOf invalid", `{"allOf": [{"properties": {"a": true}}], "unevaluatedProperties": false}`, `{"a": 1, "b": 2}`, false},
+		{"nested unevaluatedItems", `{"properties": {"x": {"prefixItems": [true], "unevaluatedItems": false}}}`, `{"x": [1, 2]}`, false},
+		{"string enum", `{"enum": ["a", "b"]}`, `"b"`, true},
+		{"string enum number", `{"enum": ["1"]}`, `1`, false},
+		{"string enum null", `{"enum": ["a"]}`, `null`, false},
+		{"mixed enum", `{"enum": ["a", 1.0]}`, `1`, true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var schema Schema
+			if err := json.Unmarshal([]byte(tt.schema), &schema); err != nil {
+				t.Fatal(err)
+			}
+			var instance any
+			if err := json.Unmarshal([]byte(tt.instance), &instance); err != nil {
+				t.Fatal(err)
+			}
+			rs, err := schema.Resolve(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rs.Validate(instance); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid = %t", err, tt.valid)
+			}
+		})
+	}
+
+	// Integers beyond the precision of a float64 are compared exactly.
+	rs, err := (&Schema{Maximum: Ptr(float64(1 << 53))}).Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(int64(1<<53 + 1)); err == nil {
+		t.Error("got nil error for integer above maximum")
+	}
+}
+
+func TestValidateJSONNumber(t *testing.T) {
+	// Instances decoded with json.Decoder.UseNumber, and integers too large
+	// for a float64, are validated exactly.
+	for _, tt := range []struct {
+		name     string
+		schema   *Schema
+		instance any
+		valid    bool
+	}{
+		{"integer", &Schema{Type: "integer"}, json.Number("9007199254740993"), true},
+		{"not integer", &Schema{Type: "integer"}, json.Number("9007199254740992.5"), false},
+		{"number", &Schema{Type: "number"}, json.Number("1.5"), true},
+		{"not string", &Schema{Type: "string"}, json.Number("1"), false},
+		{"no string keywords", &Schema{MaxLength: Ptr(1)}, json.Number("12345"), true},
+		{"maximum", &Schema{Maximum: Ptr(float64(1 << 53))}, json.Number("9007199254740993"), false},
+		{"minimum", &Schema{Minimum: Ptr(float64(1 << 53))}, json.Number("9007199254740993"), true},
+		{"multipleOf", &Schema{MultipleOf: Ptr(2.0)}, json.Number("9007199254740993"), false},
+		{"multipleOf int64", &Schema{MultipleOf: Ptr(2.0)}, int64(1<<53 + 1), false},
+		{"multipleOf uint64", &Schema{MultipleOf: Ptr(3.0)}, uint64(1<<64 - 1), true},
+		{"multipleOf decimal", &Schema{MultipleOf: Ptr(0.1)}, json.Number("0.3"), true},
+		{"enum", &Schema{Enum: []any{json.Number("9007199254740993")}}, int64(1<<53 + 1), true},
+		{"enum inexact", &Schema{Enum: []any{float64(1 << 53)}}, json.Number("9007199254740993"), false},
+		{"string enum", &Schema{Enum: []any{"1"}}, json.Number("1"), true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rs, err := tt.schema.Resolve(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := rs.Validate(tt.instance); (err == nil) != tt.valid {
+				t.Errorf("got %v, want valid = %t", err, tt.valid)
+			}
+		})
+	}
+}
+
+func TestValidateReusedState(t *testing.T) {
+	// Validation reuses its state between calls. Check that the results of
+	// one call are not affected by later ones.
+	rs, err := (&Schema{
+		Type:                 "object",
+		Properties:           map[string]*Schema{"a": {Type: "integer"}, "b": {Type: "string"}},
+		AdditionalProperties: falseSchema(),
+	}).Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err1 := rs.ValidateAll(map[string]any{"a": "x", "b": 1})
+	err2 := rs.ValidateAll(map[string]any{"a": 1, "c": true})
+	if err := rs.Validate(map[string]any{"a": 1, "b": "y"}); err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range []struct {
+		err  error
+		want []string // keywords
+	}{
+		{err1, []string{"type", "type"}},
+		{err2, []string{"additionalProperties"}},
+	} {
+		var errs ValidationErrors
+		if !errors.As(tt.err, &errs) {
+			t.Fatalf("got %v, want ValidationErrors", tt.err)
+		}
+		var got []string
+		for _, e := range errs {
+			got = append(got, e.Keyword)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("got keywords %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	var schema Schema
+	if err := json.Unmarshal([]byte(`{
+		"type": "object",
+		"properties": {
+			"id": {"type": "string", "pattern": "^[a-z]+-[0-9]+$"},
+			"count": {"type": "integer", "minimum": 0, "maximum": 1000},
+			"price": {"type": "number", "exclusiveMinimum": 0},
+			"tags": {"type": "array", "items": {"type": "string", "maxLength": 20}},
+			"owner": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "email": {"type": "string"}},
+				"required": ["name"]
+			}
+		},
+		"required": ["id", "count"],
+		"additionalProperties": false
+	}`), &schema); err != nil {
+		b.Fatal(err)
+	}
+	rs, err := schema.Resolve(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	var instance any
+	if err := json.Unmarshal([]byte(`{
+		"id": "abc-123",
+		"count": 17,
+		"price": 9.99,
+		"tags": ["a", "b", "c", "d", "e", "f", "g", "h"],
+		"owner": {"name": "gopher", "email": "gopher@example.com"}
+	}`), &instance); err != nil {
+		b.Fatal(err)
+	}
+	b.ReportAllocs()
+	for range b.N {
+		if err := rs.Validate(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateAssertions(b *testing.B) {
+	item := &Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"kind":  {Type: "string", Enum: []any{"a", "b", "c", "d"}},
+			"day":   {Type: "string", Format: "date", MaxLength: Ptr(10)},
+			"score": {Types: []string{"number", "null"}, Minimum: Ptr(0.0), Maximum: Ptr(100.0), MultipleOf: Ptr(0.5)},
+			"code":  {Type: "string", MinLength: Ptr(3), Pattern: "^[A-Z]+$"},
+		},
+	}
+	rs, err := (&Schema{Type: "array", Items: item}).Resolve(&ResolveOptions{AssertFormats: true})
+	if err != nil {
+		b.Fatal(err)
+	}
+	var instance []any
+	for range 20 {
+		instance = append(instance, map[string]any{"kind": "c", "day": "2025-06-18", "score": 42.5, "code": "ABC"})
+	}
+	b.ReportAllocs()
+	for range b.N {
+		if err := rs.Validate(instance); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidateParallel(b *testing.B) {
+	rs, err := (&Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MinLength: Ptr(1)},
+			"tags": {Type: "array", Items: &Schema{Type: "string"}},
+		},
+		Required:             []string{"name"},
+		AdditionalProperties: falseSchema(),
+	}).Resolve(nil)
+	if err != nil {
+		b.Fatal(err)
+	}
+	instance := map[string]any{"name": "gopher", "tags": []any{"a", "b", "c"}}
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if err := rs.Validate(instance); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
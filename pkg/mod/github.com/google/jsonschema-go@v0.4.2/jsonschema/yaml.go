@@ -0,0 +1,93 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file supports schemas and instances written in YAML.
+
+package jsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2,
+// which github.com/goccy/go-yaml also supports, so that a Schema can be read
+// from a YAML document. The document is converted with [FromYAML] and then
+// unmarshaled like JSON.
+//
+// The Unmarshaler interface of gopkg.in/yaml.v3 takes a *yaml.Node instead;
+// yaml.v3 calls this method only through its support for the obsolete yaml.v2
+// interface. With yaml.v3, you can also decode the document into an [any],
+// convert it with FromYAML, and unmarshal its JSON encoding.
+func (s *Schema) UnmarshalYAML(unmarshal func(any) error) error {
+	var v any
+	if err := unmarshal(&v); err != nil {
+		return err
+	}
+	j, err := FromYAML(v)
+	if err != nil {
+		return err
+	}
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, s)
+}
+
+// FromYAML converts a value that a YAML package decoded into an [any] to a
+// value that can be validated like the result of unmarshaling JSON.
+//
+// Mappings with keys of type any become map[string]any. Keys must be strings,
+// numbers or booleans; the latter two are formatted as strings, so that the
+// YAML key 200 names the property "200". Timestamps become strings in RFC 3339
+// format. Other values are unchanged.
+func FromYAML(v any) (any, error) {
+	switch v := v.(type) {
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			var key string
+			switch k := k.(type) {
+			case string:
+				key = k
+			case int, int64, uint64, float64, bool:
+				key = fmt.Sprint(k)
+			default:
+				return nil, fmt.Errorf("YAML mapping key %v has unsupported type %T", k, k)
+			}
+			ce, err := FromYAML(e)
+			if err != nil {
+				return nil, err
+			}
+			m[key] = ce
+		}
+		return m, nil
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			ce, err := FromYAML(e)
+			if err != nil {
+				return nil, err
+			}
+			m[k] = ce
+		}
+		return m, nil
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			ce, err := FromYAML(e)
+			if err != nil {
+				return nil, err
+			}
+			s[i] = ce
+		}
+		return s, nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 The JSON Schema Go Project Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package jsonschema
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// yamlUnmarshal returns an unmarshal function like the one a YAML package
+// passes to UnmarshalYAML, which stores v.
+func yamlUnmarshal(v any) func(any) error {
+	return func(p any) error {
+		*p.(*any) = v
+		return nil
+	}
+}
+
+func TestSchemaUnmarshalYAML(t *testing.T) {
+	// What gopkg.in/yaml.v2 produces for
+	//
+	//	type: object
+	//	properties:
+	//	  name: {type: string, minLength: 1}
+	//	  codes:
+	//	    type: object
+	//	    properties:
+	//	      200: {const: true}
+	//	required: [name]
+	doc := map[any]any{
+		"type": "object",
+		"properties": map[any]any{
+			"name": map[any]any{"type": "string", "minLength": 1},
+			"codes": map[any]any{
+				"type":       "object",
+				"properties": map[any]any{200: map[any]any{"const": true}},
+			},
+		},
+		"required": []any{"name"},
+	}
+	var got Schema
+	if err := got.UnmarshalYAML(yamlUnmarshal(doc)); err != nil {
+		t.Fatal(err)
+	}
+	want := Schema{
+		Type: "object",
+		Properties: map[string]*Schema{
+			"name": {Type: "string", MinLength: Ptr(1)},
+			"codes": {
+				Type:       "object",
+				Properties: map[string]*Schema{"200": {Const: Ptr[any](true)}},
+			},
+		},
+		Required: []string{"name"},
+	}
+	if diff := cmp.Diff(&want, &got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Boolean schemas.
+	var b Schema
+	if err := b.UnmarshalYAML(yamlUnmarshal(false)); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(falseSchema(), &b); diff != "" {
+		t.Errorf("false: mismatch (-want, +got):\n%s", diff)
+	}
+
+	// Errors from the YAML package are returned.
+	yerr := errors.New("bad YAML")
+	if err := b.UnmarshalYAML(func(any) error { return yerr }); err != yerr {
+		t.Errorf("got %v, want %v", err, yerr)
+	}
+}
+
+func TestFromYAML(t *testing.T) {
+	ts := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	got, err := FromYAML(map[any]any{
+		"a":   []any{1, map[any]any{true: "t", 1.5: "f"}},
+		"b":   map[string]any{"c": map[any]any{"d": nil}},
+		"ts":  ts,
+		"int": int64(3),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{
+		"a":   []any{1, map[string]any{"true": "t", "1.5": "f"}},
+		"b":   map[string]any{"c": map[string]any{"d": nil}},
+		"ts":  "2025-01-02T03:04:05Z",
+		"int": int64(3),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("mismatch (-want, +got):\n%s", diff)
+	}
+
+	// The result can be validated.
+	rs, err := (&Schema{Properties: map[string]*Schema{"int": {Type: "integer", Maximum: Ptr(2.0)}}}).Resolve(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := rs.Validate(got); err == nil {
+		t.Error("got nil error, want maximum failure")
+	}
+
+	if _, err := FromYAML(map[any]any{nil: 2}); err == nil {
+		t.Error("null key: got nil error, want error")
+	}
+}
@@ -8,7 +8,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"slices"
 	"strings"
 	"time"
@@ -75,7 +77,7 @@ func RequireBearerToken(verifier TokenVerifier, opts *RequireBearerTokenOptions)
 			if code != 0 {
 				if code == http.StatusUnauthorized || code == http.StatusForbidden {
 					if opts != nil && opts.ResourceMetadataURL != "" {
-						w.Header().Add("WWW-Authenticate", "Bearer resource_metadata="+opts.ResourceMetadataURL)
+						w.Header().Add("WWW-Authenticate", bearerChallenge(r, code, errmsg, opts))
 					}
 				}
 				http.Error(w, errmsg, code)
@@ -87,6 +89,31 @@ func RequireBearerToken(verifier TokenVerifier, opts *RequireBearerTokenOptions)
 	}
 }
 
+// bearerChallenge returns the value of the WWW-Authenticate header for a failed
+// request, as described in RFC 6750 section 3 and RFC 9728 section 5.1.
+func bearerChallenge(req *http.Request, code int, errmsg string, opts *RequireBearerTokenOptions) string {
+	params := []string{"resource_metadata=" + quote(opts.ResourceMetadataURL)}
+	switch {
+	case code == http.StatusForbidden:
+		params = append(params, `error="insufficient_scope"`)
+		if len(opts.Scopes) > 0 {
+			params = append(params, "scope="+quote(strings.Join(opts.Scopes, " ")))
+		}
+	case req.Header.Get("Authorization") != "":
+		// RFC 6750 section 3.1: if the request lacks authentication
+		// information, the challenge should not include an error code.
+		params = append(params, `error="invalid_token"`, "error_description="+quote(errmsg))
+	}
+	return "Bearer " + strings.Join(params, ", ")
+}
+
+// quote returns s as an HTTP quoted-string (RFC 9110 section 5.6.4).
+func quote(s string) string {
+	return `"` + quoteReplacer.Replace(s) + `"`
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
 func verify(req *http.Request, verifier TokenVerifier, opts *RequireBearerTokenOptions) (_ *TokenInfo, errmsg string, code int) {
 	// Extract bearer token.
 	authHeader := req.Header.Get("Authorization")
@@ -166,3 +193,84 @@ func ProtectedResourceMetadataHandler(metadata *oauthex.ProtectedResourceMetadat
 		}
 	})
 }
+
+// ProtectedResourceMetadataURL returns the URL at which the protected resource
+// metadata for the given resource identifier is served, following RFC 9728
+// section 3.1: the well-known path is inserted between the host and the path
+// of the resource identifier.
+//
+// For example, the metadata for https://example.com/mcp is served at
+// https://example.com/.well-known/oauth-protected-resource/mcp.
+func ProtectedResourceMetadataURL(resource string) (string, error) {
+	u, err := url.Parse(resource)
+	if err != nil {
+		return "", err
+	}
+	u.Path = strings.TrimSuffix(wellKnownProtectedResourcePath+"/"+strings.TrimPrefix(u.Path, "/"), "/")
+	u.RawPath = ""
+	return u.String(), nil
+}
+
+const wellKnownProtectedResourcePath = "/.well-known/oauth-protected-resource"
+
+// ServeProtectedResourceMetadata validates metadata and registers a
+// [ProtectedResourceMetadataHandler] for it on mux, at the well-known path
+// derived from metadata.Resource.
+//
+// It returns the URL of the metadata, suitable for
+// [RequireBearerTokenOptions.ResourceMetadataURL], so that 401 responses
+// from the protected handler carry a WWW-Authenticate challenge pointing
+// clients to the metadata, as the MCP authorization spec requires.
+//
+// Validation checks that the resource identifier is an absolute https URL
+// without a fragment (RFC 9728 section 1.2), and that the authorization
+// servers are https URLs.
+func ServeProtectedResourceMetadata(mux *http.ServeMux, metadata *oauthex.ProtectedResourceMetadata) (string, error) {
+	if err := validateProtectedResourceMetadata(metadata); err != nil {
+		return "", err
+	}
+	metadataURL, err := ProtectedResourceMetadataURL(metadata.Resource)
+	if err != nil {
+		return "", err
+	}
+	u, err := url.Parse(metadataURL)
+	if err != nil {
+		return "", err
+	}
+	mux.Handle(u.EscapedPath(), ProtectedResourceMetadataHandler(metadata))
+	return metadataURL, nil
+}
+
+func validateProtectedResourceMetadata(m *oauthex.ProtectedResourceMetadata) error {
+	if m == nil {
+		return errors.New("nil protected resource metadata")
+	}
+	if m.Resource == "" {
+		return errors.New("protected resource metadata: missing resource")
+	}
+	if err := checkHTTPSURL(m.Resource); err != nil {
+		return fmt.Errorf("protected resource metadata: resource: %w", err)
+	}
+	u, _ := url.Parse(m.Resource) // checked above
+	if u.Fragment != "" {
+		return fmt.Errorf("protected resource metadata: resource %q has a fragment", m.Resource)
+	}
+	for i, as := range m.AuthorizationServers {
+		if err := checkHTTPSURL(as); err != nil {
+			return fmt.Errorf("protected resource metadata: authorization_servers[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// checkHTTPSURL reports an error if s is not an absolute https URL.
+func checkHTTPSURL(s string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(u.Scheme, "https") || u.Host == "" {
+		return fmt.Errorf("%q is not an absolute https URL", s)
+	}
+	return nil
+}
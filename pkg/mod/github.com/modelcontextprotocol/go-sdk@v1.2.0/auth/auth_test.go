@@ -188,3 +188,92 @@ func TestProtectedResourceMetadataHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestBearerChallenge(t *testing.T) {
+	verifier := func(_ context.Context, token string, _ *http.Request) (*TokenInfo, error) {
+		if token == "valid" {
+			return &TokenInfo{Expiration: time.Now().Add(time.Hour)}, nil
+		}
+		return nil, ErrInvalidToken
+	}
+	opts := &RequireBearerTokenOptions{
+		ResourceMetadataURL: "https://example.com/.well-known/oauth-protected-resource/mcp",
+		Scopes:              []string{"read", "write"},
+	}
+	handler := RequireBearerToken(verifier, opts)(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {}))
+
+	for _, tt := range []struct {
+		name   string
+		header string
+		want   string
+	}{
+		{
+			"no token", "",
+			`Bearer resource_metadata="https://example.com/.well-known/oauth-protected-resource/mcp"`,
+		},
+		{
+			"invalid token", "Bearer bad",
+			`Bearer resource_metadata="https://example.com/.well-known/oauth-protected-resource/mcp", error="invalid_token", error_description="invalid token"`,
+		},
+		{
+			"insufficient scope", "Bearer valid",
+			`Bearer resource_metadata="https://example.com/.well-known/oauth-protected-resource/mcp", error="insufficient_scope", scope="read write"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			got := rec.Header().Get("WWW-Authenticate")
+			if got != tt.want {
+				t.Errorf("WWW-Authenticate:\ngot  %s\nwant %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestServeProtectedResourceMetadata(t *testing.T) {
+	for _, tt := range []struct {
+		resource, want string
+	}{
+		{"https://example.com", "https://example.com/.well-known/oauth-protected-resource"},
+		{"https://example.com/", "https://example.com/.well-known/oauth-protected-resource"},
+		{"https://example.com/mcp", "https://example.com/.well-known/oauth-protected-resource/mcp"},
+	} {
+		got, err := ProtectedResourceMetadataURL(tt.resource)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("ProtectedResourceMetadataURL(%q) = %q, want %q", tt.resource, got, tt.want)
+		}
+	}
+
+	mux := http.NewServeMux()
+	metadataURL, err := ServeProtectedResourceMetadata(mux, &oauthex.ProtectedResourceMetadata{
+		Resource:             "https://example.com/mcp",
+		AuthorizationServers: []string{"https://auth.example.com"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, metadataURL, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: got status %d", metadataURL, rec.Code)
+	}
+
+	for _, bad := range []*oauthex.ProtectedResourceMetadata{
+		{},
+		{Resource: "http://example.com/mcp"},
+		{Resource: "https://example.com/mcp#frag"},
+		{Resource: "https://example.com/mcp", AuthorizationServers: []string{"javascript:alert(1)"}},
+	} {
+		if _, err := ServeProtectedResourceMetadata(http.NewServeMux(), bad); err == nil {
+			t.Errorf("%+v: got nil error, want validation error", bad)
+		}
+	}
+}
@@ -0,0 +1,386 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// JWTVerifierOptions are options for [NewJWTVerifier].
+type JWTVerifierOptions struct {
+	// Issuer is the issuer identifier of the authorization server.
+	// Tokens must have a matching "iss" claim. Required.
+	Issuer string
+	// Audience is the audience that tokens must be issued for, typically the
+	// canonical URI of the MCP server. Checking it prevents tokens issued for
+	// other resources from being accepted. Required.
+	Audience string
+	// JWKSURL is the URL of the authorization server's JSON Web Key Set.
+	// If empty, it is discovered from the authorization server metadata of
+	// Issuer.
+	JWKSURL string
+	// HTTPClient is used to fetch metadata and keys.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+	// CacheDuration is how long fetched keys are cached.
+	// If zero, it defaults to one hour.
+	// Keys are also refetched, at most once a minute, when a token refers
+	// to an unknown key ID, to pick up key rotations promptly.
+	CacheDuration time.Duration
+	// Leeway is the allowed clock skew when checking time-based claims.
+	Leeway time.Duration
+}
+
+// NewJWTVerifier returns a [TokenVerifier] for JWT access tokens signed by
+// the keys of an authorization server.
+//
+// The verifier checks the signature, issuer, audience and expiration of each
+// token. Its [TokenInfo] has the scopes from the "scope" or "scp" claim,
+// the user ID from the "sub" claim, and all claims in Extra.
+// Use [JWTClaims] to retrieve them.
+func NewJWTVerifier(ctx context.Context, opts *JWTVerifierOptions) (TokenVerifier, error) {
+	if opts == nil || opts.Issuer == "" || opts.Audience == "" {
+		return nil, errors.New("NewJWTVerifier: Issuer and Audience are required")
+	}
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	jwksURL := opts.JWKSURL
+	if jwksURL == "" {
+		var err error
+		jwksURL, err = discoverJWKSURL(ctx, client, opts.Issuer)
+		if err != nil {
+			return nil, fmt.Errorf("NewJWTVerifier: %w", err)
+		}
+	}
+	keys := &jwksCache{
+		url:    jwksURL,
+		client: client,
+		ttl:    opts.CacheDuration,
+	}
+	if keys.ttl == 0 {
+		keys.ttl = time.Hour
+	}
+	parser := jwt.NewParser(
+		jwt.WithIssuer(opts.Issuer),
+		jwt.WithAudience(opts.Audience),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(opts.Leeway),
+		jwt.WithValidMethods([]string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}),
+	)
+
+	return func(ctx context.Context, token string, _ *http.Request) (*TokenInfo, error) {
+		claims := jwt.MapClaims{}
+		_, err := parser.ParseWithClaims(token, claims, func(t *jwt.Token) (any, error) {
+			kid, _ := t.Header["kid"].(string)
+			return keys.key(ctx, kid)
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		exp, err := claims.GetExpirationTime()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+		}
+		sub, _ := claims.GetSubject()
+		return &TokenInfo{
+			Scopes:     scopesFromClaims(claims),
+			Expiration: exp.Time,
+			UserID:     sub,
+			Extra:      claims,
+		}, nil
+	}, nil
+}
+
+// JWTClaims returns the claims of a token verified by a verifier from
+// [NewJWTVerifier], or nil if ti is nil.
+//
+// In tool and other feature handlers, pass the TokenInfo from the request's
+// Extra field. In HTTP handlers, use [TokenInfoFromContext].
+func JWTClaims(ti *TokenInfo) map[string]any {
+	if ti == nil {
+		return nil
+	}
+	return ti.Extra
+}
+
+// scopesFromClaims returns the scopes in claims, from either the "scope"
+// claim (a space-separated string, RFC 9068 section 2.2.3) or the "scp"
+// claim (a string or array, used by some providers).
+func scopesFromClaims(claims jwt.MapClaims) []string {
+	for _, name := range []string{"scope", "scp"} {
+		switch v := claims[name].(type) {
+		case string:
+			return strings.Fields(v)
+		case []any:
+			var scopes []string
+			for _, s := range v {
+				if s, ok := s.(string); ok {
+					scopes = append(scopes, s)
+				}
+			}
+			return scopes
+		}
+	}
+	return nil
+}
+
+// discoverJWKSURL returns the jwks_uri from the metadata of the authorization
+// server with the given issuer, at the well-known locations of RFC 8414 and
+// OpenID Connect Discovery.
+func discoverJWKSURL(ctx context.Context, client *http.Client, issuer string) (string, error) {
+	u, err := url.Parse(issuer)
+	if err != nil {
+		return "", err
+	}
+	// RFC 8414 section 3.1: the well-known path goes between the host and the
+	// path of the issuer.
+	u.Path = "/.well-known/oauth-authorization-server" + strings.TrimSuffix(u.Path, "/")
+	// OpenID Connect Discovery 1.0 section 4: the well-known path is appended
+	// to the issuer, as in https://host/realms/x/.well-known/openid-configuration.
+	oidcURL := strings.TrimSuffix(issuer, "/") + "/.well-known/openid-configuration"
+	var errs []error
+	for _, metaURL := range []string{u.String(), oidcURL} {
+		meta, err := fetchJSON[struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}](ctx, client, metaURL)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if meta.Issuer != issuer {
+			// RFC 8414 section 3.3 and OpenID Connect Discovery section 4.3: a
+			// mismatch is a security violation; don't keep trying.
+			return "", fmt.Errorf("metadata issuer %q does not match issuer %q", meta.Issuer, issuer)
+		}
+		ju, err := url.Parse(meta.JWKSURI)
+		if err != nil || (ju.Scheme != "https" && ju.Scheme != "http") {
+			return "", fmt.Errorf("authorization server %s has no valid jwks_uri", issuer)
+		}
+		return meta.JWKSURI, nil
+	}
+	return "", fmt.Errorf("getting metadata of authorization server %s: %w", issuer, errors.Join(errs...))
+}
+
+// fetchJSON gets url and decodes the JSON in the response.
+func fetchJSON[T any](ctx context.Context, client *http.Client, url string) (*T, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s: bad status %s", url, res.Status)
+	}
+	var v T
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&v); err != nil {
+		return nil, fmt.Errorf("%s: %w", url, err)
+	}
+	return &v, nil
+}
+
+// minJWKSRefetchInterval limits refetches caused by unknown key IDs.
+const minJWKSRefetchInterval = time.Minute
+
+// A jwksCache holds the keys from a JSON Web Key Set.
+type jwksCache struct {
+	url    string
+	client *http.Client
+	ttl    time.Duration
+
+	mu       sync.Mutex
+	keys     map[string]any // from key ID
+	fetched  time.Time
+	fetching chan struct{} // non-nil during a fetch; closed when it ends
+}
+
+// key returns the public key with the given key ID.
+// If kid is empty, the set must contain exactly one key.
+//
+// Concurrent calls that need fresh keys share a single fetch, which is made
+// without holding c.mu, so that a slow key server does not block calls that
+// can use the cached keys.
+func (c *jwksCache) key(ctx context.Context, kid string) (any, error) {
+	c.mu.Lock()
+	for c.stale(kid) {
+		if done := c.fetching; done != nil {
+			// Wait for the fetch in progress, then check again: if it
+			// failed, try ourselves.
+			c.mu.Unlock()
+			select {
+			case <-done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			c.mu.Lock()
+			continue
+		}
+		done := make(chan struct{})
+		c.fetching = done
+		c.mu.Unlock()
+		keys, err := c.fetch(ctx)
+		c.mu.Lock()
+		c.fetching = nil
+		close(done)
+		if err != nil {
+			c.mu.Unlock()
+			return nil, err
+		}
+		c.keys = keys
+		c.fetched = time.Now()
+		break
+	}
+	defer c.mu.Unlock()
+
+	if kid == "" {
+		if len(c.keys) != 1 {
+			return nil, errors.New("token has no key ID and key set does not have exactly one key")
+		}
+		for _, k := range c.keys {
+			return k, nil
+		}
+	}
+	k, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID %q", kid)
+	}
+	return k, nil
+}
+
+// stale reports whether the keys must be fetched to look up kid.
+// c.mu must be held.
+func (c *jwksCache) stale(kid string) bool {
+	since := time.Since(c.fetched)
+	if c.keys == nil || since > c.ttl {
+		return true
+	}
+	_, ok := c.keys[kid]
+	return !ok && kid != "" && since > minJWKSRefetchInterval
+}
+
+// fetch fetches the key set and returns its signature keys by key ID.
+func (c *jwksCache) fetch(ctx context.Context) (map[string]any, error) {
+	set, err := fetchJSON[struct {
+		Keys []jwk `json:"keys"`
+	}](ctx, c.client, c.url)
+	if err != nil {
+		return nil, fmt.Errorf("fetching JWKS: %w", err)
+	}
+	keys := make(map[string]any)
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		pub, err := k.publicKey()
+		if err != nil {
+			// Skip keys we don't understand, rather than failing on all of them.
+			continue
+		}
+		keys[k.Kid] = pub
+	}
+	return keys, nil
+}
+
+// A jwk is a JSON Web Key (RFC 7517).
+// Only the fields needed for public signature keys are included.
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	// RSA
+	N string `json:"n"`
+	E string `json:"e"`
+	// EC and OKP
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (k *jwk) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() {
+			return nil, errors.New("RSA exponent too large")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !curve.IsOnCurve(x, y) {
+			return nil, errors.New("EC point not on curve")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("bad Ed25519 key size")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestJWTVerifier(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fetches := 0
+	jwks := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches++
+		enc := base64.RawURLEncoding.EncodeToString
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]string{{
+				"kty": "EC", "kid": "k1", "use": "sig", "crv": "P-256",
+				"x": enc(key.X.Bytes()), "y": enc(key.Y.Bytes()),
+			}},
+		})
+	}))
+	defer jwks.Close()
+
+	const issuer, audience = "https://auth.example.com", "https://mcp.example.com"
+	verifier, err := NewJWTVerifier(context.Background(), &JWTVerifierOptions{
+		Issuer:   issuer,
+		Audience: audience,
+		JWKSURL:  jwks.URL,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(claims jwt.MapClaims, kid string) string {
+		tok := jwt.NewWithClaims(jwt.SigningMethodES256, claims)
+		tok.Header["kid"] = kid
+		s, err := tok.SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	ti, err := verifier(context.Background(), sign(jwt.MapClaims{
+		"iss": issuer, "aud": audience, "exp": exp, "sub": "alice", "scope": "read write",
+	}, "k1"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ti.UserID != "alice" || !slices.Equal(ti.Scopes, []string{"read", "write"}) {
+		t.Errorf("got UserID %q, Scopes %v; want alice, [read write]", ti.UserID, ti.Scopes)
+	}
+	if got := JWTClaims(ti)["sub"]; got != "alice" {
+		t.Errorf("JWTClaims: got sub %v, want alice", got)
+	}
+
+	for _, tt := range []struct {
+		name   string
+		claims jwt.MapClaims
+		kid    string
+	}{
+		{"wrong audience", jwt.MapClaims{"iss": issuer, "aud": "https://other.example.com", "exp": exp}, "k1"},
+		{"wrong issuer", jwt.MapClaims{"iss": "https://evil.example.com", "aud": audience, "exp": exp}, "k1"},
+		{"expired", jwt.MapClaims{"iss": issuer, "aud": audience, "exp": time.Now().Add(-time.Hour).Unix()}, "k1"},
+		{"no expiry", jwt.MapClaims{"iss": issuer, "aud": audience}, "k1"},
+		{"unknown key", jwt.MapClaims{"iss": issuer, "aud": audience, "exp": exp}, "k2"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := verifier(context.Background(), sign(tt.claims, tt.kid), nil)
+			if !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("got %v, want ErrInvalidToken", err)
+			}
+		})
+	}
+	if fetches != 1 {
+		t.Errorf("got %d JWKS fetches, want 1", fetches)
+	}
+}
+
+func TestJWTVerifierDiscovery(t *testing.T) {
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/.well-known/oauth-authorization-server/tenant", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": srv.URL + "/tenant", "jwks_uri": srv.URL + "/jwks"})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		<-release
+		json.NewEncoder(w).Encode(map[string]any{"keys": []any{}})
+	})
+
+	verifier, err := NewJWTVerifier(context.Background(), &JWTVerifierOptions{
+		Issuer:   srv.URL + "/tenant",
+		Audience: "https://mcp.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Concurrent verifications share a single fetch of the keys.
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{})
+	tok.Header["kid"] = "k1"
+	token, err := tok.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			verifier(context.Background(), token, nil)
+		}()
+	}
+	for fetches.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	if got := fetches.Load(); got != 1 {
+		t.Errorf("got %d JWKS fetches, want 1", got)
+	}
+
+	if _, err := NewJWTVerifier(context.Background(), &JWTVerifierOptions{
+		Issuer:   srv.URL + "/other",
+		Audience: "https://mcp.example.com",
+	}); err == nil {
+		t.Error("NewJWTVerifier with undiscoverable issuer: got nil error, want error")
+	}
+}
+
+func TestDiscoverJWKSURLOpenID(t *testing.T) {
+	// An OpenID provider whose issuer has a path, like a Keycloak realm,
+	// serves its metadata after the path of the issuer.
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	issuer := srv.URL + "/realms/x"
+	mux.HandleFunc("/realms/x/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{"issuer": issuer, "jwks_uri": issuer + "/protocol/openid-connect/certs"})
+	})
+
+	got, err := discoverJWKSURL(context.Background(), srv.Client(), issuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := issuer + "/protocol/openid-connect/certs"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,281 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// The mcp-inspect command inspects an MCP server: it lists the server's
+// features, prints tool schemas, and calls tools, resources and prompts.
+//
+// Usage: mcp-inspect [flags] <command> [<args>] [-- <server command> [<args>]]
+//
+// The server is either run as a subprocess communicating over stdio, with
+// the command following "--", or reached over HTTP with the -http flag.
+//
+// Commands:
+//
+//	list                   list tools, resources, resource templates and prompts
+//	tools                  print tools with their input and output schemas
+//	call <tool> [<json>]   call a tool with JSON arguments
+//	read <uri>             read a resource
+//	prompt <name> [<json>] get a prompt with JSON arguments
+//
+// For example:
+//
+//	mcp-inspect tools -- go run github.com/modelcontextprotocol/go-sdk/examples/server/hello
+//	mcp-inspect -http=http://localhost:8080 call greet '{"name": "you"}'
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"iter"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+var (
+	endpoint = flag.String("http", "", "if set, connect to this streamable endpoint rather than running a stdio server")
+	sse      = flag.Bool("sse", false, "if set, connect to the -http endpoint with the SSE transport")
+	raw      = flag.Bool("raw", false, "if set, print results as raw JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		out := flag.CommandLine.Output()
+		fmt.Fprintln(out, "Usage: mcp-inspect [flags] <command> [<args>] [-- <server command> [<args>]]")
+		fmt.Fprintln(out, "Inspect an MCP server over stdio or HTTP")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Commands:")
+		fmt.Fprintln(out, "\tlist                   list tools, resources, resource templates and prompts")
+		fmt.Fprintln(out, "\ttools                  print tools with their input and output schemas")
+		fmt.Fprintln(out, "\tcall <tool> [<json>]   call a tool with JSON arguments")
+		fmt.Fprintln(out, "\tread <uri>             read a resource")
+		fmt.Fprintln(out, "\tprompt <name> [<json>] get a prompt with JSON arguments")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Examples:")
+		fmt.Fprintln(out, "\tmcp-inspect tools -- go run github.com/modelcontextprotocol/go-sdk/examples/server/hello")
+		fmt.Fprintln(out, "\tmcp-inspect -http=http://localhost:8080 call greet '{\"name\": \"you\"}'")
+		fmt.Fprintln(out)
+		fmt.Fprintln(out, "Flags:")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	args, serverCmd := flag.Args(), []string(nil)
+	if i := slices.Index(args, "--"); i >= 0 {
+		args, serverCmd = args[:i], args[i+1:]
+	}
+	if len(args) == 0 || (len(serverCmd) == 0) == (*endpoint == "") {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var transport mcp.Transport
+	switch {
+	case *endpoint != "" && *sse:
+		transport = &mcp.SSEClientTransport{Endpoint: *endpoint}
+	case *endpoint != "":
+		transport = &mcp.StreamableClientTransport{Endpoint: *endpoint}
+	default:
+		cmd := exec.Command(serverCmd[0], serverCmd[1:]...)
+		cmd.Stderr = os.Stderr
+		transport = &mcp.CommandTransport{Command: cmd}
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "mcp-inspect", Version: "v1.0.0"}, nil)
+	cs, err := client.Connect(ctx, transport, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer cs.Close()
+
+	if err := run(ctx, cs, args[0], args[1:]); err != nil {
+		cs.Close()
+		log.Fatal(err)
+	}
+}
+
+func run(ctx context.Context, cs *mcp.ClientSession, cmd string, args []string) error {
+	caps := cs.InitializeResult().Capabilities
+	if caps == nil {
+		// The server advertised no capabilities.
+		caps = &mcp.ServerCapabilities{}
+	}
+	switch cmd {
+	case "list":
+		if err := checkArgs(cmd, args, 0, 0); err != nil {
+			return err
+		}
+		if caps.Tools != nil {
+			if err := printSection("tools", cs.Tools(ctx, nil), func(t *mcp.Tool) (string, string) { return t.Name, t.Description }); err != nil {
+				return err
+			}
+		}
+		if caps.Resources != nil {
+			if err := printSection("resources", cs.Resources(ctx, nil), func(r *mcp.Resource) (string, string) { return r.URI, r.Description }); err != nil {
+				return err
+			}
+			if err := printSection("resource templates", cs.ResourceTemplates(ctx, nil), func(r *mcp.ResourceTemplate) (string, string) { return r.URITemplate, r.Description }); err != nil {
+				return err
+			}
+		}
+		if caps.Prompts != nil {
+			if err := printSection("prompts", cs.Prompts(ctx, nil), func(p *mcp.Prompt) (string, string) { return p.Name, p.Description }); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "tools":
+		if err := checkArgs(cmd, args, 0, 0); err != nil {
+			return err
+		}
+		if caps.Tools == nil {
+			return errors.New("server does not support tools")
+		}
+		for tool, err := range cs.Tools(ctx, nil) {
+			if err != nil {
+				return err
+			}
+			fmt.Printf("%s\n", tool.Name)
+			if tool.Description != "" {
+				fmt.Printf("  %s\n", tool.Description)
+			}
+			fmt.Printf("  input schema: %s\n", indent(tool.InputSchema))
+			if tool.OutputSchema != nil {
+				fmt.Printf("  output schema: %s\n", indent(tool.OutputSchema))
+			}
+			fmt.Println()
+		}
+		return nil
+
+	case "call":
+		if err := checkArgs(cmd, args, 1, 2); err != nil {
+			return err
+		}
+		params := &mcp.CallToolParams{Name: args[0]}
+		if len(args) > 1 {
+			if err := json.Unmarshal([]byte(args[1]), &params.Arguments); err != nil {
+				return fmt.Errorf("invalid tool arguments: %v", err)
+			}
+		}
+		res, err := cs.CallTool(ctx, params)
+		if err != nil {
+			return err
+		}
+		if *raw {
+			fmt.Println(indent(res))
+		} else {
+			printContent(res.Content)
+			if res.StructuredContent != nil {
+				fmt.Printf("structured content: %s\n", indent(res.StructuredContent))
+			}
+		}
+		if res.IsError {
+			return fmt.Errorf("tool %q failed", args[0])
+		}
+		return nil
+
+	case "read":
+		if err := checkArgs(cmd, args, 1, 1); err != nil {
+			return err
+		}
+		res, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: args[0]})
+		if err != nil {
+			return err
+		}
+		if *raw {
+			fmt.Println(indent(res))
+			return nil
+		}
+		for _, c := range res.Contents {
+			if c.Text != "" {
+				fmt.Println(c.Text)
+			} else {
+				fmt.Printf("[%s: %d bytes]\n", c.MIMEType, len(c.Blob))
+			}
+		}
+		return nil
+
+	case "prompt":
+		if err := checkArgs(cmd, args, 1, 2); err != nil {
+			return err
+		}
+		params := &mcp.GetPromptParams{Name: args[0]}
+		if len(args) > 1 {
+			if err := json.Unmarshal([]byte(args[1]), &params.Arguments); err != nil {
+				return fmt.Errorf("invalid prompt arguments: %v", err)
+			}
+		}
+		res, err := cs.GetPrompt(ctx, params)
+		if err != nil {
+			return err
+		}
+		if *raw {
+			fmt.Println(indent(res))
+			return nil
+		}
+		for _, m := range res.Messages {
+			fmt.Printf("%s:\n", m.Role)
+			printContent([]mcp.Content{m.Content})
+		}
+		return nil
+	}
+	return fmt.Errorf("unknown command %q", cmd)
+}
+
+func checkArgs(cmd string, args []string, lo, hi int) error {
+	if len(args) < lo || len(args) > hi {
+		return fmt.Errorf("%s: got %d arguments, want between %d and %d", cmd, len(args), lo, hi)
+	}
+	return nil
+}
+
+func printSection[T any](name string, features iter.Seq2[T, error], describe func(T) (string, string)) error {
+	fmt.Printf("%s:\n", name)
+	for feat, err := range features {
+		if err != nil {
+			return err
+		}
+		name, desc := describe(feat)
+		if desc != "" {
+			fmt.Printf("\t%s: %s\n", name, desc)
+		} else {
+			fmt.Printf("\t%s\n", name)
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func printContent(content []mcp.Content) {
+	for _, c := range content {
+		switch c := c.(type) {
+		case *mcp.TextContent:
+			fmt.Println(c.Text)
+		case *mcp.ImageContent:
+			fmt.Printf("[image %s: %d bytes]\n", c.MIMEType, len(c.Data))
+		case *mcp.AudioContent:
+			fmt.Printf("[audio %s: %d bytes]\n", c.MIMEType, len(c.Data))
+		default:
+			fmt.Println(indent(c))
+		}
+	}
+}
+
+// indent returns v as indented JSON.
+func indent(v any) string {
+	data, err := json.MarshalIndent(v, "  ", "  ")
+	if err != nil {
+		return fmt.Sprintf("<%v>", err)
+	}
+	return string(data)
+}
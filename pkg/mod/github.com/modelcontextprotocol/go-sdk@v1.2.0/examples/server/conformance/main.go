@@ -9,7 +9,7 @@ package main
 
 import (
 	"context"
-	"encoding/base64"
+	_ "embed"
 	"encoding/json"
 	"errors"
 	"flag"
@@ -34,7 +34,15 @@ func main() {
 	flag.Parse()
 
 	opts := &mcp.ServerOptions{
-		CompletionHandler:  completionHandler,
+		CompletionHandler: mcp.NewCompletionHandler(mcp.CompletionCandidates{
+			{Type: "ref/prompt", Name: "test_prompt_with_arguments"}: {
+				"arg1": {"alpha", "beta", "gamma"},
+				"arg2": {"one", "two", "three"},
+			},
+			{Type: "ref/resource", URI: "test://template/{id}/data"}: {
+				"id": {"123", "456", "789"},
+			},
+		}),
 		SubscribeHandler:   subscribeHandler,
 		UnsubscribeHandler: unsubscribeHandler,
 	}
@@ -193,10 +201,7 @@ func testSimpleTextHandler(ctx context.Context, req *mcp.CallToolRequest, _ any)
 func testImageContentHandler(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.ImageContent{
-				Data:     imageData(),
-				MIMEType: "image/png",
-			},
+			imageContent(),
 		},
 	}, nil, nil
 }
@@ -204,10 +209,7 @@ func testImageContentHandler(ctx context.Context, req *mcp.CallToolRequest, _ an
 func testAudioContentHandler(ctx context.Context, req *mcp.CallToolRequest, _ any) (*mcp.CallToolResult, any, error) {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.AudioContent{
-				Data:     audioData(),
-				MIMEType: "audio/wav",
-			},
+			audioContent(),
 		},
 	}, nil, nil
 }
@@ -230,10 +232,7 @@ func testMultipleContentTypesHandler(ctx context.Context, req *mcp.CallToolReque
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: "This is text content"},
-			&mcp.ImageContent{
-				Data:     imageData(),
-				MIMEType: "image/png",
-			},
+			imageContent(),
 			&mcp.EmbeddedResource{
 				Resource: &mcp.ResourceContents{
 					URI:      "test://embedded-in-multiple",
@@ -571,7 +570,7 @@ func staticBinaryHandler(ctx context.Context, req *mcp.ReadResourceRequest) (*mc
 			{
 				URI:      req.Params.URI,
 				MIMEType: "image/png",
-				Blob:     imageData(),
+				Blob:     testImage,
 			},
 		},
 	}, nil
@@ -708,11 +707,8 @@ func promptWithImageHandler(ctx context.Context, req *mcp.GetPromptRequest) (*mc
 		Description: "A prompt with an image",
 		Messages: []*mcp.PromptMessage{
 			{
-				Role: "user",
-				Content: &mcp.ImageContent{
-					Data:     imageData(),
-					MIMEType: "image/png",
-				},
+				Role:    "user",
+				Content: imageContent(),
 			},
 			{
 				Role:    "user",
@@ -726,16 +722,6 @@ func promptWithImageHandler(ctx context.Context, req *mcp.GetPromptRequest) (*mc
 // Server handlers
 // =============================================================================
 
-func completionHandler(ctx context.Context, req *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
-	// Return empty completion - just acknowledging the capability
-	return &mcp.CompleteResult{
-		Completion: mcp.CompletionResultDetails{
-			Values: []string{},
-			Total:  0,
-		},
-	}, nil
-}
-
 func subscribeHandler(ctx context.Context, req *mcp.SubscribeRequest) error {
 	// The SDK handles subscription tracking internally via Server.ResourceUpdated()
 	return nil
@@ -750,26 +736,26 @@ func unsubscribeHandler(ctx context.Context, req *mcp.UnsubscribeRequest) error
 // Helper functions
 // =============================================================================
 
-// Base64-encoded minimal test files, copied from the typescript conformance example.
-const (
-	// Minimal 1x1 red PNG image
-	testImageBase64 = "iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8DwHwAFBQIAX8jx0gAAAABJRU5ErkJggg=="
-	// Minimal WAV audio file (silence)
-	testAudioBase64 = "UklGRiYAAABXQVZFZm10IBAAAAABAAEAQB8AAAB9AAACABAAZGF0YQIAAAA="
+// Minimal test files, copied from the typescript conformance example.
+var (
+	//go:embed testdata/test.png
+	testImage []byte // 1x1 red PNG image
+	//go:embed testdata/test.wav
+	testAudio []byte // WAV audio file (silence)
 )
 
-func imageData() []byte {
-	data, err := base64.StdEncoding.DecodeString(testImageBase64)
+func imageContent() *mcp.ImageContent {
+	c, err := mcp.NewImageContent(testImage)
 	if err != nil {
-		panic("invalid testImageBase64: " + err.Error())
+		panic(err)
 	}
-	return data
+	return c
 }
 
-func audioData() []byte {
-	data, err := base64.StdEncoding.DecodeString(testAudioBase64)
+func audioContent() *mcp.AudioContent {
+	c, err := mcp.NewAudioContent(testAudio)
 	if err != nil {
-		panic("invalid testAudioBase64: " + err.Error())
+		panic(err)
 	}
-	return data
+	return c
 }
@@ -0,0 +1,274 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package grpcbridge exposes gRPC services as MCP tools.
+//
+// [AddTools] uses gRPC server reflection to discover the services of a gRPC
+// server, and adds a tool to an [mcp.Server] for each unary method. The
+// tool's input schema is derived from the method's request message, following
+// the protobuf JSON mapping. Calling the tool invokes the method, and returns
+// the response message as JSON.
+package grpcbridge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// Options are options for [AddTools].
+type Options struct {
+	// Services are the fully qualified names of the services to add tools
+	// for, such as "helloworld.Greeter". If empty, tools are added for all
+	// services except the reflection services.
+	Services []string
+	// Metadata, if non-nil, is called for each tool call to get metadata to
+	// send with the gRPC request, such as authorization credentials.
+	Metadata func(context.Context, *mcp.CallToolRequest) (metadata.MD, error)
+	// CallOptions are passed to each gRPC invocation.
+	CallOptions []grpc.CallOption
+}
+
+// AddTools adds a tool to s for each unary method of the services of the gRPC
+// server at the other end of conn, which must support the v1 server
+// reflection service. Streaming methods are skipped.
+//
+// Tools are named by the full name of their method, such as
+// "helloworld.Greeter.SayHello". Their descriptions come from the comments in
+// the service definition if the server provides them. Methods declared with
+// an idempotency level of NO_SIDE_EFFECTS are annotated as read-only, and
+// those declared IDEMPOTENT as idempotent.
+//
+// The ctx is used only for reflection requests.
+func AddTools(ctx context.Context, s *mcp.Server, conn grpc.ClientConnInterface, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	services, err := resolveServices(ctx, conn, opts.Services)
+	if err != nil {
+		return fmt.Errorf("grpcbridge: %w", err)
+	}
+	var tools []*mcp.Tool
+	var handlers []mcp.ToolHandler
+	for _, sd := range services {
+		methods := sd.Methods()
+		for i := range methods.Len() {
+			md := methods.Get(i)
+			if md.IsStreamingClient() || md.IsStreamingServer() {
+				continue
+			}
+			t := methodTool(md)
+			tools = append(tools, t)
+			handlers = append(handlers, methodHandler(conn, md, t.OutputSchema != nil, opts))
+		}
+	}
+	for i, t := range tools {
+		s.AddTool(t, handlers[i])
+	}
+	return nil
+}
+
+// methodTool returns the tool for md.
+func methodTool(md protoreflect.MethodDescriptor) *mcp.Tool {
+	t := &mcp.Tool{
+		Name:        string(md.FullName()),
+		Description: comments(md),
+		InputSchema: messageSchema(md.Input()),
+	}
+	if t.Description == "" {
+		t.Description = fmt.Sprintf("Calls the gRPC method %s.", methodPath(md))
+	}
+	if out := messageSchema(md.Output()); out["type"] == "object" {
+		t.OutputSchema = out
+	}
+	if mo, ok := md.Options().(*descriptorpb.MethodOptions); ok {
+		switch mo.GetIdempotencyLevel() {
+		case descriptorpb.MethodOptions_NO_SIDE_EFFECTS:
+			t.Annotations = &mcp.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+		case descriptorpb.MethodOptions_IDEMPOTENT:
+			t.Annotations = &mcp.ToolAnnotations{IdempotentHint: true}
+		}
+	}
+	return t
+}
+
+// methodPath returns the path used to invoke md, as in "/pkg.Service/Method".
+func methodPath(md protoreflect.MethodDescriptor) string {
+	return fmt.Sprintf("/%s/%s", md.Parent().FullName(), md.Name())
+}
+
+// methodHandler returns a tool handler that invokes md on conn.
+// If structured is set, results include structured content.
+func methodHandler(conn grpc.ClientConnInterface, md protoreflect.MethodDescriptor, structured bool, opts *Options) mcp.ToolHandler {
+	path := methodPath(md)
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		in := dynamicpb.NewMessage(md.Input())
+		if args := req.Params.Arguments; len(args) > 0 && string(args) != "null" {
+			if err := protojson.Unmarshal(args, in); err != nil {
+				// Report bad arguments to the model, so that it can correct them.
+				return toolError(fmt.Errorf("invalid arguments: %w", err)), nil
+			}
+		}
+		if opts.Metadata != nil {
+			m, err := opts.Metadata(ctx, req)
+			if err != nil {
+				return nil, fmt.Errorf("getting metadata: %w", err)
+			}
+			ctx = metadata.NewOutgoingContext(ctx, m)
+		}
+		out := dynamicpb.NewMessage(md.Output())
+		if err := conn.Invoke(ctx, path, in, out, opts.CallOptions...); err != nil {
+			return toolError(err), nil
+		}
+		data, err := protojson.Marshal(out)
+		if err != nil {
+			return nil, err
+		}
+		res := &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(data)}}}
+		if structured {
+			res.StructuredContent = json.RawMessage(data)
+		}
+		return res, nil
+	}
+}
+
+func toolError(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+		IsError: true,
+	}
+}
+
+// resolveServices uses server reflection to return the descriptors of the
+// named services, or of all services except the reflection services if names
+// is empty.
+func resolveServices(ctx context.Context, conn grpc.ClientConnInterface, names []string) ([]protoreflect.ServiceDescriptor, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("starting reflection: %w", err)
+	}
+	defer stream.CloseSend()
+	call := func(req *rpb.ServerReflectionRequest) (*rpb.ServerReflectionResponse, error) {
+		if err := stream.Send(req); err != nil {
+			return nil, fmt.Errorf("reflection: %w", err)
+		}
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, errors.New("reflection: stream closed")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reflection: %w", err)
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, fmt.Errorf("reflection: %s (code %d)", e.GetErrorMessage(), e.GetErrorCode())
+		}
+		return resp, nil
+	}
+
+	if len(names) == 0 {
+		resp, err := call(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_ListServices{},
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.GetListServicesResponse().GetService() {
+			if !strings.HasPrefix(s.GetName(), "grpc.reflection.") {
+				names = append(names, s.GetName())
+			}
+		}
+	}
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	addFiles := func(resp *rpb.ServerReflectionResponse) error {
+		for _, b := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			fd := new(descriptorpb.FileDescriptorProto)
+			if err := proto.Unmarshal(b, fd); err != nil {
+				return fmt.Errorf("reflection: decoding file descriptor: %w", err)
+			}
+			files[fd.GetName()] = fd
+		}
+		return nil
+	}
+	for _, name := range names {
+		resp, err := call(&rpb.ServerReflectionRequest{
+			MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: name},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		if err := addFiles(resp); err != nil {
+			return nil, err
+		}
+	}
+	// Servers should send all dependencies, but fetch any that are missing.
+	for {
+		var missing []string
+		for _, fd := range files {
+			for _, dep := range fd.GetDependency() {
+				if files[dep] == nil {
+					missing = append(missing, dep)
+				}
+			}
+		}
+		if len(missing) == 0 {
+			break
+		}
+		for _, dep := range missing {
+			if files[dep] != nil {
+				continue
+			}
+			resp, err := call(&rpb.ServerReflectionRequest{
+				MessageRequest: &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: dep},
+			})
+			if err != nil {
+				return nil, fmt.Errorf("file %s: %w", dep, err)
+			}
+			if err := addFiles(resp); err != nil {
+				return nil, err
+			}
+			if files[dep] == nil {
+				return nil, fmt.Errorf("reflection: server did not return file %s", dep)
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, fd := range files {
+		set.File = append(set.File, fd)
+	}
+	reg, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("building descriptors: %w", err)
+	}
+	var services []protoreflect.ServiceDescriptor
+	for _, name := range names {
+		d, err := reg.FindDescriptorByName(protoreflect.FullName(name))
+		if err != nil {
+			return nil, fmt.Errorf("service %s: %w", name, err)
+		}
+		sd, ok := d.(protoreflect.ServiceDescriptor)
+		if !ok {
+			return nil, fmt.Errorf("%s is not a service", name)
+		}
+		services = append(services, sd)
+	}
+	return services, nil
+}
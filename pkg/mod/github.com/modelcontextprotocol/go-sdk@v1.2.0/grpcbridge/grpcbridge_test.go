@@ -0,0 +1,145 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package grpcbridge
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestAddTools(t *testing.T) {
+	ctx := context.Background()
+
+	var (
+		mu       sync.Mutex
+		gotAuths []string
+	)
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer(grpc.UnaryInterceptor(func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		mu.Lock()
+		gotAuths = append(gotAuths, md.Get("authorization")...)
+		mu.Unlock()
+		return handler(ctx, req)
+	}))
+	hs := health.NewServer()
+	hs.SetServingStatus("db", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(gs, hs)
+	reflection.Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "bridge"}, nil)
+	err = AddTools(ctx, server, conn, &Options{
+		Metadata: func(context.Context, *mcp.CallToolRequest) (metadata.MD, error) {
+			return metadata.Pairs("authorization", "Bearer secret"), nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	res, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	const check = "grpc.health.v1.Health.Check"
+	tools := make(map[string]*mcp.Tool)
+	for _, tool := range res.Tools {
+		tools[tool.Name] = tool
+	}
+	if tools[check] == nil {
+		t.Fatalf("no tool %s", check)
+	}
+	if tools[check].OutputSchema == nil {
+		t.Errorf("%s has no output schema", check)
+	}
+	// Watch is server-streaming, so it is skipped.
+	if tools["grpc.health.v1.Health.Watch"] != nil {
+		t.Error("got tool for streaming method Watch")
+	}
+
+	for _, test := range []struct {
+		args    any
+		want    string
+		wantErr bool
+	}{
+		{map[string]any{"service": "db"}, `{"status":"NOT_SERVING"}`, false},
+		{map[string]any{"service": "unknown"}, "", true}, // NotFound
+		{map[string]any{"service": 1}, "", true},         // invalid arguments
+	} {
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: check, Arguments: test.args})
+		if err != nil {
+			t.Fatalf("CallTool(%v): %v", test.args, err)
+		}
+		if res.IsError != test.wantErr {
+			t.Errorf("CallTool(%v): got IsError %t, want %t (%v)", test.args, res.IsError, test.wantErr, res.Content)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if got := res.Content[0].(*mcp.TextContent).Text; got != test.want {
+			t.Errorf("CallTool(%v): got %s, want %s", test.args, got, test.want)
+		}
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(gotAuths) == 0 || gotAuths[0] != "Bearer secret" {
+		t.Errorf("authorization metadata: got %v, want [Bearer secret ...]", gotAuths)
+	}
+}
+
+func TestAddToolsUnknownService(t *testing.T) {
+	ctx := context.Background()
+	lis := bufconn.Listen(1 << 20)
+	gs := grpc.NewServer()
+	reflection.Register(gs)
+	go gs.Serve(lis)
+	defer gs.Stop()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "bridge"}, nil)
+	if err := AddTools(ctx, server, conn, &Options{Services: []string{"no.such.Service"}}); err == nil {
+		t.Error("got nil error, want error")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package grpcbridge
+
+import (
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// messageSchema returns a JSON Schema for the protobuf JSON form of messages
+// described by md. Other message types that md refers to are in "$defs".
+func messageSchema(md protoreflect.MessageDescriptor) map[string]any {
+	b := &schemaBuilder{defs: make(map[string]any)}
+	s := b.messageInline(md)
+	if len(b.defs) > 0 {
+		s["$defs"] = b.defs
+	}
+	return s
+}
+
+// A schemaBuilder builds JSON Schemas for protobuf messages.
+type schemaBuilder struct {
+	defs map[string]any // message full name -> schema
+}
+
+// message returns a schema that refers to the schema for md, adding it to
+// b.defs if necessary. Well-known types are returned inline.
+func (b *schemaBuilder) message(md protoreflect.MessageDescriptor) map[string]any {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+	name := string(md.FullName())
+	if _, ok := b.defs[name]; !ok {
+		b.defs[name] = nil // prevent infinite recursion
+		b.defs[name] = b.object(md)
+	}
+	return map[string]any{"$ref": "#/$defs/" + name}
+}
+
+// messageInline returns the schema for md itself, rather than a reference.
+func (b *schemaBuilder) messageInline(md protoreflect.MessageDescriptor) map[string]any {
+	if s := wellKnownSchema(md); s != nil {
+		return s
+	}
+	return b.object(md)
+}
+
+// object returns the object schema for md.
+func (b *schemaBuilder) object(md protoreflect.MessageDescriptor) map[string]any {
+	props := make(map[string]any)
+	var required []string
+	fields := md.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+		s := b.field(fd)
+		if c := comments(fd); c != "" {
+			s["description"] = c
+		}
+		props[fd.JSONName()] = s
+		if fd.Cardinality() == protoreflect.Required {
+			required = append(required, fd.JSONName())
+		}
+	}
+	s := map[string]any{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		s["required"] = required
+	}
+	if c := comments(md); c != "" {
+		s["description"] = c
+	}
+	return s
+}
+
+// field returns the schema for the value of fd.
+func (b *schemaBuilder) field(fd protoreflect.FieldDescriptor) map[string]any {
+	switch {
+	case fd.IsMap():
+		return map[string]any{
+			"type":                 "object",
+			"additionalProperties": b.singular(fd.MapValue()),
+		}
+	case fd.IsList():
+		return map[string]any{
+			"type":  "array",
+			"items": b.singular(fd),
+		}
+	default:
+		return b.singular(fd)
+	}
+}
+
+// singular returns the schema for a single value of the type of fd.
+func (b *schemaBuilder) singular(fd protoreflect.FieldDescriptor) map[string]any {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		return b.message(fd.Message())
+	case protoreflect.EnumKind:
+		if fd.Enum().FullName() == "google.protobuf.NullValue" {
+			return map[string]any{"type": "null"}
+		}
+		var names []any
+		values := fd.Enum().Values()
+		for i := range values.Len() {
+			names = append(names, string(values.Get(i).Name()))
+		}
+		return map[string]any{"type": "string", "enum": names}
+	default:
+		return scalarSchema(fd.Kind())
+	}
+}
+
+// scalarSchema returns the schema for a scalar of the given kind.
+func scalarSchema(k protoreflect.Kind) map[string]any {
+	switch k {
+	case protoreflect.BoolKind:
+		return map[string]any{"type": "boolean"}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind,
+		protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return map[string]any{"type": "integer"}
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind,
+		protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		// The JSON mapping uses strings for 64-bit integers, but accepts numbers.
+		return map[string]any{"type": []any{"integer", "string"}}
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		return map[string]any{"type": "number"}
+	case protoreflect.StringKind:
+		return map[string]any{"type": "string"}
+	case protoreflect.BytesKind:
+		return map[string]any{"type": "string", "contentEncoding": "base64"}
+	default:
+		return map[string]any{}
+	}
+}
+
+// wellKnownSchema returns the schema for a well-known type with a special
+// JSON mapping, or nil if md is not one.
+func wellKnownSchema(md protoreflect.MessageDescriptor) map[string]any {
+	if isScalarWrapper(md) {
+		return scalarSchema(md.Fields().ByName("value").Kind())
+	}
+	switch md.FullName() {
+	case "google.protobuf.Timestamp":
+		return map[string]any{"type": "string", "format": "date-time"}
+	case "google.protobuf.Duration":
+		return map[string]any{"type": "string", "pattern": `^-?[0-9]+(\.[0-9]+)?s$`}
+	case "google.protobuf.FieldMask":
+		return map[string]any{"type": "string"}
+	case "google.protobuf.Struct":
+		return map[string]any{"type": "object"}
+	case "google.protobuf.ListValue":
+		return map[string]any{"type": "array"}
+	case "google.protobuf.Value":
+		return map[string]any{}
+	case "google.protobuf.Any":
+		return map[string]any{
+			"type":       "object",
+			"properties": map[string]any{"@type": map[string]any{"type": "string"}},
+			"required":   []any{"@type"},
+		}
+	}
+	return nil
+}
+
+// isScalarWrapper reports whether md is one of the wrapper types, such as
+// google.protobuf.StringValue, whose JSON form is the wrapped value.
+func isScalarWrapper(md protoreflect.MessageDescriptor) bool {
+	switch md.FullName() {
+	case "google.protobuf.DoubleValue", "google.protobuf.FloatValue",
+		"google.protobuf.Int64Value", "google.protobuf.UInt64Value",
+		"google.protobuf.Int32Value", "google.protobuf.UInt32Value",
+		"google.protobuf.BoolValue", "google.protobuf.StringValue",
+		"google.protobuf.BytesValue":
+		return true
+	}
+	return false
+}
+
+// comments returns the leading comments of d, if any.
+func comments(d protoreflect.Descriptor) string {
+	loc := d.ParentFile().SourceLocations().ByDescriptor(d)
+	return strings.TrimSpace(loc.LeadingComments)
+}
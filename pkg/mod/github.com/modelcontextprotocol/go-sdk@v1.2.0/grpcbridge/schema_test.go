@@ -0,0 +1,91 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package grpcbridge
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	_ "google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestMessageSchema(t *testing.T) {
+	label := func(l descriptorpb.FieldDescriptorProto_Label) *descriptorpb.FieldDescriptorProto_Label { return &l }
+	typ := func(t descriptorpb.FieldDescriptorProto_Type) *descriptorpb.FieldDescriptorProto_Type { return &t }
+	optional := label(descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL)
+	repeated := label(descriptorpb.FieldDescriptorProto_LABEL_REPEATED)
+
+	fdp := &descriptorpb.FileDescriptorProto{
+		Name:       proto.String("test.proto"),
+		Package:    proto.String("test"),
+		Syntax:     proto.String("proto3"),
+		Dependency: []string{"google/protobuf/timestamp.proto"},
+		EnumType: []*descriptorpb.EnumDescriptorProto{{
+			Name: proto.String("Color"),
+			Value: []*descriptorpb.EnumValueDescriptorProto{
+				{Name: proto.String("RED"), Number: proto.Int32(0)},
+				{Name: proto.String("BLUE"), Number: proto.Int32(1)},
+			},
+		}},
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Request"),
+			Field: []*descriptorpb.FieldDescriptorProto{
+				{Name: proto.String("user_name"), JsonName: proto.String("userName"), Number: proto.Int32(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+				{Name: proto.String("count"), JsonName: proto.String("count"), Number: proto.Int32(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_INT64)},
+				{Name: proto.String("colors"), JsonName: proto.String("colors"), Number: proto.Int32(3), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_ENUM), TypeName: proto.String(".test.Color")},
+				{Name: proto.String("labels"), JsonName: proto.String("labels"), Number: proto.Int32(4), Label: repeated, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".test.Request.LabelsEntry")},
+				{Name: proto.String("parent"), JsonName: proto.String("parent"), Number: proto.Int32(5), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".test.Request")},
+				{Name: proto.String("when"), JsonName: proto.String("when"), Number: proto.Int32(6), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_MESSAGE), TypeName: proto.String(".google.protobuf.Timestamp")},
+			},
+			NestedType: []*descriptorpb.DescriptorProto{{
+				Name: proto.String("LabelsEntry"),
+				Field: []*descriptorpb.FieldDescriptorProto{
+					{Name: proto.String("key"), JsonName: proto.String("key"), Number: proto.Int32(1), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_STRING)},
+					{Name: proto.String("value"), JsonName: proto.String("value"), Number: proto.Int32(2), Label: optional, Type: typ(descriptorpb.FieldDescriptorProto_TYPE_BYTES)},
+				},
+				Options: &descriptorpb.MessageOptions{MapEntry: proto.Bool(true)},
+			}},
+		}},
+	}
+	fd, err := protodesc.NewFile(fdp, protoregistry.GlobalFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := messageSchema(fd.Messages().ByName("Request"))
+
+	requestProps := `{
+		"userName": {"type": "string"},
+		"count": {"type": ["integer", "string"]},
+		"colors": {"type": "array", "items": {"type": "string", "enum": ["RED", "BLUE"]}},
+		"labels": {"type": "object", "additionalProperties": {"type": "string", "contentEncoding": "base64"}},
+		"parent": {"$ref": "#/$defs/test.Request"},
+		"when": {"type": "string", "format": "date-time"}
+	}`
+	wantJSON := `{
+		"type": "object",
+		"properties": ` + requestProps + `,
+		"$defs": {"test.Request": {"type": "object", "properties": ` + requestProps + `}}
+	}`
+	// Compare JSON forms, to ignore differences between []any and []string.
+	var gotAny, want any
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, &gotAny); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(wantJSON), &want); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(want, gotAny); diff != "" {
+		t.Errorf("schema mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -74,6 +74,7 @@ type Connection struct {
 
 	writer  Writer
 	handler Handler
+	codec   Codec // nil means encoding/json
 
 	onInternalError func(error)
 	onDone          func()
@@ -193,7 +194,7 @@ func (s *inFlightState) shuttingDown(errClosing error) error {
 type incomingRequest struct {
 	*Request // the request being processed
 	ctx      context.Context
-	cancel   context.CancelFunc
+	cancel   context.CancelCauseFunc
 }
 
 // Bind returns the options unmodified.
@@ -210,6 +211,7 @@ type ConnectionConfig struct {
 	Bind            func(*Connection) Handler // required
 	OnDone          func()                    // optional
 	OnInternalError func(error)               // optional
+	Codec           Codec                     // optional; for params and results
 }
 
 // NewConnection creates a new [Connection] object and starts processing
@@ -223,6 +225,7 @@ func NewConnection(ctx context.Context, cfg ConnectionConfig) *Connection {
 		writer:          cfg.Writer,
 		onDone:          cfg.OnDone,
 		onInternalError: cfg.OnInternalError,
+		codec:           cfg.Codec,
 	}
 	c.handler = cfg.Bind(c)
 	c.start(ctx, cfg.Reader, cfg.Preempter)
@@ -319,10 +322,11 @@ func (c *Connection) Notify(ctx context.Context, method string, params any) (err
 		return err
 	}
 
-	notify, err := NewNotification(method, params)
+	p, err := marshalToRaw(c.codec, params)
 	if err != nil {
 		return fmt.Errorf("marshaling notify parameters: %v", err)
 	}
+	notify := &Request{Method: method, Params: p}
 
 	return c.write(ctx, notify)
 }
@@ -339,16 +343,18 @@ func (c *Connection) Call(ctx context.Context, method string, params any) *Async
 	ac := &AsyncCall{
 		id:    id,
 		ready: make(chan struct{}),
+		codec: c.codec,
 	}
 	// When this method returns, either ac is retired, or the request has been
 	// written successfully and the call is awaiting a response (to be provided by
 	// the readIncoming goroutine).
 
-	call, err := NewCall(ac.id, method, params)
+	p, err := marshalToRaw(c.codec, params)
 	if err != nil {
 		ac.retire(&Response{ID: id, Error: fmt.Errorf("marshaling call parameters: %w", err)})
 		return ac
 	}
+	call := &Request{ID: ac.id, Method: method, Params: p}
 
 	c.updateInFlight(func(s *inFlightState) {
 		err = s.shuttingDown(ErrClientClosing)
@@ -364,6 +370,9 @@ func (c *Connection) Call(ctx context.Context, method string, params any) *Async
 		ac.retire(&Response{ID: id, Error: err})
 		return ac
 	}
+	if f, ok := ctx.Value(idFuncKey).(func(ID)); ok {
+		f(id)
+	}
 
 	if err := c.write(ctx, call); err != nil {
 		// Sending failed. We will never get a response, so deliver a fake one if it
@@ -388,6 +397,20 @@ func (c *Connection) Retire(ac *AsyncCall, err error) {
 	})
 }
 
+// RetireID is like [Connection.Retire], for the outgoing call with the given
+// ID. It reports whether such a call was in flight.
+func (c *Connection) RetireID(id ID, err error) bool {
+	var ok bool
+	c.updateInFlight(func(s *inFlightState) {
+		var ac *AsyncCall
+		if ac, ok = s.outgoingCalls[id]; ok {
+			delete(s.outgoingCalls, id)
+			ac.retire(&Response{ID: id, Error: err})
+		}
+	})
+	return ok
+}
+
 // Async, signals that the current jsonrpc2 request may be handled
 // asynchronously to subsequent requests, when ctx is the request context.
 //
@@ -403,6 +426,17 @@ type asyncKeyType struct{}
 
 var asyncKey = asyncKeyType{}
 
+// WithIDFunc returns a context that makes [Connection.Call] call f with the ID
+// of the request, once the call is awaiting a response but before the request
+// is written.
+func WithIDFunc(ctx context.Context, f func(ID)) context.Context {
+	return context.WithValue(ctx, idFuncKey, f)
+}
+
+type idFuncKeyType struct{}
+
+var idFuncKey = idFuncKeyType{}
+
 // A releaser implements concurrency safe 'releasing' of async requests. (A
 // request is released when it is allowed to run concurrent with other
 // requests, via a call to [Async].)
@@ -432,6 +466,7 @@ type AsyncCall struct {
 	id       ID
 	ready    chan struct{} // closed after response has been set
 	response *Response
+	codec    Codec // for the result; nil means encoding/json
 }
 
 // ID used for this call.
@@ -482,6 +517,9 @@ func (ac *AsyncCall) Await(ctx context.Context, result any) error {
 	if result == nil {
 		return nil
 	}
+	if ac.codec != nil {
+		return ac.codec.Unmarshal(ac.response.Result, result)
+	}
 	return json.Unmarshal(ac.response.Result, result)
 }
 
@@ -492,12 +530,18 @@ func (ac *AsyncCall) Await(ctx context.Context, result any) error {
 // will not cause any messages that have not arrived yet with that ID to be
 // cancelled.
 func (c *Connection) Cancel(id ID) {
+	c.CancelCause(id, nil)
+}
+
+// CancelCause is like [Connection.Cancel], but sets the cause of the
+// cancellation to cause, as with [context.WithCancelCause].
+func (c *Connection) CancelCause(id ID, cause error) {
 	var req *incomingRequest
 	c.updateInFlight(func(s *inFlightState) {
 		req = s.incomingByID[id]
 	})
 	if req != nil {
-		req.cancel()
+		req.cancel(cause)
 	}
 }
 
@@ -595,7 +639,7 @@ func (c *Connection) readIncoming(ctx context.Context, reader Reader, preempter
 func (c *Connection) acceptRequest(ctx context.Context, msg *Request, preempter Preempter) {
 	// In theory notifications cannot be cancelled, but we build them a cancel
 	// context anyway.
-	reqCtx, cancel := context.WithCancel(ctx)
+	reqCtx, cancel := context.WithCancelCause(ctx)
 	req := &incomingRequest{
 		Request: msg,
 		ctx:     reqCtx,
@@ -722,9 +766,10 @@ func (c *Connection) handleAsync() {
 
 // processResult processes the result of a request and, if appropriate, sends a response.
 func (c *Connection) processResult(from any, req *incomingRequest, result any, err error) error {
-	switch err {
-	case ErrNotHandled, ErrMethodNotFound:
-		// Add detail describing the unhandled method.
+	switch {
+	case errors.Is(err, ErrNotHandled), err == ErrMethodNotFound:
+		// Add detail describing the unhandled method. Handlers may wrap
+		// ErrNotHandled, but it must still be reported as "method not found".
 		err = fmt.Errorf("%w: %q", ErrMethodNotFound, req.Method)
 	}
 
@@ -738,7 +783,8 @@ func (c *Connection) processResult(from any, req *incomingRequest, result any, e
 			err = c.internalErrorf("%#v returned a nil result and nil error for a %q Request that requires a Response", from, req.Method)
 		}
 
-		response, respErr := NewResponse(req.ID, result, err)
+		r, respErr := marshalToRaw(c.codec, result)
+		response := &Response{ID: req.ID, Result: r, Error: err}
 
 		// The caller could theoretically reuse the request's ID as soon as we've
 		// sent the response, so ensure that it is removed from the incoming map
@@ -767,7 +813,7 @@ func (c *Connection) processResult(from any, req *incomingRequest, result any, e
 	}
 
 	// Cancel the request to free any associated resources.
-	req.cancel()
+	req.cancel(nil)
 	c.updateInFlight(func(s *inFlightState) {
 		if s.incoming == 0 {
 			panic("jsonrpc2: processResult called when incoming count is already zero")
@@ -807,7 +853,7 @@ func (c *Connection) write(ctx context.Context, msg Message) error {
 			if s.writeErr == nil {
 				s.writeErr = err
 				for _, r := range s.incomingByID {
-					r.cancel()
+					r.cancel(nil)
 				}
 			}
 		})
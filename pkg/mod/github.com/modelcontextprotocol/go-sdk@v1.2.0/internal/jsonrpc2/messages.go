@@ -91,14 +91,14 @@ func (id ID) Raw() any { return id.value }
 // NewNotification constructs a new Notification message for the supplied
 // method and parameters.
 func NewNotification(method string, params any) (*Request, error) {
-	p, merr := marshalToRaw(params)
+	p, merr := marshalToRaw(nil, params)
 	return &Request{Method: method, Params: p}, merr
 }
 
 // NewCall constructs a new Call message for the supplied ID, method and
 // parameters.
 func NewCall(id ID, method string, params any) (*Request, error) {
-	p, merr := marshalToRaw(params)
+	p, merr := marshalToRaw(nil, params)
 	return &Request{ID: id, Method: method, Params: p}, merr
 }
 
@@ -113,7 +113,7 @@ func (msg *Request) marshal(to *wireCombined) {
 // NewResponse constructs a new Response message that is a reply to the
 // supplied. If err is set result may be ignored.
 func NewResponse(id ID, result any, rerr error) (*Response, error) {
-	r, merr := marshalToRaw(result)
+	r, merr := marshalToRaw(nil, result)
 	return &Response{ID: id, Result: r, Error: rerr}, merr
 }
 
@@ -200,11 +200,28 @@ func DecodeMessage(data []byte) (Message, error) {
 	return resp, nil
 }
 
-func marshalToRaw(obj any) (json.RawMessage, error) {
+// A Codec marshals and unmarshals the params and results of messages.
+// It must follow the conventions of encoding/json, such as the meaning of
+// struct field tags and the use of the json.Marshaler interface.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// marshalToRaw marshals obj with codec, or with encoding/json if codec is nil.
+func marshalToRaw(codec Codec, obj any) (json.RawMessage, error) {
 	if obj == nil {
 		return nil, nil
 	}
-	data, err := json.Marshal(obj)
+	var (
+		data []byte
+		err  error
+	)
+	if codec != nil {
+		data, err = codec.Marshal(obj)
+	} else {
+		data, err = json.Marshal(obj)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -6,7 +6,13 @@
 // for use by mcp transport authors.
 package jsonrpc
 
-import "github.com/modelcontextprotocol/go-sdk/internal/jsonrpc2"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/internal/jsonrpc2"
+)
 
 type (
 	// ID is a JSON-RPC request ID.
@@ -54,3 +60,34 @@ const (
 	// CodeInternalError indicates an internal JSON-RPC error.
 	CodeInternalError = -32603
 )
+
+// NewError returns an [Error] with the given code and message. If data is
+// non-nil, it is marshaled as JSON into the error's Data field.
+//
+// NewError panics if data cannot be marshaled.
+func NewError(code int64, message string, data any) *Error {
+	e := &Error{Code: code, Message: message}
+	if data != nil {
+		raw, err := json.Marshal(data)
+		if err != nil {
+			panic(fmt.Sprintf("jsonrpc.NewError: marshaling data: %v", err))
+		}
+		e.Data = raw
+	}
+	return e
+}
+
+// DecodeErrorData reports whether err is, or wraps, an [Error] with the given
+// code. If so, and v is non-nil, the error's data is unmarshaled into v, and
+// DecodeErrorData reports false if the data is absent or cannot be
+// unmarshaled.
+func DecodeErrorData(err error, code int64, v any) bool {
+	var e *Error
+	if !errors.As(err, &e) || e.Code != code {
+		return false
+	}
+	if v == nil {
+		return true
+	}
+	return len(e.Data) > 0 && json.Unmarshal(e.Data, v) == nil
+}
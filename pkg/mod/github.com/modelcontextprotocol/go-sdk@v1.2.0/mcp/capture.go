@@ -0,0 +1,558 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// A TrafficCapture records MCP traffic over HTTP, for debugging and bug
+// reports. It captures each HTTP exchange, including its headers and the
+// JSON-RPC messages in its body, and writes them in the HAR (HTTP Archive)
+// format with [TrafficCapture.WriteHAR].
+//
+// Use [TrafficCapture.RoundTripper] to capture the traffic of a client, by
+// setting it as the transport of [StreamableClientTransport.HTTPClient] or
+// [SSEClientTransport.HTTPClient], and [TrafficCapture.Handler] to capture
+// the traffic of a server. For transports other than HTTP, use
+// [LoggingTransport].
+//
+// Secrets are redacted: the values of credential headers such as
+// Authorization and Cookie, and of JSON fields, form fields and URL query
+// parameters with sensitive names such as "password" or "access_token", are
+// replaced by "[REDACTED]". Redaction fails closed: a body that cannot be
+// parsed, for example because it was truncated, is replaced entirely.
+//
+// A new TrafficCapture is enabled. Capture can be paused and resumed at any
+// time with [TrafficCapture.SetEnabled]; exchanges that begin while capture is
+// paused are not recorded.
+type TrafficCapture struct {
+	opts          TrafficCaptureOptions
+	redactHeaders map[string]bool // canonical header names
+	redactFields  map[string]bool // lower-case JSON field names
+	redactForm    map[string]bool // lower-case form field and query parameter names
+
+	enabled atomic.Bool
+
+	mu      sync.Mutex
+	entries []*captureEntry
+}
+
+// TrafficCaptureOptions configure a [TrafficCapture].
+type TrafficCaptureOptions struct {
+	// RedactHeaders are the names of HTTP headers to redact, in addition to
+	// Authorization, Proxy-Authorization, Cookie and Set-Cookie.
+	RedactHeaders []string
+	// RedactFields are the names of JSON fields, form fields and URL query
+	// parameters to redact, in addition to the defaults, such as "password",
+	// "token", "access_token" and "client_secret". Names are matched
+	// case-insensitively.
+	RedactFields []string
+	// MaxEntries is the maximum number of exchanges to keep. When it is
+	// exceeded, the oldest exchange is dropped. If zero, it defaults to 1000.
+	MaxEntries int
+	// MaxBodySize is the maximum number of bytes of each request and response
+	// body to keep. Longer bodies are truncated. If zero, it defaults to 1 MiB.
+	MaxBodySize int
+}
+
+const (
+	defaultCaptureMaxEntries  = 1000
+	defaultCaptureMaxBodySize = 1 << 20
+	redactedValue             = "[REDACTED]"
+	redactedBody              = "[REDACTED: body could not be parsed]"
+)
+
+var (
+	defaultRedactHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+	defaultRedactFields  = []string{
+		"password", "secret", "token", "access_token", "refresh_token", "id_token",
+		"client_secret", "api_key", "apikey", "authorization",
+	}
+	// defaultRedactFormFields are redacted in form bodies and URL queries, in
+	// addition to the fields above. They hold OAuth credentials, but are too
+	// common as JSON field names to redact in JSON-RPC messages.
+	defaultRedactFormFields = []string{
+		"code", "code_verifier", "device_code", "assertion", "client_assertion",
+	}
+)
+
+// NewTrafficCapture returns a new, enabled [TrafficCapture].
+// If opts is nil, default options are used.
+func NewTrafficCapture(opts *TrafficCaptureOptions) *TrafficCapture {
+	c := &TrafficCapture{
+		redactHeaders: make(map[string]bool),
+		redactFields:  make(map[string]bool),
+		redactForm:    make(map[string]bool),
+	}
+	if opts != nil {
+		c.opts = *opts
+	}
+	if c.opts.MaxEntries <= 0 {
+		c.opts.MaxEntries = defaultCaptureMaxEntries
+	}
+	if c.opts.MaxBodySize <= 0 {
+		c.opts.MaxBodySize = defaultCaptureMaxBodySize
+	}
+	for _, h := range slices.Concat(defaultRedactHeaders, c.opts.RedactHeaders) {
+		c.redactHeaders[http.CanonicalHeaderKey(h)] = true
+	}
+	for _, f := range slices.Concat(defaultRedactFields, c.opts.RedactFields) {
+		c.redactFields[strings.ToLower(f)] = true
+		c.redactForm[strings.ToLower(f)] = true
+	}
+	for _, f := range defaultRedactFormFields {
+		c.redactForm[f] = true
+	}
+	c.enabled.Store(true)
+	return c
+}
+
+// SetEnabled pauses or resumes capture.
+func (c *TrafficCapture) SetEnabled(enabled bool) { c.enabled.Store(enabled) }
+
+// Enabled reports whether capture is enabled.
+func (c *TrafficCapture) Enabled() bool { return c.enabled.Load() }
+
+// Reset discards all captured exchanges.
+func (c *TrafficCapture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = nil
+}
+
+// A captureEntry is a captured HTTP exchange. Its fields are guarded by the
+// TrafficCapture's mutex, since response bodies are captured as they stream.
+type captureEntry struct {
+	started    time.Time
+	finished   time.Time // zero while the exchange is in progress
+	method     string
+	url        string
+	proto      string
+	reqHeader  http.Header
+	reqBody    []byte
+	status     int
+	respHeader http.Header
+	respBody   []byte
+	err        string
+}
+
+func (c *TrafficCapture) newEntry(req *http.Request, body []byte) *captureEntry {
+	e := &captureEntry{
+		started:   time.Now(),
+		method:    req.Method,
+		url:       req.URL.String(),
+		proto:     req.Proto,
+		reqHeader: req.Header.Clone(),
+		reqBody:   c.truncate(body),
+	}
+	if req.URL.Host == "" && req.Host != "" {
+		// Server requests have only the path in their URL.
+		e.url = "http://" + req.Host + req.URL.String()
+	}
+	if e.proto == "" {
+		e.proto = "HTTP/1.1"
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) == c.opts.MaxEntries {
+		c.entries = slices.Delete(c.entries, 0, 1)
+	}
+	c.entries = append(c.entries, e)
+	return e
+}
+
+func (c *TrafficCapture) truncate(data []byte) []byte {
+	if len(data) > c.opts.MaxBodySize {
+		data = data[:c.opts.MaxBodySize]
+	}
+	return slices.Clone(data)
+}
+
+// appendBody appends data to the response body of e, up to the maximum size.
+func (c *TrafficCapture) appendBody(e *captureEntry, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if n := c.opts.MaxBodySize - len(e.respBody); n > 0 {
+		e.respBody = append(e.respBody, data[:min(n, len(data))]...)
+	}
+}
+
+func (c *TrafficCapture) finish(e *captureEntry, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e.finished.IsZero() {
+		e.finished = time.Now()
+	}
+	if err != nil && err != io.EOF && e.err == "" {
+		e.err = err.Error()
+	}
+}
+
+// readBody reads and replaces *body, so that it can be both captured and
+// read by its consumer.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(*body)
+	(*body).Close()
+	*body = io.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// RoundTripper returns an [http.RoundTripper] that captures the exchanges
+// made through base. If base is nil, [http.DefaultTransport] is used.
+func (c *TrafficCapture) RoundTripper(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &captureRoundTripper{c: c, base: base}
+}
+
+type captureRoundTripper struct {
+	c    *TrafficCapture
+	base http.RoundTripper
+}
+
+func (rt *captureRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !rt.c.Enabled() {
+		return rt.base.RoundTrip(req)
+	}
+	// A RoundTripper must not modify the request, so read the body of a clone.
+	req = req.Clone(req.Context())
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	e := rt.c.newEntry(req, body)
+	resp, err := rt.base.RoundTrip(req)
+	if err != nil {
+		rt.c.finish(e, err)
+		return nil, err
+	}
+	rt.c.mu.Lock()
+	e.status = resp.StatusCode
+	e.respHeader = resp.Header.Clone()
+	rt.c.mu.Unlock()
+	resp.Body = &captureBody{ReadCloser: resp.Body, c: rt.c, e: e}
+	return resp, nil
+}
+
+// A captureBody captures a response body as it is read, since it may be a
+// long-lived event stream.
+type captureBody struct {
+	io.ReadCloser
+	c *TrafficCapture
+	e *captureEntry
+}
+
+func (b *captureBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.c.appendBody(b.e, p[:n])
+	if err != nil {
+		b.c.finish(b.e, err)
+	}
+	return n, err
+}
+
+func (b *captureBody) Close() error {
+	b.c.finish(b.e, nil)
+	return b.ReadCloser.Close()
+}
+
+// Handler returns an [http.Handler] that captures the exchanges served by h,
+// such as a [StreamableHTTPHandler].
+func (c *TrafficCapture) Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if !c.Enabled() {
+			h.ServeHTTP(w, req)
+			return
+		}
+		body, err := readBody(&req.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		e := c.newEntry(req, body)
+		h.ServeHTTP(&captureResponseWriter{ResponseWriter: w, c: c, e: e}, req)
+		c.finish(e, nil)
+	})
+}
+
+// A captureResponseWriter captures a response as it is written. It supports
+// flushing, as required by streaming responses.
+type captureResponseWriter struct {
+	http.ResponseWriter
+	c           *TrafficCapture
+	e           *captureEntry
+	wroteHeader bool
+}
+
+func (w *captureResponseWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.c.mu.Lock()
+		w.e.status = status
+		w.e.respHeader = w.Header().Clone()
+		w.c.mu.Unlock()
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureResponseWriter) Write(data []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	w.c.appendBody(w.e, data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *captureResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap supports [http.ResponseController].
+func (w *captureResponseWriter) Unwrap() http.ResponseWriter { return w.ResponseWriter }
+
+// HAR types. See http://www.softwareishard.com/blog/har-12-spec/.
+// Only the fields that are relevant to MCP traffic are included.
+type (
+	harFile struct {
+		Log harLog `json:"log"`
+	}
+	harLog struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime string      `json:"startedDateTime"`
+		Time            float64     `json:"time"` // milliseconds
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Comment         string      `json:"comment,omitempty"`
+	}
+	harRequest struct {
+		Method      string       `json:"method"`
+		URL         string       `json:"url"`
+		HTTPVersion string       `json:"httpVersion"`
+		Headers     []harNameVal `json:"headers"`
+		PostData    *harPostData `json:"postData,omitempty"`
+	}
+	harResponse struct {
+		Status      int          `json:"status"`
+		StatusText  string       `json:"statusText"`
+		HTTPVersion string       `json:"httpVersion"`
+		Headers     []harNameVal `json:"headers"`
+		Content     harContent   `json:"content"`
+	}
+	harNameVal struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	harPostData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	}
+	harContent struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text,omitempty"`
+	}
+)
+
+// WriteHAR writes the captured exchanges to w as a HAR file. Exchanges that
+// are still in progress, such as open event streams, are included with the
+// part of their response received so far.
+func (c *TrafficCapture) WriteHAR(w io.Writer) error {
+	c.mu.Lock()
+	entries := make([]harEntry, 0, len(c.entries))
+	for _, e := range c.entries {
+		entries = append(entries, c.harEntry(e))
+	}
+	c.mu.Unlock()
+
+	f := harFile{Log: harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "github.com/modelcontextprotocol/go-sdk/mcp", Version: "1"},
+		Entries: entries,
+	}}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(f)
+}
+
+// harEntry converts e to a HAR entry, redacting secrets.
+// c.mu must be held.
+func (c *TrafficCapture) harEntry(e *captureEntry) harEntry {
+	finished := e.finished
+	if finished.IsZero() {
+		finished = time.Now()
+	}
+	he := harEntry{
+		StartedDateTime: e.started.Format(time.RFC3339Nano),
+		Time:            float64(finished.Sub(e.started).Microseconds()) / 1000,
+		Request: harRequest{
+			Method:      e.method,
+			URL:         c.redactURL(e.url),
+			HTTPVersion: e.proto,
+			Headers:     c.harHeaders(e.reqHeader),
+		},
+		Response: harResponse{
+			Status:      e.status,
+			StatusText:  http.StatusText(e.status),
+			HTTPVersion: e.proto,
+			Headers:     c.harHeaders(e.respHeader),
+			Content: harContent{
+				Size:     len(e.respBody),
+				MimeType: e.respHeader.Get("Content-Type"),
+				Text:     c.redactBody(e.respBody, e.respHeader.Get("Content-Type")),
+			},
+		},
+		Comment: e.err,
+	}
+	if len(e.reqBody) > 0 {
+		he.Request.PostData = &harPostData{
+			MimeType: e.reqHeader.Get("Content-Type"),
+			Text:     c.redactBody(e.reqBody, e.reqHeader.Get("Content-Type")),
+		}
+	}
+	return he
+}
+
+func (c *TrafficCapture) harHeaders(h http.Header) []harNameVal {
+	res := []harNameVal{} // avoid JSON null
+	for _, name := range slices.Sorted(maps.Keys(h)) {
+		for _, v := range h[name] {
+			if c.redactHeaders[http.CanonicalHeaderKey(name)] {
+				v = redactedValue
+			}
+			res = append(res, harNameVal{Name: name, Value: v})
+		}
+	}
+	return res
+}
+
+// redactURL returns rawURL with the password and sensitive query parameters
+// redacted.
+func (c *TrafficCapture) redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return redactedValue
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), redactedValue)
+	}
+	if u.RawQuery != "" {
+		q, err := url.ParseQuery(u.RawQuery)
+		if err != nil {
+			u.RawQuery = redactedValue
+		} else {
+			c.redactValues(q)
+			u.RawQuery = q.Encode()
+		}
+	}
+	return u.String()
+}
+
+// redactValues redacts the sensitive fields of a form or query.
+func (c *TrafficCapture) redactValues(q url.Values) {
+	for k, vs := range q {
+		if c.redactForm[strings.ToLower(k)] {
+			for i := range vs {
+				vs[i] = redactedValue
+			}
+		}
+	}
+}
+
+// redactBody returns body as text, with sensitive fields redacted. The body
+// may be a form, a JSON value, or an event stream of JSON values, according to
+// its content type. A body that cannot be parsed, including one that was
+// truncated, is replaced by a placeholder, as is the data of such an event.
+func (c *TrafficCapture) redactBody(body []byte, contentType string) string {
+	if len(body) == 0 {
+		return ""
+	}
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/x-www-form-urlencoded":
+		q, err := url.ParseQuery(string(body))
+		if err != nil {
+			return redactedBody
+		}
+		c.redactValues(q)
+		return q.Encode()
+	case "text/event-stream":
+		lines := strings.Split(string(body), "\n")
+		for i, line := range lines {
+			data, ok := strings.CutPrefix(line, "data:")
+			data = strings.TrimSpace(data)
+			if !ok || data == "" {
+				continue
+			}
+			if s, ok := c.redactJSON([]byte(data)); ok {
+				lines[i] = "data: " + s
+			} else {
+				lines[i] = "data: " + redactedBody
+			}
+		}
+		return strings.Join(lines, "\n")
+	}
+	if s, ok := c.redactJSON(body); ok {
+		return s
+	}
+	return redactedBody
+}
+
+// redactJSON redacts the sensitive fields of data, reporting whether it is
+// valid JSON.
+func (c *TrafficCapture) redactJSON(data []byte) (string, bool) {
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "", false
+	}
+	out, err := json.Marshal(c.redactValue(v))
+	if err != nil {
+		return "", false
+	}
+	return string(out), true
+}
+
+func (c *TrafficCapture) redactValue(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, x := range v {
+			if c.redactFields[strings.ToLower(k)] {
+				v[k] = redactedValue
+			} else {
+				v[k] = c.redactValue(x)
+			}
+		}
+	case []any:
+		for i, x := range v {
+			v[i] = c.redactValue(x)
+		}
+	}
+	return v
+}
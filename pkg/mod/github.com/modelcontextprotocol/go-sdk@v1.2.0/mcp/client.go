@@ -33,6 +33,7 @@ type Client struct {
 	sessions                []*ClientSession
 	sendingMethodHandler_   MethodHandler
 	receivingMethodHandler_ MethodHandler
+	receivingMethodInfos_   map[string]methodInfo // nil unless experimental methods are added
 }
 
 // NewClient creates a new [Client].
@@ -56,6 +57,10 @@ func NewClient(impl *Implementation, opts *ClientOptions) *Client {
 	if opts != nil {
 		c.opts = *opts
 	}
+	if c.opts.JSONCodec == nil {
+		c.opts.JSONCodec = stdJSONCodec{}
+	}
+	checkProtocolVersions(c.opts.ProtocolVersions)
 	return c
 }
 
@@ -127,6 +132,17 @@ type ClientOptions struct {
 	// If the peer fails to respond to pings originating from the keepalive check,
 	// the session is automatically closed.
 	KeepAlive time.Duration
+	// ProtocolVersions, if non-empty, restricts the protocol versions that
+	// the client will use to those listed, each of which must be supported by
+	// the SDK. The client requests the latest of them, and [Client.Connect]
+	// fails if the server responds with a version that is not listed, with
+	// an error that [ParseUnsupportedProtocolVersionError] recognizes.
+	//
+	// To pin the protocol version, list a single version.
+	ProtocolVersions []string
+	// JSONCodec, if non-nil, is used instead of encoding/json to marshal and
+	// unmarshal the client's messages. See [JSONCodec].
+	JSONCodec JSONCodec
 }
 
 // bind implements the binder[*ClientSession] interface, so that Clients can
@@ -153,15 +169,6 @@ func (c *Client) disconnect(cs *ClientSession) {
 	})
 }
 
-// TODO: Consider exporting this type and its field.
-type unsupportedProtocolVersionError struct {
-	version string
-}
-
-func (e unsupportedProtocolVersionError) Error() string {
-	return fmt.Sprintf("unsupported protocol version: %q", e.version)
-}
-
 // ClientSessionOptions is reserved for future use.
 type ClientSessionOptions struct {
 	// protocolVersion overrides the protocol version sent in the initialize
@@ -225,6 +232,10 @@ func (c *Client) Connect(ctx context.Context, t Transport, opts *ClientSessionOp
 	}
 
 	protocolVersion := latestProtocolVersion
+	if allowed := c.opts.ProtocolVersions; len(allowed) > 0 {
+		// Versions are dates, so the latest version sorts last.
+		protocolVersion = slices.Max(allowed)
+	}
 	if opts != nil && opts.protocolVersion != "" {
 		protocolVersion = opts.protocolVersion
 	}
@@ -240,7 +251,12 @@ func (c *Client) Connect(ctx context.Context, t Transport, opts *ClientSessionOp
 		return nil, err
 	}
 	if !slices.Contains(supportedProtocolVersions, res.ProtocolVersion) {
-		return nil, unsupportedProtocolVersionError{res.ProtocolVersion}
+		_ = cs.Close()
+		return nil, fmt.Errorf("%w: %q", UnsupportedProtocolVersionError(res.ProtocolVersion, supportedProtocolVersions), res.ProtocolVersion)
+	}
+	if allowed := c.opts.ProtocolVersions; len(allowed) > 0 && !slices.Contains(allowed, res.ProtocolVersion) {
+		_ = cs.Close()
+		return nil, fmt.Errorf("%w: %q (allowed versions: %s)", UnsupportedProtocolVersionError(res.ProtocolVersion, allowed), res.ProtocolVersion, strings.Join(allowed, ","))
 	}
 	cs.state.InitializeResult = res
 	if hc, ok := cs.mcpConn.(clientConnection); ok {
@@ -284,6 +300,10 @@ type ClientSession struct {
 	// Pending URL elicitations waiting for completion notifications.
 	pendingElicitationsMu sync.Mutex
 	pendingElicitations   map[string]chan struct{}
+
+	// Roots of this session only, in addition to those of the client.
+	// Guarded by client.mu. Nil until AddRoots is called.
+	roots *featureSet[*Root]
 }
 
 type clientSessionState struct {
@@ -393,6 +413,46 @@ func (c *Client) RemoveRoots(uris ...string) {
 		func() bool { return c.roots.remove(uris...) })
 }
 
+// AddRoots adds the given roots to the session, replacing any with the same
+// URIs, and notifies the server.
+//
+// Unlike the roots added with [Client.AddRoots], these roots are listed only
+// to this session's server, after the client's roots. A session root takes
+// the place of a client root with the same URI.
+func (cs *ClientSession) AddRoots(roots ...*Root) {
+	// Only notify if something could change.
+	if len(roots) == 0 {
+		return
+	}
+	cs.changeRootsAndNotify(func() bool {
+		if cs.roots == nil {
+			cs.roots = newFeatureSet(func(r *Root) string { return r.URI })
+		}
+		cs.roots.add(roots...)
+		return true
+	})
+}
+
+// RemoveRoots removes the session roots with the given URIs, and notifies
+// the server if the list has changed. It does not remove roots added with
+// [Client.AddRoots].
+// It is not an error to remove a nonexistent root.
+func (cs *ClientSession) RemoveRoots(uris ...string) {
+	cs.changeRootsAndNotify(func() bool { return cs.roots != nil && cs.roots.remove(uris...) })
+}
+
+// changeRootsAndNotify is like changeAndNotify, for the roots of a single
+// session.
+func (cs *ClientSession) changeRootsAndNotify(change func() bool) {
+	c := cs.client
+	c.mu.Lock()
+	notify := change() && c.shouldSendListChangedNotification(notificationRootsListChanged)
+	c.mu.Unlock()
+	if notify {
+		notifySessions([]*ClientSession{cs}, notificationRootsListChanged, &RootsListChangedParams{}, c.logger)
+	}
+}
+
 // changeAndNotify is called when a feature is added or removed.
 // It calls change, which should do the work and report whether a change actually occurred.
 // If there was a change, it notifies a snapshot of the sessions.
@@ -437,6 +497,13 @@ func (c *Client) listRoots(_ context.Context, req *ListRootsRequest) (*ListRoots
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	roots := slices.Collect(c.roots.all())
+	if sroots := req.Session.roots; sroots != nil {
+		roots = slices.DeleteFunc(roots, func(r *Root) bool {
+			_, ok := sroots.get(r.URI)
+			return ok
+		})
+		roots = slices.AppendSeq(roots, sroots.all())
+	}
 	if roots == nil {
 		roots = []*Root{} // avoid JSON null
 	}
@@ -496,9 +563,7 @@ func urlElicitationMiddleware() Middleware {
 			}
 
 			// Parse the elicitations from the error data.
-			var errorData struct {
-				Elicitations []*ElicitParams `json:"elicitations"`
-			}
+			var errorData URLElicitationRequiredErrorData
 			if rpcErr.Data != nil {
 				if err := json.Unmarshal(rpcErr.Data, &errorData); err != nil {
 					return nil, fmt.Errorf("failed to parse URL elicitation error data: %w", err)
@@ -808,11 +873,20 @@ var clientMethodInfos = map[string]methodInfo{
 	notificationElicitationComplete: newClientMethodInfo(clientMethod((*Client).callElicitationCompleteHandler), notification|missingParamsOK),
 }
 
+func (c *Client) jsonCodec() JSONCodec { return c.opts.JSONCodec }
+
+func (cs *ClientSession) jsonCodec() JSONCodec { return cs.client.jsonCodec() }
+
 func (cs *ClientSession) sendingMethodInfos() map[string]methodInfo {
 	return serverMethodInfos
 }
 
 func (cs *ClientSession) receivingMethodInfos() map[string]methodInfo {
+	cs.client.mu.Lock()
+	defer cs.client.mu.Unlock()
+	if cs.client.receivingMethodInfos_ != nil {
+		return cs.client.receivingMethodInfos_
+	}
 	return clientMethodInfos
 }
 
@@ -855,6 +929,21 @@ func newClientRequest[P Params](cs *ClientSession, params P) *ClientRequest[P] {
 	return &ClientRequest[P]{Session: cs, Params: params}
 }
 
+// CancelRequest cancels the in-flight request to the server with the given
+// JSON-RPC ID, by sending it a "notifications/cancelled" notification with
+// the given reason. The call that made the request returns immediately, with
+// an error wrapping a [*CancelledError].
+//
+// Request IDs are assigned by the session when a request is sent. To learn
+// the ID of a request, make it with a context from [WithRequestIDFunc]. A
+// request whose context is available can be cancelled by cancelling its
+// context instead.
+//
+// It is an error if no request with the ID is in flight.
+func (cs *ClientSession) CancelRequest(ctx context.Context, id jsonrpc.ID, reason string) error {
+	return cancelRequest(ctx, cs.conn, id, reason)
+}
+
 // Ping makes an MCP "ping" request to the server.
 func (cs *ClientSession) Ping(ctx context.Context, params *PingParams) error {
 	_, err := handleSend[*emptyResult](ctx, methodPing, newClientRequest(cs, orZero[Params](params)))
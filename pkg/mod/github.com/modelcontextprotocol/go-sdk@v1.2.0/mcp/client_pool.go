@@ -0,0 +1,266 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// A ClientPool manages client sessions to many servers, identified by name.
+//
+// Sessions are connected lazily, when first used. If a session is closed,
+// for example because the server exited or a health check failed, the next
+// use connects a new session.
+//
+// All sessions are connected with the same [Client], so they share its
+// options and roots.
+type ClientPool struct {
+	client *Client
+	opts   ClientPoolOptions
+
+	mu      sync.Mutex
+	servers map[string]*pooledServer
+	closed  bool
+	done    chan struct{} // closed by Close, to stop health checks
+}
+
+// ClientPoolOptions are options for [NewClientPool].
+type ClientPoolOptions struct {
+	// HealthCheckInterval, if positive, is the interval at which connected
+	// sessions are pinged. Sessions that fail to respond within
+	// HealthCheckTimeout are closed, to be reconnected on next use.
+	HealthCheckInterval time.Duration
+	// HealthCheckTimeout is the time to wait for a response to a health
+	// check ping. If zero, it defaults to HealthCheckInterval.
+	HealthCheckTimeout time.Duration
+}
+
+// A pooledServer is a server in a ClientPool.
+type pooledServer struct {
+	newTransport func() Transport
+
+	connectMu sync.Mutex // serializes connection attempts
+
+	mu      sync.Mutex
+	session *ClientSession // nil if not connected
+}
+
+// NewClientPool returns a new [ClientPool] with no servers, whose sessions
+// are connected with c. Use [ClientPool.Add] to add servers.
+//
+// Call [ClientPool.Close] when the pool is no longer needed.
+func NewClientPool(c *Client, opts *ClientPoolOptions) *ClientPool {
+	p := &ClientPool{
+		client:  c,
+		servers: make(map[string]*pooledServer),
+		done:    make(chan struct{}),
+	}
+	if opts != nil {
+		p.opts = *opts
+	}
+	if p.opts.HealthCheckTimeout == 0 {
+		p.opts.HealthCheckTimeout = p.opts.HealthCheckInterval
+	}
+	if p.opts.HealthCheckInterval > 0 {
+		go p.healthChecks()
+	}
+	return p
+}
+
+// Add adds a server to the pool under the given name. The server is not
+// contacted until a session to it is needed.
+//
+// Each time the pool connects to the server, it calls newTransport for a new
+// transport, since transports such as [CommandTransport] can only be
+// connected once.
+//
+// It is an error to add a server with the name of one already in the pool.
+func (p *ClientPool) Add(name string, newTransport func() Transport) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.closed {
+		return errors.New("client pool is closed")
+	}
+	if _, ok := p.servers[name]; ok {
+		return fmt.Errorf("server %q is already in the pool", name)
+	}
+	p.servers[name] = &pooledServer{newTransport: newTransport}
+	return nil
+}
+
+// Remove removes the named server from the pool, closing its session if it
+// is connected. It is not an error to remove a server that is not in the
+// pool.
+func (p *ClientPool) Remove(name string) error {
+	p.mu.Lock()
+	ps := p.servers[name]
+	delete(p.servers, name)
+	p.mu.Unlock()
+	if ps == nil {
+		return nil
+	}
+	return ps.close()
+}
+
+// Names returns the names of the servers in the pool, in sorted order.
+func (p *ClientPool) Names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var names []string
+	for name := range p.servers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Session returns a session to the named server, connecting it if
+// necessary.
+//
+// The session is owned by the pool: callers must not close it. To
+// disconnect from the server, use [ClientPool.Remove].
+func (p *ClientPool) Session(ctx context.Context, name string) (*ClientSession, error) {
+	p.mu.Lock()
+	ps := p.servers[name]
+	closed := p.closed
+	p.mu.Unlock()
+	if closed {
+		return nil, errors.New("client pool is closed")
+	}
+	if ps == nil {
+		return nil, fmt.Errorf("no server %q in the pool", name)
+	}
+
+	ps.connectMu.Lock()
+	defer ps.connectMu.Unlock()
+	ps.mu.Lock()
+	cs := ps.session
+	ps.mu.Unlock()
+	if cs != nil {
+		return cs, nil
+	}
+	cs, err := p.client.Connect(ctx, ps.newTransport(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("connecting to %q: %w", name, err)
+	}
+	// The server may have been removed, or the pool closed, while connecting.
+	// Store the session while holding p.mu, so that Remove and Close either
+	// see it and close it, or happen before and are detected here.
+	p.mu.Lock()
+	inPool := !p.closed && p.servers[name] == ps
+	if inPool {
+		ps.mu.Lock()
+		ps.session = cs
+		ps.mu.Unlock()
+	}
+	p.mu.Unlock()
+	if !inPool {
+		cs.Close()
+		return nil, fmt.Errorf("server %q was removed from the pool", name)
+	}
+	// Forget the session when it closes, so that the next use reconnects.
+	go func() {
+		cs.Wait()
+		ps.mu.Lock()
+		if ps.session == cs {
+			ps.session = nil
+		}
+		ps.mu.Unlock()
+	}()
+	return cs, nil
+}
+
+// CallTool calls the named tool of the named server with the given
+// arguments, connecting to the server if necessary.
+func (p *ClientPool) CallTool(ctx context.Context, server, name string, args any) (*CallToolResult, error) {
+	cs, err := p.Session(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+	return cs.CallTool(ctx, &CallToolParams{Name: name, Arguments: args})
+}
+
+// Close closes all sessions in the pool and stops health checks. The pool
+// cannot be used after it is closed.
+func (p *ClientPool) Close() error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.done)
+	servers := p.servers
+	p.servers = nil
+	p.mu.Unlock()
+
+	var errs []error
+	for _, ps := range servers {
+		if err := ps.close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// close closes the server's session, if any.
+func (ps *pooledServer) close() error {
+	ps.mu.Lock()
+	cs := ps.session
+	ps.session = nil
+	ps.mu.Unlock()
+	if cs == nil {
+		return nil
+	}
+	return cs.Close()
+}
+
+// healthChecks pings connected sessions periodically until the pool is
+// closed.
+func (p *ClientPool) healthChecks() {
+	ticker := time.NewTicker(p.opts.HealthCheckInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.done:
+			return
+		case <-ticker.C:
+		}
+		p.mu.Lock()
+		var servers []*pooledServer
+		for _, ps := range p.servers {
+			servers = append(servers, ps)
+		}
+		p.mu.Unlock()
+		var sessions []*ClientSession
+		for _, ps := range servers {
+			ps.mu.Lock()
+			if ps.session != nil {
+				sessions = append(sessions, ps.session)
+			}
+			ps.mu.Unlock()
+		}
+
+		var wg sync.WaitGroup
+		for _, cs := range sessions {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				ctx, cancel := context.WithTimeout(context.Background(), p.opts.HealthCheckTimeout)
+				defer cancel()
+				if err := cs.Ping(ctx, nil); err != nil {
+					// Closing the session causes it to be reconnected on next use.
+					cs.Close()
+				}
+			}()
+		}
+		wg.Wait()
+	}
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// poolServer is a server for ClientPool tests, whose tool "whoami" returns
+// its name.
+type poolServer struct {
+	server      *Server
+	connects    atomic.Int32
+	blockPings  atomic.Bool
+	mu          sync.Mutex
+	lastSession *ServerSession
+}
+
+func newPoolServer(t *testing.T, name string) *poolServer {
+	ps := &poolServer{server: NewServer(&Implementation{Name: name}, nil)}
+	AddTool(ps.server, &Tool{Name: "whoami"}, func(context.Context, *CallToolRequest, any) (*CallToolResult, any, error) {
+		return &CallToolResult{Content: []Content{&TextContent{Text: name}}}, nil, nil
+	})
+	ps.server.AddReceivingMiddleware(func(h MethodHandler) MethodHandler {
+		return func(ctx context.Context, method string, req Request) (Result, error) {
+			if method == methodPing && ps.blockPings.Load() {
+				<-ctx.Done()
+				return nil, ctx.Err()
+			}
+			return h(ctx, method, req)
+		}
+	})
+	return ps
+}
+
+func (ps *poolServer) newTransport(t *testing.T) func() Transport {
+	return func() Transport {
+		ct, st := NewInMemoryTransports()
+		ss, err := ps.server.Connect(context.Background(), st, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		ps.connects.Add(1)
+		ps.mu.Lock()
+		ps.lastSession = ss
+		ps.mu.Unlock()
+		return ct
+	}
+}
+
+// pollUntil waits until cond holds.
+func pollUntil(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out")
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestClientPool(t *testing.T) {
+	ctx := context.Background()
+	pool := NewClientPool(NewClient(testImpl, nil), nil)
+	defer pool.Close()
+
+	a, b := newPoolServer(t, "a"), newPoolServer(t, "b")
+	if err := pool.Add("a", a.newTransport(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Add("b", b.newTransport(t)); err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Add("a", a.newTransport(t)); err == nil {
+		t.Error("adding duplicate server: got nil error, want error")
+	}
+	if got, want := pool.Names(), []string{"a", "b"}; !slices.Equal(got, want) {
+		t.Errorf("Names: got %v, want %v", got, want)
+	}
+	// Sessions are connected lazily.
+	if n := a.connects.Load() + b.connects.Load(); n != 0 {
+		t.Errorf("got %d connections before use, want 0", n)
+	}
+
+	whoami := func(server string) string {
+		t.Helper()
+		res, err := pool.CallTool(ctx, server, "whoami", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Content[0].(*TextContent).Text
+	}
+	for _, name := range []string{"a", "b", "a"} {
+		if got := whoami(name); got != name {
+			t.Errorf("CallTool(%q): got %q", name, got)
+		}
+	}
+	if got := a.connects.Load(); got != 1 {
+		t.Errorf("got %d connections to a, want 1", got)
+	}
+	if _, err := pool.CallTool(ctx, "c", "whoami", nil); err == nil {
+		t.Error("calling unknown server: got nil error, want error")
+	}
+
+	// If the server closes the session, the next use reconnects.
+	cs, err := pool.Session(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a.mu.Lock()
+	a.lastSession.Close()
+	a.mu.Unlock()
+	cs.Wait()
+	pollUntil(t, func() bool {
+		cs2, err := pool.Session(ctx, "a")
+		return err == nil && cs2 != cs
+	})
+	if got := whoami("a"); got != "a" {
+		t.Errorf("after reconnect: got %q, want a", got)
+	}
+	if got := a.connects.Load(); got != 2 {
+		t.Errorf("got %d connections to a, want 2", got)
+	}
+
+	if err := pool.Remove("b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Session(ctx, "b"); err == nil {
+		t.Error("session to removed server: got nil error, want error")
+	}
+}
+
+func TestClientPoolHealthCheck(t *testing.T) {
+	ctx := context.Background()
+	pool := NewClientPool(NewClient(testImpl, nil), &ClientPoolOptions{
+		HealthCheckInterval: 10 * time.Millisecond,
+	})
+	defer pool.Close()
+
+	a := newPoolServer(t, "a")
+	if err := pool.Add("a", a.newTransport(t)); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := pool.Session(ctx, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An unresponsive session is closed by the health check.
+	a.blockPings.Store(true)
+	cs.Wait()
+	a.blockPings.Store(false)
+	pollUntil(t, func() bool {
+		cs2, err := pool.Session(ctx, "a")
+		return err == nil && cs2 != cs
+	})
+	if got := a.connects.Load(); got != 2 {
+		t.Errorf("got %d connections, want 2", got)
+	}
+}
+
+// TestClientPoolRemoveWhileConnecting checks that a session connected for a
+// server that was removed meanwhile is closed rather than leaked.
+func TestClientPoolRemoveWhileConnecting(t *testing.T) {
+	pool := NewClientPool(NewClient(testImpl, nil), nil)
+	defer pool.Close()
+
+	a := newPoolServer(t, "a")
+	newTransport := a.newTransport(t)
+	if err := pool.Add("a", func() Transport {
+		pool.Remove("a")
+		return newTransport()
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pool.Session(context.Background(), "a"); err == nil {
+		t.Fatal("Session: got nil error, want error for a removed server")
+	}
+	a.mu.Lock()
+	ss := a.lastSession
+	a.mu.Unlock()
+	// Wait returns once the client closes the connection.
+	ss.Wait()
+}
@@ -7,7 +7,10 @@ package mcp
 import (
 	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -488,3 +491,145 @@ func TestClientCapabilitiesOverWire(t *testing.T) {
 		})
 	}
 }
+
+func TestClientSessionRoots(t *testing.T) {
+	ctx := context.Background()
+	changed := make(chan *ServerSession, 10)
+	server := NewServer(testImpl, &ServerOptions{
+		RootsListChangedHandler: func(_ context.Context, req *RootsListChangedRequest) {
+			changed <- req.Session
+		},
+	})
+	client := NewClient(testImpl, nil)
+	client.AddRoots(&Root{URI: "file://shared"}, &Root{URI: "file://common", Name: "client"})
+
+	connect := func() (*ClientSession, *ServerSession) {
+		ct, st := NewInMemoryTransports()
+		ss, err := server.Connect(ctx, st, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cs, err := client.Connect(ctx, ct, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		return cs, ss
+	}
+	cs1, ss1 := connect()
+	_, ss2 := connect()
+
+	listRoots := func(ss *ServerSession) []*Root {
+		t.Helper()
+		res, err := ss.ListRoots(ctx, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Roots
+	}
+
+	cs1.AddRoots(&Root{URI: "file://private"}, &Root{URI: "file://common", Name: "session"})
+	if got := <-changed; got != ss1 {
+		t.Error("list_changed notification sent to the wrong session")
+	}
+	want1 := []*Root{
+		{URI: "file://shared"},
+		{URI: "file://common", Name: "session"},
+		{URI: "file://private"},
+	}
+	if diff := cmp.Diff(want1, listRoots(ss1)); diff != "" {
+		t.Errorf("session 1 roots mismatch (-want +got):\n%s", diff)
+	}
+	want2 := []*Root{
+		{URI: "file://common", Name: "client"},
+		{URI: "file://shared"},
+	}
+	if diff := cmp.Diff(want2, listRoots(ss2)); diff != "" {
+		t.Errorf("session 2 roots mismatch (-want +got):\n%s", diff)
+	}
+
+	// Removing a session root restores the client root it replaced.
+	cs1.RemoveRoots("file://common", "file://private")
+	if got := <-changed; got != ss1 {
+		t.Error("list_changed notification sent to the wrong session")
+	}
+	if diff := cmp.Diff(want2, listRoots(ss1)); diff != "" {
+		t.Errorf("session 1 roots after removal mismatch (-want +got):\n%s", diff)
+	}
+
+	// Removing a nonexistent root does not notify.
+	cs1.RemoveRoots("file://nonexistent")
+	select {
+	case <-changed:
+		t.Error("got list_changed notification after no-op removal")
+	case <-time.After(notificationDelay * 2):
+	}
+}
+
+func TestProtocolVersions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name           string
+		clientVersions []string
+		serverVersions []string
+		serverResponse string // if set, the server responds with this version
+		want           string // negotiated version, or "" for error
+		wantErr        string
+	}{
+		{"default", nil, nil, "", latestProtocolVersion, ""},
+		{"client pins newer", []string{protocolVersion20251125}, nil, "", protocolVersion20251125, ""},
+		{"client picks latest", []string{protocolVersion20250326, protocolVersion20250618}, nil, "", protocolVersion20250618, ""},
+		{"server allows", []string{protocolVersion20250326}, []string{protocolVersion20250326, protocolVersion20250618}, "", protocolVersion20250326, ""},
+		{"server counters", nil, []string{protocolVersion20250326, protocolVersion20250618}, "", protocolVersion20250618, ""},
+		{"server counters, client refuses", []string{protocolVersion20250326}, []string{protocolVersion20250618}, "", "", "allowed versions: 2025-03-26"},
+		{"client refuses", []string{protocolVersion20250618}, nil, protocolVersion20250326, "", "allowed versions: 2025-06-18"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			server := NewServer(testImpl, &ServerOptions{ProtocolVersions: test.serverVersions})
+			if test.serverResponse != "" {
+				server.AddReceivingMiddleware(func(h MethodHandler) MethodHandler {
+					return func(ctx context.Context, method string, req Request) (Result, error) {
+						res, err := h(ctx, method, req)
+						if ir, ok := res.(*InitializeResult); ok {
+							ir.ProtocolVersion = test.serverResponse
+						}
+						return res, err
+					}
+				})
+			}
+			client := NewClient(testImpl, &ClientOptions{ProtocolVersions: test.clientVersions})
+			ct, st := NewInMemoryTransports()
+			ss, err := server.Connect(ctx, st, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ss.Close()
+			cs, err := client.Connect(ctx, ct, nil)
+			if test.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+					t.Fatalf("Connect: got error %v, want containing %q", err, test.wantErr)
+				}
+				data, ok := ParseUnsupportedProtocolVersionError(err)
+				if !ok || !slices.Equal(data.Supported, test.clientVersions) {
+					t.Errorf("ParseUnsupportedProtocolVersionError(%v): got %+v, %t; want supported versions %q", err, data, ok, test.clientVersions)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cs.Close()
+			if got := cs.InitializeResult().ProtocolVersion; got != test.want {
+				t.Errorf("negotiated version %q, want %q", got, test.want)
+			}
+		})
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("NewClient with unknown protocol version: did not panic")
+		}
+	}()
+	NewClient(testImpl, &ClientOptions{ProtocolVersions: []string{"1999-01-01"}})
+}
@@ -0,0 +1,33 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import "encoding/json"
+
+// A JSONCodec marshals and unmarshals JSON.
+//
+// By default, the SDK uses encoding/json. For deployments where JSON encoding
+// dominates CPU profiles, set [ServerOptions.JSONCodec] or
+// [ClientOptions.JSONCodec] to use another implementation, such as one based
+// on encoding/json/v2 or a third-party library.
+//
+// The codec is used for the params and results of requests and
+// notifications, and for the input and output of tools added with [AddTool].
+// JSON-RPC message framing and JSON schema validation always use
+// encoding/json.
+//
+// A JSONCodec must be compatible with encoding/json: it must honor `json`
+// struct tags, including "omitempty", and the [json.Marshaler] and
+// [json.Unmarshaler] interfaces, which many SDK types implement.
+type JSONCodec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// stdJSONCodec is the default JSONCodec, which uses encoding/json.
+type stdJSONCodec struct{}
+
+func (stdJSONCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (stdJSONCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
@@ -0,0 +1,74 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// countingCodec is a JSONCodec that counts its uses.
+type countingCodec struct {
+	marshals, unmarshals atomic.Int64
+}
+
+func (c *countingCodec) Marshal(v any) ([]byte, error) {
+	c.marshals.Add(1)
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) Unmarshal(data []byte, v any) error {
+	c.unmarshals.Add(1)
+	return json.Unmarshal(data, v)
+}
+
+func TestJSONCodec(t *testing.T) {
+	ctx := context.Background()
+
+	var serverCodec, clientCodec countingCodec
+	server := NewServer(testImpl, &ServerOptions{JSONCodec: &serverCodec})
+	type args struct {
+		Name string `json:"name"`
+	}
+	type greeting struct {
+		Message string `json:"message"`
+	}
+	AddTool(server, &Tool{Name: "greet"}, func(_ context.Context, _ *CallToolRequest, in args) (*CallToolResult, greeting, error) {
+		return nil, greeting{Message: "hi " + in.Name}, nil
+	})
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := NewClient(testImpl, &ClientOptions{JSONCodec: &clientCodec}).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	before := serverCodec.unmarshals.Load()
+	res, err := cs.CallTool(ctx, &CallToolParams{Name: "greet", Arguments: map[string]any{"name": "gopher"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(map[string]any{"message": "hi gopher"}, res.StructuredContent); diff != "" {
+		t.Errorf("structured content mismatch (-want +got):\n%s", diff)
+	}
+	// The server unmarshals the call params and the tool arguments.
+	if got := serverCodec.unmarshals.Load() - before; got < 2 {
+		t.Errorf("server codec: got %d unmarshals for tool call, want at least 2", got)
+	}
+	for name, c := range map[string]*countingCodec{"server": &serverCodec, "client": &clientCodec} {
+		if c.marshals.Load() == 0 || c.unmarshals.Load() == 0 {
+			t.Errorf("%s codec: got %d marshals and %d unmarshals, want both non-zero", name, c.marshals.Load(), c.unmarshals.Load())
+		}
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxCompletionValues is the maximum number of values in a completion result,
+// from the spec.
+const maxCompletionValues = 100
+
+// CompletionCandidates maps a completion reference to the candidate values
+// of its arguments, keyed by argument name.
+//
+// For a prompt, the reference is {Type: "ref/prompt", Name: promptName}.
+// For a resource template, it is {Type: "ref/resource", URI: uriTemplate},
+// and the arguments are the template's variables.
+type CompletionCandidates map[CompleteReference]map[string][]string
+
+// NewCompletionHandler returns a handler for [ServerOptions.CompletionHandler]
+// that completes argument values from the given candidates, using
+// [CompleteValues].
+//
+// Requests for references or arguments without candidates get an empty
+// result.
+func NewCompletionHandler(candidates CompletionCandidates) func(context.Context, *CompleteRequest) (*CompleteResult, error) {
+	return func(_ context.Context, req *CompleteRequest) (*CompleteResult, error) {
+		var values []string
+		if ref := req.Params.Ref; ref != nil {
+			values = candidates[*ref][req.Params.Argument.Name]
+		}
+		return &CompleteResult{Completion: CompleteValues(req.Params.Argument.Value, values)}, nil
+	}
+}
+
+// CompleteValues returns the candidates that match value, best match first.
+//
+// Matching ignores case. An exact match ranks first, followed by candidates
+// that begin with value, then those that contain it, and finally those that
+// contain the characters of value in order ("fuzzy" matches), with fewer
+// intervening characters ranking higher. Ties keep the order of candidates.
+// If value is empty, all candidates match.
+//
+// At most 100 values are returned, as the spec requires. Total is the number
+// of matches, and HasMore reports whether some were omitted.
+func CompleteValues(value string, candidates []string) CompletionResultDetails {
+	type match struct {
+		value      string
+		rank, cost int
+	}
+	query := strings.ToLower(value)
+	var matches []match
+	for _, c := range candidates {
+		if rank, cost, ok := matchCompletion(query, strings.ToLower(c)); ok {
+			matches = append(matches, match{c, rank, cost})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		if a.rank != b.rank {
+			return a.rank - b.rank
+		}
+		return a.cost - b.cost
+	})
+	res := CompletionResultDetails{
+		Values:  []string{}, // values is a required field
+		Total:   len(matches),
+		HasMore: len(matches) > maxCompletionValues,
+	}
+	for _, m := range matches[:min(len(matches), maxCompletionValues)] {
+		res.Values = append(res.Values, m.value)
+	}
+	return res
+}
+
+// matchCompletion reports whether candidate matches query, both in lower
+// case. If so, it returns the rank of the kind of match (lower is better),
+// and a cost that orders matches of the same rank.
+func matchCompletion(query, candidate string) (rank, cost int, ok bool) {
+	switch {
+	case query == candidate:
+		return 0, 0, true
+	case strings.HasPrefix(candidate, query):
+		return 1, 0, true
+	}
+	if i := strings.Index(candidate, query); i >= 0 {
+		return 2, i, true
+	}
+	// Look for the runes of query in order, counting the skipped bytes
+	// between the first and last of them.
+	pos := 0
+	for j, r := range query {
+		i := strings.IndexRune(candidate[pos:], r)
+		if i < 0 {
+			return 0, 0, false
+		}
+		if j > 0 {
+			cost += i
+		}
+		pos += i + utf8.RuneLen(r)
+	}
+	return 3, cost, true
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func TestCompleteValues(t *testing.T) {
+	candidates := []string{"Python", "TypeScript", "Go", "go-sdk", "Golang", "JavaScript", "Haskell"}
+	for _, test := range []struct {
+		value string
+		want  []string
+	}{
+		{"", candidates},
+		{"go", []string{"Go", "go-sdk", "Golang"}},
+		{"GO-", []string{"go-sdk"}},
+		{"script", []string{"TypeScript", "JavaScript"}},
+		{"pt", []string{"TypeScript", "JavaScript", "Python"}}, // substrings before fuzzy matches
+		{"pyn", []string{"Python"}},
+		{"rust", []string{}},
+	} {
+		got := CompleteValues(test.value, candidates)
+		if !slices.Equal(got.Values, test.want) {
+			t.Errorf("CompleteValues(%q): got %q, want %q", test.value, got.Values, test.want)
+		}
+		if got.Total != len(test.want) || got.HasMore {
+			t.Errorf("CompleteValues(%q): got Total %d, HasMore %t; want %d, false", test.value, got.Total, got.HasMore, len(test.want))
+		}
+	}
+}
+
+func TestCompleteValuesLimit(t *testing.T) {
+	var candidates []string
+	for i := range 150 {
+		candidates = append(candidates, fmt.Sprintf("item%d", i))
+	}
+	got := CompleteValues("item", candidates)
+	if len(got.Values) != maxCompletionValues || got.Total != 150 || !got.HasMore {
+		t.Errorf("got %d values, Total %d, HasMore %t; want %d, 150, true", len(got.Values), got.Total, got.HasMore, maxCompletionValues)
+	}
+}
+
+func TestNewCompletionHandler(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(testImpl, &ServerOptions{
+		CompletionHandler: NewCompletionHandler(CompletionCandidates{
+			{Type: "ref/prompt", Name: "greet"}:                 {"language": {"English", "French", "German"}},
+			{Type: "ref/resource", URI: "file:///{dir}/{name}"}: {"dir": {"src", "docs"}},
+		}),
+	})
+	ct, st := NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	for _, test := range []struct {
+		ref  *CompleteReference
+		arg  CompleteParamsArgument
+		want []string
+	}{
+		{&CompleteReference{Type: "ref/prompt", Name: "greet"}, CompleteParamsArgument{Name: "language", Value: "e"}, []string{"English", "German", "French"}},
+		{&CompleteReference{Type: "ref/prompt", Name: "greet"}, CompleteParamsArgument{Name: "language", Value: "fr"}, []string{"French"}},
+		{&CompleteReference{Type: "ref/resource", URI: "file:///{dir}/{name}"}, CompleteParamsArgument{Name: "dir", Value: "d"}, []string{"docs"}},
+		{&CompleteReference{Type: "ref/resource", URI: "file:///{dir}/{name}"}, CompleteParamsArgument{Name: "name", Value: ""}, []string{}},
+		{&CompleteReference{Type: "ref/prompt", Name: "other"}, CompleteParamsArgument{Name: "language", Value: ""}, []string{}},
+	} {
+		res, err := cs.Complete(ctx, &CompleteParams{Ref: test.ref, Argument: test.arg})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(res.Completion.Values, test.want) {
+			t.Errorf("Complete(%+v, %+v): got %q, want %q", test.ref, test.arg, res.Completion.Values, test.want)
+		}
+	}
+}
+
+func TestPromptCompletion(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(testImpl, nil)
+	s.AddPromptWithCompletion(&Prompt{
+		Name: "greet",
+		Arguments: []*PromptArgument{
+			{Name: "language"},
+			{Name: "name"},
+			{Name: "greeting"},
+		},
+	}, func(context.Context, *GetPromptRequest) (*GetPromptResult, error) {
+		return &GetPromptResult{}, nil
+	}, func(_ context.Context, req *CompleteRequest) (*CompleteResult, error) {
+		var values []string
+		switch req.Params.Argument.Name {
+		case "language":
+			values = []string{"English", "French", "German"}
+		case "name":
+			// Complete names depending on the language already chosen.
+			values = []string{"Alice", "Bob"}
+			if req.Params.Context != nil && req.Params.Context.Arguments["language"] == "French" {
+				values = []string{"Amélie", "Benoît"}
+			}
+		default:
+			return nil, nil
+		}
+		return &CompleteResult{Completion: CompleteValues(req.Params.Argument.Value, values)}, nil
+	})
+	s.AddPrompt(&Prompt{Name: "plain"}, func(context.Context, *GetPromptRequest) (*GetPromptResult, error) {
+		return &GetPromptResult{}, nil
+	})
+	ct, st := NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	if cs.InitializeResult().Capabilities.Completions == nil {
+		t.Error("completions capability not advertised")
+	}
+	ref := &CompleteReference{Type: "ref/prompt", Name: "greet"}
+	for _, test := range []struct {
+		params *CompleteParams
+		want   []string
+	}{
+		{&CompleteParams{Ref: ref, Argument: CompleteParamsArgument{Name: "language", Value: "g"}}, []string{"German", "English"}},
+		{&CompleteParams{Ref: ref, Argument: CompleteParamsArgument{Name: "name", Value: "a"}}, []string{"Alice"}},
+		{&CompleteParams{
+			Ref:      ref,
+			Argument: CompleteParamsArgument{Name: "name", Value: "a"},
+			Context:  &CompleteContext{Arguments: map[string]string{"language": "French"}},
+		}, []string{"Amélie"}},
+	} {
+		res, err := cs.Complete(ctx, test.params)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(res.Completion.Values, test.want) {
+			t.Errorf("Complete(%q, %q): got %q, want %q", test.params.Argument.Name, test.params.Argument.Value, res.Completion.Values, test.want)
+		}
+	}
+
+	// Requests that no handler completes have no values, since the server
+	// advertises completions: arguments that the prompt's handler declines,
+	// with no CompletionHandler to fall back to, resources, and prompts
+	// without a completion handler.
+	for _, params := range []*CompleteParams{
+		{Ref: ref, Argument: CompleteParamsArgument{Name: "greeting"}},
+		{Ref: &CompleteReference{Type: "ref/resource", URI: "file:///{path}"}, Argument: CompleteParamsArgument{Name: "path"}},
+		{Ref: &CompleteReference{Type: "ref/prompt", Name: "plain"}, Argument: CompleteParamsArgument{Name: "x"}},
+	} {
+		res, err := cs.Complete(ctx, params)
+		if err != nil {
+			t.Errorf("Complete(%+v): %v", params.Ref, err)
+			continue
+		}
+		if res.Completion.Values == nil || len(res.Completion.Values) != 0 {
+			t.Errorf("Complete(%+v): got %q, want no values", params.Ref, res.Completion.Values)
+		}
+	}
+}
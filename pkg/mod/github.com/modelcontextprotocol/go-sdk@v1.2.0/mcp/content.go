@@ -11,6 +11,14 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+	"unicode/utf8"
 )
 
 // A Content is a [TextContent], [ImageContent], [AudioContent],
@@ -287,3 +295,139 @@ func contentFromWire(wire *wireContent, allow map[string]bool) (Content, error)
 	}
 	return nil, fmt.Errorf("internal error: unrecognized content type %s", wire.Type)
 }
+
+// MaxContentSize is the maximum size of the data read by the content
+// construction helpers, such as [ImageContentFromFile] and
+// [ResourceContentsFromReader]. Larger data is rejected, since it is
+// base64-encoded into a single JSON-RPC message.
+const MaxContentSize = 10 << 20
+
+// NewImageContent returns an [ImageContent] holding data, whose MIME type is
+// detected from its contents. It returns an error if data is not an image
+// or is larger than [MaxContentSize].
+func NewImageContent(data []byte) (*ImageContent, error) {
+	mimeType, err := sniffMedia(data, "image")
+	if err != nil {
+		return nil, err
+	}
+	return &ImageContent{Data: data, MIMEType: mimeType}, nil
+}
+
+// ImageContentFromFile returns an [ImageContent] holding the contents of the
+// named file, as by [NewImageContent].
+func ImageContentFromFile(name string) (*ImageContent, error) {
+	data, err := readContentFile(name)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewImageContent(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return c, nil
+}
+
+// NewAudioContent returns an [AudioContent] holding data, whose MIME type is
+// detected from its contents. It returns an error if data is not audio
+// or is larger than [MaxContentSize].
+func NewAudioContent(data []byte) (*AudioContent, error) {
+	mimeType, err := sniffMedia(data, "audio")
+	if err != nil {
+		return nil, err
+	}
+	return &AudioContent{Data: data, MIMEType: mimeType}, nil
+}
+
+// AudioContentFromFile returns an [AudioContent] holding the contents of the
+// named file, as by [NewAudioContent].
+func AudioContentFromFile(name string) (*AudioContent, error) {
+	data, err := readContentFile(name)
+	if err != nil {
+		return nil, err
+	}
+	c, err := NewAudioContent(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	return c, nil
+}
+
+// ResourceContentsFromReader reads r to its end and returns the
+// [ResourceContents] for the resource with the given URI.
+//
+// The MIME type is derived from the extension of the URI's path, or, failing
+// that, detected from the data. Textual data is stored in the Text field,
+// and other data in the Blob field. It returns an error if the data is larger
+// than [MaxContentSize].
+func ResourceContentsFromReader(r io.Reader, uri string) (*ResourceContents, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxContentSize {
+		return nil, errContentTooLarge
+	}
+	mimeType := ""
+	if u, err := url.Parse(uri); err == nil {
+		mimeType = mime.TypeByExtension(path.Ext(u.Path))
+	}
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+	// Drop parameters such as "charset=utf-8": the text is always UTF-8.
+	mimeType, _, _ = strings.Cut(mimeType, ";")
+	rc := &ResourceContents{URI: uri, MIMEType: mimeType}
+	if isTextMIMEType(mimeType) && utf8.Valid(data) {
+		rc.Text = string(data)
+	} else {
+		rc.Blob = data
+	}
+	return rc, nil
+}
+
+var errContentTooLarge = fmt.Errorf("content exceeds %d bytes", MaxContentSize)
+
+func readContentFile(name string) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, MaxContentSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxContentSize {
+		return nil, fmt.Errorf("%s: %w", name, errContentTooLarge)
+	}
+	return data, nil
+}
+
+// sniffMedia returns the MIME type of data, which must be of the given
+// top-level media type ("image" or "audio").
+func sniffMedia(data []byte, mediaType string) (string, error) {
+	if len(data) > MaxContentSize {
+		return "", errContentTooLarge
+	}
+	mimeType := http.DetectContentType(data)
+	// Normalize the types that DetectContentType reports differently from
+	// common usage.
+	switch mimeType {
+	case "application/ogg":
+		mimeType = "audio/ogg"
+	case "audio/wave":
+		mimeType = "audio/wav"
+	}
+	if !strings.HasPrefix(mimeType, mediaType+"/") {
+		return "", fmt.Errorf("data has MIME type %s, want %s/*", mimeType, mediaType)
+	}
+	return mimeType, nil
+}
+
+// isTextMIMEType reports whether the MIME type t describes text.
+func isTextMIMEType(t string) bool {
+	return strings.HasPrefix(t, "text/") ||
+		strings.HasSuffix(t, "/json") || strings.HasSuffix(t, "+json") ||
+		strings.HasSuffix(t, "/xml") || strings.HasSuffix(t, "+xml") ||
+		t == "application/javascript" || t == "application/yaml"
+}
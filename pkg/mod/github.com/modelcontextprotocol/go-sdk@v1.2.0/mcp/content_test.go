@@ -5,8 +5,12 @@
 package mcp_test
 
 import (
+	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
@@ -243,3 +247,61 @@ func TestContentUnmarshal(t *testing.T) {
 		})
 	}
 }
+
+func TestContentHelpers(t *testing.T) {
+	// Minimal 1x1 PNG image and WAV file.
+	png, _ := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUhEUgAAAAEAAAABCAYAAAAfFcSJAAAADUlEQVR42mP8z8DwHwAFBQIAX8jx0gAAAABJRU5ErkJggg==")
+	wav, _ := base64.StdEncoding.DecodeString("UklGRiYAAABXQVZFZm10IBAAAAABAAEAQB8AAAB9AAACABAAZGF0YQIAAAA=")
+
+	dir := t.TempDir()
+	pngFile := filepath.Join(dir, "red.png")
+	if err := os.WriteFile(pngFile, png, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	img, err := mcp.ImageContentFromFile(pngFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&mcp.ImageContent{Data: png, MIMEType: "image/png"}, img); diff != "" {
+		t.Errorf("ImageContentFromFile mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := mcp.ImageContentFromFile(filepath.Join(dir, "missing.png")); err == nil {
+		t.Error("ImageContentFromFile of missing file: got nil error")
+	}
+
+	audio, err := mcp.NewAudioContent(wav)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(&mcp.AudioContent{Data: wav, MIMEType: "audio/wav"}, audio); diff != "" {
+		t.Errorf("NewAudioContent mismatch (-want +got):\n%s", diff)
+	}
+	if _, err := mcp.NewAudioContent(png); err == nil {
+		t.Error("NewAudioContent of image: got nil error")
+	}
+	if _, err := mcp.NewImageContent(make([]byte, mcp.MaxContentSize+1)); err == nil {
+		t.Error("NewImageContent of oversized data: got nil error")
+	}
+
+	for _, test := range []struct {
+		uri  string
+		data []byte
+		want *mcp.ResourceContents
+	}{
+		{"file:///a/b.json", []byte(`{"x":1}`), &mcp.ResourceContents{URI: "file:///a/b.json", MIMEType: "application/json", Text: `{"x":1}`}},
+		{"file:///a/b", []byte("hello"), &mcp.ResourceContents{URI: "file:///a/b", MIMEType: "text/plain", Text: "hello"}},
+		{"file:///a/red", png, &mcp.ResourceContents{URI: "file:///a/red", MIMEType: "image/png", Blob: png}},
+		{"file:///a/bad.txt", []byte{0xff, 0xfe}, &mcp.ResourceContents{URI: "file:///a/bad.txt", MIMEType: "text/plain", Blob: []byte{0xff, 0xfe}}},
+	} {
+		got, err := mcp.ResourceContentsFromReader(bytes.NewReader(test.data), test.uri)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(test.want, got); diff != "" {
+			t.Errorf("ResourceContentsFromReader(%s) mismatch (-want +got):\n%s", test.uri, diff)
+		}
+	}
+	if _, err := mcp.ResourceContentsFromReader(bytes.NewReader(make([]byte, mcp.MaxContentSize+1)), "file:///big"); err == nil {
+		t.Error("ResourceContentsFromReader of oversized data: got nil error")
+	}
+}
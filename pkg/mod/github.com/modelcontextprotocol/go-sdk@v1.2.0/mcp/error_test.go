@@ -8,6 +8,8 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"reflect"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -357,3 +359,85 @@ func TestURLElicitationRequired(t *testing.T) {
 		})
 	})
 }
+
+func TestTypedErrors(t *testing.T) {
+	// roundTrip returns err as the peer sees it.
+	roundTrip := func(err error) error {
+		data, merr := json.Marshal(err)
+		if merr != nil {
+			t.Fatal(merr)
+		}
+		var wireErr jsonrpc.Error
+		if err := json.Unmarshal(data, &wireErr); err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Errorf("calling method: %w", &wireErr)
+	}
+
+	err := roundTrip(ResourceNotFoundError("file:///a.txt"))
+	if got, ok := ParseResourceNotFoundError(err); !ok || got.URI != "file:///a.txt" {
+		t.Errorf("ParseResourceNotFoundError: got %+v, %t", got, ok)
+	}
+	if _, ok := ParseTooManyRequestsError(err); ok {
+		t.Error("ParseTooManyRequestsError of resource not found error: got true")
+	}
+
+	err = roundTrip(URLElicitationRequiredError([]*ElicitParams{{Mode: "url", URL: "https://example.com", ElicitationID: "e1"}}))
+	if got, ok := ParseURLElicitationRequiredError(err); !ok || len(got.Elicitations) != 1 || got.Elicitations[0].ElicitationID != "e1" {
+		t.Errorf("ParseURLElicitationRequiredError: got %+v, %t", got, ok)
+	}
+
+	err = roundTrip(TooManyRequestsError(3))
+	if got, ok := ParseTooManyRequestsError(err); !ok || got.Limit != 3 {
+		t.Errorf("ParseTooManyRequestsError: got %+v, %t", got, ok)
+	}
+
+	err = roundTrip(UnsupportedProtocolVersionError("1999-01-01", []string{"2025-06-18"}))
+	want := &UnsupportedProtocolVersionErrorData{Supported: []string{"2025-06-18"}, Requested: "1999-01-01"}
+	if got, ok := ParseUnsupportedProtocolVersionError(err); !ok || !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseUnsupportedProtocolVersionError: got %+v, %t; want %+v", got, ok, want)
+	}
+	// Other invalid params errors are not protocol version errors.
+	if _, ok := ParseUnsupportedProtocolVersionError(roundTrip(jsonrpc.NewError(jsonrpc.CodeInvalidParams, "bad", nil))); ok {
+		t.Error("ParseUnsupportedProtocolVersionError of plain invalid params error: got true")
+	}
+	if _, ok := ParseResourceNotFoundError(errors.New("not found")); ok {
+		t.Error("ParseResourceNotFoundError of non-JSON-RPC error: got true")
+	}
+}
+
+func TestTypedErrorsOverWire(t *testing.T) {
+	ctx := context.Background()
+	cs, _, cleanup := basicConnection(t, func(s *Server) {
+		s.AddResource(&Resource{URI: "file:///missing.txt", Name: "missing"}, func(_ context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+			return nil, ResourceNotFoundError(req.Params.URI)
+		})
+	})
+	defer cleanup()
+
+	_, err := cs.ReadResource(ctx, &ReadResourceParams{URI: "file:///missing.txt"})
+	if got, ok := ParseResourceNotFoundError(err); !ok || got.URI != "file:///missing.txt" {
+		t.Errorf("ParseResourceNotFoundError(%v): got %+v, %t", err, got, ok)
+	}
+
+	// A server may reject the client's protocol version.
+	ct, st := NewInMemoryTransports()
+	server := NewServer(testImpl, nil)
+	server.AddReceivingMiddleware(func(h MethodHandler) MethodHandler {
+		return func(ctx context.Context, method string, req Request) (Result, error) {
+			if method == methodInitialize {
+				return nil, UnsupportedProtocolVersionError(req.GetParams().(*InitializeParams).ProtocolVersion, []string{protocolVersion20250618})
+			}
+			return h(ctx, method, req)
+		}
+	})
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	_, err = NewClient(testImpl, nil).Connect(ctx, ct, &ClientSessionOptions{protocolVersion: protocolVersion20250326})
+	if got, ok := ParseUnsupportedProtocolVersionError(err); !ok || got.Requested != protocolVersion20250326 {
+		t.Errorf("ParseUnsupportedProtocolVersionError(%v): got %+v, %t", err, got, ok)
+	}
+}
@@ -0,0 +1,414 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file contains support for experimental capabilities and methods:
+// extensions to the protocol that peers negotiate through the "experimental"
+// field of their capabilities.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// ExperimentalParams are the parameters of an experimental method. Since
+// experimental methods are not part of the protocol, their parameters are
+// held as raw JSON.
+type ExperimentalParams struct {
+	// Raw is the JSON encoding of the parameters. If empty, the parameters
+	// are sent as an empty object.
+	Raw json.RawMessage
+}
+
+func (*ExperimentalParams) isParams() {}
+
+// GetMeta returns the "_meta" field of the parameters, if they are a JSON
+// object.
+func (p *ExperimentalParams) GetMeta() map[string]any { return getRawMeta(p.Raw) }
+
+// SetMeta sets the "_meta" field of the parameters. It has no effect if the
+// parameters are not a JSON object.
+func (p *ExperimentalParams) SetMeta(m map[string]any) { p.Raw = setRawMeta(p.Raw, m) }
+
+func (p *ExperimentalParams) MarshalJSON() ([]byte, error) { return marshalRaw(p.Raw), nil }
+
+func (p *ExperimentalParams) UnmarshalJSON(data []byte) error {
+	p.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+// ExperimentalResult is the result of an experimental method, held as raw
+// JSON.
+type ExperimentalResult struct {
+	// Raw is the JSON encoding of the result. If empty, the result is sent as
+	// an empty object.
+	Raw json.RawMessage
+}
+
+func (*ExperimentalResult) isResult() {}
+
+// GetMeta returns the "_meta" field of the result, if it is a JSON object.
+func (r *ExperimentalResult) GetMeta() map[string]any { return getRawMeta(r.Raw) }
+
+// SetMeta sets the "_meta" field of the result. It has no effect if the
+// result is not a JSON object.
+func (r *ExperimentalResult) SetMeta(m map[string]any) { r.Raw = setRawMeta(r.Raw, m) }
+
+func (r *ExperimentalResult) MarshalJSON() ([]byte, error) { return marshalRaw(r.Raw), nil }
+
+func (r *ExperimentalResult) UnmarshalJSON(data []byte) error {
+	r.Raw = append(json.RawMessage(nil), data...)
+	return nil
+}
+
+func marshalRaw(raw json.RawMessage) []byte {
+	if len(raw) == 0 {
+		return []byte("{}")
+	}
+	return raw
+}
+
+func getRawMeta(raw json.RawMessage) map[string]any {
+	var x struct {
+		Meta map[string]any `json:"_meta"`
+	}
+	// Ignore the error: raw need not be an object.
+	_ = json.Unmarshal(raw, &x)
+	return x.Meta
+}
+
+func setRawMeta(raw json.RawMessage, meta map[string]any) json.RawMessage {
+	obj := make(map[string]json.RawMessage)
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &obj); err != nil || obj == nil {
+			return raw
+		}
+	}
+	if meta == nil {
+		delete(obj, "_meta")
+	} else {
+		m, err := json.Marshal(meta)
+		if err != nil {
+			return raw
+		}
+		obj["_meta"] = m
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return raw
+	}
+	return data
+}
+
+// HasExperimental reports whether the capabilities include the named
+// experimental capability.
+func (c *ClientCapabilities) HasExperimental(name string) bool {
+	_, ok := c.Experimental[name]
+	return ok
+}
+
+// SetExperimental declares the named experimental capability, with the given
+// settings. If settings is nil, the capability is declared with an empty
+// object.
+func (c *ClientCapabilities) SetExperimental(name string, settings any) {
+	c.Experimental = setExperimental(c.Experimental, name, settings)
+}
+
+// HasExperimental reports whether the capabilities include the named
+// experimental capability.
+func (c *ServerCapabilities) HasExperimental(name string) bool {
+	_, ok := c.Experimental[name]
+	return ok
+}
+
+// SetExperimental declares the named experimental capability, with the given
+// settings. If settings is nil, the capability is declared with an empty
+// object.
+func (c *ServerCapabilities) SetExperimental(name string, settings any) {
+	c.Experimental = setExperimental(c.Experimental, name, settings)
+}
+
+func setExperimental(m map[string]any, name string, settings any) map[string]any {
+	if m == nil {
+		m = make(map[string]any)
+	}
+	if settings == nil {
+		settings = map[string]any{}
+	}
+	m[name] = settings
+	return m
+}
+
+// HasExperimental reports whether the server advertised the named
+// experimental capability during initialization.
+func (cs *ClientSession) HasExperimental(name string) bool {
+	res := cs.InitializeResult()
+	return res != nil && res.Capabilities != nil && res.Capabilities.HasExperimental(name)
+}
+
+// HasExperimental reports whether the client advertised the named
+// experimental capability during initialization.
+func (ss *ServerSession) HasExperimental(name string) bool {
+	params := ss.InitializeParams()
+	return params != nil && params.Capabilities != nil && params.Capabilities.HasExperimental(name)
+}
+
+// AddExperimentalMethod registers a handler for the named experimental
+// method, which the server's peers may call once it is added.
+//
+// If the method name begins with "notifications/", it is a notification: the
+// result of h is ignored. Otherwise it is a request, and a nil result is sent
+// as an empty object.
+//
+// AddExperimentalMethod panics if method is a standard MCP method. Adding a
+// method that has already been added replaces its handler.
+func (s *Server) AddExperimentalMethod(method string, h func(context.Context, *ServerRequest[*ExperimentalParams]) (*ExperimentalResult, error)) {
+	if _, ok := serverMethodInfos[method]; ok {
+		panic(fmt.Sprintf("AddExperimentalMethod: %q is a standard method", method))
+	}
+	flags := experimentalMethodFlags(method)
+	info := experimentalHandlerInfo(newServerMethodInfo(typedServerMethodHandler[*ExperimentalParams, *ExperimentalResult](h), flags))
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.receivingMethodInfos_ = addExperimentalMethodInfo(s.receivingMethodInfos_, serverMethodInfos, method, info)
+}
+
+// AddExperimentalMethod registers a handler for the named experimental
+// method, which the client's peers may call once it is added.
+//
+// If the method name begins with "notifications/", it is a notification: the
+// result of h is ignored. Otherwise it is a request, and a nil result is sent
+// as an empty object.
+//
+// AddExperimentalMethod panics if method is a standard MCP method. Adding a
+// method that has already been added replaces its handler.
+func (c *Client) AddExperimentalMethod(method string, h func(context.Context, *ClientRequest[*ExperimentalParams]) (*ExperimentalResult, error)) {
+	if _, ok := clientMethodInfos[method]; ok {
+		panic(fmt.Sprintf("AddExperimentalMethod: %q is a standard method", method))
+	}
+	flags := experimentalMethodFlags(method)
+	info := experimentalHandlerInfo(newClientMethodInfo(typedClientMethodHandler[*ExperimentalParams, *ExperimentalResult](h), flags))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.receivingMethodInfos_ = addExperimentalMethodInfo(c.receivingMethodInfos_, clientMethodInfos, method, info)
+}
+
+func experimentalMethodFlags(method string) methodFlags {
+	if strings.HasPrefix(method, "notifications/") {
+		return notification | missingParamsOK
+	}
+	return missingParamsOK
+}
+
+// experimentalHandlerInfo adjusts the methodInfo for an experimental method
+// handler. Params are never nil, so that handlers need not check; the results
+// of notifications are dropped; and nil results of requests are sent as empty
+// objects.
+func experimentalHandlerInfo(info methodInfo) methodInfo {
+	info.unmarshalParams = func(_ JSONCodec, m json.RawMessage) (Params, error) {
+		return &ExperimentalParams{Raw: m}, nil
+	}
+	handle := info.handleMethod
+	info.handleMethod = func(ctx context.Context, method string, req Request) (Result, error) {
+		res, err := handle(ctx, method, req)
+		if err != nil || info.flags&notification != 0 {
+			return nil, err
+		}
+		if res.(*ExperimentalResult) == nil {
+			res = &ExperimentalResult{}
+		}
+		return res, nil
+	}
+	return info
+}
+
+// addExperimentalMethodInfo returns a copy of infos (or of std, if infos is
+// nil) with the given method added. Copying lets sessions use the map
+// without holding a lock.
+func addExperimentalMethodInfo(infos, std map[string]methodInfo, method string, info methodInfo) map[string]methodInfo {
+	if infos == nil {
+		infos = std
+	}
+	infos = maps.Clone(infos)
+	infos[method] = info
+	return infos
+}
+
+// experimentalMethodInfo is used to send experimental methods, which have no
+// entry in the method tables.
+var experimentalMethodInfo = newMethodInfo[*ExperimentalParams, *ExperimentalResult](missingParamsOK)
+
+// checkExperimentalMethod reports an error if method cannot be sent as an
+// experimental method, because it is a standard method or is of the wrong
+// kind.
+func checkExperimentalMethod(method string, infos map[string]methodInfo, isNotification bool) error {
+	if _, ok := infos[method]; ok {
+		return fmt.Errorf("%q is a standard method", method)
+	}
+	if isNotification != strings.HasPrefix(method, "notifications/") {
+		if isNotification {
+			return fmt.Errorf("notification %q must begin with \"notifications/\"", method)
+		}
+		return fmt.Errorf("request %q must not begin with \"notifications/\"", method)
+	}
+	return nil
+}
+
+// CallExperimental calls the named experimental method on the server.
+//
+// The server must have registered a handler for the method, and typically
+// advertises that it does with an experimental capability: see
+// [ClientSession.HasExperimental].
+func (cs *ClientSession) CallExperimental(ctx context.Context, method string, params *ExperimentalParams) (*ExperimentalResult, error) {
+	if err := checkExperimentalMethod(method, cs.sendingMethodInfos(), false); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = &ExperimentalParams{}
+	}
+	return handleSend[*ExperimentalResult](ctx, method, newClientRequest(cs, params))
+}
+
+// NotifyExperimental sends the named experimental notification to the
+// server. The method name must begin with "notifications/".
+func (cs *ClientSession) NotifyExperimental(ctx context.Context, method string, params *ExperimentalParams) error {
+	if err := checkExperimentalMethod(method, cs.sendingMethodInfos(), true); err != nil {
+		return err
+	}
+	if params == nil {
+		params = &ExperimentalParams{}
+	}
+	return handleNotify(ctx, method, newClientRequest(cs, params))
+}
+
+// CallExperimental calls the named experimental method on the client.
+//
+// The client must have registered a handler for the method, and typically
+// advertises that it does with an experimental capability: see
+// [ServerSession.HasExperimental].
+func (ss *ServerSession) CallExperimental(ctx context.Context, method string, params *ExperimentalParams) (*ExperimentalResult, error) {
+	if err := checkExperimentalMethod(method, ss.sendingMethodInfos(), false); err != nil {
+		return nil, err
+	}
+	if params == nil {
+		params = &ExperimentalParams{}
+	}
+	return handleSend[*ExperimentalResult](ctx, method, newServerRequest(ss, params))
+}
+
+// NotifyExperimental sends the named experimental notification to the
+// client. The method name must begin with "notifications/".
+func (ss *ServerSession) NotifyExperimental(ctx context.Context, method string, params *ExperimentalParams) error {
+	if err := checkExperimentalMethod(method, ss.sendingMethodInfos(), true); err != nil {
+		return err
+	}
+	if params == nil {
+		params = &ExperimentalParams{}
+	}
+	return handleNotify(ctx, method, newServerRequest(ss, params))
+}
+
+// ExperimentalHandler adapts a handler with typed params and results for
+// [Server.AddExperimentalMethod] or [Client.AddExperimentalMethod], for
+// vendor extensions and the like.
+//
+// The params of incoming requests are unmarshaled into an In; missing params
+// leave it as the zero value, and params that fail to unmarshal are rejected
+// with an invalid params error. The Out returned by h is marshaled as the
+// result, which must be a JSON object or null. For notifications, the result
+// is ignored.
+func ExperimentalHandler[R experimentalRequest, In, Out any](h func(context.Context, R, In) (Out, error)) func(context.Context, R) (*ExperimentalResult, error) {
+	return func(ctx context.Context, req R) (*ExperimentalResult, error) {
+		codec := req.GetSession().jsonCodec()
+		var in In
+		if p := req.GetParams().(*ExperimentalParams); len(p.Raw) > 0 {
+			if err := codec.Unmarshal(p.Raw, &in); err != nil {
+				return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: fmt.Sprintf("invalid params: %v", err)}
+			}
+		}
+		out, err := h(ctx, req, in)
+		if err != nil {
+			return nil, err
+		}
+		data, err := marshalObject(codec, out)
+		if err != nil {
+			return nil, fmt.Errorf("marshaling result: %w", err)
+		}
+		return &ExperimentalResult{Raw: data}, nil
+	}
+}
+
+// experimentalRequest is the type of the requests passed to handlers of
+// experimental methods.
+type experimentalRequest interface {
+	*ServerRequest[*ExperimentalParams] | *ClientRequest[*ExperimentalParams]
+	Request
+}
+
+// experimentalSession is the type of the sessions that can call experimental
+// methods.
+type experimentalSession interface {
+	*ClientSession | *ServerSession
+	Session
+	CallExperimental(context.Context, string, *ExperimentalParams) (*ExperimentalResult, error)
+	NotifyExperimental(context.Context, string, *ExperimentalParams) error
+}
+
+// CallExperimentalMethod is like [ClientSession.CallExperimental] or
+// [ServerSession.CallExperimental], but marshals params, which must be a
+// JSON object or nil, as JSON, and unmarshals the result into an Out.
+func CallExperimentalMethod[Out any, S experimentalSession](ctx context.Context, session S, method string, params any) (Out, error) {
+	var out Out
+	data, err := marshalObject(session.jsonCodec(), params)
+	if err != nil {
+		return out, fmt.Errorf("marshaling params: %w", err)
+	}
+	res, err := session.CallExperimental(ctx, method, &ExperimentalParams{Raw: data})
+	if err != nil {
+		return out, err
+	}
+	if err := session.jsonCodec().Unmarshal(marshalRaw(res.Raw), &out); err != nil {
+		return out, fmt.Errorf("unmarshaling result of %q: %w", method, err)
+	}
+	return out, nil
+}
+
+// NotifyExperimentalMethod is like [ClientSession.NotifyExperimental] or
+// [ServerSession.NotifyExperimental], but marshals params, which must be a
+// JSON object or nil, as JSON.
+func NotifyExperimentalMethod[S experimentalSession](ctx context.Context, session S, method string, params any) error {
+	data, err := marshalObject(session.jsonCodec(), params)
+	if err != nil {
+		return fmt.Errorf("marshaling params: %w", err)
+	}
+	return session.NotifyExperimental(ctx, method, &ExperimentalParams{Raw: data})
+}
+
+// marshalObject marshals v, which must encode as a JSON object or null. It
+// returns nil for null.
+func marshalObject(codec JSONCodec, v any) (json.RawMessage, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := codec.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	switch data = bytes.TrimSpace(data); {
+	case string(data) == "null":
+		return nil, nil
+	case len(data) == 0 || data[0] != '{':
+		return nil, fmt.Errorf("%T is not a JSON object", v)
+	}
+	return data, nil
+}
@@ -0,0 +1,237 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+func TestExperimental(t *testing.T) {
+	ctx := context.Background()
+
+	serverCaps := &ServerCapabilities{}
+	serverCaps.SetExperimental("x-echo/v1", nil)
+	server := NewServer(testImpl, &ServerOptions{Capabilities: serverCaps})
+	server.AddExperimentalMethod("x-echo/echo", func(_ context.Context, req *ServerRequest[*ExperimentalParams]) (*ExperimentalResult, error) {
+		return &ExperimentalResult{Raw: req.Params.Raw}, nil
+	})
+	notified := make(chan string, 1)
+	server.AddExperimentalMethod("notifications/x-echo/hello", func(_ context.Context, req *ServerRequest[*ExperimentalParams]) (*ExperimentalResult, error) {
+		notified <- string(req.Params.Raw)
+		return nil, nil
+	})
+
+	clientCaps := &ClientCapabilities{}
+	clientCaps.SetExperimental("x-ping/v1", map[string]any{"depth": 1})
+	client := NewClient(testImpl, &ClientOptions{Capabilities: clientCaps})
+	client.AddExperimentalMethod("x-ping/ping", func(context.Context, *ClientRequest[*ExperimentalParams]) (*ExperimentalResult, error) {
+		return nil, nil
+	})
+
+	for _, test := range []struct {
+		name    string
+		connect func(t *testing.T) (*ClientSession, *ServerSession)
+	}{
+		{"in-memory", func(t *testing.T) (*ClientSession, *ServerSession) {
+			ct, st := NewInMemoryTransports()
+			ss, err := server.Connect(ctx, st, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { ss.Close() })
+			cs, err := client.Connect(ctx, ct, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { cs.Close() })
+			return cs, ss
+		}},
+		{"streamable", func(t *testing.T) (*ClientSession, *ServerSession) {
+			httpServer := httptest.NewServer(NewStreamableHTTPHandler(func(*http.Request) *Server { return server }, nil))
+			t.Cleanup(httpServer.Close)
+			cs, err := client.Connect(ctx, &StreamableClientTransport{Endpoint: httpServer.URL}, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { cs.Close() })
+			server.mu.Lock()
+			defer server.mu.Unlock()
+			return cs, server.sessions[len(server.sessions)-1]
+		}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			cs, ss := test.connect(t)
+
+			if !cs.HasExperimental("x-echo/v1") || cs.HasExperimental("x-ping/v1") {
+				t.Errorf("client session: wrong experimental capabilities %v", cs.InitializeResult().Capabilities.Experimental)
+			}
+			if !ss.HasExperimental("x-ping/v1") || ss.HasExperimental("x-echo/v1") {
+				t.Errorf("server session: wrong experimental capabilities %v", ss.InitializeParams().Capabilities.Experimental)
+			}
+
+			res, err := cs.CallExperimental(ctx, "x-echo/echo", &ExperimentalParams{Raw: json.RawMessage(`{"x":1}`)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(`{"x":1}`, string(res.Raw)); diff != "" {
+				t.Errorf("CallExperimental mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := cs.NotifyExperimental(ctx, "notifications/x-echo/hello", &ExperimentalParams{Raw: json.RawMessage(`"hi"`)}); err != nil {
+				t.Fatal(err)
+			}
+			if got := <-notified; got != `"hi"` {
+				t.Errorf("notification params: got %s, want \"hi\"", got)
+			}
+
+			res, err = ss.CallExperimental(ctx, "x-ping/ping", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := string(res.Raw); got != "{}" {
+				t.Errorf("ping result: got %s, want {}", got)
+			}
+		})
+	}
+}
+
+func TestExperimentalMethodErrors(t *testing.T) {
+	ctx := context.Background()
+	ct, st := NewInMemoryTransports()
+	ss, err := NewServer(testImpl, nil).Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	if _, err := cs.CallExperimental(ctx, "x-foo/unknown", nil); err == nil {
+		t.Error("calling unregistered method: got nil error, want error")
+	}
+	if _, err := cs.CallExperimental(ctx, methodListTools, nil); err == nil {
+		t.Error("calling standard method: got nil error, want error")
+	}
+	if _, err := cs.CallExperimental(ctx, "notifications/x-foo", nil); err == nil {
+		t.Error("calling notification: got nil error, want error")
+	}
+	if err := cs.NotifyExperimental(ctx, "x-foo", nil); err == nil {
+		t.Error("notifying request: got nil error, want error")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("adding standard method: did not panic")
+		}
+	}()
+	NewServer(testImpl, nil).AddExperimentalMethod(methodCallTool, nil)
+}
+
+func TestExperimentalParamsMeta(t *testing.T) {
+	p := &ExperimentalParams{Raw: json.RawMessage(`{"a":1}`)}
+	p.SetMeta(map[string]any{"progressToken": "t"})
+	if got := p.GetMeta()["progressToken"]; got != "t" {
+		t.Errorf("GetMeta: got progressToken %v, want t", got)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(p.Raw, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m["a"] != 1.0 {
+		t.Errorf("SetMeta lost params: got %s", p.Raw)
+	}
+	// Non-object params are left alone.
+	p = &ExperimentalParams{Raw: json.RawMessage(`[1]`)}
+	p.SetMeta(map[string]any{"x": 1})
+	if string(p.Raw) != "[1]" || p.GetMeta() != nil {
+		t.Errorf("SetMeta on array: got %s", p.Raw)
+	}
+}
+
+func TestCustomMethods(t *testing.T) {
+	ctx := context.Background()
+
+	type flushParams struct {
+		Buffer string `json:"buffer"`
+	}
+	type flushResult struct {
+		Flushed int `json:"flushed"`
+	}
+	server := NewServer(testImpl, nil)
+	server.AddExperimentalMethod("x-vendor/flush", ExperimentalHandler(func(_ context.Context, _ *ServerRequest[*ExperimentalParams], in flushParams) (*flushResult, error) {
+		if in.Buffer == "" {
+			return nil, errors.New("missing buffer")
+		}
+		return &flushResult{Flushed: len(in.Buffer)}, nil
+	}))
+	server.AddExperimentalMethod("x-vendor/count", ExperimentalHandler(func(context.Context, *ServerRequest[*ExperimentalParams], any) (int, error) {
+		return 1, nil
+	}))
+	client := NewClient(testImpl, nil)
+	flushed := make(chan flushResult, 1)
+	client.AddExperimentalMethod("notifications/x-vendor/flushed", ExperimentalHandler(func(_ context.Context, _ *ClientRequest[*ExperimentalParams], in flushResult) (any, error) {
+		flushed <- in
+		return nil, nil
+	}))
+
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	got, err := CallExperimentalMethod[flushResult](ctx, cs, "x-vendor/flush", flushParams{Buffer: "abc"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (flushResult{Flushed: 3}); got != want {
+		t.Errorf("CallExperimentalMethod: got %+v, want %+v", got, want)
+	}
+
+	// Handler errors and bad params are returned to the caller.
+	if _, err := CallExperimentalMethod[flushResult](ctx, cs, "x-vendor/flush", nil); err == nil || !strings.Contains(err.Error(), "missing buffer") {
+		t.Errorf("CallExperimentalMethod with no params: got error %v, want handler error", err)
+	}
+	var wireErr *jsonrpc.Error
+	if _, err := CallExperimentalMethod[flushResult](ctx, cs, "x-vendor/flush", map[string]any{"buffer": 1}); !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeInvalidParams {
+		t.Errorf("CallExperimentalMethod with bad params: got error %v, want invalid params", err)
+	}
+	// Params and results must be objects.
+	if _, err := CallExperimentalMethod[flushResult](ctx, cs, "x-vendor/flush", []int{1}); err == nil {
+		t.Error("CallExperimentalMethod with array params: got nil error, want error")
+	}
+	if _, err := CallExperimentalMethod[any](ctx, cs, "x-vendor/count", nil); err == nil {
+		t.Error("CallExperimentalMethod with number result: got nil error, want error")
+	}
+	// Unregistered methods are not found.
+	if _, err := CallExperimentalMethod[any](ctx, cs, "x-vendor/other", nil); !errors.As(err, &wireErr) || wireErr.Code != jsonrpc.CodeMethodNotFound {
+		t.Errorf("CallExperimentalMethod of unregistered method: got error %v, want method not found", err)
+	}
+
+	if err := NotifyExperimentalMethod(ctx, ss, "notifications/x-vendor/flushed", flushResult{Flushed: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if got := <-flushed; got.Flushed != 3 {
+		t.Errorf("notification: got %+v, want 3 flushed", got)
+	}
+}
@@ -73,6 +73,10 @@ type LoggingHandlerOptions struct {
 	// Excess messages are dropped.
 	// If zero, there is no rate limiting.
 	MinInterval time.Duration
+	// Fallback, if non-nil, handles records that the handler cannot send to
+	// a client, because it was created without a session and the record's
+	// context does not belong to a request. If nil, such records are dropped.
+	Fallback slog.Handler
 }
 
 // A LoggingHandler is a [slog.Handler] for MCP.
@@ -107,6 +111,14 @@ func ensureLogger(l *slog.Logger) *slog.Logger {
 
 // NewLoggingHandler creates a [LoggingHandler] that logs to the given [ServerSession] using a
 // [slog.JSONHandler].
+//
+// If ss is nil, the handler logs to the session handling the request whose
+// context is passed to the logging call, so that a single handler, such as
+// the one installed with [slog.SetDefault], can serve every session.
+// Code running inside a request handler must then log with a context-aware
+// method like [slog.Logger.InfoContext], passing the handler's context.
+// Records logged with any other context go to
+// [LoggingHandlerOptions.Fallback].
 func NewLoggingHandler(ss *ServerSession, opts *LoggingHandlerOptions) *LoggingHandler {
 	var buf bytes.Buffer
 	jsonHandler := slog.NewJSONHandler(&buf, &slog.HandlerOptions{
@@ -130,20 +142,33 @@ func NewLoggingHandler(ss *ServerSession, opts *LoggingHandlerOptions) *LoggingH
 	return lh
 }
 
+// session returns the session to log to, or nil if there is none.
+func (h *LoggingHandler) session(ctx context.Context) *ServerSession {
+	if h.ss != nil {
+		return h.ss
+	}
+	return sessionFromContext(ctx)
+}
+
 // Enabled implements [slog.Handler.Enabled] by comparing level to the [ServerSession]'s level.
 func (h *LoggingHandler) Enabled(ctx context.Context, level slog.Level) bool {
-	// This is also checked in ServerSession.LoggingMessage, so checking it here
+	ss := h.session(ctx)
+	if ss == nil {
+		return h.opts.Fallback != nil && h.opts.Fallback.Enabled(ctx, level)
+	}
+	// This is also checked in ServerSession.Log, so checking it here
 	// is just an optimization that skips building the JSON.
-	h.ss.mu.Lock()
-	mcpLevel := h.ss.state.LogLevel
-	h.ss.mu.Unlock()
-	return level >= mcpLevelToSlog(mcpLevel)
+	mcpLevel := ss.LogLevel()
+	return mcpLevel != "" && level >= mcpLevelToSlog(mcpLevel)
 }
 
 // WithAttrs implements [slog.Handler.WithAttrs].
 func (h *LoggingHandler) WithAttrs(as []slog.Attr) slog.Handler {
 	h2 := *h
 	h2.handler = h.handler.WithAttrs(as)
+	if h.opts.Fallback != nil {
+		h2.opts.Fallback = h.opts.Fallback.WithAttrs(as)
+	}
 	return &h2
 }
 
@@ -151,6 +176,9 @@ func (h *LoggingHandler) WithAttrs(as []slog.Attr) slog.Handler {
 func (h *LoggingHandler) WithGroup(name string) slog.Handler {
 	h2 := *h
 	h2.handler = h.handler.WithGroup(name)
+	if h.opts.Fallback != nil {
+		h2.opts.Fallback = h.opts.Fallback.WithGroup(name)
+	}
 	return &h2
 }
 
@@ -164,6 +192,14 @@ func (h *LoggingHandler) Handle(ctx context.Context, r slog.Record) error {
 }
 
 func (h *LoggingHandler) handle(ctx context.Context, r slog.Record) error {
+	ss := h.session(ctx)
+	if ss == nil {
+		if h.opts.Fallback != nil {
+			return h.opts.Fallback.Handle(ctx, r)
+		}
+		return nil
+	}
+
 	// Observe the rate limit.
 	// TODO(jba): use golang.org/x/time/rate. (We can't here because it would require adding
 	// golang.org/x/time to the go.mod file.)
@@ -203,5 +239,5 @@ func (h *LoggingHandler) handle(ctx context.Context, r slog.Record) error {
 	// documentation says not to.
 	// In this case logging is a service to clients, not a means for debugging the
 	// server, so we want to cancel the log message.
-	return h.ss.Log(ctx, params)
+	return ss.Log(ctx, params)
 }
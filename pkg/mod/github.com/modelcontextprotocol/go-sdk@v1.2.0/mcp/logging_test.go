@@ -0,0 +1,117 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoggingHandlerFromContext(t *testing.T) {
+	ctx := context.Background()
+
+	var fallback bytes.Buffer
+	logger := slog.New(NewLoggingHandler(nil, &LoggingHandlerOptions{
+		LoggerName: "lib",
+		Fallback:   slog.NewTextHandler(&fallback, nil),
+	}))
+
+	s := NewServer(testImpl, nil)
+	AddTool(s, &Tool{Name: "work"}, func(ctx context.Context, req *CallToolRequest, _ any) (*CallToolResult, any, error) {
+		// Simulate a library that logs with the context it was given.
+		logger.With("tool", req.Params.Name).InfoContext(ctx, "working")
+		logger.DebugContext(ctx, "details") // below the level
+		return &CallToolResult{}, nil, nil
+	})
+	ct, st := NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	msgs := make(chan *LoggingMessageParams, 10)
+	c := NewClient(testImpl, &ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *LoggingMessageRequest) {
+			msgs <- req.Params
+		},
+	})
+	cs, err := c.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "info"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cs.CallTool(ctx, &CallToolParams{Name: "work"}); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case m := <-msgs:
+		if m.Logger != "lib" || m.Level != "info" {
+			t.Errorf("got logger %q, level %q; want lib, info", m.Logger, m.Level)
+		}
+		data, _ := m.Data.(map[string]any)
+		if data["msg"] != "working" || data["tool"] != "work" {
+			t.Errorf("got data %v, want msg=working, tool=work", data)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for log message")
+	}
+	select {
+	case m := <-msgs:
+		t.Errorf("got unexpected log message %+v", m)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// Outside of a request, records go to the fallback.
+	logger.Info("idle")
+	if got := fallback.String(); !strings.Contains(got, "msg=idle") {
+		t.Errorf("fallback output %q does not contain msg=idle", got)
+	}
+}
+
+func TestServerSessionLogLevel(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(testImpl, nil)
+	ct, st := NewInMemoryTransports()
+	ss, err := s.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	if got := ss.LogLevel(); got != "" {
+		t.Errorf("initial LogLevel: got %q, want empty", got)
+	}
+	if ss.LogEnabled("emergency") {
+		t.Error("LogEnabled before SetLoggingLevel: got true, want false")
+	}
+	if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "warning"}); err != nil {
+		t.Fatal(err)
+	}
+	if got := ss.LogLevel(); got != "warning" {
+		t.Errorf("LogLevel: got %q, want warning", got)
+	}
+	for level, want := range map[LoggingLevel]bool{"debug": false, "info": false, "warning": true, "error": true} {
+		if got := ss.LogEnabled(level); got != want {
+			t.Errorf("LogEnabled(%q): got %t, want %t", level, got, want)
+		}
+	}
+	if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "verbose"}); err == nil {
+		t.Error("SetLoggingLevel with unknown level: got nil error, want error")
+	}
+	if got := ss.LogLevel(); got != "warning" {
+		t.Errorf("LogLevel after bad SetLoggingLevel: got %q, want warning", got)
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// A ToolManifest describes the tools of a server: their names, descriptions,
+// schemas and annotations. It marshals to JSON, for publishing to registries
+// or generating documentation.
+type ToolManifest struct {
+	// Server identifies the server that provides the tools, if known.
+	Server *Implementation `json:"server,omitempty"`
+	// Tools are the tools of the server, sorted by name.
+	Tools []*Tool `json:"tools"`
+}
+
+// ToolManifest returns a manifest of the tools currently added to s.
+//
+// The tools in the manifest are those added to s, so they must not be
+// modified.
+func (s *Server) ToolManifest() *ToolManifest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	m := &ToolManifest{Server: s.impl, Tools: []*Tool{}}
+	for st := range s.tools.all() {
+		m.Tools = append(m.Tools, st.tool)
+	}
+	return m
+}
+
+// AddToolsFromManifest adds the tools in m to s, replacing any tools with the
+// same names.
+//
+// Each tool is served by h. If h is nil, the tools are served by a stub
+// handler that reports that the tool is not implemented, which is useful for
+// testing clients and for servers that only need to advertise tools.
+//
+// Unlike [Server.AddTool], AddToolsFromManifest returns an error rather than
+// panicking if a tool is invalid. In that case, no tools are added.
+func (s *Server) AddToolsFromManifest(m *ToolManifest, h ToolHandler) error {
+	if m == nil {
+		return errors.New("AddToolsFromManifest: nil manifest")
+	}
+	for _, t := range m.Tools {
+		if t == nil || t.Name == "" {
+			return errors.New("AddToolsFromManifest: tool without a name")
+		}
+		if err := checkToolSchemas(t); err != nil {
+			return fmt.Errorf("AddToolsFromManifest: tool %q: %v", t.Name, err)
+		}
+	}
+	for _, t := range m.Tools {
+		th := h
+		if th == nil {
+			th = stubToolHandler(t.Name)
+		}
+		s.AddTool(t, th)
+	}
+	return nil
+}
+
+// stubToolHandler returns a handler for the named tool that always
+// reports an error.
+func stubToolHandler(name string) ToolHandler {
+	return func(context.Context, *CallToolRequest) (*CallToolResult, error) {
+		return &CallToolResult{
+			Content: []Content{&TextContent{Text: fmt.Sprintf("tool %q is not implemented", name)}},
+			IsError: true,
+		}, nil
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestToolManifest(t *testing.T) {
+	ctx := context.Background()
+
+	type in struct {
+		Query string `json:"query" jsonschema:"the search query"`
+	}
+	src := NewServer(&Implementation{Name: "src", Version: "v1"}, nil)
+	AddTool(src, &Tool{
+		Name:        "search",
+		Description: "search things",
+		Annotations: &ToolAnnotations{ReadOnlyHint: true},
+	}, func(context.Context, *CallToolRequest, in) (*CallToolResult, any, error) {
+		return &CallToolResult{}, nil, nil
+	})
+	AddTool(src, &Tool{Name: "add"}, func(context.Context, *CallToolRequest, in) (*CallToolResult, any, error) {
+		return &CallToolResult{}, nil, nil
+	})
+
+	data, err := json.Marshal(src.ToolManifest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	var m ToolManifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		t.Fatal(err)
+	}
+	if m.Server == nil || m.Server.Name != "src" {
+		t.Errorf("manifest server: got %+v, want name %q", m.Server, "src")
+	}
+
+	dst := NewServer(testImpl, nil)
+	if err := dst.AddToolsFromManifest(&m, nil); err != nil {
+		t.Fatal(err)
+	}
+	ct, st := NewInMemoryTransports()
+	if _, err := dst.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	res, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Compare the JSON forms, since schemas are unmarshaled as maps.
+	var got, want any
+	gotData, _ := json.Marshal(res.Tools)
+	wantData, _ := json.Marshal(m.Tools)
+	json.Unmarshal(gotData, &got)
+	json.Unmarshal(wantData, &want)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("imported tools mismatch (-want +got):\n%s", diff)
+	}
+
+	cres, err := cs.CallTool(ctx, &CallToolParams{Name: "search", Arguments: map[string]any{"query": "q"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cres.IsError {
+		t.Error("stub handler: got IsError false, want true")
+	}
+}
+
+func TestAddToolsFromManifestErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		m    *ToolManifest
+	}{
+		{"nil manifest", nil},
+		{"no name", &ToolManifest{Tools: []*Tool{{InputSchema: map[string]any{"type": "object"}}}}},
+		{"no schema", &ToolManifest{Tools: []*Tool{{Name: "t"}}}},
+		{"bad schema type", &ToolManifest{Tools: []*Tool{{Name: "t", InputSchema: map[string]any{"type": "string"}}}}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := NewServer(testImpl, nil)
+			if err := s.AddToolsFromManifest(test.m, nil); err == nil {
+				t.Error("got nil error, want error")
+			}
+			if n := s.tools.len(); n != 0 {
+				t.Errorf("got %d tools, want 0", n)
+			}
+		})
+	}
+}
@@ -741,6 +741,64 @@ func TestCancellation(t *testing.T) {
 	}
 }
 
+func TestCancelRequest(t *testing.T) {
+	ctx := context.Background()
+	var (
+		start  = make(chan struct{})
+		causes = make(chan error, 1)
+	)
+	slowTool := func(ctx context.Context, req *CallToolRequest, args any) (*CallToolResult, any, error) {
+		start <- struct{}{}
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return nil, nil, ctx.Err()
+	}
+	server := NewServer(testImpl, nil)
+	AddTool(server, &Tool{Name: "slow", InputSchema: &jsonschema.Schema{Type: "object"}}, slowTool)
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	ids := make(chan jsonrpc.ID, 1)
+	errc := make(chan error, 1)
+	go func() {
+		_, err := cs.CallTool(WithRequestIDFunc(ctx, func(id jsonrpc.ID) { ids <- id }), &CallToolParams{Name: "slow"})
+		errc <- err
+	}()
+	id := <-ids
+	<-start
+	if err := cs.CancelRequest(ctx, id, "user abort"); err != nil {
+		t.Fatal(err)
+	}
+	var cerr *CancelledError
+	if err := <-errc; !errors.As(err, &cerr) || cerr.Reason != "user abort" {
+		t.Errorf("CallTool: got error %v, want CancelledError with reason %q", err, "user abort")
+	}
+	if cause := <-causes; !errors.As(cause, &cerr) || cerr.Reason != "user abort" {
+		t.Errorf("handler context cause: got %v, want CancelledError with reason %q", cause, "user abort")
+	}
+	if err := cs.CancelRequest(ctx, id, ""); err == nil {
+		t.Error("cancelling a finished request: got nil error, want error")
+	}
+
+	// Cancelling the context of a call also gives the handler a cause.
+	cctx, cancel := context.WithCancel(ctx)
+	go cs.CallTool(cctx, &CallToolParams{Name: "slow"})
+	<-start
+	cancel()
+	if cause := <-causes; !errors.As(cause, &cerr) || cerr.Reason != context.Canceled.Error() {
+		t.Errorf("handler context cause: got %v, want CancelledError with reason %q", cause, context.Canceled.Error())
+	}
+}
+
 func TestMiddleware(t *testing.T) {
 	ctx := context.Background()
 	ct, st := NewInMemoryTransports()
@@ -11,7 +11,13 @@ import (
 // A PromptHandler handles a call to prompts/get.
 type PromptHandler func(context.Context, *GetPromptRequest) (*GetPromptResult, error)
 
+// A PromptCompletionHandler handles a completion request for an argument of a
+// prompt. It may return a nil result and error to leave the request to
+// [ServerOptions.CompletionHandler].
+type PromptCompletionHandler func(context.Context, *CompleteRequest) (*CompleteResult, error)
+
 type serverPrompt struct {
-	prompt  *Prompt
-	handler PromptHandler
+	prompt   *Prompt
+	handler  PromptHandler
+	complete PromptCompletionHandler // may be nil
 }
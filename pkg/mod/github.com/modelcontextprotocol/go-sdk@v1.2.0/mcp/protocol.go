@@ -12,6 +12,7 @@ package mcp
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -118,10 +119,21 @@ type CallToolResult struct {
 
 // TODO(#64): consider exposing setError (and getError), by adding an error
 // field on CallToolResult.
+//
+// If err is or wraps a [*ToolError], it is also recorded in the result's
+// _meta. StructuredContent is left unset, since the error need not conform
+// to the tool's output schema.
 func (r *CallToolResult) setError(err error) {
 	r.Content = []Content{&TextContent{Text: err.Error()}}
 	r.IsError = true
 	r.err = err
+	var te *ToolError
+	if errors.As(err, &te) {
+		if r.Meta == nil {
+			r.Meta = Meta{}
+		}
+		r.Meta[toolErrorMetaKey] = te
+	}
 }
 
 // getError returns the error set with setError, or nil if none.
@@ -761,9 +773,7 @@ const (
 )
 
 // Icon provides visual identifiers for their resources, tools, prompts, and implementations
-// See [/specification/draft/basic/index#icons] for notes on icons
-//
-// TODO(iamsurajbobade): update specification url from draft.
+// See https://modelcontextprotocol.io/specification/2025-11-25/basic/index#icons for notes on icons.
 type Icon struct {
 	// Source is A URI pointing to the icon resource (required). This can be:
 	// - An HTTP/HTTPS URL pointing to an image file
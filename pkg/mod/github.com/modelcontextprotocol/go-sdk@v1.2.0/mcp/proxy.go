@@ -0,0 +1,717 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// A ProxyServer aggregates the features of several upstream MCP servers and
+// re-exports them from a single downstream [Server].
+//
+// The ProxyServer connects to each upstream server as a client. It adds the
+// upstream tools, prompts, resources and resource templates to its server,
+// with handlers that forward requests to the upstream server. When an
+// upstream server reports that a list has changed, the proxy updates its
+// server accordingly, which in turn notifies downstream clients.
+//
+// Progress notifications, resource-updated notifications, log messages,
+// completions and resource subscriptions are also forwarded. Since log
+// messages do not say which request caused them, an upstream server's log
+// messages are forwarded only while a single downstream session has requests
+// in progress on it, and are dropped otherwise. Requests that
+// upstream servers make of their clients, such as sampling or elicitation,
+// are not forwarded.
+//
+// If two upstream servers provide a feature with the same name (or URI), the
+// one added first wins, and the collision is logged. To avoid collisions, use
+// [ProxyServer.Mount] to expose each upstream server's features under a
+// distinct prefix.
+//
+// Use [ProxyServer.Server] to serve downstream clients, for example with
+// [Server.Run] or [NewStreamableHTTPHandler].
+type ProxyServer struct {
+	server *Server
+	impl   *Implementation
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	upstreams map[string]*upstream
+	owners    map[featureKind]map[string]*upstream // feature name or URI -> upstream
+	subs      map[string]int                       // resource URI -> downstream subscription count
+
+	progressMu    sync.Mutex
+	progress      map[string]progressTarget // upstream progress token -> downstream
+	progressToken atomic.Int64
+}
+
+// ProxyServerOptions are options for [NewProxyServer].
+type ProxyServerOptions struct {
+	// ServerOptions configures the downstream server.
+	//
+	// If CompletionHandler, SubscribeHandler or UnsubscribeHandler are nil,
+	// the proxy sets them to forward requests to the upstream server that
+	// owns the referenced prompt or resource.
+	ServerOptions *ServerOptions
+}
+
+// A featureKind distinguishes the kinds of features that a proxy tracks.
+type featureKind int
+
+const (
+	proxyTool featureKind = iota
+	proxyPrompt
+	proxyResource
+	proxyResourceTemplate
+)
+
+// MountOptions are options for [ProxyServer.Mount].
+type MountOptions struct {
+	// NamePrefix is prepended to the names of the upstream server's tools and
+	// prompts. For example, with a NamePrefix of "github.", the upstream tool
+	// "search" is exposed as "github.search".
+	NamePrefix string
+	// URIPrefix is prepended to the URIs of the upstream server's resources
+	// and to the URI templates of its resource templates. For example, with a
+	// URIPrefix of "github+", the upstream resource "file:///README.md" is
+	// exposed as "github+file:///README.md". The prefixed URIs must still be
+	// absolute, so a non-empty URIPrefix should begin with a URI scheme.
+	// URIPrefix must not contain '{' or '}'.
+	URIPrefix string
+}
+
+// An upstream is a connection from a ProxyServer to an upstream server.
+type upstream struct {
+	name       string
+	namePrefix string
+	uriPrefix  string
+	session    *ClientSession
+	syncMu     sync.Mutex // serializes calls to ProxyServer.sync
+
+	activeMu sync.Mutex
+	active   map[*ServerSession]int // downstream session -> forwarded requests in progress
+}
+
+// forwarding records that ss has a request in progress on u, so that log
+// messages from u can be routed to ss. The caller must call done when the
+// request completes.
+func (u *upstream) forwarding(ss *ServerSession) (done func()) {
+	if ss == nil {
+		return func() {}
+	}
+	u.activeMu.Lock()
+	if u.active == nil {
+		u.active = make(map[*ServerSession]int)
+	}
+	u.active[ss]++
+	u.activeMu.Unlock()
+	return func() {
+		// As with progress, log messages sent just before the result may
+		// arrive after the call returns.
+		time.AfterFunc(proxyProgressGrace, func() {
+			u.activeMu.Lock()
+			defer u.activeMu.Unlock()
+			if u.active[ss]--; u.active[ss] == 0 {
+				delete(u.active, ss)
+			}
+		})
+	}
+}
+
+// logTarget returns the downstream session that the log messages of u
+// belong to: the only one with requests in progress on u. It returns nil if
+// there is no such session, or more than one.
+func (u *upstream) logTarget() *ServerSession {
+	u.activeMu.Lock()
+	defer u.activeMu.Unlock()
+	if len(u.active) != 1 {
+		return nil
+	}
+	for ss := range u.active {
+		return ss
+	}
+	return nil
+}
+
+// upstreamName returns the upstream name of a prefixed tool or prompt name.
+func (u *upstream) upstreamName(name string) string {
+	return strings.TrimPrefix(name, u.namePrefix)
+}
+
+// upstreamURI returns the upstream URI of a prefixed resource URI.
+func (u *upstream) upstreamURI(uri string) string {
+	return strings.TrimPrefix(uri, u.uriPrefix)
+}
+
+type progressTarget struct {
+	session *ServerSession
+	token   any
+}
+
+// NewProxyServer returns a new [ProxyServer] with no upstream servers.
+// Use [ProxyServer.AddUpstream] to add them.
+//
+// The implementation is used both for the downstream server and for the
+// clients that connect to upstream servers.
+func NewProxyServer(impl *Implementation, opts *ProxyServerOptions) *ProxyServer {
+	p := &ProxyServer{
+		impl:      impl,
+		upstreams: make(map[string]*upstream),
+		owners:    make(map[featureKind]map[string]*upstream),
+		subs:      make(map[string]int),
+		progress:  make(map[string]progressTarget),
+	}
+	var sopts ServerOptions
+	if opts != nil && opts.ServerOptions != nil {
+		sopts = *opts.ServerOptions
+	}
+	if sopts.CompletionHandler == nil {
+		sopts.CompletionHandler = p.complete
+	}
+	if sopts.SubscribeHandler == nil && sopts.UnsubscribeHandler == nil {
+		sopts.SubscribeHandler = p.subscribe
+		sopts.UnsubscribeHandler = p.unsubscribe
+	}
+	p.logger = ensureLogger(sopts.Logger)
+	p.server = NewServer(impl, &sopts)
+	return p
+}
+
+// Server returns the downstream server.
+func (p *ProxyServer) Server() *Server { return p.server }
+
+// AddUpstream connects to an upstream server over t, and adds the server's
+// features to the proxy under their own names and URIs. The name identifies
+// the upstream server in [ProxyServer.RemoveUpstream] and in log messages;
+// it must be unique.
+//
+// The connection uses ctx only while connecting and listing the initial
+// features.
+func (p *ProxyServer) AddUpstream(ctx context.Context, name string, t Transport) error {
+	if err := p.addUpstream(ctx, name, t, &MountOptions{}); err != nil {
+		return fmt.Errorf("AddUpstream: %w", err)
+	}
+	return nil
+}
+
+// Mount is like [ProxyServer.AddUpstream], but adds the upstream server's
+// features with the prefixes in opts. Downstream clients see only the
+// prefixed names and URIs; the proxy removes the prefixes when forwarding
+// requests upstream, and adds them to resource URIs in results and
+// notifications.
+//
+// If opts is nil, tool and prompt names are prefixed with name followed by
+// a dot, and resource URIs are not prefixed.
+func (p *ProxyServer) Mount(ctx context.Context, name string, t Transport, opts *MountOptions) error {
+	if opts == nil {
+		opts = &MountOptions{NamePrefix: name + "."}
+	}
+	if strings.ContainsAny(opts.URIPrefix, "{}") {
+		return fmt.Errorf("Mount: URIPrefix %q contains a brace", opts.URIPrefix)
+	}
+	if err := p.addUpstream(ctx, name, t, opts); err != nil {
+		return fmt.Errorf("Mount: %w", err)
+	}
+	return nil
+}
+
+func (p *ProxyServer) addUpstream(ctx context.Context, name string, t Transport, opts *MountOptions) error {
+	p.mu.Lock()
+	_, exists := p.upstreams[name]
+	p.mu.Unlock()
+	if exists {
+		return fmt.Errorf("upstream %q already exists", name)
+	}
+
+	u := &upstream{name: name, namePrefix: opts.NamePrefix, uriPrefix: opts.URIPrefix}
+	client := NewClient(p.impl, &ClientOptions{
+		ToolListChangedHandler: func(_ context.Context, req *ToolListChangedRequest) {
+			go p.resync(u, req.Session, proxyTool)
+		},
+		PromptListChangedHandler: func(_ context.Context, req *PromptListChangedRequest) {
+			go p.resync(u, req.Session, proxyPrompt)
+		},
+		ResourceListChangedHandler: func(_ context.Context, req *ResourceListChangedRequest) {
+			go p.resync(u, req.Session, proxyResource, proxyResourceTemplate)
+		},
+		ResourceUpdatedHandler: func(ctx context.Context, req *ResourceUpdatedNotificationRequest) {
+			params := *req.Params
+			params.URI = u.uriPrefix + params.URI
+			p.server.ResourceUpdated(ctx, &params)
+		},
+		LoggingMessageHandler: func(ctx context.Context, req *LoggingMessageRequest) {
+			// Don't send one session's log messages to another.
+			if ss := u.logTarget(); ss != nil {
+				ss.Log(ctx, req.Params) // ignore error: best effort
+			}
+		},
+		ProgressNotificationHandler: p.forwardProgress,
+	})
+	cs, err := client.Connect(ctx, t, nil)
+	if err != nil {
+		return fmt.Errorf("upstream %q: %w", name, err)
+	}
+	u.session = cs
+
+	p.mu.Lock()
+	if _, exists := p.upstreams[name]; exists {
+		p.mu.Unlock()
+		cs.Close()
+		return fmt.Errorf("upstream %q already exists", name)
+	}
+	p.upstreams[name] = u
+	p.mu.Unlock()
+
+	if caps := upstreamCapabilities(cs); caps.Logging != nil {
+		// Ask for all messages; each downstream session filters by its own level.
+		if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "debug"}); err != nil {
+			p.logger.Warn("proxy: setting upstream logging level", "upstream", name, "error", err)
+		}
+	}
+	if err := p.sync(ctx, u, cs, proxyTool, proxyPrompt, proxyResource, proxyResourceTemplate); err != nil {
+		p.RemoveUpstream(name)
+		return fmt.Errorf("upstream %q: %w", name, err)
+	}
+	return nil
+}
+
+// RemoveUpstream removes the features of the named upstream server from the
+// proxy, and closes the connection to it.
+// It is not an error to remove a nonexistent upstream.
+func (p *ProxyServer) RemoveUpstream(name string) error {
+	p.mu.Lock()
+	u, ok := p.upstreams[name]
+	if ok {
+		delete(p.upstreams, name)
+		for kind := range p.owners {
+			p.removeFeaturesLocked(u, kind, nil)
+		}
+	}
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return u.session.Close()
+}
+
+// Close closes the connections to all upstream servers.
+func (p *ProxyServer) Close() error {
+	p.mu.Lock()
+	var names []string
+	for name := range p.upstreams {
+		names = append(names, name)
+	}
+	p.mu.Unlock()
+	var errs []error
+	for _, name := range names {
+		errs = append(errs, p.RemoveUpstream(name))
+	}
+	return errors.Join(errs...)
+}
+
+// resync is called asynchronously when an upstream server reports a change.
+func (p *ProxyServer) resync(u *upstream, cs *ClientSession, kinds ...featureKind) {
+	p.mu.Lock()
+	registered := p.upstreams[u.name] == u
+	p.mu.Unlock()
+	if !registered {
+		// Either the upstream is still connecting, and addUpstream lists its
+		// features once it has connected, or it has been removed.
+		return
+	}
+	if err := p.sync(context.Background(), u, cs, kinds...); err != nil {
+		p.logger.Error("proxy: syncing upstream features", "upstream", u.name, "error", err)
+	}
+}
+
+// sync lists the features of the given kinds from an upstream server, and
+// updates the proxy's server to match.
+func (p *ProxyServer) sync(ctx context.Context, u *upstream, cs *ClientSession, kinds ...featureKind) error {
+	u.syncMu.Lock()
+	defer u.syncMu.Unlock()
+
+	caps := upstreamCapabilities(cs)
+	for _, kind := range kinds {
+		var (
+			keys []string
+			add  func() // adds the listed features to the server; called with p.mu held
+		)
+		switch kind {
+		case proxyTool:
+			if caps.Tools == nil {
+				continue
+			}
+			var tools []*Tool
+			for t, err := range cs.Tools(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				if err := checkToolSchemas(t); err != nil {
+					p.logger.Warn("proxy: skipping tool", "upstream", u.name, "tool", t.Name, "error", err)
+					continue
+				}
+				tools = append(tools, t)
+				keys = append(keys, u.namePrefix+t.Name)
+			}
+			add = func() {
+				for _, t := range tools {
+					pt := *t
+					pt.Name = u.namePrefix + t.Name
+					if p.claimLocked(u, kind, pt.Name) {
+						p.server.AddTool(&pt, p.toolHandler(u, t.Name))
+					}
+				}
+			}
+		case proxyPrompt:
+			if caps.Prompts == nil {
+				continue
+			}
+			var prompts []*Prompt
+			for pr, err := range cs.Prompts(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				prompts = append(prompts, pr)
+				keys = append(keys, u.namePrefix+pr.Name)
+			}
+			add = func() {
+				for _, pr := range prompts {
+					ppr := *pr
+					ppr.Name = u.namePrefix + pr.Name
+					if p.claimLocked(u, kind, ppr.Name) {
+						p.server.AddPrompt(&ppr, p.promptHandler(u, pr.Name))
+					}
+				}
+			}
+		case proxyResource:
+			if caps.Resources == nil {
+				continue
+			}
+			var resources []*Resource
+			for r, err := range cs.Resources(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				resources = append(resources, r)
+				keys = append(keys, u.uriPrefix+r.URI)
+			}
+			add = func() {
+				for _, r := range resources {
+					pr := *r
+					pr.URI = u.uriPrefix + r.URI
+					if p.claimLocked(u, kind, pr.URI) {
+						p.server.AddResource(&pr, p.resourceHandler(u))
+					}
+				}
+			}
+		case proxyResourceTemplate:
+			if caps.Resources == nil {
+				continue
+			}
+			var templates []*ResourceTemplate
+			for rt, err := range cs.ResourceTemplates(ctx, nil) {
+				if err != nil {
+					return err
+				}
+				templates = append(templates, rt)
+				keys = append(keys, u.uriPrefix+rt.URITemplate)
+			}
+			add = func() {
+				for _, rt := range templates {
+					prt := *rt
+					prt.URITemplate = u.uriPrefix + rt.URITemplate
+					if p.claimLocked(u, kind, prt.URITemplate) {
+						p.server.AddResourceTemplate(&prt, p.resourceHandler(u))
+					}
+				}
+			}
+		}
+
+		p.mu.Lock()
+		if p.upstreams[u.name] != u {
+			// The upstream was removed while we were listing.
+			p.mu.Unlock()
+			return nil
+		}
+		keep := make(map[string]bool)
+		for _, k := range keys {
+			keep[k] = true
+		}
+		p.removeFeaturesLocked(u, kind, keep)
+		add()
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// claimLocked records that u provides the feature with the given key,
+// and reports whether it may be added to the server.
+func (p *ProxyServer) claimLocked(u *upstream, kind featureKind, key string) bool {
+	owners := p.owners[kind]
+	if owners == nil {
+		owners = make(map[string]*upstream)
+		p.owners[kind] = owners
+	}
+	if owner, ok := owners[key]; ok && owner != u {
+		p.logger.Warn("proxy: feature provided by more than one upstream", "key", key, "using", owner.name, "ignoring", u.name)
+		return false
+	}
+	owners[key] = u
+	return true
+}
+
+// removeFeaturesLocked removes the features of the given kind owned by u,
+// except those in keep.
+func (p *ProxyServer) removeFeaturesLocked(u *upstream, kind featureKind, keep map[string]bool) {
+	var remove []string
+	for key, owner := range p.owners[kind] {
+		if owner == u && !keep[key] {
+			remove = append(remove, key)
+			delete(p.owners[kind], key)
+		}
+	}
+	if len(remove) == 0 {
+		return
+	}
+	switch kind {
+	case proxyTool:
+		p.server.RemoveTools(remove...)
+	case proxyPrompt:
+		p.server.RemovePrompts(remove...)
+	case proxyResource:
+		p.server.RemoveResources(remove...)
+	case proxyResourceTemplate:
+		p.server.RemoveResourceTemplates(remove...)
+	}
+}
+
+// owner returns the upstream that provides the feature of the given kind and
+// key, or nil if there is none.
+func (p *ProxyServer) owner(kind featureKind, key string) *upstream {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.owners[kind][key]
+}
+
+// resourceOwner returns the upstream that provides the resource with the
+// given URI, either directly or through a template.
+func (p *ProxyServer) resourceOwner(uri string) *upstream {
+	if u := p.owner(proxyResource, uri); u != nil {
+		return u
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for tmpl, u := range p.owners[proxyResourceTemplate] {
+		rt := &serverResourceTemplate{resourceTemplate: &ResourceTemplate{URITemplate: tmpl}}
+		if rt.Matches(uri) {
+			return u
+		}
+	}
+	return nil
+}
+
+func (p *ProxyServer) toolHandler(u *upstream, name string) ToolHandler {
+	return func(ctx context.Context, req *CallToolRequest) (*CallToolResult, error) {
+		defer u.forwarding(req.Session)()
+		meta, done := p.forwardProgressToken(req.Session, req.Params.Meta)
+		defer done()
+		return u.session.CallTool(ctx, &CallToolParams{
+			Meta:      meta,
+			Name:      name,
+			Arguments: req.Params.Arguments,
+		})
+	}
+}
+
+func (p *ProxyServer) promptHandler(u *upstream, name string) PromptHandler {
+	return func(ctx context.Context, req *GetPromptRequest) (*GetPromptResult, error) {
+		defer u.forwarding(req.Session)()
+		params := *req.Params
+		params.Name = name
+		var done func()
+		params.Meta, done = p.forwardProgressToken(req.Session, req.Params.Meta)
+		defer done()
+		return u.session.GetPrompt(ctx, &params)
+	}
+}
+
+func (p *ProxyServer) resourceHandler(u *upstream) ResourceHandler {
+	return func(ctx context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+		defer u.forwarding(req.Session)()
+		params := *req.Params
+		params.URI = u.upstreamURI(params.URI)
+		var done func()
+		params.Meta, done = p.forwardProgressToken(req.Session, req.Params.Meta)
+		defer done()
+		res, err := u.session.ReadResource(ctx, &params)
+		if err != nil {
+			return nil, err
+		}
+		if u.uriPrefix != "" {
+			for _, c := range res.Contents {
+				if c != nil {
+					c.URI = u.uriPrefix + c.URI
+				}
+			}
+		}
+		return res, nil
+	}
+}
+
+func (p *ProxyServer) complete(ctx context.Context, req *CompleteRequest) (*CompleteResult, error) {
+	var u *upstream
+	if ref := req.Params.Ref; ref != nil {
+		switch ref.Type {
+		case "ref/prompt":
+			u = p.owner(proxyPrompt, ref.Name)
+		case "ref/resource":
+			u = p.owner(proxyResourceTemplate, ref.URI)
+			if u == nil {
+				u = p.owner(proxyResource, ref.URI)
+			}
+		}
+	}
+	if u == nil {
+		return nil, &jsonrpc.Error{Code: jsonrpc.CodeInvalidParams, Message: "unknown completion reference"}
+	}
+	if upstreamCapabilities(u.session).Completions == nil {
+		return &CompleteResult{Completion: CompletionResultDetails{Values: []string{}}}, nil
+	}
+	params := *req.Params
+	ref := *params.Ref
+	switch ref.Type {
+	case "ref/prompt":
+		ref.Name = u.upstreamName(ref.Name)
+	case "ref/resource":
+		ref.URI = u.upstreamURI(ref.URI)
+	}
+	params.Ref = &ref
+	defer u.forwarding(req.Session)()
+	return u.session.Complete(ctx, &params)
+}
+
+func (p *ProxyServer) subscribe(ctx context.Context, req *SubscribeRequest) error {
+	uri := req.Params.URI
+	u := p.resourceOwner(uri)
+	if u == nil {
+		return ResourceNotFoundError(uri)
+	}
+	if !upstreamCanSubscribe(u.session) {
+		return nil
+	}
+	p.mu.Lock()
+	p.subs[uri]++
+	first := p.subs[uri] == 1
+	p.mu.Unlock()
+	if !first {
+		return nil
+	}
+	if err := u.session.Subscribe(ctx, &SubscribeParams{URI: u.upstreamURI(uri)}); err != nil {
+		p.mu.Lock()
+		p.subs[uri]--
+		p.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+func (p *ProxyServer) unsubscribe(ctx context.Context, req *UnsubscribeRequest) error {
+	uri := req.Params.URI
+	u := p.resourceOwner(uri)
+	if u == nil || !upstreamCanSubscribe(u.session) {
+		return nil
+	}
+	p.mu.Lock()
+	if p.subs[uri] == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	p.subs[uri]--
+	last := p.subs[uri] == 0
+	if last {
+		delete(p.subs, uri)
+	}
+	p.mu.Unlock()
+	if !last {
+		return nil
+	}
+	return u.session.Unsubscribe(ctx, &UnsubscribeParams{URI: u.upstreamURI(uri)})
+}
+
+func upstreamCanSubscribe(cs *ClientSession) bool {
+	caps := upstreamCapabilities(cs)
+	return caps.Resources != nil && caps.Resources.Subscribe
+}
+
+// upstreamCapabilities returns the capabilities of an upstream server, which
+// are empty if the session has not finished initializing.
+func upstreamCapabilities(cs *ClientSession) *ServerCapabilities {
+	if res := cs.InitializeResult(); res != nil && res.Capabilities != nil {
+		return res.Capabilities
+	}
+	return &ServerCapabilities{}
+}
+
+// forwardProgressToken returns metadata for a request forwarded upstream on
+// behalf of ss. If meta has a progress token, it is replaced with one that is
+// unique to the proxy, so that progress notifications can be routed back to
+// ss with the original token. The caller must call done when the forwarded
+// request completes.
+func (p *ProxyServer) forwardProgressToken(ss *ServerSession, meta Meta) (_ Meta, done func()) {
+	token := meta[progressTokenKey]
+	if token == nil || ss == nil {
+		return meta, func() {}
+	}
+	upToken := "proxy-" + strconv.FormatInt(p.progressToken.Add(1), 10)
+	p.progressMu.Lock()
+	p.progress[upToken] = progressTarget{session: ss, token: token}
+	p.progressMu.Unlock()
+
+	newMeta := make(Meta, len(meta))
+	for k, v := range meta {
+		newMeta[k] = v
+	}
+	newMeta[progressTokenKey] = upToken
+	return newMeta, func() {
+		// Notifications are handled asynchronously with respect to responses,
+		// so progress sent just before the result may arrive after the call
+		// returns. Keep the mapping around a little longer.
+		time.AfterFunc(proxyProgressGrace, func() {
+			p.progressMu.Lock()
+			delete(p.progress, upToken)
+			p.progressMu.Unlock()
+		})
+	}
+}
+
+// proxyProgressGrace is how long progress tokens remain routable after the
+// forwarded request completes.
+const proxyProgressGrace = 5 * time.Second
+
+func (p *ProxyServer) forwardProgress(ctx context.Context, req *ProgressNotificationClientRequest) {
+	token, ok := req.Params.ProgressToken.(string)
+	if !ok {
+		return
+	}
+	p.progressMu.Lock()
+	target, ok := p.progress[token]
+	p.progressMu.Unlock()
+	if !ok {
+		return
+	}
+	params := *req.Params
+	params.ProgressToken = target.token
+	target.session.NotifyProgress(ctx, &params) // ignore error: best effort
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+)
+
+// connectUpstream adds an upstream named name to p, serving server s.
+func connectUpstream(t *testing.T, p *ProxyServer, name string, s *Server) {
+	t.Helper()
+	ctx := context.Background()
+	ct, st := NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddUpstream(ctx, name, ct); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// connectDownstream connects a client with the given options to p.
+func connectDownstream(t *testing.T, p *ProxyServer, opts *ClientOptions) *ClientSession {
+	t.Helper()
+	ctx := context.Background()
+	ct, st := NewInMemoryTransports()
+	if _, err := p.Server().Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, opts).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	return cs
+}
+
+func toolNames(t *testing.T, cs *ClientSession) []string {
+	t.Helper()
+	var names []string
+	for tool, err := range cs.Tools(context.Background(), nil) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+type proxyArgs struct {
+	X string `json:"x"`
+}
+
+func TestProxyServer(t *testing.T) {
+	ctx := context.Background()
+
+	a := NewServer(&Implementation{Name: "a"}, nil)
+	AddTool(a, &Tool{Name: "echo"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		return &CallToolResult{Content: []Content{&TextContent{Text: "a:" + args.X}}}, nil, nil
+	})
+	a.AddPrompt(&Prompt{Name: "greet"}, func(ctx context.Context, req *GetPromptRequest) (*GetPromptResult, error) {
+		return &GetPromptResult{Messages: []*PromptMessage{{Role: "user", Content: &TextContent{Text: "hi"}}}}, nil
+	})
+
+	b := NewServer(&Implementation{Name: "b"}, nil)
+	AddTool(b, &Tool{Name: "echo"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		return &CallToolResult{Content: []Content{&TextContent{Text: "b:" + args.X}}}, nil, nil
+	})
+	AddTool(b, &Tool{Name: "add"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		return &CallToolResult{Content: []Content{&TextContent{Text: "added " + args.X}}}, nil, nil
+	})
+	b.AddResource(&Resource{URI: "file:///b.txt", Name: "b"}, func(ctx context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+		return &ReadResourceResult{Contents: []*ResourceContents{{URI: req.Params.URI, Text: "from b"}}}, nil
+	})
+
+	p := NewProxyServer(&Implementation{Name: "proxy"}, nil)
+	defer p.Close()
+	connectUpstream(t, p, "a", a)
+	connectUpstream(t, p, "b", b)
+
+	toolsChanged := make(chan struct{}, 10)
+	cs := connectDownstream(t, p, &ClientOptions{
+		ToolListChangedHandler: func(context.Context, *ToolListChangedRequest) { toolsChanged <- struct{}{} },
+	})
+
+	if got, want := toolNames(t, cs), []string{"add", "echo"}; !slices.Equal(got, want) {
+		t.Fatalf("tools: got %v, want %v", got, want)
+	}
+	res, err := cs.CallTool(ctx, &CallToolParams{Name: "echo", Arguments: map[string]any{"x": "1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The first upstream wins the collision.
+	if got := res.Content[0].(*TextContent).Text; got != "a:1" {
+		t.Errorf("echo: got %q, want %q", got, "a:1")
+	}
+	if _, err := cs.GetPrompt(ctx, &GetPromptParams{Name: "greet"}); err != nil {
+		t.Errorf("GetPrompt: %v", err)
+	}
+	rres, err := cs.ReadResource(ctx, &ReadResourceParams{URI: "file:///b.txt"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rres.Contents[0].Text; got != "from b" {
+		t.Errorf("ReadResource: got %q, want %q", got, "from b")
+	}
+
+	// Upstream changes propagate downstream.
+	AddTool(a, &Tool{Name: "later"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		return &CallToolResult{}, nil, nil
+	})
+	waitFor(t, toolsChanged, func() bool { return slices.Contains(toolNames(t, cs), "later") })
+
+	if err := p.RemoveUpstream("a"); err != nil {
+		t.Fatal(err)
+	}
+	waitFor(t, toolsChanged, func() bool { return slices.Equal(toolNames(t, cs), []string{"add"}) })
+}
+
+// waitFor waits for notifications on ch until cond holds.
+func waitFor(t *testing.T, ch <-chan struct{}, cond func() bool) {
+	t.Helper()
+	timeout := time.After(5 * time.Second)
+	for !cond() {
+		select {
+		case <-ch:
+		case <-timeout:
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestProxyServerProgress(t *testing.T) {
+	ctx := context.Background()
+	up := NewServer(&Implementation{Name: "up"}, nil)
+	AddTool(up, &Tool{Name: "slow"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		err := req.Session.NotifyProgress(ctx, &ProgressNotificationParams{
+			ProgressToken: req.Params.GetProgressToken(),
+			Progress:      0.5,
+		})
+		return &CallToolResult{}, nil, err
+	})
+	p := NewProxyServer(&Implementation{Name: "proxy"}, nil)
+	defer p.Close()
+	connectUpstream(t, p, "up", up)
+
+	progress := make(chan any, 1)
+	cs := connectDownstream(t, p, &ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *ProgressNotificationClientRequest) {
+			progress <- req.Params.ProgressToken
+		},
+	})
+	if _, err := cs.CallTool(ctx, &CallToolParams{Meta: Meta{"progressToken": "mine"}, Name: "slow", Arguments: map[string]any{"x": ""}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-progress:
+		if got != "mine" {
+			t.Errorf("got progress token %v, want %q", got, "mine")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for progress")
+	}
+}
+
+func TestProxyServerLogs(t *testing.T) {
+	ctx := context.Background()
+	up := NewServer(&Implementation{Name: "up"}, nil)
+	AddTool(up, &Tool{Name: "log"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+		err := req.Session.Log(ctx, &LoggingMessageParams{Level: "info", Data: args.X})
+		return &CallToolResult{}, nil, err
+	})
+	p := NewProxyServer(&Implementation{Name: "proxy"}, nil)
+	defer p.Close()
+	connectUpstream(t, p, "up", up)
+
+	connect := func() (*ClientSession, chan any) {
+		logs := make(chan any, 10)
+		cs := connectDownstream(t, p, &ClientOptions{
+			LoggingMessageHandler: func(_ context.Context, req *LoggingMessageRequest) {
+				logs <- req.Params.Data
+			},
+		})
+		if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "debug"}); err != nil {
+			t.Fatal(err)
+		}
+		return cs, logs
+	}
+	cs1, logs1 := connect()
+	_, logs2 := connect()
+
+	if _, err := cs1.CallTool(ctx, &CallToolParams{Name: "log", Arguments: map[string]any{"x": "secret"}}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case got := <-logs1:
+		if got != "secret" {
+			t.Errorf("got log %v, want %q", got, "secret")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for log")
+	}
+	select {
+	case got := <-logs2:
+		t.Errorf("other session got log %v", got)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestProxyServerMount(t *testing.T) {
+	ctx := context.Background()
+	newUpstream := func(name string) *Server {
+		s := NewServer(&Implementation{Name: name}, nil)
+		AddTool(s, &Tool{Name: "search"}, func(ctx context.Context, req *CallToolRequest, args proxyArgs) (*CallToolResult, any, error) {
+			return &CallToolResult{Content: []Content{&TextContent{Text: name + ":" + args.X}}}, nil, nil
+		})
+		s.AddResource(&Resource{URI: "file:///info", Name: "info"}, func(ctx context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+			return &ReadResourceResult{Contents: []*ResourceContents{{URI: req.Params.URI, Text: name}}}, nil
+		})
+		return s
+	}
+
+	p := NewProxyServer(&Implementation{Name: "proxy"}, nil)
+	defer p.Close()
+	for _, name := range []string{"github", "jira"} {
+		ct, st := NewInMemoryTransports()
+		if _, err := newUpstream(name).Connect(ctx, st, nil); err != nil {
+			t.Fatal(err)
+		}
+		var opts *MountOptions
+		if name == "jira" {
+			opts = &MountOptions{NamePrefix: "jira_", URIPrefix: "jira+"}
+		}
+		if err := p.Mount(ctx, name, ct, opts); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cs := connectDownstream(t, p, nil)
+
+	if got, want := toolNames(t, cs), []string{"github.search", "jira_search"}; !slices.Equal(got, want) {
+		t.Fatalf("tools: got %v, want %v", got, want)
+	}
+	for name, want := range map[string]string{"github.search": "github:q", "jira_search": "jira:q"} {
+		res, err := cs.CallTool(ctx, &CallToolParams{Name: name, Arguments: map[string]any{"x": "q"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Content[0].(*TextContent).Text; got != want {
+			t.Errorf("%s: got %q, want %q", name, got, want)
+		}
+	}
+
+	// Mounting with nil options does not prefix URIs.
+	for uri, want := range map[string]string{"file:///info": "github", "jira+file:///info": "jira"} {
+		res, err := cs.ReadResource(ctx, &ReadResourceParams{URI: uri})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := res.Contents[0]; got.Text != want || got.URI != uri {
+			t.Errorf("ReadResource(%q): got (%q, %q), want (%q, %q)", uri, got.URI, got.Text, uri, want)
+		}
+	}
+
+	ct, _ := NewInMemoryTransports()
+	if err := p.Mount(ctx, "bad", ct, &MountOptions{URIPrefix: "x{y}"}); err == nil {
+		t.Error("Mount with brace in URIPrefix succeeded, want error")
+	}
+}
+
+// TestProxyServerEarlyNotification checks that a list_changed notification
+// that arrives while an upstream is still connecting is ignored.
+func TestProxyServerEarlyNotification(t *testing.T) {
+	p := NewProxyServer(testImpl, nil)
+	u := &upstream{name: "early"}
+	cs := &ClientSession{} // not yet initialized
+	p.resync(u, cs, proxyTool, proxyPrompt, proxyResource, proxyResourceTemplate)
+	if err := p.sync(context.Background(), u, cs, proxyTool, proxyPrompt, proxyResource, proxyResourceTemplate); err != nil {
+		t.Errorf("sync with an uninitialized session: %v", err)
+	}
+}
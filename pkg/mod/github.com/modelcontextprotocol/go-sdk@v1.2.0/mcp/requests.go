@@ -10,6 +10,7 @@ type (
 	CallToolRequest                   = ServerRequest[*CallToolParamsRaw]
 	CompleteRequest                   = ServerRequest[*CompleteParams]
 	GetPromptRequest                  = ServerRequest[*GetPromptParams]
+	InitializeServerRequest           = ServerRequest[*InitializeParams]
 	InitializedRequest                = ServerRequest[*InitializedParams]
 	ListPromptsRequest                = ServerRequest[*ListPromptsParams]
 	ListResourcesRequest              = ServerRequest[*ListResourcesParams]
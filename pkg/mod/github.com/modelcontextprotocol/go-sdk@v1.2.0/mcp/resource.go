@@ -6,7 +6,6 @@ package mcp
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
@@ -40,11 +39,26 @@ type ResourceHandler func(context.Context, *ReadResourceRequest) (*ReadResourceR
 // ResourceNotFoundError returns an error indicating that a resource being read could
 // not be found.
 func ResourceNotFoundError(uri string) error {
-	return &jsonrpc.Error{
-		Code:    CodeResourceNotFound,
-		Message: "Resource not found",
-		Data:    json.RawMessage(fmt.Sprintf(`{"uri":%q}`, uri)),
+	return jsonrpc.NewError(CodeResourceNotFound, "Resource not found", &ResourceNotFoundErrorData{URI: uri})
+}
+
+// ResourceNotFoundErrorData is the data of the error returned by
+// [ResourceNotFoundError].
+type ResourceNotFoundErrorData struct {
+	// URI is the URI of the resource that could not be found.
+	URI string `json:"uri"`
+}
+
+// ParseResourceNotFoundError reports whether err is, or wraps, an error
+// returned by [ResourceNotFoundError], such as the error returned by
+// [ClientSession.ReadResource] for a missing resource. If so, it returns the
+// error's data.
+func ParseResourceNotFoundError(err error) (*ResourceNotFoundErrorData, bool) {
+	var data ResourceNotFoundErrorData
+	if !jsonrpc.DecodeErrorData(err, CodeResourceNotFound, &data) {
+		return nil, false
 	}
+	return &data, true
 }
 
 // readFileResource reads from the filesystem at a URI relative to dirFilepath, respecting
@@ -96,23 +110,58 @@ func computeURIFilepath(rawURI, dirFilepath string, rootFilepaths []string) (str
 		// To check against the roots, we need an absolute file path, not relative to the directory.
 		// uriFilepath is local, so the joined path is under dirFilepath.
 		uriFilepathAbs := filepath.Join(dirFilepath, uriFilepathRel)
-		rootOK := false
-		// Check that the requested file path is under some root.
-		// Since both paths are absolute, that's equivalent to filepath.Rel constructing
-		// a local path.
-		for _, rootFilepathAbs := range rootFilepaths {
-			if rel, err := filepath.Rel(rootFilepathAbs, uriFilepathAbs); err == nil && filepath.IsLocal(rel) {
-				rootOK = true
-				break
-			}
-		}
-		if !rootOK {
+		if !underRoot(uriFilepathAbs, rootFilepaths) {
 			return "", fmt.Errorf("URI path %q is not under any root", uriFilepathAbs)
 		}
 	}
 	return uriFilepathRel, nil
 }
 
+// underRoot reports whether the absolute file path is under one of the
+// absolute root file paths.
+func underRoot(fpath string, rootFilepaths []string) bool {
+	// Since both paths are absolute, being under a root is equivalent to
+	// filepath.Rel constructing a local path.
+	for _, rootFilepathAbs := range rootFilepaths {
+		if rel, err := filepath.Rel(rootFilepathAbs, fpath); err == nil && filepath.IsLocal(rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkFileRoots returns an error if req is for a file URI whose path is not
+// under one of the client's file roots. Requests for other URIs are allowed.
+//
+// Unlike readFileResource, it interprets the URI path as an absolute path.
+func checkFileRoots(ctx context.Context, req *ReadResourceRequest) error {
+	uri, err := url.Parse(req.Params.URI)
+	if err != nil || uri.Scheme != "file" {
+		// Leave other URIs, and the reporting of bad ones, to the handler.
+		return nil
+	}
+	rootRes, err := req.Session.ListRoots(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("listing roots: %w", err)
+	}
+	// Ignore roots that aren't absolute file paths: they can't contain
+	// the URI.
+	var roots []string
+	for _, r := range rootRes.Roots {
+		if fr, err := fileRoot(r); err == nil {
+			roots = append(roots, fr)
+		}
+	}
+	fpath := filepath.Clean(filepath.FromSlash(uri.Path))
+	if !filepath.IsAbs(fpath) || !underRoot(fpath, roots) {
+		return &jsonrpc.Error{
+			Code:    jsonrpc.CodeInvalidParams,
+			Message: fmt.Sprintf("resource %q is not under any root", req.Params.URI),
+		}
+	}
+	return nil
+}
+
 // fileRoots transforms the Roots obtained from the client into absolute paths on
 // the local filesystem.
 // TODO(jba): expose this functionality to user ResourceHandlers,
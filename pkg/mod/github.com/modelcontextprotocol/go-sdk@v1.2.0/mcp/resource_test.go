@@ -5,10 +5,14 @@
 package mcp
 
 import (
+	"context"
+	"errors"
 	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 )
 
 func TestFileRoot(t *testing.T) {
@@ -113,6 +117,65 @@ func TestReadFileResource(t *testing.T) {
 	}
 }
 
+func TestCheckFileRoots(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("TODO: fix for Windows")
+	}
+	ctx := context.Background()
+	server := NewServer(testImpl, &ServerOptions{CheckFileRoots: true})
+	server.AddResourceTemplate(&ResourceTemplate{URITemplate: "file:///{+path}"},
+		func(_ context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+			return &ReadResourceResult{Contents: []*ResourceContents{{Text: "ok"}}}, nil
+		})
+	server.AddResource(&Resource{URI: "mem://data"},
+		func(_ context.Context, req *ReadResourceRequest) (*ReadResourceResult, error) {
+			return &ReadResourceResult{Contents: []*ResourceContents{{Text: "ok"}}}, nil
+		})
+	client := NewClient(testImpl, nil)
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	read := func(uri string) error {
+		_, err := cs.ReadResource(ctx, &ReadResourceParams{URI: uri})
+		return err
+	}
+	// With no roots, no file can be read.
+	if err := read("file:///home/user/a.txt"); err == nil {
+		t.Error("read with no roots: got nil error, want error")
+	}
+	cs.AddRoots(&Root{URI: "file:///home/user"}, &Root{URI: "https://example.com"})
+	for _, tt := range []struct {
+		uri  string
+		want bool
+	}{
+		{"file:///home/user/a.txt", true},
+		{"file:///home/user/sub/b.txt", true},
+		{"file:///home/user", true},
+		{"file:///home/other/a.txt", false},
+		{"file:///home/user/../other/a.txt", false},
+		{"file:///home/username/a.txt", false},
+		{"mem://data", true}, // not a file: not checked
+	} {
+		err := read(tt.uri)
+		if got := err == nil; got != tt.want {
+			t.Errorf("read %s: got error %v, want success %t", tt.uri, err, tt.want)
+		}
+		var werr *jsonrpc.Error
+		if err != nil && (!errors.As(err, &werr) || werr.Code != jsonrpc.CodeInvalidParams) {
+			t.Errorf("read %s: got error %v, want invalid params", tt.uri, err)
+		}
+	}
+}
+
 func TestTemplateMatch(t *testing.T) {
 	uri := "file:///path/to/file"
 	for _, tt := range []struct {
@@ -50,6 +50,7 @@ type Server struct {
 	sessions                []*ServerSession
 	sendingMethodHandler_   MethodHandler
 	receivingMethodHandler_ MethodHandler
+	receivingMethodInfos_   map[string]methodInfo              // nil unless experimental methods are added
 	resourceSubscriptions   map[string]map[*ServerSession]bool // uri -> session -> bool
 	pendingNotifications    map[string]*time.Timer             // notification name -> timer for pending notification send
 }
@@ -57,7 +58,17 @@ type Server struct {
 // ServerOptions is used to configure behavior of the server.
 type ServerOptions struct {
 	// Optional instructions for connected clients.
+	// If InstructionsFunc is set, Instructions is ignored.
 	Instructions string
+	// InstructionsFunc, if non-nil, computes the instructions for each session
+	// when it is initialized. Use it to tailor the instructions to the client,
+	// for example to the authenticated user reported in the request's
+	// [RequestExtra.TokenInfo], to the client's capabilities, or to feature
+	// flags. If it returns an error, initialization fails with that error.
+	//
+	// Since the session is not yet initialized, InstructionsFunc must not
+	// make requests of the client, such as [ServerSession.ListRoots].
+	InstructionsFunc func(context.Context, *InitializeServerRequest) (string, error)
 	// If non-nil, log server activity.
 	Logger *slog.Logger
 	// If non-nil, called when "notifications/initialized" is received.
@@ -81,6 +92,51 @@ type ServerOptions struct {
 	SubscribeHandler func(context.Context, *SubscribeRequest) error
 	// Function called when a client session unsubscribes from a resource.
 	UnsubscribeHandler func(context.Context, *UnsubscribeRequest) error
+	// ProtocolVersions, if non-empty, restricts the protocol versions that the
+	// server will use to those listed, each of which must be supported by the
+	// SDK. The server responds to an initialize request for any other version
+	// with the latest listed version, and the client decides whether to
+	// proceed, as the lifecycle section of the specification requires.
+	//
+	// To pin the protocol version, list a single version.
+	ProtocolVersions []string
+	// If true, a "resources/read" request for a file:// URI succeeds only if
+	// the URI's path is under one of the client's file roots, as reported by
+	// "roots/list". The check is made before the resource handler is called.
+	// If the client has no file roots, or does not support roots, all such
+	// reads fail.
+	//
+	// The check is lexical: it does not follow symbolic links.
+	//
+	// The check is deliberately opt-in rather than on by default: enabling it
+	// by default would make file:// reads fail on existing servers whose
+	// clients do not support roots.
+	CheckFileRoots bool
+	// ToolTimeout, if positive, limits the time that a tool call may take.
+	// When the limit is exceeded, the handler's context is cancelled, and
+	// once the handler returns with an error, the call returns a tool error
+	// whose [ToolError] has code [ToolErrorCodeTimeout]. Handlers must honor
+	// cancellation for the limit to take effect.
+	ToolTimeout time.Duration
+	// ToolTimeouts holds time limits for individual tools, keyed by tool
+	// name, that override ToolTimeout. A zero limit disables the default.
+	ToolTimeouts map[string]time.Duration
+	// MaxConcurrentRequestsPerSession, if positive, limits the number of
+	// requests from a single session that are handled at once, so that one
+	// client cannot saturate the server with parallel calls. Pings and the
+	// initialize request are not limited.
+	//
+	// Excess requests are rejected with the error code
+	// [CodeTooManyRequests], unless QueueExcessRequests is set.
+	MaxConcurrentRequestsPerSession int
+	// If true, requests in excess of MaxConcurrentRequestsPerSession wait
+	// until an earlier request completes, or until they are cancelled,
+	// rather than being rejected.
+	QueueExcessRequests bool
+
+	// JSONCodec, if non-nil, is used instead of encoding/json to marshal and
+	// unmarshal the server's messages. See [JSONCodec].
+	JSONCodec JSONCodec
 
 	// Capabilities optionally configures the server's default capabilities,
 	// before any capabilities are inferred from other configuration or server
@@ -169,10 +225,15 @@ func NewServer(impl *Implementation, options *ServerOptions) *Server {
 		panic("UnsubscribeHandler requires SubscribeHandler")
 	}
 
+	checkProtocolVersions(opts.ProtocolVersions)
+
 	if opts.GetSessionID == nil {
 		opts.GetSessionID = randText
 	}
 
+	if opts.JSONCodec == nil {
+		opts.JSONCodec = stdJSONCodec{}
+	}
 	if opts.Logger == nil { // ensure we have a logger
 		opts.Logger = ensureLogger(nil)
 	}
@@ -193,11 +254,23 @@ func NewServer(impl *Implementation, options *ServerOptions) *Server {
 
 // AddPrompt adds a [Prompt] to the server, or replaces one with the same name.
 func (s *Server) AddPrompt(p *Prompt, h PromptHandler) {
+	s.addPrompt(&serverPrompt{prompt: p, handler: h})
+}
+
+// AddPromptWithCompletion is like [Server.AddPrompt], but the server also
+// answers completion requests for the arguments of the prompt by calling c,
+// and advertises the completions capability. Completion requests that
+// neither c nor [ServerOptions.CompletionHandler] answers get an empty result.
+func (s *Server) AddPromptWithCompletion(p *Prompt, h PromptHandler, c PromptCompletionHandler) {
+	s.addPrompt(&serverPrompt{prompt: p, handler: h, complete: c})
+}
+
+func (s *Server) addPrompt(sp *serverPrompt) {
 	// Assume there was a change, since add replaces existing items.
 	// (It's possible an item was replaced with an identical one, but not worth checking.)
 	s.changeAndNotify(
 		notificationPromptListChanged,
-		func() bool { s.prompts.add(&serverPrompt{p, h}); return true })
+		func() bool { s.prompts.add(sp); return true })
 }
 
 // RemovePrompts removes the prompts with the given names.
@@ -232,47 +305,56 @@ func (s *Server) AddTool(t *Tool, h ToolHandler) {
 	if err := validateToolName(t.Name); err != nil {
 		s.opts.Logger.Error(fmt.Sprintf("AddTool: invalid tool name %q: %v", t.Name, err))
 	}
+	if err := checkToolSchemas(t); err != nil {
+		panic(fmt.Errorf("AddTool %q: %v", t.Name, err))
+	}
+	st := &serverTool{tool: t, handler: h}
+	// Assume there was a change, since add replaces existing tools.
+	// (It's possible a tool was replaced with an identical one, but not worth checking.)
+	// TODO: Batch these changes by size and time? The typescript SDK doesn't.
+	// TODO: Surface notify error here? best not, in case we need to batch.
+	s.changeAndNotify(notificationToolListChanged, func() bool { s.tools.add(st); return true })
+}
+
+// checkToolSchemas reports an error if the schemas of t are not valid
+// for [Server.AddTool].
+func checkToolSchemas(t *Tool) error {
 	if t.InputSchema == nil {
 		// This prevents the tool author from forgetting to write a schema where
 		// one should be provided. If we papered over this by supplying the empty
 		// schema, then every input would be validated and the problem wouldn't be
 		// discovered until runtime, when the LLM sent bad data.
-		panic(fmt.Errorf("AddTool %q: missing input schema", t.Name))
+		return errors.New("missing input schema")
 	}
 	if s, ok := t.InputSchema.(*jsonschema.Schema); ok {
 		if s.Type != "object" {
-			panic(fmt.Errorf(`AddTool %q: input schema must have type "object"`, t.Name))
+			return errors.New(`input schema must have type "object"`)
 		}
 	} else {
 		var m map[string]any
 		if err := remarshal(t.InputSchema, &m); err != nil {
-			panic(fmt.Errorf("AddTool %q: can't marshal input schema to a JSON object: %v", t.Name, err))
+			return fmt.Errorf("can't marshal input schema to a JSON object: %v", err)
 		}
 		if typ := m["type"]; typ != "object" {
-			panic(fmt.Errorf(`AddTool %q: input schema must have type "object" (got %v)`, t.Name, typ))
+			return fmt.Errorf(`input schema must have type "object" (got %v)`, typ)
 		}
 	}
 	if t.OutputSchema != nil {
 		if s, ok := t.OutputSchema.(*jsonschema.Schema); ok {
 			if s.Type != "object" {
-				panic(fmt.Errorf(`AddTool %q: output schema must have type "object"`, t.Name))
+				return errors.New(`output schema must have type "object"`)
 			}
 		} else {
 			var m map[string]any
 			if err := remarshal(t.OutputSchema, &m); err != nil {
-				panic(fmt.Errorf("AddTool %q: can't marshal output schema to a JSON object: %v", t.Name, err))
+				return fmt.Errorf("can't marshal output schema to a JSON object: %v", err)
 			}
 			if typ := m["type"]; typ != "object" {
-				panic(fmt.Errorf(`AddTool %q: output schema must have type "object" (got %v)`, t.Name, typ))
+				return fmt.Errorf(`output schema must have type "object" (got %v)`, typ)
 			}
 		}
 	}
-	st := &serverTool{tool: t, handler: h}
-	// Assume there was a change, since add replaces existing tools.
-	// (It's possible a tool was replaced with an identical one, but not worth checking.)
-	// TODO: Batch these changes by size and time? The typescript SDK doesn't.
-	// TODO: Surface notify error here? best not, in case we need to batch.
-	s.changeAndNotify(notificationToolListChanged, func() bool { s.tools.add(st); return true })
+	return nil
 }
 
 func toolForErr[In, Out any](t *Tool, h ToolHandlerFor[In, Out]) (*Tool, ToolHandler, error) {
@@ -321,7 +403,7 @@ func toolForErr[In, Out any](t *Tool, h ToolHandlerFor[In, Out]) (*Tool, ToolHan
 		// Unmarshal and validate args.
 		var in In
 		if input != nil {
-			if err := json.Unmarshal(input, &in); err != nil {
+			if err := req.Session.jsonCodec().Unmarshal(input, &in); err != nil {
 				return nil, fmt.Errorf("%w: %v", jsonrpc2.ErrInvalidParams, err)
 			}
 		}
@@ -358,7 +440,7 @@ func toolForErr[In, Out any](t *Tool, h ToolHandlerFor[In, Out]) (*Tool, ToolHan
 			}
 		}
 		if outval != nil {
-			outbytes, err := json.Marshal(outval)
+			outbytes, err := req.Session.jsonCodec().Marshal(outval)
 			if err != nil {
 				return nil, fmt.Errorf("marshaling output: %w", err)
 			}
@@ -538,8 +620,9 @@ func (s *Server) capabilities() *ServerCapabilities {
 		}
 	}
 
-	// Augment with completions capability if handler is set.
-	if s.opts.CompletionHandler != nil {
+	// Augment with completions capability if handler is set, or a prompt has
+	// a completion handler.
+	if s.opts.CompletionHandler != nil || s.hasCompletablePrompts() {
 		if caps.Completions == nil {
 			caps.Completions = &CompletionCapabilities{}
 		}
@@ -548,13 +631,52 @@ func (s *Server) capabilities() *ServerCapabilities {
 	return caps
 }
 
+// hasCompletablePrompts reports whether any prompt has a completion handler.
+// s.mu must be held.
+func (s *Server) hasCompletablePrompts() bool {
+	for p := range s.prompts.all() {
+		if p.complete != nil {
+			return true
+		}
+	}
+	return false
+}
+
 func (s *Server) complete(ctx context.Context, req *CompleteRequest) (*CompleteResult, error) {
+	if c := s.promptCompletionHandler(req.Params); c != nil {
+		res, err := c(ctx, req)
+		if res != nil || err != nil {
+			return res, err
+		}
+	}
 	if s.opts.CompletionHandler == nil {
-		return nil, jsonrpc2.ErrMethodNotFound
+		s.mu.Lock()
+		advertised := s.hasCompletablePrompts()
+		s.mu.Unlock()
+		if !advertised {
+			return nil, jsonrpc2.ErrMethodNotFound
+		}
+		// The server offers completions, just none for this request.
+		return &CompleteResult{Completion: CompletionResultDetails{Values: []string{}}}, nil
 	}
 	return s.opts.CompletionHandler(ctx, req)
 }
 
+// promptCompletionHandler returns the completion handler of the prompt
+// referred to by params, or nil if there is none.
+func (s *Server) promptCompletionHandler(params *CompleteParams) PromptCompletionHandler {
+	if params.Ref == nil || params.Ref.Type != "ref/prompt" {
+		return nil
+	}
+	s.mu.Lock()
+	p, ok := s.prompts.get(params.Ref.Name)
+	s.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	return p.complete
+}
+
 // Map from notification name to its corresponding params. The params have no fields,
 // so a single struct can be reused.
 var changeNotificationParams = map[string]Params{
@@ -686,7 +808,16 @@ func (s *Server) callTool(ctx context.Context, req *CallToolRequest) (*CallToolR
 			Message: fmt.Sprintf("unknown tool %q", req.Params.Name),
 		}
 	}
-	res, err := st.handler(ctx, req)
+	if d := st.tool.Deprecation(); d != nil {
+		// The warning is advisory: failing to send it doesn't fail the call.
+		if err := req.Session.Log(ctx, &LoggingMessageParams{
+			Level: "warning",
+			Data:  d.warning(st.tool.Name),
+		}); err != nil {
+			s.opts.Logger.Warn("failed to send tool deprecation warning", "tool", st.tool.Name, "error", err)
+		}
+	}
+	res, err := s.runTool(ctx, st, req)
 	if err == nil && res != nil && res.Content == nil {
 		res2 := *res
 		res2.Content = []Content{} // avoid "null"
@@ -695,6 +826,32 @@ func (s *Server) callTool(ctx context.Context, req *CallToolRequest) (*CallToolR
 	return res, err
 }
 
+// runTool calls the tool's handler, enforcing its timeout, if any.
+func (s *Server) runTool(ctx context.Context, st *serverTool, req *CallToolRequest) (*CallToolResult, error) {
+	timeout, ok := s.opts.ToolTimeouts[st.tool.Name]
+	if !ok {
+		timeout = s.opts.ToolTimeout
+	}
+	if timeout <= 0 {
+		return st.handler(ctx, req)
+	}
+	tctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	res, err := st.handler(tctx, req)
+	failed := err != nil || (res != nil && res.IsError)
+	if !failed || tctx.Err() == nil || ctx.Err() != nil {
+		return res, err
+	}
+	// The handler failed after the deadline passed, most likely because
+	// of it: report the timeout.
+	res = &CallToolResult{}
+	res.setError(&ToolError{
+		Code:    ToolErrorCodeTimeout,
+		Message: fmt.Sprintf("tool %q timed out after %v", st.tool.Name, timeout),
+	})
+	return res, nil
+}
+
 func (s *Server) listResources(_ context.Context, req *ListResourcesRequest) (*ListResourcesResult, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -734,6 +891,11 @@ func (s *Server) readResource(ctx context.Context, req *ReadResourceRequest) (*R
 		// Treat an unregistered resource the same as a registered one that couldn't be found.
 		return nil, ResourceNotFoundError(uri)
 	}
+	if s.opts.CheckFileRoots {
+		if err := checkFileRoots(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 	res, err := handler(ctx, req)
 	if err != nil {
 		return nil, err
@@ -913,6 +1075,9 @@ func (s *Server) Run(ctx context.Context, t Transport) error {
 func (s *Server) bind(mcpConn Connection, conn *jsonrpc2.Connection, state *ServerSessionState, onClose func()) *ServerSession {
 	assert(mcpConn != nil && conn != nil, "nil connection")
 	ss := &ServerSession{conn: conn, mcpConn: mcpConn, server: s, onClose: onClose}
+	if n := s.opts.MaxConcurrentRequestsPerSession; n > 0 {
+		ss.requestSlots = make(chan struct{}, n)
+	}
 	if state != nil {
 		ss.state = *state
 	}
@@ -961,6 +1126,10 @@ func (s *Server) Connect(ctx context.Context, t Transport, opts *ServerSessionOp
 		onClose = opts.onClose
 	}
 
+	if mt, ok := t.(methodCheckingTransport); ok {
+		mt.setMethodInfos(s.methodInfos)
+	}
+
 	s.opts.Logger.Info("server connecting")
 	ss, err := connect(ctx, t, s, state, onClose)
 	if err != nil {
@@ -1048,6 +1217,11 @@ type ServerSession struct {
 	mcpConn         Connection
 	keepaliveCancel context.CancelFunc // TODO: theory around why keepaliveCancel need not be guarded
 
+	// requestSlots, if non-nil, limits concurrent requests: handling a
+	// request requires a send, and completing it a receive.
+	// See [ServerOptions.MaxConcurrentRequestsPerSession].
+	requestSlots chan struct{}
+
 	mu    sync.Mutex
 	state ServerSessionState
 }
@@ -1096,6 +1270,21 @@ func (ss *ServerSession) ID() string {
 	return ""
 }
 
+// CancelRequest cancels the in-flight request to the client with the given
+// JSON-RPC ID, by sending it a "notifications/cancelled" notification with
+// the given reason. The call that made the request returns immediately, with
+// an error wrapping a [*CancelledError].
+//
+// Request IDs are assigned by the session when a request is sent. To learn
+// the ID of a request, make it with a context from [WithRequestIDFunc]. A
+// request whose context is available can be cancelled by cancelling its
+// context instead.
+//
+// It is an error if no request with the ID is in flight.
+func (ss *ServerSession) CancelRequest(ctx context.Context, id jsonrpc.ID, reason string) error {
+	return cancelRequest(ctx, ss.conn, id, reason)
+}
+
 // Ping pings the client.
 func (ss *ServerSession) Ping(ctx context.Context, params *PingParams) error {
 	_, err := handleSend[*emptyResult](ctx, methodPing, newServerRequest(ss, orZero[Params](params)))
@@ -1197,19 +1386,33 @@ func (ss *ServerSession) Elicit(ctx context.Context, params *ElicitParams) (*Eli
 // The message is not sent if the client has not called SetLevel, or if its level
 // is below that of the last SetLevel.
 func (ss *ServerSession) Log(ctx context.Context, params *LoggingMessageParams) error {
+	if !ss.LogEnabled(params.Level) {
+		return nil
+	}
+	return handleNotify(ctx, notificationLoggingMessage, newServerRequest(ss, orZero[Params](params)))
+}
+
+// LogLevel returns the minimum level of the log messages sent by
+// [ServerSession.Log], as set by the client's last logging/setLevel request.
+// It returns the empty string if the client has not set a level.
+func (ss *ServerSession) LogLevel() LoggingLevel {
 	ss.mu.Lock()
-	logLevel := ss.state.LogLevel
-	ss.mu.Unlock()
+	defer ss.mu.Unlock()
+	return ss.state.LogLevel
+}
+
+// LogEnabled reports whether [ServerSession.Log] sends messages at the given
+// level. Handlers can use it to avoid building log messages that would be
+// dropped, such as expensive debug output.
+func (ss *ServerSession) LogEnabled(level LoggingLevel) bool {
+	logLevel := ss.LogLevel()
 	if logLevel == "" {
 		// The spec is unclear, but seems to imply that no log messages are sent until the client
 		// sets the level.
 		// TODO(jba): read other SDKs, possibly file an issue.
-		return nil
+		return false
 	}
-	if compareLevels(params.Level, logLevel) < 0 {
-		return nil
-	}
-	return handleNotify(ctx, notificationLoggingMessage, newServerRequest(ss, orZero[Params](params)))
+	return compareLevels(level, logLevel) >= 0
 }
 
 // AddSendingMiddleware wraps the current sending method handler using the provided
@@ -1270,11 +1473,13 @@ var serverMethodInfos = map[string]methodInfo{
 // initializeMethodInfo handles the workaround for #607: we must set
 // params.Capabilities.RootsV2.
 func initializeMethodInfo() methodInfo {
-	info := newServerMethodInfo(serverSessionMethod((*ServerSession).initialize), 0)
-	info.unmarshalParams = func(m json.RawMessage) (Params, error) {
+	info := newServerMethodInfo(typedServerMethodHandler[*InitializeParams, *InitializeResult](func(ctx context.Context, req *InitializeServerRequest) (*InitializeResult, error) {
+		return req.Session.initialize(ctx, req)
+	}), 0)
+	info.unmarshalParams = func(codec JSONCodec, m json.RawMessage) (Params, error) {
 		var params *initializeParamsV2
 		if m != nil {
-			if err := json.Unmarshal(m, &params); err != nil {
+			if err := codec.Unmarshal(m, &params); err != nil {
 				return nil, fmt.Errorf("unmarshaling %q into a %T: %w", m, params, err)
 			}
 		}
@@ -1288,7 +1493,28 @@ func initializeMethodInfo() methodInfo {
 
 func (ss *ServerSession) sendingMethodInfos() map[string]methodInfo { return clientMethodInfos }
 
-func (ss *ServerSession) receivingMethodInfos() map[string]methodInfo { return serverMethodInfos }
+func (s *Server) jsonCodec() JSONCodec { return s.opts.JSONCodec }
+
+func (ss *ServerSession) jsonCodec() JSONCodec {
+	if ss == nil {
+		// Tool handlers may be called without a session in tests.
+		return stdJSONCodec{}
+	}
+	return ss.server.jsonCodec()
+}
+
+func (ss *ServerSession) receivingMethodInfos() map[string]methodInfo { return ss.server.methodInfos() }
+
+// methodInfos returns the methods the server handles: the standard methods,
+// plus any experimental methods.
+func (s *Server) methodInfos() map[string]methodInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.receivingMethodInfos_ != nil {
+		return s.receivingMethodInfos_
+	}
+	return serverMethodInfos
+}
 
 func (ss *ServerSession) sendingMethodHandler() MethodHandler {
 	s := ss.server
@@ -1332,13 +1558,55 @@ func (ss *ServerSession) handle(ctx context.Context, req *jsonrpc.Request) (any,
 		jsonrpc2.Async(ctx)
 	}
 
+	if req.IsCall() && ss.requestSlots != nil && req.Method != methodInitialize && req.Method != methodPing {
+		if err := ss.acquireRequestSlot(ctx); err != nil {
+			return nil, err
+		}
+		defer func() { <-ss.requestSlots }()
+	}
+
 	// For the streamable transport, we need the request ID to correlate
 	// server->client calls and notifications to the incoming request from which
 	// they originated. See [idContextKey] for details.
 	ctx = context.WithValue(ctx, idContextKey{}, req.ID)
+	ctx = context.WithValue(ctx, sessionContextKey{}, ss)
+	if extra, ok := req.Extra.(*RequestExtra); ok && extra.contextHeader != nil {
+		ctx = context.WithValue(ctx, headerContextKey{}, extra.contextHeader)
+	}
 	return handleReceive(ctx, ss, req)
 }
 
+// acquireRequestSlot reserves one of the session's concurrent request slots,
+// waiting for one if the server queues excess requests.
+func (ss *ServerSession) acquireRequestSlot(ctx context.Context) error {
+	select {
+	case ss.requestSlots <- struct{}{}:
+		return nil
+	default:
+	}
+	if !ss.server.opts.QueueExcessRequests {
+		return TooManyRequestsError(cap(ss.requestSlots))
+	}
+	select {
+	case ss.requestSlots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// sessionContextKey is the context key for the [ServerSession] handling a
+// request. It lets a [LoggingHandler] created without a session log to the
+// session of the current request.
+type sessionContextKey struct{}
+
+// sessionFromContext returns the ServerSession handling the request whose
+// context is ctx, or nil.
+func sessionFromContext(ctx context.Context) *ServerSession {
+	ss, _ := ctx.Value(sessionContextKey{}).(*ServerSession)
+	return ss
+}
+
 // InitializeParams returns the InitializeParams provided during the client's
 // initial connection.
 func (ss *ServerSession) InitializeParams() *InitializeParams {
@@ -1347,21 +1615,38 @@ func (ss *ServerSession) InitializeParams() *InitializeParams {
 	return ss.state.InitializeParams
 }
 
-func (ss *ServerSession) initialize(ctx context.Context, params *InitializeParams) (*InitializeResult, error) {
+func (ss *ServerSession) initialize(ctx context.Context, req *InitializeServerRequest) (*InitializeResult, error) {
+	params := req.Params
 	if params == nil {
 		return nil, fmt.Errorf("%w: \"params\" must be be provided", jsonrpc2.ErrInvalidParams)
 	}
+	s := ss.server
+	version := negotiatedVersion(params.ProtocolVersion)
+	if allowed := s.opts.ProtocolVersions; len(allowed) > 0 {
+		if slices.Contains(allowed, params.ProtocolVersion) {
+			version = params.ProtocolVersion
+		} else {
+			// Versions are dates, so the latest version sorts last.
+			version = slices.Max(allowed)
+		}
+	}
+	instructions := s.opts.Instructions
+	if f := s.opts.InstructionsFunc; f != nil {
+		var err error
+		if instructions, err = f(ctx, req); err != nil {
+			return nil, err
+		}
+	}
 	ss.updateState(func(state *ServerSessionState) {
 		state.InitializeParams = params
 	})
 
-	s := ss.server
 	return &InitializeResult{
 		// TODO(rfindley): alter behavior when falling back to an older version:
 		// reject unsupported features.
-		ProtocolVersion: negotiatedVersion(params.ProtocolVersion),
+		ProtocolVersion: version,
 		Capabilities:    s.capabilities(),
-		Instructions:    s.opts.Instructions,
+		Instructions:    instructions,
 		ServerInfo:      s.impl,
 	}, nil
 }
@@ -1380,6 +1665,9 @@ func (ss *ServerSession) cancel(context.Context, *CancelledParams) (Result, erro
 }
 
 func (ss *ServerSession) setLevel(_ context.Context, params *SetLoggingLevelParams) (*emptyResult, error) {
+	if _, ok := mcpToSlog[params.Level]; !ok {
+		return nil, fmt.Errorf("%w: unknown logging level %q", jsonrpc2.ErrInvalidParams, params.Level)
+	}
 	ss.updateState(func(state *ServerSessionState) {
 		state.LogLevel = params.Level
 	})
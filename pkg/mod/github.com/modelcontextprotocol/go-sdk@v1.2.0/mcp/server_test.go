@@ -8,6 +8,8 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"log/slog"
 	"slices"
@@ -18,6 +20,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/internal/jsonrpc2"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 )
 
 type testItem struct {
@@ -499,7 +502,7 @@ func TestServerSessionkeepaliveCancelOverwritten(t *testing.T) {
 	ss := &ServerSession{server: server}
 
 	// 1. Initialize the session.
-	_, err := ss.initialize(context.Background(), &InitializeParams{})
+	_, err := ss.initialize(context.Background(), &InitializeServerRequest{Session: ss, Params: &InitializeParams{}})
 	if err != nil {
 		t.Fatalf("ServerSession initialize failed: %v", err)
 	}
@@ -938,3 +941,165 @@ func TestServerCapabilitiesOverWire(t *testing.T) {
 		})
 	}
 }
+
+func TestIconsInListResults(t *testing.T) {
+	ctx := context.Background()
+	icons := []Icon{
+		{Source: "https://example.com/icon.svg", MIMEType: "image/svg+xml", Sizes: []string{"any"}},
+		{Source: "data:image/png;base64,iVBORw0KGgo=", MIMEType: "image/png", Theme: IconThemeDark},
+	}
+	impl := &Implementation{Name: "server", Version: "v1", Icons: icons}
+	s := NewServer(impl, nil)
+	s.AddTool(&Tool{Name: "t", InputSchema: &jsonschema.Schema{Type: "object"}, Icons: icons}, nil)
+	s.AddPrompt(&Prompt{Name: "p", Icons: icons}, nil)
+	s.AddResource(&Resource{Name: "r", URI: "file:///r", Icons: icons}, nil)
+	s.AddResourceTemplate(&ResourceTemplate{Name: "rt", URITemplate: "file:///{x}", Icons: icons}, nil)
+
+	ct, st := NewInMemoryTransports()
+	if _, err := s.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	check := func(what string, got []Icon) {
+		t.Helper()
+		if diff := cmp.Diff(icons, got); diff != "" {
+			t.Errorf("%s icons mismatch (-want +got):\n%s", what, diff)
+		}
+	}
+	check("server", cs.InitializeResult().ServerInfo.Icons)
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("tool", tools.Tools[0].Icons)
+	prompts, err := cs.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("prompt", prompts.Prompts[0].Icons)
+	resources, err := cs.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("resource", resources.Resources[0].Icons)
+	templates, err := cs.ListResourceTemplates(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	check("resource template", templates.ResourceTemplates[0].Icons)
+}
+
+func TestMaxConcurrentRequestsPerSession(t *testing.T) {
+	for _, queue := range []bool{false, true} {
+		t.Run(fmt.Sprintf("queue=%t", queue), func(t *testing.T) {
+			ctx := context.Background()
+			server := NewServer(testImpl, &ServerOptions{
+				MaxConcurrentRequestsPerSession: 1,
+				QueueExcessRequests:             queue,
+			})
+			started := make(chan struct{}, 2)
+			release := make(chan struct{})
+			AddTool(server, &Tool{Name: "block"}, func(context.Context, *CallToolRequest, any) (*CallToolResult, any, error) {
+				started <- struct{}{}
+				<-release
+				return nil, nil, nil
+			})
+			ct, st := NewInMemoryTransports()
+			ss, err := server.Connect(ctx, st, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ss.Close()
+			cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cs.Close()
+
+			first := make(chan error, 1)
+			go func() {
+				_, err := cs.CallTool(ctx, &CallToolParams{Name: "block"})
+				first <- err
+			}()
+			<-started
+
+			// Pings are not limited.
+			if err := cs.Ping(ctx, nil); err != nil {
+				t.Errorf("Ping: %v", err)
+			}
+
+			if !queue {
+				_, err := cs.CallTool(ctx, &CallToolParams{Name: "block"})
+				var wireErr *jsonrpc.Error
+				if !errors.As(err, &wireErr) || wireErr.Code != CodeTooManyRequests {
+					t.Errorf("excess call: got error %v, want code %d", err, CodeTooManyRequests)
+				}
+				close(release)
+			} else {
+				second := make(chan error, 1)
+				go func() {
+					_, err := cs.CallTool(ctx, &CallToolParams{Name: "block"})
+					second <- err
+				}()
+				// The second call waits for the first.
+				select {
+				case <-started:
+					t.Fatal("second call started while first was running")
+				case <-time.After(20 * time.Millisecond):
+				}
+				close(release)
+				if err := <-second; err != nil {
+					t.Errorf("queued call: %v", err)
+				}
+			}
+			if err := <-first; err != nil {
+				t.Errorf("first call: %v", err)
+			}
+		})
+	}
+}
+
+func TestInstructionsFunc(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, &ServerOptions{
+		Instructions: "ignored",
+		InstructionsFunc: func(_ context.Context, req *InitializeServerRequest) (string, error) {
+			name := req.Params.ClientInfo.Name
+			if name == "banned" {
+				return "", errors.New("client not allowed")
+			}
+			return "Hello, " + name + ".", nil
+		},
+	})
+
+	connect := func(name string) (*ClientSession, error) {
+		ct, st := NewInMemoryTransports()
+		ss, err := server.Connect(ctx, st, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { ss.Close() })
+		return NewClient(&Implementation{Name: name, Version: "v1"}, nil).Connect(ctx, ct, nil)
+	}
+
+	cs, err := connect("gopher")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if got, want := cs.InitializeResult().Instructions, "Hello, gopher."; got != want {
+		t.Errorf("Instructions: got %q, want %q", got, want)
+	}
+
+	if cs, err := connect("banned"); err == nil {
+		cs.Close()
+		t.Error("connecting banned client: got nil error, want error")
+	} else if !strings.Contains(err.Error(), "client not allowed") {
+		t.Errorf("connecting banned client: got error %v, want InstructionsFunc error", err)
+	}
+}
@@ -61,6 +61,17 @@ func negotiatedVersion(clientVersion string) string {
 	return clientVersion
 }
 
+// checkProtocolVersions panics if versions, from the ProtocolVersions field of
+// [ClientOptions] or [ServerOptions], contains a version that the SDK does not
+// support.
+func checkProtocolVersions(versions []string) {
+	for _, v := range versions {
+		if !slices.Contains(supportedProtocolVersions, v) {
+			panic(fmt.Errorf("unsupported protocol version %q in ProtocolVersions (supported versions: %s)", v, strings.Join(supportedProtocolVersions, ",")))
+		}
+	}
+}
+
 // A MethodHandler handles MCP messages.
 // For methods, exactly one of the return values must be nil.
 // For notifications, both must be nil.
@@ -76,6 +87,7 @@ type Session interface {
 	sendingMethodHandler() MethodHandler
 	receivingMethodHandler() MethodHandler
 	getConn() *jsonrpc2.Connection
+	jsonCodec() JSONCodec
 }
 
 // Middleware is a function from [MethodHandler] to [MethodHandler].
@@ -91,8 +103,12 @@ func addMiddleware(handlerp *MethodHandler, middleware []Middleware) {
 func defaultSendingMethodHandler(ctx context.Context, method string, req Request) (Result, error) {
 	info, ok := req.GetSession().sendingMethodInfos()[method]
 	if !ok {
-		// This can be called from user code, with an arbitrary value for method.
-		return nil, jsonrpc2.ErrNotHandled
+		// Experimental methods are not in the method tables.
+		if _, isExp := req.GetParams().(*ExperimentalParams); !isExp {
+			// This can be called from user code, with an arbitrary value for method.
+			return nil, jsonrpc2.ErrNotHandled
+		}
+		info = experimentalMethodInfo
 	}
 	params := req.GetParams()
 	if initParams, ok := params.(*InitializeParams); ok {
@@ -156,7 +172,7 @@ func handleReceive[S Session](ctx context.Context, session S, jreq *jsonrpc.Requ
 	if err != nil {
 		return nil, err
 	}
-	params, err := info.unmarshalParams(jreq.Params)
+	params, err := info.unmarshalParams(session.jsonCodec(), jreq.Params)
 	if err != nil {
 		return nil, fmt.Errorf("handling '%s': %w", jreq.Method, err)
 	}
@@ -209,7 +225,7 @@ type methodInfo struct {
 	flags methodFlags
 	// Unmarshal params from the wire into a Params struct.
 	// Used on the receive side.
-	unmarshalParams func(json.RawMessage) (Params, error)
+	unmarshalParams func(JSONCodec, json.RawMessage) (Params, error)
 	newRequest      func(Session, Params, *RequestExtra) Request
 	// Run the code when a call to the method is received.
 	// Used on the receive side.
@@ -280,10 +296,10 @@ func newServerMethodInfo[P paramsPtr[T], R Result, T any](d typedServerMethodHan
 func newMethodInfo[P paramsPtr[T], R Result, T any](flags methodFlags) methodInfo {
 	return methodInfo{
 		flags: flags,
-		unmarshalParams: func(m json.RawMessage) (Params, error) {
+		unmarshalParams: func(codec JSONCodec, m json.RawMessage) (Params, error) {
 			var p P
 			if m != nil {
-				if err := json.Unmarshal(m, &p); err != nil {
+				if err := codec.Unmarshal(m, &p); err != nil {
 					return nil, fmt.Errorf("unmarshaling %q into a %T: %w", m, p, err)
 				}
 			}
@@ -347,6 +363,15 @@ const (
 	// before processing the request. The client should execute the elicitation handler
 	// with the elicitations provided in the error data.
 	CodeURLElicitationRequired = -32042
+	// CodeTooManyRequests indicates that the server rejected a request because
+	// the session has too many requests in progress.
+	// See [ServerOptions.MaxConcurrentRequestsPerSession].
+	//
+	// This code is not defined by MCP: it is specific to this SDK, taken from
+	// the range -32000 to -32099 that JSON-RPC reserves for
+	// implementation-defined server errors. Other implementations may not
+	// recognize it.
+	CodeTooManyRequests = -32029
 )
 
 // URLElicitationRequiredError returns an error indicating that URL elicitation is required
@@ -364,18 +389,90 @@ func URLElicitationRequiredError(elicitations []*ElicitParams) error {
 		}
 	}
 
-	data, err := json.Marshal(map[string]any{
-		"elicitations": elicitations,
+	return jsonrpc.NewError(CodeURLElicitationRequired, "URL elicitation required", &URLElicitationRequiredErrorData{
+		Elicitations: elicitations,
 	})
-	if err != nil {
-		// This should never happen with valid ElicitParams
-		panic(fmt.Sprintf("failed to marshal elicitations: %v", err))
+}
+
+// URLElicitationRequiredErrorData is the data of the error returned by
+// [URLElicitationRequiredError].
+type URLElicitationRequiredErrorData struct {
+	// Elicitations are the URL mode elicitations that must be completed before
+	// the request can be processed.
+	Elicitations []*ElicitParams `json:"elicitations"`
+}
+
+// ParseURLElicitationRequiredError reports whether err is, or wraps, an error
+// returned by [URLElicitationRequiredError]. If so, it returns the error's
+// data.
+func ParseURLElicitationRequiredError(err error) (*URLElicitationRequiredErrorData, bool) {
+	var data URLElicitationRequiredErrorData
+	if !jsonrpc.DecodeErrorData(err, CodeURLElicitationRequired, &data) {
+		return nil, false
 	}
-	return &jsonrpc.Error{
-		Code:    CodeURLElicitationRequired,
-		Message: "URL elicitation required",
-		Data:    json.RawMessage(data),
+	return &data, true
+}
+
+// TooManyRequestsError returns an error indicating that a request was rejected
+// because the session already has limit requests in progress.
+func TooManyRequestsError(limit int) error {
+	return jsonrpc.NewError(CodeTooManyRequests, fmt.Sprintf("too many concurrent requests (limit %d)", limit), &TooManyRequestsErrorData{
+		Limit: limit,
+	})
+}
+
+// TooManyRequestsErrorData is the data of the error returned by
+// [TooManyRequestsError].
+type TooManyRequestsErrorData struct {
+	// Limit is the maximum number of concurrent requests per session.
+	Limit int `json:"limit"`
+}
+
+// ParseTooManyRequestsError reports whether err is, or wraps, an error
+// returned by [TooManyRequestsError]. If so, it returns the error's data.
+func ParseTooManyRequestsError(err error) (*TooManyRequestsErrorData, bool) {
+	var data TooManyRequestsErrorData
+	if !jsonrpc.DecodeErrorData(err, CodeTooManyRequests, &data) {
+		return nil, false
+	}
+	return &data, true
+}
+
+// UnsupportedProtocolVersionError returns an error indicating that a protocol
+// version proposed during initialization is not supported. The supported
+// parameter lists the versions that are.
+//
+// A server may return it for the version the client requests, although
+// servers built with this SDK respond with a version they support instead.
+// [Client.Connect] returns it, wrapped, for a version that the server responds
+// with and the client does not support or allow.
+func UnsupportedProtocolVersionError(requested string, supported []string) error {
+	return jsonrpc.NewError(jsonrpc.CodeInvalidParams, "Unsupported protocol version", &UnsupportedProtocolVersionErrorData{
+		Supported: supported,
+		Requested: requested,
+	})
+}
+
+// UnsupportedProtocolVersionErrorData is the data of the error returned by
+// [UnsupportedProtocolVersionError].
+type UnsupportedProtocolVersionErrorData struct {
+	// Supported lists the protocol versions that are supported.
+	Supported []string `json:"supported"`
+	// Requested is the protocol version that is not supported: the one that
+	// the client requested, or the one that the server responded with.
+	Requested string `json:"requested"`
+}
+
+// ParseUnsupportedProtocolVersionError reports whether err is, or wraps, an
+// error returned by [UnsupportedProtocolVersionError], such as the error
+// returned by [Client.Connect] when the client and server do not agree on a
+// protocol version. If so, it returns the error's data.
+func ParseUnsupportedProtocolVersionError(err error) (*UnsupportedProtocolVersionErrorData, bool) {
+	var data UnsupportedProtocolVersionErrorData
+	if !jsonrpc.DecodeErrorData(err, jsonrpc.CodeInvalidParams, &data) || data.Supported == nil {
+		return nil, false
 	}
+	return &data, true
 }
 
 // Internal error codes
@@ -479,6 +576,10 @@ type RequestExtra struct {
 	TokenInfo *auth.TokenInfo // bearer token info (e.g. from OAuth) if any
 	Header    http.Header     // header from HTTP request, if any
 
+	// contextHeader holds the headers to add to the handler's context.
+	// See [StreamableHTTPOptions.ContextHeaders].
+	contextHeader http.Header
+
 	// If set, CloseSSEStream explicitly closes the current SSE request stream.
 	//
 	// [SEP-1699] introduced server-side SSE stream disconnection: for
@@ -498,6 +599,36 @@ type CloseSSEStreamArgs struct {
 	RetryAfter time.Duration
 }
 
+// headerContextKey is the context key for the headers selected by
+// [StreamableHTTPOptions.ContextHeaders].
+type headerContextKey struct{}
+
+// HeaderFromContext returns the HTTP request headers that were added to ctx
+// because they are listed in [StreamableHTTPOptions.ContextHeaders], or nil
+// if there are none.
+//
+// The result must not be modified.
+func HeaderFromContext(ctx context.Context) http.Header {
+	h, _ := ctx.Value(headerContextKey{}).(http.Header)
+	return h
+}
+
+// selectHeaders returns the headers of h with the given names, or nil if there
+// are none.
+func selectHeaders(h http.Header, names []string) http.Header {
+	var sel http.Header
+	for _, name := range names {
+		name = http.CanonicalHeaderKey(name)
+		if vs := h[name]; len(vs) > 0 {
+			if sel == nil {
+				sel = make(http.Header)
+			}
+			sel[name] = vs
+		}
+	}
+	return sel
+}
+
 func (*ClientRequest[P]) isRequest() {}
 func (*ServerRequest[P]) isRequest() {}
 
@@ -7,6 +7,7 @@ package mcp
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -122,8 +123,14 @@ type SSEServerTransport struct {
 	mu     sync.Mutex    // also guards writes to Response
 	closed bool          // set when the stream is closed
 	done   chan struct{} // closed when the connection is closed
+
+	// methodInfos, if set, returns the methods handled by the server, for
+	// validating incoming requests. See [methodCheckingTransport].
+	methodInfos func() map[string]methodInfo
 }
 
+func (t *SSEServerTransport) setMethodInfos(f func() map[string]methodInfo) { t.methodInfos = f }
+
 // ServeHTTP handles POST requests to the transport endpoint.
 func (t *SSEServerTransport) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	if t.incoming == nil {
@@ -146,7 +153,11 @@ func (t *SSEServerTransport) ServeHTTP(w http.ResponseWriter, req *http.Request)
 		return
 	}
 	if req, ok := msg.(*jsonrpc.Request); ok {
-		if _, err := checkRequest(req, serverMethodInfos); err != nil {
+		infos := serverMethodInfos
+		if t.methodInfos != nil {
+			infos = t.methodInfos()
+		}
+		if _, err := checkRequest(req, infos); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
 		}
@@ -477,3 +488,112 @@ func (c *sseClientConn) Close() error {
 	}
 	return nil
 }
+
+// An sseFallbackConn is the [Connection] of a [StreamableClientTransport]
+// with SSEFallback set.
+//
+// Its first write, of the initialize request, determines which transport the
+// server supports: if the server rejects the POST with status 400, 404 or
+// 405, the connection switches to the SSE transport and repeats the write
+// there. Other statuses, such as 401 and 403, are left to the caller, so that
+// authorization is handled as usual.
+// Reads wait until the transport is determined.
+type sseFallbackConn struct {
+	ctx        context.Context // for the hanging SSE GET request; detached from Connect
+	streamable *streamableClientConn
+	sse        *SSEClientTransport
+
+	mu    sync.Mutex // held while determining the transport
+	conn  Connection // the determined connection; set before ready is closed
+	ready chan struct{}
+
+	closeOnce sync.Once
+	done      chan struct{} // closed by Close
+}
+
+var _ clientConnection = (*sseFallbackConn)(nil)
+
+func (c *sseFallbackConn) SessionID() string {
+	select {
+	case <-c.ready:
+		return c.conn.SessionID()
+	default:
+		return ""
+	}
+}
+
+func (c *sseFallbackConn) sessionUpdated(state clientSessionState) {
+	select {
+	case <-c.ready:
+		if cc, ok := c.conn.(clientConnection); ok {
+			cc.sessionUpdated(state)
+		}
+	default:
+	}
+}
+
+func (c *sseFallbackConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.done:
+		return nil, io.EOF
+	case <-c.ready:
+		return c.conn.Read(ctx)
+	}
+}
+
+func (c *sseFallbackConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	select {
+	case <-c.ready:
+		return c.conn.Write(ctx, msg)
+	default:
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		// Determined while we waited for the lock.
+		return c.conn.Write(ctx, msg)
+	}
+	err := c.streamable.Write(ctx, msg)
+	var status httpStatusError
+	fallback := errors.Is(err, errSessionMissing) || errors.As(err, &status) && isFallbackStatus(int(status))
+	if !fallback {
+		if err == nil {
+			c.conn = c.streamable
+			close(c.ready)
+		}
+		return err
+	}
+	// The server doesn't support the streamable transport. It may be that
+	// the endpoint doesn't exist at all, but the SSE GET will tell.
+	c.streamable.Close()
+	conn, err := c.sse.Connect(c.ctx)
+	if err != nil {
+		return fmt.Errorf("falling back to SSE transport: %w", err)
+	}
+	c.conn = conn
+	close(c.ready)
+	return conn.Write(ctx, msg)
+}
+
+// isFallbackStatus reports whether a response with the given status to the
+// initialize request indicates that the server does not support the
+// streamable transport, as the spec's backwards compatibility section lists.
+func isFallbackStatus(status int) bool {
+	switch status {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusMethodNotAllowed:
+		return true
+	}
+	return false
+}
+
+func (c *sseFallbackConn) Close() error {
+	c.closeOnce.Do(func() { close(c.done) })
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn != nil {
+		return c.conn.Close()
+	}
+	return c.streamable.Close()
+}
@@ -131,3 +131,74 @@ type roundTripperFunc func(*http.Request) (*http.Response, error)
 func (f roundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
 	return f(req)
 }
+
+func TestStreamableClientSSEFallback(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, nil)
+	AddTool(server, &Tool{Name: "greet"}, sayHi)
+
+	for _, test := range []struct {
+		name    string
+		handler http.Handler
+	}{
+		{"sse", NewSSEHandler(func(*http.Request) *Server { return server }, nil)},
+		{"streamable", NewStreamableHTTPHandler(func(*http.Request) *Server { return server }, nil)},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			var gets atomic.Int32
+			httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				if req.Method == http.MethodGet {
+					gets.Add(1)
+				}
+				test.handler.ServeHTTP(w, req)
+			}))
+			defer httpServer.Close()
+
+			transport := &StreamableClientTransport{Endpoint: httpServer.URL, SSEFallback: true}
+			cs, err := NewClient(testImpl, nil).Connect(ctx, transport, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer cs.Close()
+			res, err := cs.CallTool(ctx, &CallToolParams{
+				Name:      "greet",
+				Arguments: map[string]any{"Name": "user"},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := res.Content[0].(*TextContent).Text, "hi user"; got != want {
+				t.Errorf("got %q, want %q", got, want)
+			}
+			// Exactly one GET: either the SSE stream, or the standalone stream of
+			// the streamable transport.
+			if got := gets.Load(); got != 1 {
+				t.Errorf("got %d GET requests, want 1", got)
+			}
+			if _, isSSE := cs.mcpConn.(*sseFallbackConn).conn.(*sseClientConn); isSSE != (test.name == "sse") {
+				t.Errorf("connected with SSE transport: %t, want %t", isSSE, test.name == "sse")
+			}
+		})
+	}
+
+	// An unauthorized response is not a reason to fall back.
+	t.Run("unauthorized", func(t *testing.T) {
+		var gets atomic.Int32
+		httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if req.Method == http.MethodGet {
+				gets.Add(1)
+			}
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+		}))
+		defer httpServer.Close()
+
+		transport := &StreamableClientTransport{Endpoint: httpServer.URL, SSEFallback: true}
+		if cs, err := NewClient(testImpl, nil).Connect(ctx, transport, nil); err == nil {
+			cs.Close()
+			t.Fatal("Connect succeeded, want error")
+		}
+		if got := gets.Load(); got != 0 {
+			t.Errorf("got %d GET requests, want none", got)
+		}
+	})
+}
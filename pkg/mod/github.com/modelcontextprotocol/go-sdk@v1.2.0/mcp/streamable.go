@@ -10,6 +10,7 @@ package mcp
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"encoding/json"
 	"errors"
@@ -19,6 +20,7 @@ import (
 	"maps"
 	"math"
 	"math/rand/v2"
+	"net"
 	"net/http"
 	"slices"
 	"strconv"
@@ -49,6 +51,10 @@ type StreamableHTTPHandler struct {
 
 	onTransportDeletion func(sessionID string) // for testing
 
+	// If bindPath is set, sessions may only be used at the URL path at which
+	// they were created. See [StreamableHTTPMux].
+	bindPath bool
+
 	mu       sync.Mutex
 	sessions map[string]*sessionInfo // keyed by session ID
 }
@@ -60,6 +66,9 @@ type sessionInfo struct {
 	// If non-empty, subsequent requests must have the same user ID to prevent
 	// session hijacking.
 	userID string
+	// path is the URL path of the request that created the session, if the
+	// handler binds sessions to paths.
+	path string
 
 	// If timeout is set, automatically close the session after an idle period.
 	timeout time.Duration
@@ -160,6 +169,105 @@ type StreamableHTTPOptions struct {
 	//
 	// If SessionTimeout is the zero value, idle sessions are never closed.
 	SessionTimeout time.Duration
+
+	// SessionIDGenerator, if non-nil, returns the ID for a new session, given
+	// the HTTP request that creates it. Use it to issue IDs that can be
+	// validated without access to the handler, such as signed or
+	// tenant-scoped IDs.
+	//
+	// It takes precedence over [ServerOptions.GetSessionID]. As with that
+	// function, returning the empty string means that the session has no ID.
+	// If it returns an error, the request fails with 500 Internal Server
+	// Error.
+	SessionIDGenerator func(*http.Request) (string, error)
+
+	// ContextHeaders are the names of HTTP request headers, such as
+	// "Traceparent" or "X-Tenant", to add to the context of the handlers of
+	// each request. Handlers, and any code they call, can retrieve them with
+	// [HeaderFromContext].
+	//
+	// All request headers are also available to handlers in
+	// [RequestExtra.Header].
+	ContextHeaders []string
+
+	// NotificationBuffer, if non-nil, enables buffering of server-initiated
+	// notifications, such as resource updates and log messages, while a
+	// session's client has no standalone SSE stream (hanging GET) open.
+	// Buffered notifications are replayed on the client's next GET.
+	//
+	// If nil, such notifications are dropped.
+	//
+	// Buffering is not needed with an EventStore, which already persists
+	// these notifications for clients that resume the stream with
+	// Last-Event-ID, and is not used if EventStore is set.
+	NotificationBuffer *NotificationBufferOptions
+}
+
+// NotificationBufferOptions configure the buffering of notifications for
+// disconnected clients. See [StreamableHTTPOptions.NotificationBuffer].
+type NotificationBufferOptions struct {
+	// MaxMessages is the maximum number of notifications to buffer for each
+	// session. When the buffer is full, the oldest notification is dropped.
+	//
+	// If zero, it defaults to 100.
+	MaxMessages int
+
+	// MaxAge is the maximum time to buffer a notification. Older
+	// notifications are dropped rather than replayed.
+	//
+	// If zero, notifications do not expire.
+	MaxAge time.Duration
+}
+
+const defaultNotificationBufferSize = 100
+
+// A notificationBuffer holds notifications for the standalone SSE stream
+// while it is not connected.
+type notificationBuffer struct {
+	opts NotificationBufferOptions
+	msgs []bufferedNotification
+}
+
+type bufferedNotification struct {
+	data  []byte
+	added time.Time
+}
+
+func newNotificationBuffer(opts *NotificationBufferOptions) *notificationBuffer {
+	b := &notificationBuffer{opts: *opts}
+	if b.opts.MaxMessages <= 0 {
+		b.opts.MaxMessages = defaultNotificationBufferSize
+	}
+	return b
+}
+
+// add buffers data, dropping the oldest notification if the buffer is full.
+func (b *notificationBuffer) add(data []byte, now time.Time) {
+	if len(b.msgs) == b.opts.MaxMessages {
+		b.msgs = slices.Delete(b.msgs, 0, 1)
+	}
+	b.msgs = append(b.msgs, bufferedNotification{data: data, added: now})
+}
+
+// pending discards expired notifications and returns the rest, oldest first.
+// They stay in the buffer until removed with [notificationBuffer.remove], so
+// that notifications that could not be written are replayed later.
+func (b *notificationBuffer) pending(now time.Time) [][]byte {
+	if b.opts.MaxAge > 0 {
+		b.msgs = slices.DeleteFunc(b.msgs, func(m bufferedNotification) bool {
+			return now.Sub(m.added) > b.opts.MaxAge
+		})
+	}
+	res := make([][]byte, len(b.msgs))
+	for i, m := range b.msgs {
+		res[i] = m.data
+	}
+	return res
+}
+
+// remove removes the n oldest notifications.
+func (b *notificationBuffer) remove(n int) {
+	b.msgs = slices.Delete(b.msgs, 0, n)
 }
 
 // NewStreamableHTTPHandler returns a new [StreamableHTTPHandler].
@@ -183,6 +291,48 @@ func NewStreamableHTTPHandler(getServer func(*http.Request) *Server, opts *Strea
 	return h
 }
 
+// A StreamableHTTPMux is an http.Handler that routes streamable MCP requests
+// to different servers according to their URL path.
+//
+// Patterns are those of [http.ServeMux], including wildcards, so a single
+// pattern such as "/tenants/{id}/mcp" can serve a separate server for each
+// tenant: the getServer function passed to [StreamableHTTPMux.Handle] can
+// call [http.Request.PathValue] to select the tenant's server.
+//
+// Sessions are bound to the URL path at which they were created. A request
+// with the ID of a session created at a different path, such as that of
+// another tenant, is rejected as if the session did not exist.
+type StreamableHTTPMux struct {
+	opts StreamableHTTPOptions
+	mux  *http.ServeMux
+}
+
+// NewStreamableHTTPMux returns a new [StreamableHTTPMux] with no patterns.
+// The options apply to the handlers of all patterns.
+func NewStreamableHTTPMux(opts *StreamableHTTPOptions) *StreamableHTTPMux {
+	m := &StreamableHTTPMux{mux: http.NewServeMux()}
+	if opts != nil {
+		m.opts = *opts
+	}
+	return m
+}
+
+// Handle registers a [StreamableHTTPHandler] for the given pattern, which uses
+// getServer to create or look up servers for new sessions, as with
+// [NewStreamableHTTPHandler]. Each pattern has its own set of sessions.
+//
+// Handle panics if the pattern is invalid or conflicts with a registered
+// pattern, like [http.ServeMux.Handle].
+func (m *StreamableHTTPMux) Handle(pattern string, getServer func(*http.Request) *Server) {
+	h := NewStreamableHTTPHandler(getServer, &m.opts)
+	h.bindPath = true
+	m.mux.Handle(pattern, h)
+}
+
+func (m *StreamableHTTPMux) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	m.mux.ServeHTTP(w, req)
+}
+
 // closeAll closes all ongoing sessions, for tests.
 //
 // TODO(rfindley): investigate the best API for callers to configure their
@@ -247,6 +397,12 @@ func (h *StreamableHTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Reque
 			http.Error(w, "session not found", http.StatusNotFound)
 			return
 		}
+		// A session bound to a path does not exist at other paths, so that
+		// sessions of different tenants are isolated from each other.
+		if sessInfo != nil && h.bindPath && sessInfo.path != req.URL.Path {
+			http.Error(w, "session not found", http.StatusNotFound)
+			return
+		}
 		// Prevent session hijacking: if the session was created with a user ID,
 		// verify that subsequent requests come from the same user.
 		if sessInfo != nil && sessInfo.userID != "" {
@@ -333,14 +489,28 @@ func (h *StreamableHTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Reque
 		if sessionID == "" {
 			// In stateless mode, sessionID may be nonempty even if there's no
 			// existing transport.
-			sessionID = server.opts.GetSessionID()
+			if h.opts.SessionIDGenerator != nil {
+				id, err := h.opts.SessionIDGenerator(req)
+				if err != nil {
+					h.opts.Logger.Error("generating session ID", "error", err)
+					http.Error(w, "failed to generate session ID", http.StatusInternalServerError)
+					return
+				}
+				sessionID = id
+			} else {
+				sessionID = server.opts.GetSessionID()
+			}
 		}
 		transport := &StreamableServerTransport{
-			SessionID:    sessionID,
-			Stateless:    h.opts.Stateless,
-			EventStore:   h.opts.EventStore,
-			jsonResponse: h.opts.JSONResponse,
-			logger:       h.opts.Logger,
+			SessionID:      sessionID,
+			Stateless:      h.opts.Stateless,
+			EventStore:     h.opts.EventStore,
+			jsonResponse:   h.opts.JSONResponse,
+			logger:         h.opts.Logger,
+			contextHeaders: h.opts.ContextHeaders,
+		}
+		if h.opts.NotificationBuffer != nil && h.opts.EventStore == nil {
+			transport.notificationBuffer = newNotificationBuffer(h.opts.NotificationBuffer)
 		}
 
 		// Sessions without a session ID are also stateless: there's no way to
@@ -433,6 +603,9 @@ func (h *StreamableHTTPHandler) ServeHTTP(w http.ResponseWriter, req *http.Reque
 			transport: transport,
 			userID:    userID,
 		}
+		if h.bindPath {
+			sessInfo.path = req.URL.Path
+		}
 
 		if stateless {
 			// Stateless mode: close the session when the request exits.
@@ -532,10 +705,25 @@ type StreamableServerTransport struct {
 	// to write their own streamable HTTP handler.
 	logger *slog.Logger
 
+	// contextHeaders are the headers to add to handler contexts.
+	// See [StreamableHTTPOptions.ContextHeaders].
+	contextHeaders []string
+
+	// notificationBuffer, if set, buffers notifications for the standalone
+	// SSE stream while it is not connected.
+	// See [StreamableHTTPOptions.NotificationBuffer].
+	notificationBuffer *notificationBuffer
+
+	// methodInfos, if set, returns the methods handled by the server, for
+	// validating incoming requests. See [methodCheckingTransport].
+	methodInfos func() map[string]methodInfo
+
 	// connection is non-nil if and only if the transport has been connected.
 	connection *streamableServerConn
 }
 
+func (t *StreamableServerTransport) setMethodInfos(f func() map[string]methodInfo) { t.methodInfos = f }
+
 // Connect implements the [Transport] interface.
 func (t *StreamableServerTransport) Connect(ctx context.Context) (Connection, error) {
 	if t.connection != nil {
@@ -546,6 +734,8 @@ func (t *StreamableServerTransport) Connect(ctx context.Context) (Connection, er
 		stateless:      t.Stateless,
 		eventStore:     t.EventStore,
 		jsonResponse:   t.jsonResponse,
+		contextHeaders: t.contextHeaders,
+		methodInfos:    t.methodInfos,
 		logger:         ensureLogger(t.logger), // see #556: must be non-nil
 		incoming:       make(chan jsonrpc.Message, 10),
 		done:           make(chan struct{}),
@@ -561,14 +751,17 @@ func (t *StreamableServerTransport) Connect(ctx context.Context) (Connection, er
 	if err != nil {
 		return nil, err
 	}
+	t.connection.streams[""].buffer = t.notificationBuffer
 	return t.connection, nil
 }
 
 type streamableServerConn struct {
-	sessionID    string
-	stateless    bool
-	jsonResponse bool
-	eventStore   EventStore
+	sessionID      string
+	stateless      bool
+	jsonResponse   bool
+	eventStore     EventStore
+	contextHeaders []string
+	methodInfos    func() map[string]methodInfo // may be nil
 
 	logger *slog.Logger
 
@@ -659,6 +852,11 @@ type stream struct {
 	// the spec and earlier there was a concept of batching, in which POST
 	// payloads could hold multiple requests or responses.
 	requests map[jsonrpc.ID]struct{}
+
+	// buffer, if non-nil, holds notifications that could not be delivered
+	// because the stream was not connected. Only the standalone SSE stream
+	// has a buffer.
+	buffer *notificationBuffer
 }
 
 // close sends a 'close' event to the client (if protocolVersion >= 2025-11-25
@@ -970,16 +1168,29 @@ func (c *streamableServerConn) acquireStream(ctx context.Context, w http.Respons
 		}
 	}
 
-	for _, data := range toReplay {
+	// Buffered notifications follow the stored events. They are removed from
+	// the buffer only once written, so that a failed write leaves the rest for
+	// the next GET.
+	stored := len(toReplay)
+	if !tempStream && s.buffer != nil {
+		toReplay = append(toReplay, s.buffer.pending(time.Now())...)
+	}
+	for i, data := range toReplay {
 		lastIdx++
 		e := Event{Name: "message", Data: data}
 		if c.eventStore != nil {
 			e.ID = formatEventID(s.id, lastIdx)
 		}
 		if _, err := writeEvent(w, e); err != nil {
+			if i > stored {
+				s.buffer.remove(i - stored)
+			}
 			return nil, nil
 		}
 	}
+	if len(toReplay) > stored {
+		s.buffer.remove(len(toReplay) - stored)
+	}
 
 	if tempStream || s.doneLocked() {
 		// Nothing more to do.
@@ -1051,7 +1262,11 @@ func (c *streamableServerConn) servePOST(w http.ResponseWriter, req *http.Reques
 			// Preemptively check that this is a valid request, so that we can fail
 			// the HTTP request. If we didn't do this, a request with a bad method or
 			// missing ID could be silently swallowed.
-			if _, err := checkRequest(jreq, serverMethodInfos); err != nil {
+			infos := serverMethodInfos
+			if c.methodInfos != nil {
+				infos = c.methodInfos()
+			}
+			if _, err := checkRequest(jreq, infos); err != nil {
 				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
@@ -1065,8 +1280,9 @@ func (c *streamableServerConn) servePOST(w http.ResponseWriter, req *http.Reques
 			}
 			// Include metadata for all requests (including notifications).
 			jreq.Extra = &RequestExtra{
-				TokenInfo: tokenInfo,
-				Header:    req.Header,
+				TokenInfo:     tokenInfo,
+				Header:        req.Header,
+				contextHeader: selectHeaders(req.Header, c.contextHeaders),
 			}
 			if jreq.IsCall() {
 				calls[jreq.ID] = struct{}{}
@@ -1347,6 +1563,12 @@ func (c *streamableServerConn) Write(ctx context.Context, msg jsonrpc.Message) e
 	done, err := s.deliverLocked(data, eventID, responseTo)
 	if err != nil {
 		errs = append(errs, err)
+		if req, ok := msg.(*jsonrpc.Request); ok && !req.IsCall() && s.buffer != nil && s.done == nil {
+			// The standalone stream is not connected: buffer the notification for
+			// the next GET.
+			s.buffer.add(data, time.Now())
+			delivered = true
+		}
 	} else {
 		delivered = true
 	}
@@ -1388,6 +1610,18 @@ type StreamableClientTransport struct {
 	// MaxRetries is the maximum number of times to attempt a reconnect before giving up.
 	// It defaults to 5. To disable retries, use a negative number.
 	MaxRetries int
+	// ReconnectPolicy, if non-nil, configures the delay between attempts to
+	// reconnect an interrupted SSE stream.
+	ReconnectPolicy *ReconnectPolicy
+	// RetryPolicy, if non-nil, configures the retrying of POST requests that
+	// fail with a transient error. If nil, such requests are not retried.
+	RetryPolicy *RetryPolicy
+	// If SSEFallback is set and the server responds to the initialize request
+	// with status 400, 404 or 405, the transport falls back to the HTTP+SSE
+	// transport of the 2024-11-05 version of the spec (see
+	// [SSEClientTransport]), as the spec recommends for clients that support
+	// older servers. Endpoint is then used as the SSE endpoint.
+	SSEFallback bool
 
 	// TODO(rfindley): propose exporting these.
 	// If strict is set, the transport is in 'strict mode', where any violation
@@ -1398,21 +1632,120 @@ type StreamableClientTransport struct {
 	logger *slog.Logger
 }
 
-// These settings are not (yet) exposed to the user in
-// StreamableClientTransport.
-const (
-	// reconnectGrowFactor is the multiplicative factor by which the delay increases after each attempt.
-	// A value of 1.0 results in a constant delay, while a value of 2.0 would double it each time.
-	// It must be 1.0 or greater if MaxRetries is greater than 0.
-	reconnectGrowFactor = 1.5
-	// reconnectMaxDelay caps the backoff delay, preventing it from growing indefinitely.
-	reconnectMaxDelay = 30 * time.Second
-)
+// A RetryPolicy configures how a [StreamableClientTransport] retries POST
+// requests that fail with a transient error.
+//
+// Requests are retried if they were never sent, because the connection to the
+// server could not be established, or if the server responds with a
+// retryable HTTP status code. Requests that fail with other network errors
+// may have reached the server, and are retried only if RetrySent is set.
+//
+// The delay between attempts grows exponentially, with jitter, unless the
+// server sets the Retry-After header.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent, including
+	// the first. If it is less than 2, requests are not retried.
+	MaxAttempts int
+	// InitialBackoff is the base delay before the first retry.
+	// If zero, it defaults to 1s.
+	InitialBackoff time.Duration
+	// MaxBackoff caps the base delay between attempts, before jitter is added,
+	// and delays requested with Retry-After. If zero, it defaults to 30s.
+	MaxBackoff time.Duration
+	// BackoffMultiplier is the factor by which the base delay grows after each
+	// attempt. If less than 1, it defaults to 1.5.
+	BackoffMultiplier float64
+	// RetryableStatusCodes are the HTTP status codes of responses that are
+	// retried. If nil, they are 429 (Too Many Requests) and 503 (Service
+	// Unavailable), with which the server declines to process a request.
+	RetryableStatusCodes []int
+	// If IgnoreRetryAfter is set, the Retry-After header of retryable
+	// responses is ignored.
+	IgnoreRetryAfter bool
+	// If RetrySent is set, requests that fail with a network error after the
+	// connection was established are retried too. Since the server may
+	// already have processed such a request, for example by calling a tool,
+	// set RetrySent only if repeating requests is harmless.
+	RetrySent bool
+}
 
-var (
-	// reconnectInitialDelay is the base delay for the first reconnect attempt.
-	//
-	// Mutable for testing.
+// retryable reports whether a request with the given result should be
+// retried.
+func (p *RetryPolicy) retryable(resp *http.Response, err error) bool {
+	if err != nil {
+		// An error from dialing means the request was never sent. Other
+		// errors may occur after the server has received the request.
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return true
+		}
+		return p.RetrySent
+	}
+	if p.RetryableStatusCodes == nil {
+		return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable
+	}
+	return slices.Contains(p.RetryableStatusCodes, resp.StatusCode)
+}
+
+// delay returns the delay before retrying the given attempt (1-based), whose
+// response was resp, or nil.
+func (p *RetryPolicy) delay(attempt int, resp *http.Response) time.Duration {
+	maxDelay := cmp.Or(p.MaxBackoff, reconnectMaxDelay)
+	if resp != nil && !p.IgnoreRetryAfter {
+		if d, ok := parseRetryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, maxDelay)
+		}
+	}
+	factor := p.BackoffMultiplier
+	if factor < 1 {
+		factor = reconnectGrowFactor
+	}
+	return backoffDelay(cmp.Or(p.InitialBackoff, reconnectInitialDelay), factor, maxDelay, attempt, true)
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either
+// a number of seconds or an HTTP date.
+func parseRetryAfter(v string) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(v); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+	if t, err := http.ParseTime(v); err == nil {
+		return max(time.Until(t), 0), true
+	}
+	return 0, false
+}
+
+// A ReconnectPolicy configures the delay between attempts of a
+// [StreamableClientTransport] to reconnect an interrupted SSE stream. The
+// number of attempts is set by [StreamableClientTransport.MaxRetries].
+//
+// If the server sets the SSE retry field, its value is used as the delay
+// before the first attempt.
+type ReconnectPolicy struct {
+	// InitialDelay is the base delay for the first reconnect attempt.
+	// If zero, it defaults to 1s.
+	InitialDelay time.Duration
+	// MaxDelay caps the base delay, preventing it from growing indefinitely.
+	// If zero, it defaults to 30s.
+	MaxDelay time.Duration
+	// GrowFactor is the multiplicative factor by which the base delay
+	// increases after each attempt. A value of 1.0 results in a constant
+	// delay, while a value of 2.0 would double it each time.
+	// If less than 1, it defaults to 1.5.
+	GrowFactor float64
+	// If DisableJitter is set, the delay before each attempt is exactly the
+	// base delay. Otherwise, a random jitter of up to the base delay is added
+	// to it.
+	DisableJitter bool
+}
+
+// Defaults for ReconnectPolicy, also used by RetryPolicy.
+const (
+	reconnectGrowFactor   = 1.5
+	reconnectMaxDelay     = 30 * time.Second
 	reconnectInitialDelay = 1 * time.Second
 )
 
@@ -1435,6 +1768,15 @@ func (t *StreamableClientTransport) Connect(ctx context.Context) (Connection, er
 	} else if maxRetries < 0 {
 		maxRetries = 0
 	}
+	var reconnect ReconnectPolicy
+	if t.ReconnectPolicy != nil {
+		reconnect = *t.ReconnectPolicy
+	}
+	reconnect.InitialDelay = cmp.Or(reconnect.InitialDelay, reconnectInitialDelay)
+	reconnect.MaxDelay = cmp.Or(reconnect.MaxDelay, reconnectMaxDelay)
+	if reconnect.GrowFactor < 1 {
+		reconnect.GrowFactor = reconnectGrowFactor
+	}
 	// Create a new cancellable context that will manage the connection's lifecycle.
 	// This is crucial for cleanly shutting down the background SSE listener by
 	// cancelling its blocking network operations, which prevents hangs on exit.
@@ -1458,12 +1800,23 @@ func (t *StreamableClientTransport) Connect(ctx context.Context) (Connection, er
 		incoming:   make(chan jsonrpc.Message, 10),
 		done:       make(chan struct{}),
 		maxRetries: maxRetries,
+		reconnect:  reconnect,
+		retry:      t.RetryPolicy,
 		strict:     t.strict,
 		logger:     ensureLogger(t.logger), // must be non-nil for safe logging
 		ctx:        connCtx,
 		cancel:     cancel,
 		failed:     make(chan struct{}),
 	}
+	if t.SSEFallback {
+		return &sseFallbackConn{
+			ctx:        xcontext.Detach(ctx),
+			streamable: conn,
+			sse:        &SSEClientTransport{Endpoint: t.Endpoint, HTTPClient: t.HTTPClient},
+			ready:      make(chan struct{}),
+			done:       make(chan struct{}),
+		}, nil
+	}
 	return conn, nil
 }
 
@@ -1474,8 +1827,10 @@ type streamableClientConn struct {
 	cancel     context.CancelFunc // cancels ctx
 	incoming   chan jsonrpc.Message
 	maxRetries int
-	strict     bool         // from [StreamableClientTransport.strict]
-	logger     *slog.Logger // from [StreamableClientTransport.logger]
+	reconnect  ReconnectPolicy // from [StreamableClientTransport.ReconnectPolicy], with defaults applied
+	retry      *RetryPolicy    // from [StreamableClientTransport.RetryPolicy]; may be nil
+	strict     bool            // from [StreamableClientTransport.strict]
+	logger     *slog.Logger    // from [StreamableClientTransport.logger]
 
 	// Guard calls to Close, as it may be called multiple times.
 	closeOnce sync.Once
@@ -1647,17 +2002,9 @@ func (c *streamableClientConn) Write(ctx context.Context, msg jsonrpc.Message) e
 		return fmt.Errorf("%s: %v", requestSummary, err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json, text/event-stream")
-	c.setMCPHeaders(req)
-
-	resp, err := c.client.Do(req)
+	resp, err := c.post(ctx, data)
 	if err != nil {
-		// Any error from client.Do means the request didn't reach the server.
+		// The request failed, though it may have reached the server.
 		// Wrap with ErrRejected so the jsonrpc2 connection doesn't set writeErr
 		// and permanently break the connection.
 		return fmt.Errorf("%w: %s: %v", jsonrpc2.ErrRejected, requestSummary, err)
@@ -1728,6 +2075,37 @@ func (c *streamableClientConn) Write(ctx context.Context, msg jsonrpc.Message) e
 	return nil
 }
 
+// post sends data in a POST request, retrying transient failures according
+// to the retry policy.
+func (c *streamableClientConn) post(ctx context.Context, data []byte) (*http.Response, error) {
+	for attempt := 1; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		c.setMCPHeaders(req)
+
+		resp, err := c.client.Do(req)
+		if c.retry == nil || attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !c.retry.retryable(resp, err) {
+			return resp, err
+		}
+		delay := c.retry.delay(attempt, resp)
+		if resp != nil {
+			resp.Body.Close()
+		}
+		c.logger.Debug(fmt.Sprintf("retrying POST after %v (attempt %d)", delay, attempt))
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.done:
+			return nil, errors.New("connection closed by client during retry")
+		case <-time.After(delay):
+		}
+	}
+}
+
 // testAuth controls whether a fake Authorization header is added to outgoing requests.
 // TODO: replace with a better mechanism when client-side auth is in place.
 var testAuth atomic.Bool
@@ -1839,11 +2217,16 @@ func (c *streamableClientConn) checkResponse(requestSummary string, resp *http.R
 		return fmt.Errorf("%w: %s: %v", jsonrpc2.ErrRejected, requestSummary, http.StatusText(resp.StatusCode))
 	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("%s: %v", requestSummary, http.StatusText(resp.StatusCode))
+		return fmt.Errorf("%s: %w", requestSummary, httpStatusError(resp.StatusCode))
 	}
 	return nil
 }
 
+// An httpStatusError reports an unsuccessful HTTP response status code.
+type httpStatusError int
+
+func (e httpStatusError) Error() string { return http.StatusText(int(e)) }
+
 // processStream reads from a single response body, sending events to the
 // incoming channel. It returns the ID of the last processed event and a flag
 // indicating if the connection was closed by the client. If resp is nil, it
@@ -1945,7 +2328,7 @@ func (c *streamableClientConn) connectSSE(ctx context.Context, lastEventID strin
 		// logical request.
 		attempt = 1
 	}
-	delay := calculateReconnectDelay(attempt)
+	delay := c.reconnectDelay(attempt)
 	if reconnectDelay > 0 {
 		delay = reconnectDelay // honor the server's requested initial delay
 	}
@@ -1972,7 +2355,7 @@ func (c *streamableClientConn) connectSSE(ctx context.Context, lastEventID strin
 			resp, err := c.client.Do(req)
 			if err != nil {
 				finalErr = err // Store the error and try again.
-				delay = calculateReconnectDelay(attempt + 1)
+				delay = c.reconnectDelay(attempt + 1)
 				continue
 			}
 			return resp, nil
@@ -2009,20 +2392,30 @@ func (c *streamableClientConn) Close() error {
 	return c.closeErr
 }
 
-// calculateReconnectDelay calculates a delay using exponential backoff with full jitter.
-func calculateReconnectDelay(attempt int) time.Duration {
+// reconnectDelay calculates the delay before the given reconnect attempt
+// according to the reconnect policy, using exponential backoff with full
+// jitter unless jitter is disabled.
+func (c *streamableClientConn) reconnectDelay(attempt int) time.Duration {
 	if attempt == 0 {
 		return 0
 	}
+	p := c.reconnect
+	return backoffDelay(p.InitialDelay, p.GrowFactor, p.MaxDelay, attempt, !p.DisableJitter)
+}
+
+// backoffDelay calculates the delay before the given attempt (1-based) using
+// exponential backoff, with full jitter if jitter is set.
+func backoffDelay(initial time.Duration, factor float64, maxDelay time.Duration, attempt int, jitter bool) time.Duration {
 	// Calculate the exponential backoff using the grow factor.
-	backoffDuration := time.Duration(float64(reconnectInitialDelay) * math.Pow(reconnectGrowFactor, float64(attempt-1)))
+	backoffDuration := time.Duration(float64(initial) * math.Pow(factor, float64(attempt-1)))
 	// Cap the backoffDuration at maxDelay.
-	backoffDuration = min(backoffDuration, reconnectMaxDelay)
+	backoffDuration = min(backoffDuration, maxDelay)
+	if backoffDuration <= 0 || !jitter {
+		return max(backoffDuration, 0)
+	}
 
 	// Use a full jitter using backoffDuration
-	jitter := rand.N(backoffDuration)
-
-	return backoffDuration + jitter
+	return backoffDuration + rand.N(backoffDuration)
 }
 
 // isTransientHTTPStatus reports whether the HTTP status code indicates a
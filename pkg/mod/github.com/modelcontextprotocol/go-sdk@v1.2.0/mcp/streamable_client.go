@@ -128,7 +128,7 @@ Event ID tracking:
   - On reconnection, the Last-Event-ID header is set to resume from that point
   - Server replays missed events if it has an [EventStore] configured
 
-See [calculateReconnectDelay] for the reconnect delay details.
+See [streamableClientConn.reconnectDelay] for the reconnect delay details.
 
 Server-initiated reconnection (SEP-1699)
   - SSE retry field: Sets the delay for the next reconnect attempt
@@ -6,10 +6,13 @@ package mcp
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -444,10 +447,7 @@ func TestStreamableClientResumption_Cancelled(t *testing.T) {
 	//
 	// TODO(#680): experiment with instead using synctest.
 	const tick = 10 * time.Millisecond
-	defer func(delay time.Duration) {
-		reconnectInitialDelay = delay
-	}(reconnectInitialDelay)
-	reconnectInitialDelay = 2 * tick
+	reconnect := &ReconnectPolicy{InitialDelay: 2 * tick}
 
 	// The setup: terminate a request stream and make the resumed request hang
 	// indefinitely. CallTool should still exit when its context is canceled.
@@ -531,7 +531,7 @@ data: { "jsonrpc": "2.0", "method": "notifications/message", "params": { "level"
 			defer httpServer.Close()
 			defer close(allDone) // must be deferred *after* httpServer.Close, to avoid deadlock
 
-			transport := &StreamableClientTransport{Endpoint: httpServer.URL}
+			transport := &StreamableClientTransport{Endpoint: httpServer.URL, ReconnectPolicy: reconnect}
 			client := NewClient(testImpl, nil)
 			cs, err := client.Connect(ctx, transport, nil)
 			if err != nil {
@@ -693,3 +693,164 @@ func TestStreamableClientTransientErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestStreamableClientRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name         string
+		policy       *RetryPolicy
+		failures     int // number of 503 responses before success
+		wantAttempts int32
+		wantErr      bool
+	}{
+		{"no policy", nil, 1, 1, true},
+		{"default policy", &RetryPolicy{MaxAttempts: 3}, 1, 2, false},
+		{"recovers", &RetryPolicy{MaxAttempts: 3}, 2, 3, false},
+		{"exhausted", &RetryPolicy{MaxAttempts: 2}, 2, 2, true},
+		{"status not retryable", &RetryPolicy{MaxAttempts: 3, RetryableStatusCodes: []int{http.StatusTooManyRequests}}, 1, 1, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if test.policy != nil {
+				// The server's Retry-After of 0 must take precedence, or the test
+				// times out.
+				test.policy.InitialBackoff = time.Hour
+			}
+			var attempts atomic.Int32
+			fake := &fakeStreamableServer{
+				t: t,
+				responses: fakeResponses{
+					{"POST", "", methodInitialize, ""}: {
+						header: header{
+							"Content-Type":  "application/json",
+							sessionIDHeader: "123",
+						},
+						body: jsonBody(t, initResp),
+					},
+					{"POST", "123", notificationInitialized, ""}: {
+						status:              http.StatusAccepted,
+						wantProtocolVersion: latestProtocolVersion,
+					},
+					{"GET", "123", "", ""}: {
+						status: http.StatusMethodNotAllowed,
+					},
+					{"POST", "123", methodListTools, ""}: {
+						header: header{
+							"Content-Type":  "application/json",
+							sessionIDHeader: "123",
+							"Retry-After":   "0",
+						},
+						responseFunc: func(r *jsonrpc.Request) (string, int) {
+							if n := attempts.Add(1); int(n) <= test.failures {
+								return "", http.StatusServiceUnavailable
+							}
+							return jsonBody(t, resp(r.ID.Raw().(int64), &ListToolsResult{Tools: []*Tool{}}, nil)), 0
+						},
+					},
+					{"DELETE", "123", "", ""}: {optional: true},
+				},
+			}
+
+			httpServer := httptest.NewServer(fake)
+			defer httpServer.Close()
+
+			transport := &StreamableClientTransport{Endpoint: httpServer.URL, RetryPolicy: test.policy}
+			session, err := NewClient(testImpl, nil).Connect(ctx, transport, nil)
+			if err != nil {
+				t.Fatalf("Connect failed: %v", err)
+			}
+			defer session.Close()
+
+			_, err = session.ListTools(ctx, nil)
+			if gotErr := err != nil; gotErr != test.wantErr {
+				t.Errorf("ListTools: got error %v, want error: %t", err, test.wantErr)
+			}
+			if got := attempts.Load(); got != test.wantAttempts {
+				t.Errorf("got %d attempts, want %d", got, test.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyRetryable(t *testing.T) {
+	dialErr := &url.Error{Op: "Post", URL: "http://example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}}
+	readErr := &url.Error{Op: "Post", URL: "http://example.com", Err: &net.OpError{Op: "read", Net: "tcp", Err: errors.New("connection reset")}}
+	unavailable := &http.Response{StatusCode: http.StatusServiceUnavailable}
+	tooMany := &http.Response{StatusCode: http.StatusTooManyRequests}
+	internal := &http.Response{StatusCode: http.StatusInternalServerError}
+	for _, test := range []struct {
+		name      string
+		retrySent bool
+		codes     []int
+		resp      *http.Response
+		err       error
+		want      bool
+	}{
+		{"dial error", false, nil, nil, dialErr, true},
+		{"read error", false, nil, nil, readErr, false},
+		{"read error, retry sent", true, nil, nil, readErr, true},
+		{"unavailable", false, nil, unavailable, nil, true},
+		{"too many requests", false, nil, tooMany, nil, true},
+		{"internal error", false, nil, internal, nil, false},
+		{"internal error, retry sent", true, nil, internal, nil, false},
+		{"internal error, retryable code", false, []int{http.StatusInternalServerError}, internal, nil, true},
+		{"unavailable, other codes", false, []int{http.StatusInternalServerError}, unavailable, nil, false},
+	} {
+		p := &RetryPolicy{RetrySent: test.retrySent, RetryableStatusCodes: test.codes}
+		if got := p.retryable(test.resp, test.err); got != test.want {
+			t.Errorf("%s: retryable = %t, want %t", test.name, got, test.want)
+		}
+	}
+}
+
+func TestReconnectDelay(t *testing.T) {
+	transport := &StreamableClientTransport{
+		ReconnectPolicy: &ReconnectPolicy{
+			InitialDelay:  10 * time.Millisecond,
+			MaxDelay:      25 * time.Millisecond,
+			GrowFactor:    2,
+			DisableJitter: true,
+		},
+	}
+	conn, err := transport.Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := conn.(*streamableClientConn)
+	for attempt, want := range []time.Duration{0, 10 * time.Millisecond, 20 * time.Millisecond, 25 * time.Millisecond} {
+		if got := c.reconnectDelay(attempt); got != want {
+			t.Errorf("reconnectDelay(%d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	// With the default policy, the delay is jittered between the base delay
+	// and twice it.
+	conn, err = (&StreamableClientTransport{}).Connect(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = conn.(*streamableClientConn)
+	if got := c.reconnectDelay(1); got < reconnectInitialDelay || got >= 2*reconnectInitialDelay {
+		t.Errorf("default reconnectDelay(1) = %v, want in [%v, %v)", got, reconnectInitialDelay, 2*reconnectInitialDelay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	for _, test := range []struct {
+		in     string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, true},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0, true}, // in the past
+		{"soon", 0, false},
+	} {
+		got, ok := parseRetryAfter(test.in)
+		if got != test.want || ok != test.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %t, want %v, %t", test.in, got, ok, test.want, test.wantOK)
+		}
+	}
+}
@@ -2183,3 +2183,276 @@ data: {"jsonrpc":"2.0","method":"test2","params":{}}
 		}
 	}
 }
+
+func TestStreamableHTTPMux(t *testing.T) {
+	ctx := context.Background()
+
+	var mu sync.Mutex
+	tenants := make(map[string]*Server)
+	mux := NewStreamableHTTPMux(nil)
+	mux.Handle("/tenants/{id}/mcp", func(req *http.Request) *Server {
+		id := req.PathValue("id")
+		mu.Lock()
+		defer mu.Unlock()
+		if s := tenants[id]; s != nil {
+			return s
+		}
+		s := NewServer(&Implementation{Name: "tenant-" + id}, nil)
+		tenants[id] = s
+		return s
+	})
+	admin := NewServer(&Implementation{Name: "admin"}, nil)
+	mux.Handle("/admin/mcp", func(*http.Request) *Server { return admin })
+	httpServer := httptest.NewServer(mustNotPanic(t, mux))
+	// Close the server after the client sessions, which are closed in cleanups.
+	t.Cleanup(httpServer.Close)
+
+	connect := func(path string) *ClientSession {
+		t.Helper()
+		cs, err := NewClient(testImpl, nil).Connect(ctx, &StreamableClientTransport{Endpoint: httpServer.URL + path}, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		return cs
+	}
+	sessA := connect("/tenants/a/mcp")
+	for path, want := range map[string]string{
+		"/tenants/a/mcp": "tenant-a",
+		"/tenants/b/mcp": "tenant-b",
+		"/admin/mcp":     "admin",
+	} {
+		cs := connect(path)
+		if got := cs.InitializeResult().ServerInfo.Name; got != want {
+			t.Errorf("%s: got server %q, want %q", path, got, want)
+		}
+	}
+
+	// A session of tenant a cannot be used at the path of tenant b.
+	ping := func(path string) int {
+		t.Helper()
+		msg := &jsonrpc.Request{Method: "ping", ID: jsonrpc2.Int64ID(1), Params: json.RawMessage(`{}`)}
+		data, _ := jsonrpc2.EncodeMessage(msg)
+		req, _ := http.NewRequestWithContext(ctx, http.MethodPost, httpServer.URL+path, bytes.NewReader(data))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		req.Header.Set(sessionIDHeader, sessA.ID())
+		req.Header.Set(protocolVersionHeader, sessA.InitializeResult().ProtocolVersion)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	if got := ping("/tenants/b/mcp"); got != http.StatusNotFound {
+		t.Errorf("ping with tenant a session at tenant b: got status %d, want %d", got, http.StatusNotFound)
+	}
+	if got := ping("/tenants/a/mcp"); got != http.StatusOK {
+		t.Errorf("ping with tenant a session at tenant a: got status %d, want %d", got, http.StatusOK)
+	}
+	if got := ping("/unknown"); got != http.StatusNotFound {
+		t.Errorf("ping at unknown path: got status %d, want %d", got, http.StatusNotFound)
+	}
+}
+
+func TestStreamableSessionIDGenerator(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, &ServerOptions{
+		GetSessionID: func() string { return "unused" },
+	})
+	var n atomic.Int64
+	handler := NewStreamableHTTPHandler(func(*http.Request) *Server { return server }, &StreamableHTTPOptions{
+		SessionIDGenerator: func(req *http.Request) (string, error) {
+			tenant := req.Header.Get("X-Tenant")
+			if tenant == "" {
+				return "", errors.New("missing tenant")
+			}
+			return fmt.Sprintf("%s.%d", tenant, n.Add(1)), nil
+		},
+	})
+	httpServer := httptest.NewServer(mustNotPanic(t, handler))
+	t.Cleanup(httpServer.Close)
+
+	connect := func(tenant string) (*ClientSession, error) {
+		httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			if tenant != "" {
+				req.Header.Set("X-Tenant", tenant)
+			}
+			return http.DefaultTransport.RoundTrip(req)
+		})}
+		return NewClient(testImpl, nil).Connect(ctx, &StreamableClientTransport{Endpoint: httpServer.URL, HTTPClient: httpClient}, nil)
+	}
+	cs, err := connect("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	if got, want := cs.ID(), "acme.1"; got != want {
+		t.Errorf("session ID: got %q, want %q", got, want)
+	}
+	if err := cs.Ping(ctx, nil); err != nil {
+		t.Errorf("Ping: %v", err)
+	}
+
+	if cs, err := connect(""); err == nil {
+		cs.Close()
+		t.Error("connect without tenant: got nil error, want error")
+	}
+}
+
+func TestStreamableContextHeaders(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, nil)
+	type result struct {
+		ctxHeader, extraHeader http.Header
+	}
+	results := make(chan result, 1)
+	AddTool(server, &Tool{Name: "headers"}, func(ctx context.Context, req *CallToolRequest, _ any) (*CallToolResult, any, error) {
+		results <- result{HeaderFromContext(ctx), req.Extra.Header}
+		return &CallToolResult{}, nil, nil
+	})
+	handler := NewStreamableHTTPHandler(func(*http.Request) *Server { return server }, &StreamableHTTPOptions{
+		ContextHeaders: []string{"traceparent", "X-Tenant", "X-Missing"},
+	})
+	httpServer := httptest.NewServer(mustNotPanic(t, handler))
+	t.Cleanup(httpServer.Close)
+
+	httpClient := &http.Client{Transport: roundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		req.Header.Set("Traceparent", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+		req.Header.Set("X-Tenant", "acme")
+		req.Header.Set("X-Other", "other")
+		return http.DefaultTransport.RoundTrip(req)
+	})}
+	cs, err := NewClient(testImpl, nil).Connect(ctx, &StreamableClientTransport{Endpoint: httpServer.URL, HTTPClient: httpClient}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { cs.Close() })
+	if _, err := cs.CallTool(ctx, &CallToolParams{Name: "headers"}); err != nil {
+		t.Fatal(err)
+	}
+	got := <-results
+	want := http.Header{
+		"Traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		"X-Tenant":    {"acme"},
+	}
+	if diff := cmp.Diff(want, got.ctxHeader); diff != "" {
+		t.Errorf("HeaderFromContext mismatch (-want +got):\n%s", diff)
+	}
+	if got := got.extraHeader.Get("X-Other"); got != "other" {
+		t.Errorf("Extra.Header X-Other: got %q, want %q", got, "other")
+	}
+}
+
+func TestStreamableNotificationBuffer(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	sessions := make(chan *ServerSession, 1)
+	server := NewServer(testImpl, &ServerOptions{
+		InitializedHandler: func(_ context.Context, req *InitializedRequest) { sessions <- req.Session },
+	})
+	handler := NewStreamableHTTPHandler(func(*http.Request) *Server { return server }, &StreamableHTTPOptions{
+		NotificationBuffer: &NotificationBufferOptions{MaxMessages: 2},
+	})
+	httpServer := httptest.NewServer(mustNotPanic(t, handler))
+	defer httpServer.Close()
+
+	sessionID := ""
+	do := func(method string, msg jsonrpc.Message) *http.Response {
+		t.Helper()
+		var body io.Reader
+		if msg != nil {
+			data, err := jsonrpc2.EncodeMessage(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body = bytes.NewReader(data)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, httpServer.URL, body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if msg != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		if sessionID != "" {
+			req.Header.Set(sessionIDHeader, sessionID)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp
+	}
+
+	resp := do(http.MethodPost, req(1, methodInitialize, &InitializeParams{}))
+	resp.Body.Close()
+	sessionID = resp.Header.Get(sessionIDHeader)
+	do(http.MethodPost, req(0, notificationInitialized, &InitializedParams{})).Body.Close()
+	ss := <-sessions
+
+	// With no GET open, notifications are buffered. The buffer holds two, so
+	// the first is dropped.
+	for i := range 3 {
+		if err := ss.NotifyProgress(ctx, &ProgressNotificationParams{ProgressToken: "t", Progress: float64(i)}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A GET whose writes fail leaves the notifications buffered.
+	failed := httptest.NewRequestWithContext(ctx, http.MethodGet, "/", nil)
+	failed.Header.Set("Accept", "application/json, text/event-stream")
+	failed.Header.Set(sessionIDHeader, sessionID)
+	handler.ServeHTTP(failingResponseWriter{httptest.NewRecorder()}, failed)
+
+	get := do(http.MethodGet, nil)
+	defer get.Body.Close()
+	var got []float64
+	for e, err := range scanEvents(get.Body) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := jsonrpc2.DecodeMessage(e.Data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var params ProgressNotificationParams
+		if err := json.Unmarshal(msg.(*jsonrpc.Request).Params, &params); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, params.Progress)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if want := []float64{1, 2}; !slices.Equal(got, want) {
+		t.Errorf("replayed progress: got %v, want %v", got, want)
+	}
+}
+
+// failingResponseWriter is an http.ResponseWriter whose writes fail.
+type failingResponseWriter struct {
+	*httptest.ResponseRecorder
+}
+
+func (failingResponseWriter) Write([]byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func TestNotificationBufferExpiry(t *testing.T) {
+	b := newNotificationBuffer(&NotificationBufferOptions{MaxAge: time.Minute})
+	start := time.Now()
+	b.add([]byte("old"), start)
+	b.add([]byte("new"), start.Add(time.Minute))
+	got := b.pending(start.Add(90 * time.Second))
+	if len(got) != 1 || string(got[0]) != "new" {
+		t.Errorf("pending: got %q, want [new]", got)
+	}
+	b.remove(len(got))
+	if got := b.pending(start); len(got) != 0 {
+		t.Errorf("pending after remove: got %q, want none", got)
+	}
+}
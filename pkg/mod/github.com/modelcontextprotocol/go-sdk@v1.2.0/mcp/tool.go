@@ -55,6 +55,56 @@ type ToolHandler func(context.Context, *CallToolRequest) (*CallToolResult, error
 // or error. The effective result will be populated as described above.
 type ToolHandlerFor[In, Out any] func(_ context.Context, request *CallToolRequest, input In) (result *CallToolResult, output Out, _ error)
 
+// A ToolError is a tool error with a machine-readable code, so that clients
+// can branch on the kind of failure.
+//
+// If a [ToolHandlerFor] returns an error that is or wraps a *ToolError, the
+// result's _meta holds the ToolError under a key private to this SDK, in
+// addition to the error text in [CallToolResult.Content].
+// Clients can retrieve it with [CallToolResult.ToolError].
+type ToolError struct {
+	// Code identifies the kind of error, such as "not_found" or
+	// "rate_limited". Codes are defined by the tool.
+	Code string `json:"code"`
+	// Message is a human-readable description of the error.
+	Message string `json:"message,omitempty"`
+	// Details holds optional additional information about the error. It must
+	// marshal to JSON.
+	Details any `json:"details,omitempty"`
+}
+
+func (e *ToolError) Error() string {
+	if e.Message == "" {
+		return e.Code
+	}
+	return e.Code + ": " + e.Message
+}
+
+// ToolErrorCodeTimeout is the [ToolError] code of a tool call that exceeded
+// its time limit. See [ServerOptions.ToolTimeout].
+const ToolErrorCodeTimeout = "timeout"
+
+// sdkMetaPrefix is the prefix of the _meta keys that this SDK defines. The
+// specification reserves unprefixed keys and those of the MCP namespace.
+const sdkMetaPrefix = "io.github.modelcontextprotocol.go-sdk/"
+
+// toolErrorMetaKey is the _meta key of a ToolError in a tool result.
+const toolErrorMetaKey = sdkMetaPrefix + "toolError"
+
+// ToolError returns the structured tool error of an error result, or nil if
+// the result is not an error or does not hold a [ToolError].
+func (r *CallToolResult) ToolError() *ToolError {
+	v, ok := r.Meta[toolErrorMetaKey]
+	if !r.IsError || !ok {
+		return nil
+	}
+	var te *ToolError
+	if err := remarshal(v, &te); err != nil || te == nil || te.Code == "" {
+		return nil
+	}
+	return te
+}
+
 // A serverTool is a tool definition that is bound to a tool handler.
 type serverTool struct {
 	tool    *Tool
@@ -137,3 +187,61 @@ func validToolNameRune(r rune) bool {
 		(r >= '0' && r <= '9') ||
 		r == '_' || r == '-' || r == '.'
 }
+
+// ToolDeprecation describes the deprecation of a [Tool].
+// Since the MCP spec has no notion of deprecation, it is carried in the
+// tool's _meta: see [Tool.SetDeprecation].
+type ToolDeprecation struct {
+	// Message explains the deprecation, for example when the tool will be
+	// removed.
+	Message string `json:"message,omitempty"`
+	// Replacement is the name of the tool to use instead, if any.
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// toolDeprecationMetaKey is the _meta key of a ToolDeprecation in a tool.
+const toolDeprecationMetaKey = sdkMetaPrefix + "deprecation"
+
+// SetDeprecation marks t as deprecated, or, if d is nil, as not deprecated.
+// Clients should prefer the replacement, if any, and servers warn sessions
+// that call the tool.
+func (t *Tool) SetDeprecation(d *ToolDeprecation) {
+	if d == nil {
+		delete(t.Meta, toolDeprecationMetaKey)
+		return
+	}
+	if t.Meta == nil {
+		t.Meta = Meta{}
+	}
+	t.Meta[toolDeprecationMetaKey] = d
+}
+
+// Deprecation returns the deprecation of t set with [Tool.SetDeprecation],
+// or nil if t is not deprecated.
+func (t *Tool) Deprecation() *ToolDeprecation {
+	v, ok := t.Meta[toolDeprecationMetaKey]
+	if !ok {
+		return nil
+	}
+	if d, ok := v.(*ToolDeprecation); ok {
+		return d
+	}
+	// On clients, the deprecation has been unmarshaled as a map.
+	var d *ToolDeprecation
+	if err := remarshal(v, &d); err != nil {
+		return nil
+	}
+	return d
+}
+
+// warning returns a warning describing the deprecation of the named tool.
+func (d *ToolDeprecation) warning(name string) string {
+	msg := fmt.Sprintf("tool %q is deprecated", name)
+	if d.Replacement != "" {
+		msg += fmt.Sprintf("; use %q instead", d.Replacement)
+	}
+	if d.Message != "" {
+		msg += ": " + d.Message
+	}
+	return msg
+}
@@ -12,6 +12,7 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
@@ -76,8 +77,18 @@ func TestToolErrorHandling(t *testing.T) {
 		return nil, nil, fmt.Errorf("tool execution failed")
 	}
 
+	// Create a tool that returns a tool error with a code
+	toolErrorHandler := func(ctx context.Context, req *CallToolRequest, args map[string]any) (*CallToolResult, any, error) {
+		return nil, nil, fmt.Errorf("looking up user: %w", &ToolError{
+			Code:    "not_found",
+			Message: "no such user",
+			Details: map[string]any{"user": "bob"},
+		})
+	}
+
 	AddTool(server, &Tool{Name: "error_tool", Description: "returns structured error"}, structuredErrorHandler)
 	AddTool(server, &Tool{Name: "regular_error_tool", Description: "returns regular error"}, regularErrorHandler)
+	AddTool(server, &Tool{Name: "tool_error_tool", Description: "returns tool error"}, toolErrorHandler)
 
 	// Connect server and client once
 	ct, st := NewInMemoryTransports()
@@ -143,6 +154,40 @@ func TestToolErrorHandling(t *testing.T) {
 		} else if !strings.Contains(textContent.Text, "tool execution failed") {
 			t.Errorf("expected error message in content, got: %s", textContent.Text)
 		}
+
+		if te := result.ToolError(); te != nil {
+			t.Errorf("expected no ToolError, got %+v", te)
+		}
+	})
+
+	// Test that tool errors are embedded in _meta
+	t.Run("tool_error", func(t *testing.T) {
+		result, err := cs.CallTool(context.Background(), &CallToolParams{
+			Name:      "tool_error_tool",
+			Arguments: map[string]any{},
+		})
+		if err != nil {
+			t.Fatalf("unexpected protocol error: %v", err)
+		}
+		if !result.IsError {
+			t.Error("expected IsError=true, got false")
+		}
+		if got, want := result.Content[0].(*TextContent).Text, "looking up user: not_found: no such user"; got != want {
+			t.Errorf("got content %q, want %q", got, want)
+		}
+		if result.StructuredContent != nil {
+			t.Errorf("got structured content %v, want none", result.StructuredContent)
+		}
+		te := result.ToolError()
+		if te == nil {
+			t.Fatalf("expected ToolError, got nil; _meta: %v", result.Meta)
+		}
+		if te.Code != "not_found" || te.Message != "no such user" {
+			t.Errorf("got ToolError %+v", te)
+		}
+		if got := te.Details.(map[string]any)["user"]; got != "bob" {
+			t.Errorf("got details %v, want user bob", te.Details)
+		}
 	})
 }
 
@@ -195,3 +240,105 @@ func TestValidateToolName(t *testing.T) {
 	})
 
 }
+
+func TestToolTimeout(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, &ServerOptions{
+		ToolTimeout:  10 * time.Millisecond,
+		ToolTimeouts: map[string]time.Duration{"patient": time.Minute},
+	})
+
+	// cooperative returns when its context is done.
+	AddTool(server, &Tool{Name: "cooperative"}, func(ctx context.Context, _ *CallToolRequest, _ any) (*CallToolResult, any, error) {
+		<-ctx.Done()
+		return nil, nil, ctx.Err()
+	})
+	// patient overrides the default timeout.
+	AddTool(server, &Tool{Name: "patient"}, func(context.Context, *CallToolRequest, any) (*CallToolResult, any, error) {
+		time.Sleep(50 * time.Millisecond)
+		return &CallToolResult{Content: []Content{&TextContent{Text: "done"}}}, nil, nil
+	})
+
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := NewClient(testImpl, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(ctx, &CallToolParams{Name: "cooperative"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if te := res.ToolError(); te == nil || te.Code != ToolErrorCodeTimeout {
+		t.Errorf("cooperative: got result %+v, want timeout error", res)
+	}
+	res, err = cs.CallTool(ctx, &CallToolParams{Name: "patient"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.IsError {
+		t.Errorf("patient: got error result %+v", res)
+	}
+}
+
+func TestToolDeprecation(t *testing.T) {
+	ctx := context.Background()
+	server := NewServer(testImpl, nil)
+	deprecation := &ToolDeprecation{Message: "removed in v2", Replacement: "greet2"}
+	tool := &Tool{Name: "greet"}
+	tool.SetDeprecation(deprecation)
+	AddTool(server, tool, func(context.Context, *CallToolRequest, any) (*CallToolResult, any, error) {
+		return &CallToolResult{Content: []Content{&TextContent{Text: "hi"}}}, nil, nil
+	})
+
+	warnings := make(chan *LoggingMessageParams, 1)
+	client := NewClient(testImpl, &ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *LoggingMessageRequest) {
+			warnings <- req.Params
+		},
+	})
+	ct, st := NewInMemoryTransports()
+	ss, err := server.Connect(ctx, st, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ss.Close()
+	cs, err := client.Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+	if err := cs.SetLoggingLevel(ctx, &SetLoggingLevelParams{Level: "warning"}); err != nil {
+		t.Fatal(err)
+	}
+
+	lres, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := lres.Tools[0].Deprecation(); got == nil || *got != *deprecation {
+		t.Errorf("listed tool: got deprecation %+v, want %+v", got, deprecation)
+	}
+	if _, ok := lres.Tools[0].Meta[toolDeprecationMetaKey]; !ok {
+		t.Errorf("listed tool: deprecation not in _meta %v", lres.Tools[0].Meta)
+	}
+
+	if _, err := cs.CallTool(ctx, &CallToolParams{Name: "greet"}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case w := <-warnings:
+		want := `tool "greet" is deprecated; use "greet2" instead: removed in v2`
+		if w.Level != "warning" || w.Data != want {
+			t.Errorf("got warning %+v, want level warning and data %q", w, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no deprecation warning")
+	}
+}
@@ -24,6 +24,27 @@ import (
 // is closed or in the process of closing.
 var ErrConnectionClosed = errors.New("connection closed")
 
+// A CancelledError reports that a request was cancelled by a
+// "notifications/cancelled" notification.
+//
+// When the peer cancels a request, the context of its handler is cancelled
+// with a CancelledError as its cause, which can be retrieved with
+// [context.Cause]. When a request is cancelled with
+// [ClientSession.CancelRequest] or [ServerSession.CancelRequest], the call that
+// made it returns an error wrapping a CancelledError.
+type CancelledError struct {
+	// Reason is the reason for the cancellation given in the notification, if
+	// any.
+	Reason string
+}
+
+func (e *CancelledError) Error() string {
+	if e.Reason == "" {
+		return "request cancelled"
+	}
+	return "request cancelled: " + e.Reason
+}
+
 // A Transport is used to create a bidirectional connection between MCP client
 // and server.
 //
@@ -141,6 +162,7 @@ type binder[T handler, State any] interface {
 	// TODO(rfindley): the bind API has gotten too complicated. Simplify.
 	bind(Connection, *jsonrpc2.Connection, State, func()) T
 	disconnect(T)
+	jsonCodec() JSONCodec
 }
 
 type handler interface {
@@ -174,11 +196,20 @@ func connect[H handler, State any](ctx context.Context, t Transport, b binder[H,
 			b.disconnect(h)
 		},
 		OnInternalError: func(err error) { log.Printf("jsonrpc2 error: %v", err) },
+		Codec:           b.jsonCodec(),
 	})
 	assert(preempter.conn != nil, "unbound preempter")
 	return h, nil
 }
 
+// A methodCheckingTransport is a server transport that validates incoming
+// requests before they reach the session, such as to fail the HTTP request
+// carrying them. It must be told which methods the server handles, since
+// they may include experimental methods.
+type methodCheckingTransport interface {
+	setMethodInfos(func() map[string]methodInfo)
+}
+
 // A canceller is a jsonrpc2.Preempter that cancels in-flight requests on MCP
 // cancelled notifications.
 type canceller struct {
@@ -196,7 +227,7 @@ func (c *canceller) Preempt(ctx context.Context, req *jsonrpc.Request) (result a
 		if err != nil {
 			return nil, err
 		}
-		go c.conn.Cancel(id)
+		go c.conn.CancelCause(id, &CancelledError{Reason: params.Reason})
 	}
 	return nil, jsonrpc2.ErrNotHandled
 }
@@ -235,6 +266,30 @@ func call(ctx context.Context, conn *jsonrpc2.Connection, method string, params
 	return nil
 }
 
+// WithRequestIDFunc returns a context that, when passed to a method of
+// [ClientSession] or [ServerSession] that makes a request, causes f to be
+// called with the JSON-RPC ID of the request before it is sent. The ID can be
+// passed to [ClientSession.CancelRequest] or [ServerSession.CancelRequest] to
+// cancel the request while the method waits for its response.
+//
+// f is called for each request made with the context, from the goroutine
+// making it, and must not block.
+func WithRequestIDFunc(ctx context.Context, f func(jsonrpc.ID)) context.Context {
+	return jsonrpc2.WithIDFunc(ctx, f)
+}
+
+// cancelRequest stops waiting for the outgoing call with the given ID, and
+// notifies the peer of its cancellation.
+func cancelRequest(ctx context.Context, conn *jsonrpc2.Connection, id jsonrpc.ID, reason string) error {
+	if !conn.RetireID(id, &CancelledError{Reason: reason}) {
+		return fmt.Errorf("no request with ID %v is in flight", id.Raw())
+	}
+	return conn.Notify(ctx, notificationCancelled, &CancelledParams{
+		Reason:    reason,
+		RequestID: id.Raw(),
+	})
+}
+
 // A LoggingTransport is a [Transport] that delegates to another transport,
 // writing RPC logs to an io.Writer.
 type LoggingTransport struct {
@@ -0,0 +1,250 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the authorization code grant with PKCE.
+// See https://www.rfc-editor.org/rfc/rfc6749.html#section-4.1
+// and https://www.rfc-editor.org/rfc/rfc7636.html.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/internal/util"
+	"golang.org/x/oauth2"
+)
+
+// A RedirectListener receives the redirect from the authorization server
+// at the end of an authorization code flow.
+type RedirectListener interface {
+	// RedirectURL returns the redirect URI to send to the authorization server.
+	RedirectURL() string
+	// Wait blocks until the redirect arrives or ctx is done, and returns the
+	// query parameters of the redirect.
+	Wait(ctx context.Context) (url.Values, error)
+}
+
+// AuthorizeOptions are options for [AuthorizeAndExchange].
+type AuthorizeOptions struct {
+	// Config describes the client and the authorization server.
+	// Its ClientID is required. Its RedirectURL is ignored: the listener's
+	// RedirectURL is used instead.
+	Config *oauth2.Config
+	// AuthServerMeta, if non-nil, is used to populate missing endpoints of
+	// Config, and to check that the server supports the S256 challenge method.
+	AuthServerMeta *AuthServerMeta
+	// Resource is the resource indicator (RFC 8707) to include in the
+	// authorization and token requests. The MCP specification requires
+	// clients to set it to the canonical URI of the MCP server.
+	Resource string
+	// Listener receives the authorization server's redirect.
+	// If nil, a [LoopbackRedirectListener] on an ephemeral port is used for
+	// the duration of the call.
+	Listener RedirectListener
+	// OpenURL directs the user to the authorization URL, typically by opening
+	// a browser. It is required.
+	OpenURL func(ctx context.Context, authURL string) error
+	// HTTPClient is used for the token exchange.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+}
+
+// AuthorizationError is an error response from the authorization endpoint
+// (RFC 6749, Section 4.1.2.1).
+type AuthorizationError struct {
+	// ErrorCode is the REQUIRED error code.
+	ErrorCode string
+	// ErrorDescription is an OPTIONAL human-readable error message.
+	ErrorDescription string
+	// ErrorURI is an OPTIONAL URI of a page describing the error.
+	ErrorURI string
+}
+
+func (e *AuthorizationError) Error() string {
+	if e.ErrorDescription == "" {
+		return "authorization failed: " + e.ErrorCode
+	}
+	return fmt.Sprintf("authorization failed: %s (%s)", e.ErrorCode, e.ErrorDescription)
+}
+
+// AuthorizeAndExchange performs an OAuth 2.0 authorization code flow with PKCE.
+//
+// It generates a code verifier and state, calls opts.OpenURL with the
+// authorization URL, waits for the redirect on opts.Listener, checks the
+// returned state, and exchanges the code for a token.
+func AuthorizeAndExchange(ctx context.Context, opts *AuthorizeOptions) (_ *oauth2.Token, err error) {
+	defer util.Wrapf(&err, "AuthorizeAndExchange")
+
+	if opts == nil || opts.Config == nil {
+		return nil, errors.New("missing Config")
+	}
+	if opts.Config.ClientID == "" {
+		return nil, errors.New("missing ClientID")
+	}
+	if opts.OpenURL == nil {
+		return nil, errors.New("missing OpenURL")
+	}
+	cfg := *opts.Config
+	if asm := opts.AuthServerMeta; asm != nil {
+		if !slices.Contains(asm.CodeChallengeMethodsSupported, "S256") {
+			return nil, fmt.Errorf("authorization server %s does not support S256 code challenges", asm.Issuer)
+		}
+		if cfg.Endpoint.AuthURL == "" {
+			cfg.Endpoint.AuthURL = asm.AuthorizationEndpoint
+		}
+		if cfg.Endpoint.TokenURL == "" {
+			cfg.Endpoint.TokenURL = asm.TokenEndpoint
+		}
+	}
+	if cfg.Endpoint.AuthURL == "" || cfg.Endpoint.TokenURL == "" {
+		return nil, errors.New("missing authorization or token endpoint")
+	}
+
+	listener := opts.Listener
+	if listener == nil {
+		l, err := NewLoopbackRedirectListener("", "")
+		if err != nil {
+			return nil, err
+		}
+		defer l.Close()
+		listener = l
+	}
+	cfg.RedirectURL = listener.RedirectURL()
+
+	state, err := randomString()
+	if err != nil {
+		return nil, err
+	}
+	verifier := oauth2.GenerateVerifier()
+	var extra []oauth2.AuthCodeOption
+	if opts.Resource != "" {
+		extra = append(extra, oauth2.SetAuthURLParam("resource", opts.Resource))
+	}
+
+	authURL := cfg.AuthCodeURL(state, append(extra, oauth2.S256ChallengeOption(verifier))...)
+	if err := opts.OpenURL(ctx, authURL); err != nil {
+		return nil, fmt.Errorf("opening authorization URL: %w", err)
+	}
+
+	params, err := listener.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if code := params.Get("error"); code != "" {
+		return nil, &AuthorizationError{
+			ErrorCode:        code,
+			ErrorDescription: params.Get("error_description"),
+			ErrorURI:         params.Get("error_uri"),
+		}
+	}
+	// Compare in constant time; the state guards against CSRF (RFC 6749, Section 10.12).
+	if subtle.ConstantTimeCompare([]byte(params.Get("state")), []byte(state)) != 1 {
+		return nil, errors.New("state mismatch in redirect")
+	}
+	code := params.Get("code")
+	if code == "" {
+		return nil, errors.New("redirect is missing code")
+	}
+
+	if opts.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, opts.HTTPClient)
+	}
+	return cfg.Exchange(ctx, code, append(extra, oauth2.VerifierOption(verifier))...)
+}
+
+// randomString returns a random URL-safe string suitable for a state parameter.
+func randomString() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// A LoopbackRedirectListener is a [RedirectListener] that serves the redirect
+// on a loopback interface, as recommended for native apps by RFC 8252,
+// Section 7.3.
+type LoopbackRedirectListener struct {
+	ln     net.Listener
+	srv    *http.Server
+	url    string
+	once   sync.Once
+	params chan url.Values
+}
+
+// NewLoopbackRedirectListener starts listening for a redirect on addr, which
+// should be a loopback address. If addr is empty, "127.0.0.1:0" is used.
+// The redirect is served at path, or "/callback" if path is empty.
+// Call Close to stop listening.
+func NewLoopbackRedirectListener(addr, path string) (*LoopbackRedirectListener, error) {
+	if addr == "" {
+		addr = "127.0.0.1:0"
+	}
+	if path == "" {
+		path = "/callback"
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	l := &LoopbackRedirectListener{
+		ln:     ln,
+		url:    (&url.URL{Scheme: "http", Host: ln.Addr().String(), Path: path}).String(),
+		params: make(chan url.Values, 1),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc(path, l.serveRedirect)
+	l.srv = &http.Server{Handler: mux}
+	go l.srv.Serve(ln)
+	return l, nil
+}
+
+func (l *LoopbackRedirectListener) serveRedirect(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	delivered := false
+	l.once.Do(func() {
+		l.params <- q
+		delivered = true
+	})
+	if !delivered {
+		http.Error(w, "redirect already received", http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if q.Get("error") != "" {
+		fmt.Fprintln(w, "Authorization failed. You may close this window.")
+		return
+	}
+	fmt.Fprintln(w, "Authorization complete. You may close this window.")
+}
+
+// RedirectURL implements [RedirectListener.RedirectURL].
+func (l *LoopbackRedirectListener) RedirectURL() string { return l.url }
+
+// Wait implements [RedirectListener.Wait].
+func (l *LoopbackRedirectListener) Wait(ctx context.Context) (url.Values, error) {
+	select {
+	case q := <-l.params:
+		return q, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Close stops the listener.
+func (l *LoopbackRedirectListener) Close() error {
+	return l.srv.Close()
+}
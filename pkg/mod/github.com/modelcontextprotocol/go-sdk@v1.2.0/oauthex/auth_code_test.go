@@ -0,0 +1,151 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeAuthServer returns a test server with an authorization endpoint that
+// redirects immediately, and a token endpoint that checks the PKCE verifier.
+// If authErr is non-empty, the authorization endpoint redirects with that error.
+func fakeAuthServer(t *testing.T, authErr string) *httptest.Server {
+	var challenge string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if got := q.Get("code_challenge_method"); got != "S256" {
+			t.Errorf("code_challenge_method = %q, want S256", got)
+		}
+		if got, want := q.Get("resource"), "https://mcp.example.com"; got != want {
+			t.Errorf("resource = %q, want %q", got, want)
+		}
+		challenge = q.Get("code_challenge")
+		redir, err := url.Parse(q.Get("redirect_uri"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rq := url.Values{"state": {q.Get("state")}}
+		if authErr != "" {
+			rq.Set("error", authErr)
+		} else {
+			rq.Set("code", "the-code")
+		}
+		redir.RawQuery = rq.Encode()
+		http.Redirect(w, r, redir.String(), http.StatusFound)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if got := base64.RawURLEncoding.EncodeToString(sum[:]); got != challenge {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		if got := r.Form.Get("code"); got != "the-code" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok","token_type":"Bearer","expires_in":3600}`))
+	})
+	return httptest.NewServer(mux)
+}
+
+func browse(ctx context.Context, authURL string) error {
+	res, err := http.Get(authURL)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func TestAuthorizeAndExchange(t *testing.T) {
+	srv := fakeAuthServer(t, "")
+	defer srv.Close()
+
+	tok, err := AuthorizeAndExchange(context.Background(), &AuthorizeOptions{
+		Config: &oauth2.Config{ClientID: "client"},
+		AuthServerMeta: &AuthServerMeta{
+			Issuer:                        srv.URL,
+			AuthorizationEndpoint:         srv.URL + "/authorize",
+			TokenEndpoint:                 srv.URL + "/token",
+			CodeChallengeMethodsSupported: []string{"S256"},
+		},
+		Resource: "https://mcp.example.com",
+		OpenURL:  browse,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "tok" {
+		t.Errorf("got access token %q, want %q", tok.AccessToken, "tok")
+	}
+}
+
+func TestAuthorizeAndExchangeErrors(t *testing.T) {
+	srv := fakeAuthServer(t, "access_denied")
+	defer srv.Close()
+	cfg := &oauth2.Config{
+		ClientID: "client",
+		Endpoint: oauth2.Endpoint{AuthURL: srv.URL + "/authorize", TokenURL: srv.URL + "/token"},
+	}
+
+	t.Run("denied", func(t *testing.T) {
+		_, err := AuthorizeAndExchange(context.Background(), &AuthorizeOptions{
+			Config:   cfg,
+			Resource: "https://mcp.example.com",
+			OpenURL:  browse,
+		})
+		var aerr *AuthorizationError
+		if !errors.As(err, &aerr) || aerr.ErrorCode != "access_denied" {
+			t.Errorf("got %v, want AuthorizationError with access_denied", err)
+		}
+		// The server sent no description.
+		if got, want := aerr.Error(), "authorization failed: access_denied"; got != want {
+			t.Errorf("Error() = %q, want %q", got, want)
+		}
+	})
+	t.Run("state mismatch", func(t *testing.T) {
+		l, err := NewLoopbackRedirectListener("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer l.Close()
+		_, err = AuthorizeAndExchange(context.Background(), &AuthorizeOptions{
+			Config:   cfg,
+			Listener: l,
+			OpenURL: func(ctx context.Context, authURL string) error {
+				return browse(ctx, l.RedirectURL()+"?code=c&state=wrong")
+			},
+		})
+		if err == nil || !strings.Contains(err.Error(), "state mismatch") {
+			t.Errorf("got %v, want state mismatch", err)
+		}
+	})
+	t.Run("no S256", func(t *testing.T) {
+		_, err := AuthorizeAndExchange(context.Background(), &AuthorizeOptions{
+			Config:         cfg,
+			AuthServerMeta: &AuthServerMeta{CodeChallengeMethodsSupported: []string{"plain"}},
+			OpenURL:        browse,
+		})
+		if err == nil || !strings.Contains(err.Error(), "S256") {
+			t.Errorf("got %v, want S256 error", err)
+		}
+	})
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements the client credentials grant.
+// See https://www.rfc-editor.org/rfc/rfc6749.html#section-4.4.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+// ClientCredentialsOptions are options for [ClientCredentialsTokenSource].
+type ClientCredentialsOptions struct {
+	// ClientID is the client identifier. Required.
+	ClientID string
+	// ClientSecret is the client secret.
+	ClientSecret string
+	// Scopes are the requested scopes.
+	Scopes []string
+	// Resource is the resource indicator (RFC 8707) to include in token
+	// requests, typically the canonical URI of the MCP server.
+	Resource string
+	// HTTPClient is used for token requests.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+}
+
+// ClientCredentialsTokenSource returns a token source that obtains tokens from
+// the token endpoint in asm using the client credentials grant.
+// Tokens are cached and a new one is requested when the current one expires.
+// The context is used for all token requests, so it should outlive the
+// token source.
+//
+// The grant types supported by asm must include client_credentials. The client authentication method is chosen from the methods that asm
+// lists as supported, preferring client_secret_basic.
+func ClientCredentialsTokenSource(ctx context.Context, asm *AuthServerMeta, opts *ClientCredentialsOptions) (oauth2.TokenSource, error) {
+	if asm == nil {
+		return nil, errors.New("missing authorization server metadata")
+	}
+	if opts == nil || opts.ClientID == "" {
+		return nil, errors.New("missing ClientID")
+	}
+	if asm.TokenEndpoint == "" {
+		return nil, fmt.Errorf("authorization server %s has no token endpoint", asm.Issuer)
+	}
+	// RFC 8414 says that if grant_types_supported is omitted, the default
+	// is ["authorization_code", "implicit"].
+	if !slices.Contains(asm.GrantTypesSupported, "client_credentials") {
+		return nil, fmt.Errorf("authorization server %s does not support the client_credentials grant", asm.Issuer)
+	}
+
+	cfg := &clientcredentials.Config{
+		ClientID:     opts.ClientID,
+		ClientSecret: opts.ClientSecret,
+		TokenURL:     asm.TokenEndpoint,
+		Scopes:       opts.Scopes,
+		AuthStyle:    authStyle(asm.TokenEndpointAuthMethodsSupported),
+	}
+	if opts.Resource != "" {
+		cfg.EndpointParams = url.Values{"resource": {opts.Resource}}
+	}
+	if opts.HTTPClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, opts.HTTPClient)
+	}
+	return cfg.TokenSource(ctx), nil
+}
+
+// authStyle returns the client authentication style to use for a token
+// endpoint that supports the given methods.
+func authStyle(methods []string) oauth2.AuthStyle {
+	// RFC 8414 says that if token_endpoint_auth_methods_supported is omitted,
+	// the default is client_secret_basic.
+	if len(methods) == 0 || slices.Contains(methods, "client_secret_basic") {
+		return oauth2.AuthStyleInHeader
+	}
+	if slices.Contains(methods, "client_secret_post") {
+		return oauth2.AuthStyleInParams
+	}
+	return oauth2.AuthStyleAutoDetect
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestClientCredentialsTokenSource(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if got := r.Form.Get("grant_type"); got != "client_credentials" {
+			t.Errorf("grant_type = %q, want client_credentials", got)
+		}
+		if got, want := r.Form.Get("resource"), "https://mcp.example.com"; got != want {
+			t.Errorf("resource = %q, want %q", got, want)
+		}
+		if id, secret, ok := r.BasicAuth(); !ok || id != "id" || secret != "secret" {
+			t.Errorf("got basic auth %q, %q, %t; want id, secret, true", id, secret, ok)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"tok","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer srv.Close()
+
+	asm := &AuthServerMeta{
+		Issuer:              srv.URL,
+		TokenEndpoint:       srv.URL,
+		GrantTypesSupported: []string{"client_credentials"},
+	}
+	ts, err := ClientCredentialsTokenSource(context.Background(), asm, &ClientCredentialsOptions{
+		ClientID:     "id",
+		ClientSecret: "secret",
+		Resource:     "https://mcp.example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tok, err := ts.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "tok" {
+		t.Errorf("got %q, want tok", tok.AccessToken)
+	}
+
+	asm.GrantTypesSupported = []string{"authorization_code"}
+	if _, err := ClientCredentialsTokenSource(context.Background(), asm, &ClientCredentialsOptions{ClientID: "id"}); err == nil {
+		t.Error("got nil error for unsupported grant type")
+	}
+}
+
+func TestAuthStyle(t *testing.T) {
+	for _, test := range []struct {
+		methods []string
+		want    oauth2.AuthStyle
+	}{
+		{nil, oauth2.AuthStyleInHeader},
+		{[]string{"client_secret_post", "client_secret_basic"}, oauth2.AuthStyleInHeader},
+		{[]string{"client_secret_post"}, oauth2.AuthStyleInParams},
+		{[]string{"private_key_jwt"}, oauth2.AuthStyleAutoDetect},
+	} {
+		if got := authStyle(test.methods); got != test.want {
+			t.Errorf("authStyle(%v) = %v, want %v", test.methods, got, test.want)
+		}
+	}
+}
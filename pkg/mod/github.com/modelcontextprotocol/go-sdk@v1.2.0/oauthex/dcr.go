@@ -110,6 +110,15 @@ type ClientRegistrationResponse struct {
 	// ClientSecretExpiresAt is the REQUIRED (if client_secret is issued) Unix
 	// timestamp when the secret expires, or 0 if it never expires.
 	ClientSecretExpiresAt time.Time `json:"client_secret_expires_at,omitempty"`
+
+	// RegistrationAccessToken is an OPTIONAL token for accessing the client
+	// configuration endpoint (RFC 7592, Section 3).
+	RegistrationAccessToken string `json:"registration_access_token,omitempty"`
+
+	// RegistrationClientURI is the OPTIONAL URL of the client configuration
+	// endpoint (RFC 7592, Section 3). It is returned if and only if
+	// RegistrationAccessToken is.
+	RegistrationClientURI string `json:"registration_client_uri,omitempty"`
 }
 
 func (r *ClientRegistrationResponse) MarshalJSON() ([]byte, error) {
@@ -216,6 +225,9 @@ func RegisterClient(ctx context.Context, registrationEndpoint string, clientMeta
 		if err := validateClientRegistrationURLs(&regResponse.ClientRegistrationMetadata); err != nil {
 			return nil, err
 		}
+		if err := checkURLScheme(regResponse.RegistrationClientURI); err != nil {
+			return nil, fmt.Errorf("registration_client_uri: %w", err)
+		}
 		return &regResponse, nil
 	}
 
@@ -0,0 +1,219 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements persistence of dynamic client registrations,
+// and reading client configuration as in RFC 7592.
+// See https://www.rfc-editor.org/rfc/rfc7592.html.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// A ClientStore persists client registrations, keyed by the issuer of the
+// authorization server that issued them. Implementations must be safe for
+// concurrent use.
+type ClientStore interface {
+	// LoadClient returns the registration for issuer, or nil if there is none.
+	LoadClient(ctx context.Context, issuer string) (*ClientRegistrationResponse, error)
+	// SaveClient stores reg for issuer, replacing any previous registration.
+	SaveClient(ctx context.Context, issuer string, reg *ClientRegistrationResponse) error
+}
+
+// MemoryClientStore is a [ClientStore] that keeps registrations in memory.
+// The zero value is ready to use.
+type MemoryClientStore struct {
+	mu      sync.Mutex
+	clients map[string]*ClientRegistrationResponse
+}
+
+// LoadClient implements [ClientStore.LoadClient].
+func (s *MemoryClientStore) LoadClient(_ context.Context, issuer string) (*ClientRegistrationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.clients[issuer], nil
+}
+
+// SaveClient implements [ClientStore.SaveClient].
+func (s *MemoryClientStore) SaveClient(_ context.Context, issuer string, reg *ClientRegistrationResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.clients == nil {
+		s.clients = make(map[string]*ClientRegistrationResponse)
+	}
+	s.clients[issuer] = reg
+	return nil
+}
+
+// FileClientStore is a [ClientStore] that keeps registrations as a JSON
+// object in a file, mapping issuers to registrations.
+// The file is created with mode 0600, since it contains credentials.
+type FileClientStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileClientStore returns a [FileClientStore] that uses the file at path.
+func NewFileClientStore(path string) *FileClientStore {
+	return &FileClientStore{path: path}
+}
+
+// LoadClient implements [ClientStore.LoadClient].
+func (s *FileClientStore) LoadClient(_ context.Context, issuer string) (*ClientRegistrationResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clients, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	return clients[issuer], nil
+}
+
+// SaveClient implements [ClientStore.SaveClient].
+func (s *FileClientStore) SaveClient(_ context.Context, issuer string, reg *ClientRegistrationResponse) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	clients, err := s.read()
+	if err != nil {
+		return err
+	}
+	if clients == nil {
+		clients = make(map[string]*ClientRegistrationResponse)
+	}
+	clients[issuer] = reg
+	data, err := json.Marshal(clients)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// read returns the contents of the file, or nil if it does not exist.
+func (s *FileClientStore) read() (map[string]*ClientRegistrationResponse, error) {
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var clients map[string]*ClientRegistrationResponse
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, err
+	}
+	return clients, nil
+}
+
+// RegisterClientWithStore returns a client registration for the authorization
+// server described by asm, reusing a registration from store if possible.
+//
+// If store has no registration for asm.Issuer, it registers a new client with
+// [RegisterClient] and saves the result.
+// If the stored client secret has expired and the registration includes a
+// client configuration endpoint, the registration is read from that endpoint
+// with [ReadClientConfiguration], which lets the server rotate the secret and
+// registration access token. Otherwise an expired registration is replaced
+// by a new one.
+func RegisterClientWithStore(ctx context.Context, store ClientStore, asm *AuthServerMeta, clientMeta *ClientRegistrationMetadata, c *http.Client) (*ClientRegistrationResponse, error) {
+	if store == nil || asm == nil {
+		return nil, errors.New("missing store or authorization server metadata")
+	}
+	reg, err := store.LoadClient(ctx, asm.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if reg != nil && !secretExpired(reg) {
+		return reg, nil
+	}
+	if reg != nil && reg.RegistrationClientURI != "" && reg.RegistrationAccessToken != "" {
+		if rotated, err := ReadClientConfiguration(ctx, reg, c); err == nil && !secretExpired(rotated) {
+			if err := store.SaveClient(ctx, asm.Issuer, rotated); err != nil {
+				return nil, err
+			}
+			return rotated, nil
+		}
+		// Fall back to registering again.
+	}
+	reg, err = RegisterClient(ctx, asm.RegistrationEndpoint, clientMeta, c)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.SaveClient(ctx, asm.Issuer, reg); err != nil {
+		return nil, err
+	}
+	return reg, nil
+}
+
+// secretExpired reports whether reg has a client secret that has expired.
+func secretExpired(reg *ClientRegistrationResponse) bool {
+	return reg.ClientSecret != "" && !reg.ClientSecretExpiresAt.IsZero() && !time.Now().Before(reg.ClientSecretExpiresAt)
+}
+
+// ReadClientConfiguration retrieves the current configuration of a registered
+// client from its client configuration endpoint, as described in RFC 7592,
+// Section 2.1. The server may issue a new client secret or registration
+// access token in the response; callers should persist the result.
+func ReadClientConfiguration(ctx context.Context, reg *ClientRegistrationResponse, c *http.Client) (*ClientRegistrationResponse, error) {
+	if reg.RegistrationClientURI == "" || reg.RegistrationAccessToken == "" {
+		return nil, errors.New("registration has no client configuration endpoint")
+	}
+	if c == nil {
+		c = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reg.RegistrationClientURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client configuration request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+reg.RegistrationAccessToken)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("client configuration request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read client configuration response body: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("client configuration request failed with status %s: %s", resp.Status, string(body))
+	}
+
+	var newReg ClientRegistrationResponse
+	if err := json.Unmarshal(body, &newReg); err != nil {
+		return nil, fmt.Errorf("failed to decode client configuration response: %w (%s)", err, string(body))
+	}
+	if newReg.ClientID != reg.ClientID {
+		return nil, fmt.Errorf("client configuration response has client_id %q, want %q", newReg.ClientID, reg.ClientID)
+	}
+	// Validate URL fields to prevent XSS attacks (see #526).
+	if err := validateClientRegistrationURLs(&newReg.ClientRegistrationMetadata); err != nil {
+		return nil, err
+	}
+	if err := checkURLScheme(newReg.RegistrationClientURI); err != nil {
+		return nil, fmt.Errorf("registration_client_uri: %w", err)
+	}
+	// RFC 7592 says the server returns these, but keep the old values if
+	// it does not rotate them.
+	if newReg.RegistrationAccessToken == "" {
+		newReg.RegistrationAccessToken = reg.RegistrationAccessToken
+	}
+	if newReg.RegistrationClientURI == "" {
+		newReg.RegistrationClientURI = reg.RegistrationClientURI
+	}
+	return &newReg, nil
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRegisterClientWithStore(t *testing.T) {
+	ctx := context.Background()
+	var registrations, reads int
+	mux := http.NewServeMux()
+	srv := httptest.NewServer(mux)
+	defer srv.Close()
+	mux.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		registrations++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		// The first registration has an expired secret.
+		fmt.Fprintf(w, `{"client_id":"id","client_secret":"s%d","client_secret_expires_at":%d,
+			"registration_access_token":"rat","registration_client_uri":"%s/clients/id"}`,
+			registrations, time.Now().Add(-time.Hour).Unix(), srv.URL)
+	})
+	mux.HandleFunc("/clients/id", func(w http.ResponseWriter, r *http.Request) {
+		reads++
+		if got := r.Header.Get("Authorization"); got != "Bearer rat" {
+			t.Errorf("Authorization = %q, want Bearer rat", got)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"client_id":"id","client_secret":"rotated","client_secret_expires_at":%d,"registration_access_token":"rat2"}`,
+			time.Now().Add(time.Hour).Unix())
+	})
+	asm := &AuthServerMeta{Issuer: srv.URL, RegistrationEndpoint: srv.URL + "/register"}
+	meta := &ClientRegistrationMetadata{RedirectURIs: []string{"http://localhost/cb"}}
+	store := NewFileClientStore(filepath.Join(t.TempDir(), "clients.json"))
+
+	// No stored client: register, then rotate the expired secret.
+	reg, err := RegisterClientWithStore(ctx, store, asm, meta, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.ClientSecret != "s1" || registrations != 1 {
+		t.Fatalf("got secret %q after %d registrations, want s1 after 1", reg.ClientSecret, registrations)
+	}
+
+	reg, err = RegisterClientWithStore(ctx, store, asm, meta, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.ClientSecret != "rotated" || reg.RegistrationAccessToken != "rat2" {
+		t.Errorf("got secret %q, token %q; want rotated, rat2", reg.ClientSecret, reg.RegistrationAccessToken)
+	}
+	if reg.RegistrationClientURI != srv.URL+"/clients/id" {
+		t.Errorf("registration_client_uri not preserved: %q", reg.RegistrationClientURI)
+	}
+
+	// The rotated client is stored and reused.
+	reg, err = RegisterClientWithStore(ctx, store, asm, meta, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reg.ClientSecret != "rotated" || registrations != 1 || reads != 1 {
+		t.Errorf("got secret %q, %d registrations, %d reads; want rotated, 1, 1", reg.ClientSecret, registrations, reads)
+	}
+}
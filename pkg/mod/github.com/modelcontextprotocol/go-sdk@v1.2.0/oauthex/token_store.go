@@ -0,0 +1,245 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// This file implements token persistence and refresh.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// A TokenStore persists OAuth tokens across process restarts.
+// Implementations must be safe for concurrent use.
+type TokenStore interface {
+	// Load returns the stored token, or nil if there is none.
+	Load(ctx context.Context) (*oauth2.Token, error)
+	// Save stores tok, replacing any previously stored token.
+	Save(ctx context.Context, tok *oauth2.Token) error
+}
+
+// MemoryTokenStore is a [TokenStore] that keeps its token in memory.
+// The zero value is ready to use.
+type MemoryTokenStore struct {
+	mu  sync.Mutex
+	tok *oauth2.Token
+}
+
+// Load implements [TokenStore.Load].
+func (s *MemoryTokenStore) Load(context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tok, nil
+}
+
+// Save implements [TokenStore.Save].
+func (s *MemoryTokenStore) Save(_ context.Context, tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tok = tok
+	return nil
+}
+
+// FileTokenStore is a [TokenStore] that keeps its token as JSON in a file.
+// The file is created with mode 0600, since it contains credentials.
+type FileTokenStore struct {
+	mu   sync.Mutex
+	path string
+}
+
+// NewFileTokenStore returns a [FileTokenStore] that uses the file at path.
+func NewFileTokenStore(path string) *FileTokenStore {
+	return &FileTokenStore{path: path}
+}
+
+// Load implements [TokenStore.Load].
+// It returns nil, nil if the file does not exist.
+func (s *FileTokenStore) Load(context.Context) (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tok oauth2.Token
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, err
+	}
+	return &tok, nil
+}
+
+// Save implements [TokenStore.Save].
+func (s *FileTokenStore) Save(_ context.Context, tok *oauth2.Token) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := json.Marshal(tok)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path, data)
+}
+
+// writeFileAtomic writes data to a temporary file with mode 0600 and renames
+// it to path, so a crash never leaves a partially written file behind.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name()) // ignore error; fails after a successful rename
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Chmod(0o600); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// TokenManagerOptions are options for [NewTokenManager].
+type TokenManagerOptions struct {
+	// Config is used to refresh tokens. Its ClientID and Endpoint.TokenURL
+	// are required for refresh to succeed.
+	Config *oauth2.Config
+	// Store persists tokens. If nil, a [MemoryTokenStore] is used.
+	Store TokenStore
+	// RefreshBefore is how long before expiry a token is refreshed.
+	// If zero, it defaults to one minute.
+	RefreshBefore time.Duration
+	// HTTPClient is used for refresh requests.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+}
+
+// A TokenManager supplies access tokens from a [TokenStore], refreshing them
+// shortly before they expire and saving the result back to the store.
+//
+// A TokenManager is an [oauth2.TokenSource]. Use [TokenManager.HTTPClient] to
+// obtain a client for mcp.StreamableClientTransport that attaches the
+// current access token to every request.
+type TokenManager struct {
+	cfg           *oauth2.Config
+	store         TokenStore
+	refreshBefore time.Duration
+	httpClient    *http.Client
+
+	mu  sync.Mutex // serializes refreshes
+	tok *oauth2.Token
+}
+
+// NewTokenManager returns a new [TokenManager].
+func NewTokenManager(opts *TokenManagerOptions) (*TokenManager, error) {
+	if opts == nil || opts.Config == nil {
+		return nil, errors.New("missing Config")
+	}
+	m := &TokenManager{
+		cfg:           opts.Config,
+		store:         opts.Store,
+		refreshBefore: opts.RefreshBefore,
+		httpClient:    opts.HTTPClient,
+	}
+	if m.store == nil {
+		m.store = &MemoryTokenStore{}
+	}
+	if m.refreshBefore == 0 {
+		m.refreshBefore = time.Minute
+	}
+	return m, nil
+}
+
+// SetToken replaces the current token, for example with the result of
+// [AuthorizeAndExchange], and saves it to the store.
+func (m *TokenManager) SetToken(ctx context.Context, tok *oauth2.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if err := m.store.Save(ctx, tok); err != nil {
+		return err
+	}
+	m.tok = tok
+	return nil
+}
+
+// Token implements [oauth2.TokenSource].
+func (m *TokenManager) Token() (*oauth2.Token, error) {
+	return m.TokenContext(context.Background())
+}
+
+// TokenContext returns a valid token, loading it from the store or
+// refreshing it as needed.
+func (m *TokenManager) TokenContext(ctx context.Context) (*oauth2.Token, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tok == nil {
+		tok, err := m.store.Load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if tok == nil {
+			return nil, errors.New("no token available")
+		}
+		m.tok = tok
+	}
+	if m.fresh(m.tok) {
+		return m.tok, nil
+	}
+	if m.tok.RefreshToken == "" {
+		return nil, errors.New("token expired and has no refresh token")
+	}
+
+	if m.httpClient != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, m.httpClient)
+	}
+	// Pass only the refresh token, so that the token source refreshes
+	// immediately even if the current token is not yet expired.
+	tok, err := m.cfg.TokenSource(ctx, &oauth2.Token{RefreshToken: m.tok.RefreshToken}).Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := m.store.Save(ctx, tok); err != nil {
+		return nil, err
+	}
+	m.tok = tok
+	return tok, nil
+}
+
+// fresh reports whether tok can be used without refreshing.
+func (m *TokenManager) fresh(tok *oauth2.Token) bool {
+	if tok.AccessToken == "" {
+		return false
+	}
+	return tok.Expiry.IsZero() || time.Until(tok.Expiry) > m.refreshBefore
+}
+
+// HTTPClient returns an HTTP client that adds the current access token to
+// each request. Requests are sent using base's transport, or
+// [http.DefaultTransport] if base is nil.
+func (m *TokenManager) HTTPClient(base *http.Client) *http.Client {
+	var c http.Client
+	if base != nil {
+		c = *base
+	}
+	c.Transport = &oauth2.Transport{Source: m, Base: c.Transport}
+	return &c
+}
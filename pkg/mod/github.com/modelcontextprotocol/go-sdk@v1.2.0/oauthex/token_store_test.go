@@ -0,0 +1,118 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+//go:build mcp_go_client_oauth
+
+package oauthex
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "token.json")
+	s := NewFileTokenStore(path)
+	tok, err := s.Load(ctx)
+	if err != nil || tok != nil {
+		t.Fatalf("Load on missing file: got %v, %v; want nil, nil", tok, err)
+	}
+	want := &oauth2.Token{AccessToken: "a", RefreshToken: "r"}
+	if err := s.Save(ctx, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := s.Load(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.AccessToken != want.AccessToken || got.RefreshToken != want.RefreshToken {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+	fi, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := fi.Mode().Perm(); perm != 0o600 {
+		t.Errorf("got mode %v, want 0600", perm)
+	}
+}
+
+func TestTokenManager(t *testing.T) {
+	ctx := context.Background()
+	refreshes := 0
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if got := r.Form.Get("grant_type"); got != "refresh_token" {
+			t.Errorf("grant_type = %q, want refresh_token", got)
+		}
+		refreshes++
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"access-%d","token_type":"Bearer","expires_in":3600}`, refreshes)
+	}))
+	defer tokenServer.Close()
+
+	store := &MemoryTokenStore{}
+	m, err := NewTokenManager(&TokenManagerOptions{
+		Config: &oauth2.Config{ClientID: "c", Endpoint: oauth2.Endpoint{TokenURL: tokenServer.URL}},
+		Store:  store,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := m.Token(); err == nil {
+		t.Fatal("got nil error with empty store")
+	}
+
+	// A token close to expiry is refreshed, and the result is saved.
+	if err := m.SetToken(ctx, &oauth2.Token{
+		AccessToken:  "old",
+		RefreshToken: "r",
+		Expiry:       time.Now().Add(30 * time.Second),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	tok, err := m.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "access-1" {
+		t.Errorf("got %q, want access-1", tok.AccessToken)
+	}
+	if tok.RefreshToken != "r" {
+		t.Errorf("refresh token not preserved: got %q", tok.RefreshToken)
+	}
+	if saved, _ := store.Load(ctx); saved.AccessToken != "access-1" {
+		t.Errorf("store has %q, want access-1", saved.AccessToken)
+	}
+
+	// A fresh token is reused.
+	if _, err := m.Token(); err != nil {
+		t.Fatal(err)
+	}
+	if refreshes != 1 {
+		t.Errorf("got %d refreshes, want 1", refreshes)
+	}
+
+	// The HTTP client attaches the token.
+	resource := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer access-1"; got != want {
+			t.Errorf("Authorization = %q, want %q", got, want)
+		}
+	}))
+	defer resource.Close()
+	res, err := m.HTTPClient(nil).Get(resource.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+}
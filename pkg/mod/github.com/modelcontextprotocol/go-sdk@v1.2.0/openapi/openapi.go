@@ -0,0 +1,569 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+// Package openapi exposes REST APIs described by OpenAPI 3 documents as MCP
+// tools.
+//
+// [AddTools] adds one tool to an [mcp.Server] for each operation in the
+// document. The tool's input schema has a property for each path, query and
+// header parameter of the operation, and a "body" property for its JSON
+// request body, if any. Calling the tool makes the corresponding HTTP request
+// and returns the response body as text.
+//
+// Only documents in JSON form are supported. Convert YAML documents to JSON
+// before calling AddTools.
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Options are options for [AddTools].
+type Options struct {
+	// BaseURL is the URL that operation paths are relative to.
+	// If empty, the URL of the first server in the document is used,
+	// with any server variables set to their default values.
+	BaseURL string
+	// HTTPClient is used to make requests.
+	// If nil, [http.DefaultClient] is used.
+	HTTPClient *http.Client
+	// Authorize, if non-nil, is called with each outgoing request before it is
+	// sent, to add credentials such as an Authorization header or API key.
+	// If it returns an error, the request is not sent and the tool call fails.
+	Authorize func(*http.Request) error
+	// Include, if non-nil, reports whether to add a tool for the operation
+	// with the given method, path and operation ID (which may be empty).
+	Include func(method, path, operationID string) bool
+}
+
+// maxResponseSize limits the size of response bodies returned by tools.
+const maxResponseSize = 10 << 20
+
+// AddTools adds a tool to s for each operation in the OpenAPI 3 document doc.
+//
+// Tools are named by their operation's operationId, or if it is absent, by the
+// method and path, as in "get_pets_id". Their descriptions are taken from the
+// operation's summary and description. GET and HEAD operations are annotated
+// as read-only, and DELETE operations as destructive.
+//
+// Schemas in the document's components are included in each tool's input
+// schema as "$defs", so that references to them continue to work.
+func AddTools(s *mcp.Server, doc []byte, opts *Options) error {
+	if opts == nil {
+		opts = &Options{}
+	}
+	var d document
+	if err := json.Unmarshal(doc, &d); err != nil {
+		return fmt.Errorf("openapi: parsing document: %w", err)
+	}
+	if !strings.HasPrefix(d.OpenAPI, "3.") {
+		return fmt.Errorf("openapi: unsupported OpenAPI version %q", d.OpenAPI)
+	}
+	base, err := d.baseURL(opts.BaseURL)
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+	defs, err := d.defs()
+	if err != nil {
+		return fmt.Errorf("openapi: %w", err)
+	}
+
+	var tools []*mcp.Tool
+	var handlers []mcp.ToolHandler
+	names := make(map[string]bool)
+	for _, path := range sortedKeys(d.Paths) {
+		item := d.Paths[path]
+		for _, method := range methods {
+			raw := item.operation(method)
+			if raw == nil {
+				continue
+			}
+			if opts.Include != nil && !opts.Include(strings.ToUpper(method), path, raw.OperationID) {
+				continue
+			}
+			o, err := d.newOperation(strings.ToUpper(method), path, item.Parameters, raw)
+			if err != nil {
+				return fmt.Errorf("openapi: %s %s: %w", strings.ToUpper(method), path, err)
+			}
+			tool := o.tool(defs)
+			if names[tool.Name] {
+				return fmt.Errorf("openapi: %s %s: duplicate tool name %q", o.method, path, tool.Name)
+			}
+			names[tool.Name] = true
+			tools = append(tools, tool)
+			handlers = append(handlers, o.handler(base, opts))
+		}
+	}
+	for i, t := range tools {
+		s.AddTool(t, handlers[i])
+	}
+	return nil
+}
+
+// methods are the HTTP methods of operations in a path item, in the order
+// that tools are added.
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// A document is the part of an OpenAPI 3 document that AddTools uses.
+type document struct {
+	OpenAPI string               `json:"openapi"`
+	Servers []server             `json:"servers"`
+	Paths   map[string]*pathItem `json:"paths"`
+
+	Components struct {
+		Schemas       map[string]any          `json:"schemas"`
+		Parameters    map[string]*parameter   `json:"parameters"`
+		RequestBodies map[string]*requestBody `json:"requestBodies"`
+	} `json:"components"`
+}
+
+type server struct {
+	URL       string `json:"url"`
+	Variables map[string]struct {
+		Default string `json:"default"`
+	} `json:"variables"`
+}
+
+type pathItem struct {
+	Parameters []*parameter `json:"parameters"`
+	Get        *operation   `json:"get"`
+	Put        *operation   `json:"put"`
+	Post       *operation   `json:"post"`
+	Delete     *operation   `json:"delete"`
+	Options    *operation   `json:"options"`
+	Head       *operation   `json:"head"`
+	Patch      *operation   `json:"patch"`
+	Trace      *operation   `json:"trace"`
+}
+
+func (p *pathItem) operation(method string) *operation {
+	switch method {
+	case "get":
+		return p.Get
+	case "put":
+		return p.Put
+	case "post":
+		return p.Post
+	case "delete":
+		return p.Delete
+	case "options":
+		return p.Options
+	case "head":
+		return p.Head
+	case "patch":
+		return p.Patch
+	case "trace":
+		return p.Trace
+	}
+	return nil
+}
+
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Summary     string       `json:"summary"`
+	Description string       `json:"description"`
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *requestBody `json:"requestBody"`
+	Deprecated  bool         `json:"deprecated"`
+}
+
+type parameter struct {
+	Ref         string `json:"$ref"`
+	Name        string `json:"name"`
+	In          string `json:"in"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Schema      any    `json:"schema"`
+}
+
+type requestBody struct {
+	Ref         string `json:"$ref"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Content     map[string]struct {
+		Schema any `json:"schema"`
+	} `json:"content"`
+}
+
+// baseURL returns the URL that paths are relative to.
+func (d *document) baseURL(override string) (string, error) {
+	u := override
+	if u == "" {
+		if len(d.Servers) == 0 {
+			return "", errors.New("document has no servers and no BaseURL was provided")
+		}
+		s := d.Servers[0]
+		u = s.URL
+		for name, v := range s.Variables {
+			u = strings.ReplaceAll(u, "{"+name+"}", v.Default)
+		}
+	}
+	parsed, err := url.Parse(u)
+	if err != nil {
+		return "", fmt.Errorf("invalid base URL: %w", err)
+	}
+	if !parsed.IsAbs() {
+		return "", fmt.Errorf("base URL %q is not absolute", u)
+	}
+	return strings.TrimSuffix(u, "/"), nil
+}
+
+const (
+	componentSchemaPrefix = "#/components/schemas/"
+	componentParamPrefix  = "#/components/parameters/"
+	componentBodyPrefix   = "#/components/requestBodies/"
+	schemaDefsPrefix      = "#/$defs/"
+
+	// bodyProperty is the input property that holds the request body.
+	bodyProperty    = "body"
+	applicationJSON = "application/json"
+)
+
+// defs returns the component schemas of d, with references rewritten to
+// refer to "$defs".
+func (d *document) defs() (map[string]any, error) {
+	if len(d.Components.Schemas) == 0 {
+		return nil, nil
+	}
+	defs, ok := rewriteRefs(d.Components.Schemas).(map[string]any)
+	if !ok {
+		return nil, errors.New("invalid component schemas")
+	}
+	return defs, nil
+}
+
+// rewriteRefs returns a copy of the JSON value v in which references to
+// component schemas refer to "$defs" instead.
+func rewriteRefs(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		m := make(map[string]any, len(v))
+		for k, e := range v {
+			if s, ok := e.(string); ok && k == "$ref" && strings.HasPrefix(s, componentSchemaPrefix) {
+				m[k] = schemaDefsPrefix + strings.TrimPrefix(s, componentSchemaPrefix)
+				continue
+			}
+			m[k] = rewriteRefs(e)
+		}
+		return m
+	case []any:
+		s := make([]any, len(v))
+		for i, e := range v {
+			s[i] = rewriteRefs(e)
+		}
+		return s
+	default:
+		return v
+	}
+}
+
+// An op is an operation, with references resolved.
+type op struct {
+	method, path string
+	operation    *operation
+	params       []*parameter
+	body         *requestBody // nil if the operation has no JSON body
+}
+
+func (d *document) newOperation(method, path string, pathParams []*parameter, o *operation) (*op, error) {
+	res := &op{method: method, path: path, operation: o}
+	// Operation parameters override path item parameters with the same name
+	// and location.
+	seen := make(map[string]bool)
+	for _, ps := range [][]*parameter{o.Parameters, pathParams} {
+		for _, p := range ps {
+			p, err := d.resolveParam(p)
+			if err != nil {
+				return nil, err
+			}
+			if p.In == "cookie" {
+				continue
+			}
+			if p.In != "path" && p.In != "query" && p.In != "header" {
+				return nil, fmt.Errorf("parameter %q has invalid location %q", p.Name, p.In)
+			}
+			if seen[p.In+":"+p.Name] {
+				continue
+			}
+			seen[p.In+":"+p.Name] = true
+			if p.Name == bodyProperty && o.RequestBody != nil {
+				return nil, fmt.Errorf("parameter %q conflicts with request body", p.Name)
+			}
+			if slices.ContainsFunc(res.params, func(q *parameter) bool { return q.Name == p.Name }) {
+				return nil, fmt.Errorf("parameter %q appears in more than one location", p.Name)
+			}
+			res.params = append(res.params, p)
+		}
+	}
+	if o.RequestBody != nil {
+		b, err := d.resolveBody(o.RequestBody)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := b.Content[applicationJSON]; !ok {
+			return nil, errors.New("request body does not support application/json")
+		}
+		res.body = b
+	}
+	return res, nil
+}
+
+func (d *document) resolveParam(p *parameter) (*parameter, error) {
+	if p.Ref == "" {
+		return p, nil
+	}
+	name, ok := strings.CutPrefix(p.Ref, componentParamPrefix)
+	if r := d.Components.Parameters[name]; ok && r != nil && r.Ref == "" {
+		return r, nil
+	}
+	return nil, fmt.Errorf("cannot resolve parameter reference %q", p.Ref)
+}
+
+func (d *document) resolveBody(b *requestBody) (*requestBody, error) {
+	if b.Ref == "" {
+		return b, nil
+	}
+	name, ok := strings.CutPrefix(b.Ref, componentBodyPrefix)
+	if r := d.Components.RequestBodies[name]; ok && r != nil && r.Ref == "" {
+		return r, nil
+	}
+	return nil, fmt.Errorf("cannot resolve request body reference %q", b.Ref)
+}
+
+// tool returns the tool for o.
+func (o *op) tool(defs map[string]any) *mcp.Tool {
+	props := make(map[string]any)
+	var required []string
+	for _, p := range o.params {
+		s, _ := rewriteRefs(p.Schema).(map[string]any)
+		if s == nil {
+			s = map[string]any{"type": "string"}
+		}
+		if p.Description != "" {
+			s["description"] = p.Description
+		}
+		props[p.Name] = s
+		if p.Required || p.In == "path" {
+			required = append(required, p.Name)
+		}
+	}
+	if o.body != nil {
+		s, _ := rewriteRefs(o.body.Content[applicationJSON].Schema).(map[string]any)
+		if s == nil {
+			s = map[string]any{}
+		}
+		if o.body.Description != "" {
+			// Use allOf so as not to override the description of a referenced schema.
+			s = map[string]any{"description": o.body.Description, "allOf": []any{s}}
+		}
+		props[bodyProperty] = s
+		if o.body.Required {
+			required = append(required, bodyProperty)
+		}
+	}
+	schema := map[string]any{
+		"type":       "object",
+		"properties": props,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	if len(defs) > 0 {
+		schema["$defs"] = defs
+	}
+
+	t := &mcp.Tool{
+		Name:        o.toolName(),
+		Description: o.description(),
+		InputSchema: schema,
+	}
+	switch o.method {
+	case http.MethodGet, http.MethodHead:
+		t.Annotations = &mcp.ToolAnnotations{ReadOnlyHint: true, IdempotentHint: true}
+	case http.MethodPut:
+		t.Annotations = &mcp.ToolAnnotations{IdempotentHint: true}
+	case http.MethodDelete:
+		destructive := true
+		t.Annotations = &mcp.ToolAnnotations{DestructiveHint: &destructive, IdempotentHint: true}
+	}
+	return t
+}
+
+var invalidToolNameChars = regexp.MustCompile(`[^A-Za-z0-9_.-]+`)
+
+func (o *op) toolName() string {
+	sanitize := func(s string) string {
+		return strings.Trim(invalidToolNameChars.ReplaceAllString(s, "_"), "_")
+	}
+	name := sanitize(o.operation.OperationID)
+	if name == "" {
+		name = strings.ToLower(o.method) + "_" + sanitize(o.path)
+	}
+	if len(name) > 128 { // the maximum length of a tool name
+		name = name[:128]
+	}
+	return name
+}
+
+func (o *op) description() string {
+	var parts []string
+	for _, s := range []string{o.operation.Summary, o.operation.Description} {
+		if s != "" {
+			parts = append(parts, s)
+		}
+	}
+	if len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("Calls %s %s.", o.method, o.path))
+	}
+	if o.operation.Deprecated {
+		parts = append(parts, "Deprecated.")
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// handler returns a tool handler that makes the HTTP request for o.
+func (o *op) handler(base string, opts *Options) mcp.ToolHandler {
+	client := opts.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var args map[string]any
+		if len(req.Params.Arguments) > 0 {
+			if err := json.Unmarshal(req.Params.Arguments, &args); err != nil {
+				return nil, fmt.Errorf("invalid arguments: %w", err)
+			}
+		}
+		hreq, err := o.newRequest(ctx, base, args)
+		if err != nil {
+			// Report bad arguments to the model, so that it can correct them.
+			return toolError(err), nil
+		}
+		if opts.Authorize != nil {
+			if err := opts.Authorize(hreq); err != nil {
+				return nil, fmt.Errorf("authorizing request: %w", err)
+			}
+		}
+		resp, err := client.Do(hreq)
+		if err != nil {
+			return toolError(err), nil
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+		if err != nil {
+			return toolError(fmt.Errorf("reading response: %w", err)), nil
+		}
+		if resp.StatusCode >= 400 {
+			return toolError(fmt.Errorf("%s %s: %s: %s", o.method, hreq.URL.Path, resp.Status, body)), nil
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: string(body)}}}, nil
+	}
+}
+
+// newRequest returns the HTTP request for a call of o with the given
+// arguments.
+func (o *op) newRequest(ctx context.Context, base string, args map[string]any) (*http.Request, error) {
+	path := o.path
+	query := url.Values{}
+	header := http.Header{}
+	for _, p := range o.params {
+		v, ok := args[p.Name]
+		if !ok {
+			if p.Required || p.In == "path" {
+				return nil, fmt.Errorf("missing required parameter %q", p.Name)
+			}
+			continue
+		}
+		switch p.In {
+		case "path":
+			ps := paramString(v)
+			if ps == "." || ps == ".." {
+				// PathEscape leaves dot segments alone, so the server would
+				// resolve them to a path that the operation does not expose.
+				return nil, fmt.Errorf("invalid value %q for path parameter %q", ps, p.Name)
+			}
+			path = strings.ReplaceAll(path, "{"+p.Name+"}", url.PathEscape(ps))
+		case "query":
+			if vs, ok := v.([]any); ok {
+				for _, e := range vs {
+					query.Add(p.Name, paramString(e))
+				}
+			} else {
+				query.Set(p.Name, paramString(v))
+			}
+		case "header":
+			header.Set(p.Name, paramString(v))
+		}
+	}
+	u := base + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	var body io.Reader
+	if o.body != nil {
+		if v, ok := args[bodyProperty]; ok {
+			data, err := json.Marshal(v)
+			if err != nil {
+				return nil, err
+			}
+			body = bytes.NewReader(data)
+			header.Set("Content-Type", applicationJSON)
+		} else if o.body.Required {
+			return nil, fmt.Errorf("missing required %q", bodyProperty)
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, o.method, u, body)
+	if err != nil {
+		return nil, err
+	}
+	for k, vs := range header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Accept", "application/json, */*;q=0.5")
+	return req, nil
+}
+
+// paramString formats a parameter value for a URL or header.
+func paramString(v any) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	default:
+		// Numbers and booleans marshal to their usual forms;
+		// objects and arrays are sent as JSON.
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
+
+func toolError(err error) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}},
+		IsError: true,
+	}
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
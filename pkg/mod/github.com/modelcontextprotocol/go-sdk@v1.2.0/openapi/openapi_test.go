@@ -0,0 +1,213 @@
+// Copyright 2025 The Go MCP SDK Authors. All rights reserved.
+// Use of this source code is governed by an MIT-style
+// license that can be found in the LICENSE file.
+
+package openapi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const petstore = `{
+  "openapi": "3.0.3",
+  "servers": [{"url": "https://{host}/v1", "variables": {"host": {"default": "example.com"}}}],
+  "paths": {
+    "/pets/{id}": {
+      "parameters": [{"$ref": "#/components/parameters/ID"}],
+      "get": {
+        "operationId": "getPet",
+        "summary": "Get a pet",
+        "parameters": [{"name": "verbose", "in": "query", "schema": {"type": "boolean"}}]
+      },
+      "delete": {}
+    },
+    "/pets": {
+      "post": {
+        "operationId": "createPet",
+        "requestBody": {
+          "required": true,
+          "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "integer"}}
+    },
+    "schemas": {
+      "Pet": {
+        "type": "object",
+        "properties": {"name": {"type": "string"}, "owner": {"$ref": "#/components/schemas/Owner"}}
+      },
+      "Owner": {"type": "object", "properties": {"name": {"type": "string"}}}
+    }
+  }
+}`
+
+func TestAddTools(t *testing.T) {
+	ctx := context.Background()
+
+	backend := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%s %s %s", r.Method, r.URL.RequestURI(), body)
+	}))
+	defer backend.Close()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "petstore"}, nil)
+	err := AddTools(server, []byte(petstore), &Options{
+		BaseURL: backend.URL,
+		Authorize: func(r *http.Request) error {
+			r.Header.Set("Authorization", "Bearer secret")
+			return nil
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ct, st := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, st, nil); err != nil {
+		t.Fatal(err)
+	}
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: "client"}, nil).Connect(ctx, ct, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cs.Close()
+
+	res, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	tools := make(map[string]*mcp.Tool)
+	for _, tool := range res.Tools {
+		names = append(names, tool.Name)
+		tools[tool.Name] = tool
+	}
+	slices.Sort(names)
+	if want := []string{"createPet", "delete_pets_id", "getPet"}; !slices.Equal(names, want) {
+		t.Fatalf("tools: got %v, want %v", names, want)
+	}
+	if a := tools["getPet"].Annotations; a == nil || !a.ReadOnlyHint {
+		t.Errorf("getPet: got annotations %+v, want read-only", a)
+	}
+	if a := tools["delete_pets_id"].Annotations; a == nil || a.DestructiveHint == nil || !*a.DestructiveHint {
+		t.Errorf("delete_pets_id: got annotations %+v, want destructive", a)
+	}
+	schema, _ := json.Marshal(tools["createPet"].InputSchema)
+	if !strings.Contains(string(schema), `"$ref":"#/$defs/Pet"`) || !strings.Contains(string(schema), `"$ref":"#/$defs/Owner"`) {
+		t.Errorf("createPet schema does not refer to $defs: %s", schema)
+	}
+
+	for _, test := range []struct {
+		tool    string
+		args    map[string]any
+		want    string
+		wantErr bool
+	}{
+		{"getPet", map[string]any{"id": 7, "verbose": true}, "GET /pets/7?verbose=true ", false},
+		{"delete_pets_id", map[string]any{"id": 7}, "DELETE /pets/7 ", false},
+		{"createPet", map[string]any{"body": map[string]any{"name": "Rex"}}, `POST /pets {"name":"Rex"}`, false},
+		{"createPet", map[string]any{}, "", true},
+	} {
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: test.tool, Arguments: test.args})
+		if err != nil {
+			t.Fatalf("%s: %v", test.tool, err)
+		}
+		if res.IsError != test.wantErr {
+			t.Errorf("%s(%v): got IsError %t, want %t (%v)", test.tool, test.args, res.IsError, test.wantErr, res.Content)
+			continue
+		}
+		if test.wantErr {
+			continue
+		}
+		if got := res.Content[0].(*mcp.TextContent).Text; got != test.want {
+			t.Errorf("%s(%v): got %q, want %q", test.tool, test.args, got, test.want)
+		}
+	}
+}
+
+func TestAddToolsErrors(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		doc  string
+		opts *Options
+	}{
+		{"not JSON", "openapi: 3.0.0", nil},
+		{"swagger 2", `{"swagger": "2.0", "paths": {}}`, nil},
+		{"no base URL", `{"openapi": "3.1.0", "paths": {}}`, nil},
+		{"relative server", `{"openapi": "3.1.0", "servers": [{"url": "/v1"}], "paths": {}}`, nil},
+		{"bad parameter ref", `{"openapi": "3.1.0", "paths": {"/a": {"get": {"parameters": [{"$ref": "#/nope"}]}}}}`, &Options{BaseURL: "http://x"}},
+		{"duplicate names", `{"openapi": "3.1.0", "paths": {"/a": {"get": {"operationId": "x"}}, "/b": {"get": {"operationId": "x"}}}}`, &Options{BaseURL: "http://x"}},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+			if err := AddTools(s, []byte(test.doc), test.opts); err == nil {
+				t.Error("got nil error, want error")
+			}
+		})
+	}
+}
+
+func TestBaseURLFromServers(t *testing.T) {
+	var d document
+	if err := json.Unmarshal([]byte(petstore), &d); err != nil {
+		t.Fatal(err)
+	}
+	got, err := d.baseURL("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "https://example.com/v1"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestNewRequestPathParams(t *testing.T) {
+	o := &op{
+		method: http.MethodGet,
+		path:   "/users/{id}/keys",
+		params: []*parameter{{Name: "id", In: "path", Required: true}},
+	}
+	for _, test := range []struct {
+		id   string
+		want string // escaped path, or "" for an error
+	}{
+		{"alice", "/users/alice/keys"},
+		{"a/b", "/users/a%2Fb/keys"},
+		{"..a", "/users/..a/keys"},
+		{".", ""},
+		{"..", ""},
+	} {
+		req, err := o.newRequest(context.Background(), "http://example.com", map[string]any{"id": test.id})
+		if test.want == "" {
+			if err == nil {
+				t.Errorf("id %q: got URL %s, want error", test.id, req.URL)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("id %q: %v", test.id, err)
+			continue
+		}
+		if got := req.URL.EscapedPath(); got != test.want {
+			t.Errorf("id %q: got path %q, want %q", test.id, got, test.want)
+		}
+	}
+}